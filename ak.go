@@ -0,0 +1,146 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// AK represents a SCCP Message Data Acknowledgement (AK).
+type AK struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	ReceiveSequenceNumber     *params.ReceiveSequenceNumber
+	Credit                    *params.Credit
+
+	trailing []byte
+}
+
+// NewAK creates a new AK. pr is the 7-bit P(R), and credit is the window size.
+func NewAK(dlr uint32, pr, credit uint8) *AK {
+	return &AK{
+		Type:                      MsgTypeAK,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		ReceiveSequenceNumber:     params.NewReceiveSequenceNumberPR(pr),
+		Credit:                    params.NewCredit(credit),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an AK instance.
+func (a *AK) MarshalBinary() ([]byte, error) {
+	b := make([]byte, a.MarshalLen())
+	if err := a.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (a *AK) MarshalTo(b []byte) error {
+	return marshalSections(b, a.Type, a.fixed(), nil, nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the AK to dst, and
+// returns the extended slice.
+func (a *AK) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, a)
+}
+
+// WriteTo writes the byte sequence generated from the AK to w. It implements
+// io.WriterTo.
+func (a *AK) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, a)
+}
+
+// fixed returns the mandatory fixed parameters of the AK.
+func (a *AK) fixed() []params.Parameter {
+	return []params.Parameter{a.DestinationLocalReference, a.ReceiveSequenceNumber, a.Credit}
+}
+
+// ParseAK decodes given byte sequence as a SCCP AK.
+func ParseAK(b []byte) (*AK, error) {
+	a := &AK{}
+	if err := a.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP AK.
+func (a *AK) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	a.Type = MsgType(b[0])
+	a.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	a.ReceiveSequenceNumber = &params.ReceiveSequenceNumber{}
+	a.Credit = &params.Credit{}
+
+	_, n, err := params.UnmarshalSections(b[1:], a.fixed(), nil, false)
+	if err != nil {
+		return err
+	}
+
+	a.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the AK that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (a *AK) Clone() *AK {
+	c := *a
+	c.DestinationLocalReference = a.DestinationLocalReference.Clone()
+	c.ReceiveSequenceNumber = clonePtr(a.ReceiveSequenceNumber)
+	c.Credit = clonePtr(a.Credit)
+	c.trailing = bytes.Clone(a.trailing)
+
+	return &c
+}
+
+// Reset clears the AK to be reused with UnmarshalBinary.
+func (a *AK) Reset() {
+	*a = AK{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the AK when
+// it is parsed, or nil if there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (a *AK) TrailingBytes() []byte {
+	return a.trailing
+}
+
+// MarshalLen returns the serial length.
+func (a *AK) MarshalLen() int {
+	return 1 + params.SectionsLen(a.fixed(), nil, nil, false)
+}
+
+// String returns the AK values in human readable format.
+func (a *AK) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, ReceiveSequenceNumber: %s, Credit: %s}",
+		a.Type,
+		a.DestinationLocalReference,
+		a.ReceiveSequenceNumber,
+		a.Credit,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (a *AK) MessageType() MsgType {
+	return MsgTypeAK
+}
+
+// MessageTypeName returns the Message Type in string.
+func (a *AK) MessageTypeName() string {
+	return a.MessageType().String()
+}
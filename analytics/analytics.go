@@ -0,0 +1,156 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package analytics provides an aggregator that buckets the decoded SCCP traffic
+by the prefix of Global Title digits, and reports the volumes and message-type
+mix of each bucket.
+
+It is meant to be embedded in probes or command line tools that consume
+the parsed messages.
+*/
+package analytics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Side specifies which address the Aggregator takes the GT digits from.
+type Side uint8
+
+// Side values.
+const (
+	SideCalled  Side = iota // Called Party Address
+	SideCalling             // Calling Party Address
+)
+
+// NoGT is the prefix of the Bucket for the messages without GT.
+const NoGT = "-"
+
+// Bucket is a set of counters for the messages that share the same GT prefix.
+type Bucket struct {
+	Prefix   string
+	Messages uint64
+	Octets   uint64
+	ByType   map[sccp.MsgType]uint64
+}
+
+func (b *Bucket) clone() *Bucket {
+	c := &Bucket{
+		Prefix:   b.Prefix,
+		Messages: b.Messages,
+		Octets:   b.Octets,
+		ByType:   make(map[sccp.MsgType]uint64, len(b.ByType)),
+	}
+	for k, v := range b.ByType {
+		c.ByType[k] = v
+	}
+	return c
+}
+
+// Aggregator buckets the messages by GT prefix.
+//
+// Aggregator is safe for concurrent use.
+type Aggregator struct {
+	prefixLen int
+	side      Side
+
+	mu      sync.Mutex
+	buckets map[string]*Bucket
+}
+
+// NewAggregator creates a new Aggregator that buckets the messages by the
+// first prefixLen digits of the GT in the address specified by side.
+//
+// If prefixLen is 0 or less, the whole GT digits are used.
+func NewAggregator(prefixLen int, side Side) *Aggregator {
+	return &Aggregator{
+		prefixLen: prefixLen,
+		side:      side,
+		buckets:   map[string]*Bucket{},
+	}
+}
+
+// Observe counts the given message in the bucket it belongs to.
+func (a *Aggregator) Observe(m sccp.Message) {
+	prefix := a.prefixOf(m)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b, ok := a.buckets[prefix]
+	if !ok {
+		b = &Bucket{Prefix: prefix, ByType: map[sccp.MsgType]uint64{}}
+		a.buckets[prefix] = b
+	}
+
+	b.Messages++
+	b.Octets += uint64(m.MarshalLen())
+	b.ByType[m.MessageType()]++
+}
+
+// Buckets returns the snapshot of all the buckets sorted by prefix.
+func (a *Aggregator) Buckets() []*Bucket {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	buckets := make([]*Bucket, 0, len(a.buckets))
+	for _, b := range a.buckets {
+		buckets = append(buckets, b.clone())
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Prefix < buckets[j].Prefix
+	})
+
+	return buckets
+}
+
+// Bucket returns the snapshot of the bucket for the given prefix, or nil
+// if no message has been observed for the prefix.
+func (a *Aggregator) Bucket(prefix string) *Bucket {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b, ok := a.buckets[prefix]
+	if !ok {
+		return nil
+	}
+	return b.clone()
+}
+
+// Reset clears all the buckets.
+func (a *Aggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.buckets = map[string]*Bucket{}
+}
+
+func (a *Aggregator) prefixOf(m sccp.Message) string {
+	var cdpa, cgpa *params.PartyAddress
+	switch msg := m.(type) {
+	case *sccp.UDT:
+		cdpa, cgpa = msg.CalledPartyAddress, msg.CallingPartyAddress
+	case *sccp.XUDT:
+		cdpa, cgpa = msg.CalledPartyAddress, msg.CallingPartyAddress
+	}
+
+	addr := cdpa
+	if a.side == SideCalling {
+		addr = cgpa
+	}
+	if addr == nil || addr.GlobalTitle == nil {
+		return NoGT
+	}
+
+	digits := addr.Address()
+	if a.prefixLen > 0 && len(digits) > a.prefixLen {
+		return digits[:a.prefixLen]
+	}
+	return digits
+}
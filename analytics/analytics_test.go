@@ -0,0 +1,69 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package analytics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/analytics"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/utils"
+)
+
+func gtAddress(digits string) *params.PartyAddress {
+	es := params.ESBCDEven
+	if len(digits)%2 == 1 {
+		es = params.ESBCDOdd
+	}
+
+	return params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+		0, 6,
+		params.NewGlobalTitle(
+			params.GTITTNPESNAI,
+			params.TranslationType(0),
+			params.NPISDNTelephony,
+			es,
+			params.NAIInternationalNumber,
+			utils.MustBCDEncode(digits),
+		),
+	)
+}
+
+func TestAggregator(t *testing.T) {
+	cgpa := params.NewCallingPartyAddress(0x42, 0, 7, nil)
+
+	a := analytics.NewAggregator(4, analytics.SideCalled)
+	a.Observe(sccp.NewUDT(0, false, gtAddress("441234567"), cgpa, []byte{0x01}))
+	a.Observe(sccp.NewUDT(0, false, gtAddress("44129876"), cgpa, []byte{0x01, 0x02}))
+	a.Observe(sccp.NewXUDT(0, false, 15, gtAddress("8190123"), cgpa, []byte{0x01}))
+	a.Observe(sccp.NewUDT(0, false, params.NewCalledPartyAddress(0x42, 0, 6, nil), cgpa, nil))
+
+	var got []string
+	for _, b := range a.Buckets() {
+		got = append(got, b.Prefix)
+	}
+	if diff := cmp.Diff(got, []string{analytics.NoGT, "4412", "8190"}); diff != "" {
+		t.Error(diff)
+	}
+
+	b := a.Bucket("4412")
+	if b == nil {
+		t.Fatal("bucket 4412 not found")
+	}
+	if got, want := b.Messages, uint64(2); got != want {
+		t.Errorf("got %d messages, want %d", got, want)
+	}
+	if got, want := b.ByType[sccp.MsgTypeUDT], uint64(2); got != want {
+		t.Errorf("got %d UDTs, want %d", got, want)
+	}
+
+	a.Reset()
+	if got := len(a.Buckets()); got != 0 {
+		t.Errorf("got %d buckets after Reset, want 0", got)
+	}
+}
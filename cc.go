@@ -0,0 +1,239 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// CC represents a SCCP Message Connection Confirm (CC).
+type CC struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+	ProtocolClass             *params.ProtocolClass
+	Credit                    *params.Credit
+	CalledPartyAddress        *params.PartyAddress
+	Data                      *params.Data
+	Importance                *params.Importance
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	trailing []byte
+	opts     parseOptions
+}
+
+// NewCC creates a new CC.
+//
+// The optional parameters given as opts should be the optional ones, e.g.,
+// created with params.NewCreditOptional or params.NewCalledPartyAddressOptional.
+func NewCC(dlr, slr uint32, pcls int, opts ...params.Parameter) *CC {
+	c := &CC{
+		Type:                      MsgTypeCC,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+		ProtocolClass:             params.NewProtocolClass(pcls, false),
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCredit:
+			c.Credit = opt.(*params.Credit)
+		case params.PCodeCalledPartyAddress:
+			c.CalledPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			// always appended when there is any optional parameter.
+		default:
+			if takeUnknownParameter(&c.UnknownParameters, opt) {
+				continue
+			}
+			logf("unexpected parameter: %s in NewCC", opt.Code())
+		}
+	}
+
+	return c
+}
+
+// MarshalBinary returns the byte sequence generated from a CC instance.
+func (c *CC) MarshalBinary() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (c *CC) MarshalTo(b []byte) error {
+	if err := validateProtocolClass(c.Type, c.ProtocolClass); err != nil {
+		return err
+	}
+
+	fixed, optional := c.sections()
+	return marshalSections(b, c.Type, fixed, nil, optional, true)
+}
+
+// AppendTo appends the byte sequence generated from the CC to dst, and
+// returns the extended slice.
+func (c *CC) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, c)
+}
+
+// WriteTo writes the byte sequence generated from the CC to w. It implements
+// io.WriterTo.
+func (c *CC) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, c)
+}
+
+// sections returns the parameters in each section of the CC.
+func (c *CC) sections() (fixed, optional []params.Parameter) {
+	fixed = []params.Parameter{c.DestinationLocalReference, c.SourceLocalReference, c.ProtocolClass}
+
+	if param := c.Credit; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.CalledPartyAddress; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.Data; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.Importance; param != nil {
+		optional = append(optional, param)
+	}
+	for _, param := range c.UnknownParameters {
+		optional = append(optional, param)
+	}
+
+	return fixed, optional
+}
+
+// ParseCC decodes given byte sequence as a SCCP CC.
+func ParseCC(b []byte, opts ...ParseOption) (*CC, error) {
+	c := &CC{opts: *newParseOptions(opts)}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP CC.
+func (c *CC) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	c.Type = MsgType(b[0])
+	c.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	c.SourceLocalReference = params.NewSourceLocalReference(0)
+	c.ProtocolClass = &params.ProtocolClass{}
+	c.Credit, c.CalledPartyAddress, c.Data, c.Importance = nil, nil, nil, nil
+	c.UnknownParameters = nil
+
+	opts, n, err := c.opts.unmarshalSections(
+		b[1:],
+		[]params.Parameter{c.DestinationLocalReference, c.SourceLocalReference, c.ProtocolClass},
+		nil,
+		true,
+	)
+	if err != nil {
+		return err
+	}
+	if err := validateProtocolClass(c.Type, c.ProtocolClass); err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCredit:
+			c.Credit = opt.(*params.Credit)
+		case params.PCodeCalledPartyAddress:
+			c.CalledPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		default:
+			takeUnknownParameter(&c.UnknownParameters, opt)
+		}
+	}
+
+	c.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the CC that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (c *CC) Clone() *CC {
+	cl := *c
+	cl.DestinationLocalReference = c.DestinationLocalReference.Clone()
+	cl.SourceLocalReference = c.SourceLocalReference.Clone()
+	cl.ProtocolClass = clonePtr(c.ProtocolClass)
+	cl.Credit = clonePtr(c.Credit)
+	cl.CalledPartyAddress = c.CalledPartyAddress.Clone()
+	cl.Data = c.Data.Clone()
+	cl.Importance = clonePtr(c.Importance)
+	cl.UnknownParameters = cloneUnknownParameters(c.UnknownParameters)
+	cl.trailing = bytes.Clone(c.trailing)
+
+	return &cl
+}
+
+// Reset clears the CC to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (c *CC) Reset() {
+	*c = CC{opts: c.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the CC computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (c *CC) TrailingBytes() []byte {
+	return c.trailing
+}
+
+// MarshalLen returns the serial length.
+func (c *CC) MarshalLen() int {
+	fixed, optional := c.sections()
+	return 1 + params.SectionsLen(fixed, nil, optional, true)
+}
+
+// String returns the CC values in human readable format.
+func (c *CC) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s, ProtocolClass: %s, Credit: %v, CalledPartyAddress: %v, Data: %v, Importance: %v}",
+		c.Type,
+		c.DestinationLocalReference,
+		c.SourceLocalReference,
+		c.ProtocolClass,
+		c.Credit,
+		c.CalledPartyAddress,
+		c.Data,
+		c.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (c *CC) MessageType() MsgType {
+	return MsgTypeCC
+}
+
+// MessageTypeName returns the Message Type in string.
+func (c *CC) MessageTypeName() string {
+	return c.MessageType().String()
+}
@@ -0,0 +1,183 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Codec decodes and creates the SCCP messages in the format of a Variant,
+// which determines the length of the point codes, the layout of the Address
+// Indicator, the format of the SCMG and the maximum size of a message.
+//
+// The package-level functions such as ParseMessage use DefaultCodec unless
+// ParseOption is given, and a Codec can be given to them with Option.
+//
+// Codec is safe for concurrent use.
+type Codec struct {
+	opts parseOptions
+}
+
+// DefaultCodec is the Codec for params.VariantITU with no other ParseOption.
+var DefaultCodec = NewCodec()
+
+// NewCodec creates a new Codec with opts. The Variant is params.VariantITU
+// unless WithVariant is given.
+func NewCodec(opts ...ParseOption) *Codec {
+	return &Codec{opts: *newParseOptions(opts)}
+}
+
+// codecOf returns the Codec with opts, which is DefaultCodec if none is given.
+func codecOf(opts []ParseOption) *Codec {
+	if len(opts) == 0 {
+		return DefaultCodec
+	}
+	return NewCodec(opts...)
+}
+
+// Variant returns the Variant of the Codec.
+func (c *Codec) Variant() params.Variant {
+	return c.opts.variant
+}
+
+// Option returns the ParseOption that makes the parsing functions decode the
+// messages in the same way as the Codec.
+func (c *Codec) Option() ParseOption {
+	return func(o *parseOptions) {
+		*o = c.opts
+	}
+}
+
+// ParseMessage decodes the byte sequence into Message by Message Type.
+func (c *Codec) ParseMessage(b []byte) (Message, error) {
+	m, err := c.parseMessage(b)
+	if mt := currentMetrics(); mt != nil {
+		if err != nil {
+			mt.ParseError(parseErrorCause(err))
+		} else {
+			mt.MessageParsed(m.MessageType())
+		}
+	}
+
+	return m, err
+}
+
+func (c *Codec) parseMessage(b []byte) (Message, error) {
+	if len(b) < 1 {
+		return nil, fmt.Errorf("invalid SCCP message %v: %w", b, io.ErrUnexpectedEOF)
+	}
+
+	o := &c.opts
+
+	var m Message
+	switch MsgType(b[0]) {
+	case MsgTypeCR:
+		m = &CR{opts: *o}
+	case MsgTypeCC:
+		m = &CC{opts: *o}
+	case MsgTypeCREF:
+		m = &CREF{opts: *o}
+	case MsgTypeRLSD:
+		m = &RLSD{opts: *o}
+	case MsgTypeRLC:
+		m = &RLC{}
+	case MsgTypeDT1:
+		m = &DT1{}
+	case MsgTypeDT2:
+		m = &DT2{}
+	case MsgTypeAK:
+		m = &AK{}
+	case MsgTypeUDT:
+		m = &UDT{opts: *o}
+	case MsgTypeED:
+		m = &ED{}
+	case MsgTypeEA:
+		m = &EA{}
+	case MsgTypeRSR:
+		m = &RSR{}
+	case MsgTypeRSC:
+		m = &RSC{}
+	case MsgTypeUDTS:
+		m = &UDTS{opts: *o}
+	/* TODO: implement!
+	case MsgTypeERR:
+	*/
+	case MsgTypeIT:
+		m = &IT{}
+	case MsgTypeXUDT:
+		m = &XUDT{opts: *o}
+	case MsgTypeXUDTS:
+		m = &XUDTS{opts: *o}
+	case MsgTypeLUDT:
+		m = &LUDT{opts: *o}
+	case MsgTypeLUDTS:
+		m = &LUDTS{opts: *o}
+	default:
+		if parse := lookupMessageParser(MsgType(b[0])); parse != nil {
+			return parse(b)
+		}
+		return nil, UnsupportedTypeError(b[0])
+	}
+
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseSCMG decodes the byte sequence as a SCMG in the Variant of the Codec.
+func (c *Codec) ParseSCMG(b []byte) (*SCMG, error) {
+	s := &SCMG{variant: c.opts.variant}
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ParseView parses the byte sequence as a View with the options of the Codec.
+func (c *Codec) ParseView(b []byte) (*View, error) {
+	v := &View{opts: c.opts}
+	if err := v.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// NewPool creates a new Pool that parses the messages in the same way as the
+// Codec.
+func (c *Codec) NewPool() *Pool {
+	return NewPool(c.Option())
+}
+
+// NewSCMG creates a new SCMG in the Variant of the Codec.
+func (c *Codec) NewSCMG(typ SCMGType, assn uint8, apc params.PointCode, smi uint8, scl uint8) *SCMG {
+	s := NewSCMG(typ, assn, apc, smi, scl)
+	s.SetVariant(c.opts.variant)
+	return s
+}
+
+// AddressBuilder returns a new params.AddressBuilder that builds the
+// PartyAddress in the Variant of the Codec.
+func (c *Codec) AddressBuilder() *params.AddressBuilder {
+	return params.NewAddressBuilder().Variant(c.opts.variant)
+}
+
+// MaxMessageSize returns the maximum size of a message carried in a MTP3 MSU
+// in the Variant of the Codec, e.g., MaxMessageSizeITU.
+func (c *Codec) MaxMessageSize() int {
+	return maxMessageSize(c.opts.variant)
+}
+
+// BuildUnitdata is the same as BuildUnitdata, but the messages are sized for
+// the Variant of the Codec regardless of opts.Variant.
+func (c *Codec) BuildUnitdata(cdpa, cgpa *params.PartyAddress, data []byte, opts UnitdataOptions) ([]Message, error) {
+	opts.Variant = c.opts.variant
+	return BuildUnitdata(cdpa, cgpa, data, opts)
+}
@@ -0,0 +1,192 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"sync"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Restriction level parameters used in the traffic limitation of Q.714 5.2.8.
+const (
+	// MaxRestrictionLevel is the number of the restriction levels (N).
+	// All the messages are discarded at this level, as it exceeds the
+	// maximum importance.
+	MaxRestrictionLevel uint8 = 8
+	// MaxRestrictionSublevel is the number of the restriction sublevels (M)
+	// in each restriction level.
+	MaxRestrictionSublevel uint8 = 4
+)
+
+// DefaultImportance is the importance of UDT and XUDT used when no Importance
+// parameter is present, defined in Q.714 Table 2.
+const DefaultImportance uint8 = 4
+
+// CongestionAction is the action to take on a message to a congested
+// signalling point.
+type CongestionAction uint8
+
+// CongestionAction definitions.
+const (
+	CongestionPass  CongestionAction = iota // send the message
+	CongestionDelay                         // send the message when the congestion is abated, or discard it
+	CongestionDrop                          // discard the message
+)
+
+// CongestionTracker tracks the congestion of the remote signalling points
+// reported by SSC and by the MTP-STATUS indications, and tells which messages
+// should be dropped or delayed to them.
+//
+// For each signalling point, the restriction level RL and sublevel RSL are
+// the greater of the ones due to the MTP congestion and the congestion level
+// in SSC. The MTP congestion is handled in the international method of Q.714
+// 5.2.8: each MTPCongestion raises RSL, and RL when RSL reaches
+// MaxRestrictionSublevel; MTPCongestionAbated lowers them in the reverse way.
+// The timers T(con) and T(decay) are left to the caller.
+//
+// CongestionTracker is safe for concurrent use.
+type CongestionTracker struct {
+	mu     sync.Mutex
+	points map[params.PointCode]*congestionState
+}
+
+// congestionState is the congestion state of a remote signalling point.
+type congestionState struct {
+	mtpRL, mtpRSL uint8
+	sccpCL        uint8
+}
+
+// NewCongestionTracker creates a new CongestionTracker.
+func NewCongestionTracker() *CongestionTracker {
+	return &CongestionTracker{points: map[params.PointCode]*congestionState{}}
+}
+
+// HandleSCMG updates the SCCP congestion level of the AffectedPC on SSC.
+// The other SCMG messages are ignored.
+func (c *CongestionTracker) HandleSCMG(s *SCMG) {
+	if s.Type != SCMGTypeSSC {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.state(s.AffectedPC).sccpCL = min(s.SCCPCongestionLevel, MaxRestrictionLevel)
+	c.cleanup(s.AffectedPC)
+}
+
+// MTPCongestion handles the MTP-STATUS indication of congestion for pc.
+func (c *CongestionTracker) MTPCongestion(pc params.PointCode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st := c.state(pc)
+	if st.mtpRL >= MaxRestrictionLevel {
+		return
+	}
+
+	st.mtpRSL++
+	if st.mtpRSL >= MaxRestrictionSublevel {
+		st.mtpRL++
+		st.mtpRSL = 0
+	}
+}
+
+// MTPCongestionAbated lowers the restriction due to the MTP congestion for pc
+// by one sublevel, which should be called on the expiry of T(decay).
+func (c *CongestionTracker) MTPCongestionAbated(pc params.PointCode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st := c.state(pc)
+	switch {
+	case st.mtpRSL > 0:
+		st.mtpRSL--
+	case st.mtpRL > 0:
+		st.mtpRL--
+		st.mtpRSL = MaxRestrictionSublevel - 1
+	}
+	c.cleanup(pc)
+}
+
+// Clear clears the congestion state of pc, e.g., when it becomes accessible again.
+func (c *CongestionTracker) Clear(pc params.PointCode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.points, pc)
+}
+
+// RestrictionLevel returns the restriction level and sublevel for pc.
+func (c *CongestionTracker) RestrictionLevel(pc params.PointCode) (rl, rsl uint8) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st, ok := c.points[pc]
+	if !ok {
+		return 0, 0
+	}
+
+	if st.sccpCL > st.mtpRL {
+		return st.sccpCL, 0
+	}
+	return st.mtpRL, st.mtpRSL
+}
+
+// Action returns the action to take on a message with the given importance
+// to pc: the message is dropped if the importance is lower than the
+// restriction level, and delayed if it is equal and the sublevel is not 0.
+func (c *CongestionTracker) Action(pc params.PointCode, importance uint8) CongestionAction {
+	rl, rsl := c.RestrictionLevel(pc)
+	switch {
+	case importance < rl:
+		return CongestionDrop
+	case importance == rl && rsl > 0:
+		return CongestionDelay
+	default:
+		return CongestionPass
+	}
+}
+
+// MessageImportance returns the importance of m, which is the value of the
+// Importance parameter if present, or DefaultImportance otherwise.
+func MessageImportance(m Message) uint8 {
+	switch m := m.(type) {
+	case *XUDT:
+		if m.Importance != nil {
+			return m.Importance.Value()
+		}
+	case *XUDTS:
+		if m.Importance != nil {
+			return m.Importance.Value()
+		}
+	case *LUDT:
+		if m.Importance != nil {
+			return m.Importance.Value()
+		}
+	case *LUDTS:
+		if m.Importance != nil {
+			return m.Importance.Value()
+		}
+	}
+	return DefaultImportance
+}
+
+func (c *CongestionTracker) state(pc params.PointCode) *congestionState {
+	st, ok := c.points[pc]
+	if !ok {
+		st = &congestionState{}
+		c.points[pc] = st
+	}
+	return st
+}
+
+// cleanup removes the state of pc if it is not congested at all.
+func (c *CongestionTracker) cleanup(pc params.PointCode) {
+	if st := c.points[pc]; st != nil && *st == (congestionState{}) {
+		delete(c.points, pc)
+	}
+}
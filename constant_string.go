@@ -51,16 +51,30 @@ func _() {
 	_ = x[SCMGTypeSOR-4]
 	_ = x[SCMGTypeSOG-5]
 	_ = x[SCMGTypeSSC-6]
+	_ = x[SCMGTypeSBR-253]
+	_ = x[SCMGTypeSNR-254]
+	_ = x[SCMGTypeSRT-255]
 }
 
-const _SCMGType_name = "SSASSPSSTSORSOGSSC"
+const (
+	_SCMGType_name_0 = "SSASSPSSTSORSOGSSC"
+	_SCMGType_name_1 = "SBRSNRSRT"
+)
 
-var _SCMGType_index = [...]uint8{0, 3, 6, 9, 12, 15, 18}
+var (
+	_SCMGType_index_0 = [...]uint8{0, 3, 6, 9, 12, 15, 18}
+	_SCMGType_index_1 = [...]uint8{0, 3, 6, 9}
+)
 
 func (i SCMGType) String() string {
-	i -= 1
-	if i >= SCMGType(len(_SCMGType_index)-1) {
-		return "SCMGType(" + strconv.FormatInt(int64(i+1), 10) + ")"
+	switch {
+	case 1 <= i && i <= 6:
+		i -= 1
+		return _SCMGType_name_0[_SCMGType_index_0[i]:_SCMGType_index_0[i+1]]
+	case 253 <= i && i <= 255:
+		i -= 253
+		return _SCMGType_name_1[_SCMGType_index_1[i]:_SCMGType_index_1[i+1]]
+	default:
+		return "SCMGType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _SCMGType_name[_SCMGType_index[i]:_SCMGType_index[i+1]]
 }
@@ -0,0 +1,250 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// CR represents a SCCP Message Connection Request (CR).
+type CR struct {
+	Type                 MsgType
+	SourceLocalReference *params.LocalReference
+	ProtocolClass        *params.ProtocolClass
+	CalledPartyAddress   *params.PartyAddress
+	Credit               *params.Credit
+	CallingPartyAddress  *params.PartyAddress
+	Data                 *params.Data
+	HopCounter           *params.HopCounter
+	Importance           *params.Importance
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	trailing []byte
+	opts     parseOptions
+}
+
+// NewCR creates a new CR.
+//
+// The optional parameters given as opts should be the optional ones, e.g.,
+// created with params.NewCreditOptional or params.NewCallingPartyAddressOptional.
+func NewCR(slr uint32, pcls int, cdpa *params.PartyAddress, opts ...params.Parameter) *CR {
+	c := &CR{
+		Type:                 MsgTypeCR,
+		SourceLocalReference: params.NewSourceLocalReference(slr),
+		ProtocolClass:        params.NewProtocolClass(pcls, false),
+		CalledPartyAddress:   cdpa,
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCredit:
+			c.Credit = opt.(*params.Credit)
+		case params.PCodeCallingPartyAddress:
+			c.CallingPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeHopCounter:
+			c.HopCounter = opt.(*params.HopCounter)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			// always appended when there is any optional parameter.
+		default:
+			if takeUnknownParameter(&c.UnknownParameters, opt) {
+				continue
+			}
+			logf("unexpected parameter: %s in NewCR", opt.Code())
+		}
+	}
+
+	return c
+}
+
+// MarshalBinary returns the byte sequence generated from a CR instance.
+func (c *CR) MarshalBinary() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (c *CR) MarshalTo(b []byte) error {
+	if err := validateProtocolClass(c.Type, c.ProtocolClass); err != nil {
+		return err
+	}
+
+	fixed, variable, optional := c.sections()
+	return marshalSections(b, c.Type, fixed, variable, optional, true)
+}
+
+// AppendTo appends the byte sequence generated from the CR to dst, and
+// returns the extended slice.
+func (c *CR) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, c)
+}
+
+// WriteTo writes the byte sequence generated from the CR to w. It implements
+// io.WriterTo.
+func (c *CR) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, c)
+}
+
+// sections returns the parameters in each section of the CR.
+func (c *CR) sections() (fixed, variable, optional []params.Parameter) {
+	fixed = []params.Parameter{c.SourceLocalReference, c.ProtocolClass}
+	variable = []params.Parameter{c.CalledPartyAddress}
+
+	if param := c.Credit; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.CallingPartyAddress; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.Data; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.HopCounter; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.Importance; param != nil {
+		optional = append(optional, param)
+	}
+	for _, param := range c.UnknownParameters {
+		optional = append(optional, param)
+	}
+
+	return fixed, variable, optional
+}
+
+// ParseCR decodes given byte sequence as a SCCP CR.
+func ParseCR(b []byte, opts ...ParseOption) (*CR, error) {
+	c := &CR{opts: *newParseOptions(opts)}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP CR.
+func (c *CR) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	c.Type = MsgType(b[0])
+	c.SourceLocalReference = params.NewSourceLocalReference(0)
+	c.ProtocolClass = &params.ProtocolClass{}
+	c.CalledPartyAddress = params.NewCalledPartyAddress(0, 0, 0, nil)
+	c.Credit, c.CallingPartyAddress, c.Data, c.HopCounter, c.Importance = nil, nil, nil, nil, nil
+	c.UnknownParameters = nil
+
+	opts, n, err := c.opts.unmarshalSections(
+		b[1:],
+		[]params.Parameter{c.SourceLocalReference, c.ProtocolClass},
+		[]params.Parameter{c.CalledPartyAddress},
+		true,
+	)
+	if err != nil {
+		return err
+	}
+	if err := validateProtocolClass(c.Type, c.ProtocolClass); err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCredit:
+			c.Credit = opt.(*params.Credit)
+		case params.PCodeCallingPartyAddress:
+			c.CallingPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeHopCounter:
+			c.HopCounter = opt.(*params.HopCounter)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		default:
+			takeUnknownParameter(&c.UnknownParameters, opt)
+		}
+	}
+
+	c.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the CR that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (c *CR) Clone() *CR {
+	cl := *c
+	cl.SourceLocalReference = c.SourceLocalReference.Clone()
+	cl.ProtocolClass = clonePtr(c.ProtocolClass)
+	cl.CalledPartyAddress = c.CalledPartyAddress.Clone()
+	cl.Credit = clonePtr(c.Credit)
+	cl.CallingPartyAddress = c.CallingPartyAddress.Clone()
+	cl.Data = c.Data.Clone()
+	cl.HopCounter = clonePtr(c.HopCounter)
+	cl.Importance = clonePtr(c.Importance)
+	cl.UnknownParameters = cloneUnknownParameters(c.UnknownParameters)
+	cl.trailing = bytes.Clone(c.trailing)
+
+	return &cl
+}
+
+// Reset clears the CR to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (c *CR) Reset() {
+	*c = CR{opts: c.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the CR computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (c *CR) TrailingBytes() []byte {
+	return c.trailing
+}
+
+// MarshalLen returns the serial length.
+func (c *CR) MarshalLen() int {
+	fixed, variable, optional := c.sections()
+	return 1 + params.SectionsLen(fixed, variable, optional, true)
+}
+
+// String returns the CR values in human readable format.
+func (c *CR) String() string {
+	return fmt.Sprintf("%s: {SourceLocalReference: %s, ProtocolClass: %s, CalledPartyAddress: %v, Credit: %v, CallingPartyAddress: %v, Data: %v, HopCounter: %v, Importance: %v}",
+		c.Type,
+		c.SourceLocalReference,
+		c.ProtocolClass,
+		c.CalledPartyAddress,
+		c.Credit,
+		c.CallingPartyAddress,
+		c.Data,
+		c.HopCounter,
+		c.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (c *CR) MessageType() MsgType {
+	return MsgTypeCR
+}
+
+// MessageTypeName returns the Message Type in string.
+func (c *CR) MessageTypeName() string {
+	return c.MessageType().String()
+}
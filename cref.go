@@ -0,0 +1,217 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// CREF represents a SCCP Message Connection Refused (CREF).
+type CREF struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	RefusalCause              *params.RefusalCause
+	CalledPartyAddress        *params.PartyAddress
+	Data                      *params.Data
+	Importance                *params.Importance
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	trailing []byte
+	opts     parseOptions
+}
+
+// NewCREF creates a new CREF.
+//
+// The optional parameters given as opts should be the optional ones, e.g.,
+// created with params.NewCalledPartyAddressOptional or params.NewDataOptional.
+func NewCREF(dlr uint32, cause params.RefusalCauseValue, opts ...params.Parameter) *CREF {
+	c := &CREF{
+		Type:                      MsgTypeCREF,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		RefusalCause:              params.NewCause(cause),
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCalledPartyAddress:
+			c.CalledPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			// always appended when there is any optional parameter.
+		default:
+			if takeUnknownParameter(&c.UnknownParameters, opt) {
+				continue
+			}
+			logf("unexpected parameter: %s in NewCREF", opt.Code())
+		}
+	}
+
+	return c
+}
+
+// MarshalBinary returns the byte sequence generated from a CREF instance.
+func (c *CREF) MarshalBinary() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (c *CREF) MarshalTo(b []byte) error {
+	fixed, optional := c.sections()
+	return marshalSections(b, c.Type, fixed, nil, optional, true)
+}
+
+// AppendTo appends the byte sequence generated from the CREF to dst, and
+// returns the extended slice.
+func (c *CREF) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, c)
+}
+
+// WriteTo writes the byte sequence generated from the CREF to w. It implements
+// io.WriterTo.
+func (c *CREF) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, c)
+}
+
+// sections returns the parameters in each section of the CREF.
+func (c *CREF) sections() (fixed, optional []params.Parameter) {
+	fixed = []params.Parameter{c.DestinationLocalReference, c.RefusalCause}
+
+	if param := c.CalledPartyAddress; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.Data; param != nil {
+		optional = append(optional, param)
+	}
+	if param := c.Importance; param != nil {
+		optional = append(optional, param)
+	}
+	for _, param := range c.UnknownParameters {
+		optional = append(optional, param)
+	}
+
+	return fixed, optional
+}
+
+// ParseCREF decodes given byte sequence as a SCCP CREF.
+func ParseCREF(b []byte, opts ...ParseOption) (*CREF, error) {
+	c := &CREF{opts: *newParseOptions(opts)}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP CREF.
+func (c *CREF) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	c.Type = MsgType(b[0])
+	c.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	c.RefusalCause = &params.RefusalCause{}
+	c.CalledPartyAddress, c.Data, c.Importance = nil, nil, nil
+	c.UnknownParameters = nil
+
+	opts, n, err := c.opts.unmarshalSections(
+		b[1:],
+		[]params.Parameter{c.DestinationLocalReference, c.RefusalCause},
+		nil,
+		true,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeCalledPartyAddress:
+			c.CalledPartyAddress = opt.(*params.PartyAddress)
+		case params.PCodeData:
+			c.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			c.Importance = opt.(*params.Importance)
+		default:
+			takeUnknownParameter(&c.UnknownParameters, opt)
+		}
+	}
+
+	c.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the CREF that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (c *CREF) Clone() *CREF {
+	cl := *c
+	cl.DestinationLocalReference = c.DestinationLocalReference.Clone()
+	cl.RefusalCause = clonePtr(c.RefusalCause)
+	cl.CalledPartyAddress = c.CalledPartyAddress.Clone()
+	cl.Data = c.Data.Clone()
+	cl.Importance = clonePtr(c.Importance)
+	cl.UnknownParameters = cloneUnknownParameters(c.UnknownParameters)
+	cl.trailing = bytes.Clone(c.trailing)
+
+	return &cl
+}
+
+// Reset clears the CREF to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (c *CREF) Reset() {
+	*c = CREF{opts: c.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the CREF computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (c *CREF) TrailingBytes() []byte {
+	return c.trailing
+}
+
+// MarshalLen returns the serial length.
+func (c *CREF) MarshalLen() int {
+	fixed, optional := c.sections()
+	return 1 + params.SectionsLen(fixed, nil, optional, true)
+}
+
+// String returns the CREF values in human readable format.
+func (c *CREF) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, RefusalCause: %s, CalledPartyAddress: %v, Data: %v, Importance: %v}",
+		c.Type,
+		c.DestinationLocalReference,
+		c.RefusalCause,
+		c.CalledPartyAddress,
+		c.Data,
+		c.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (c *CREF) MessageType() MsgType {
+	return MsgTypeCREF
+}
+
+// MessageTypeName returns the Message Type in string.
+func (c *CREF) MessageTypeName() string {
+	return c.MessageType().String()
+}
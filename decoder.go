@@ -0,0 +1,100 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxFrameSize is the maximum size of a frame read by the FrameReaders
+// returned by LengthPrefixed.
+const MaxFrameSize = 0xffff
+
+// FrameReader reads a frame that contains a SCCP message from r.
+//
+// The frame should be read into buf, which is the one returned by the previous
+// call and can be grown if it is not large enough. It should return io.EOF only
+// if no byte is read before the end of r, and io.ErrUnexpectedEOF if the end is
+// reached in the middle of a frame.
+type FrameReader func(r io.Reader, buf []byte) ([]byte, error)
+
+// LengthPrefixed returns a FrameReader for the frames preceded by the length
+// in n octets in network byte order, which must be 1, 2 or 4. The frames
+// longer than MaxFrameSize are rejected with ErrFrameTooLarge.
+func LengthPrefixed(n int) FrameReader {
+	switch n {
+	case 1, 2, 4:
+	default:
+		panic(fmt.Sprintf("sccp: invalid length prefix size %d", n))
+	}
+
+	return func(r io.Reader, buf []byte) ([]byte, error) {
+		var hdr [4]byte
+		if _, err := io.ReadFull(r, hdr[4-n:]); err != nil {
+			return buf, err
+		}
+
+		l := int(binary.BigEndian.Uint32(hdr[:]))
+		if l > MaxFrameSize {
+			return buf, fmt.Errorf("%d octets: %w", l, ErrFrameTooLarge)
+		}
+
+		if cap(buf) < l {
+			buf = make([]byte, l)
+		}
+		buf = buf[:l]
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return buf, err
+		}
+
+		return buf, nil
+	}
+}
+
+// Decoder reads and decodes the SCCP messages from a stream of frames, such
+// as a capture replayed or a transport that delimits the messages.
+//
+// The frames are read into the buffer in the Decoder that is reused in every
+// call to Decode. As the Messages refer to the byte sequence they are parsed
+// from, the Message returned by Decode must not be used after the next call,
+// unless it is copied with Clone.
+type Decoder struct {
+	r     io.Reader
+	frame FrameReader
+	opts  []ParseOption
+	buf   []byte
+}
+
+// NewDecoder creates a new Decoder that reads the frames from r with frame,
+// and decodes them with opts.
+func NewDecoder(r io.Reader, frame FrameReader, opts ...ParseOption) *Decoder {
+	return &Decoder{r: r, frame: frame, opts: opts}
+}
+
+// Decode reads the next frame and decodes it as a Message. It returns io.EOF
+// when there is no more frame.
+//
+// The error in decoding a frame does not stop the Decoder, and the next call
+// reads the next frame, while the error in reading one is returned as it is.
+func (d *Decoder) Decode() (Message, error) {
+	b, err := d.frame(d.r, d.buf)
+	if err != nil {
+		return nil, err
+	}
+	d.buf = b
+
+	m, err := ParseMessage(b, d.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode frame of %d octets: %w", len(b), err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,262 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package diagram renders the captured SCCP message flows as sequence diagrams.
+
+Messages given to Recorder are grouped into flows by the pair of Calling/Called
+Party Addresses (regardless of the direction) and local references if any, and
+each flow can be exported in PlantUML or Mermaid format for troubleshooting reports.
+*/
+package diagram
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Key is a key to group the messages into a Flow.
+//
+// A and B are the human-readable representation of the addresses sorted
+// in lexical order, so that the messages in both directions belong to the
+// same Flow.
+type Key struct {
+	A, B           string
+	LocalReference uint32
+}
+
+// Event is a message observed at a certain time.
+type Event struct {
+	Time    time.Time
+	From    string
+	To      string
+	Message sccp.Message
+}
+
+// Label returns the text to be shown on the arrow of the Event.
+func (e *Event) Label() string {
+	label := e.Message.MessageTypeName()
+	if scmg := scmgIn(e.Message); scmg != nil {
+		label += " [SCMG " + scmg.MessageTypeName() + "]"
+	}
+
+	return label
+}
+
+// Flow is a series of Events that share the same Key.
+type Flow struct {
+	Key    Key
+	Events []*Event
+}
+
+// Recorder records the messages and groups them into Flows.
+//
+// Recorder is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	flows map[Key]*Flow
+	order []Key
+}
+
+// NewRecorder creates a new Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		flows: map[Key]*Flow{},
+	}
+}
+
+// Add records the message observed at t.
+//
+// The messages without Called/Calling Party Address are ignored.
+func (r *Recorder) Add(t time.Time, m sccp.Message) {
+	cdpa, cgpa, ref, ok := addressesOf(m)
+	if !ok {
+		return
+	}
+
+	from, to := AddressLabel(cgpa), AddressLabel(cdpa)
+	key := Key{A: from, B: to, LocalReference: ref}
+	if key.A > key.B {
+		key.A, key.B = key.B, key.A
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.flows[key]
+	if !ok {
+		f = &Flow{Key: key}
+		r.flows[key] = f
+		r.order = append(r.order, key)
+	}
+	f.Events = append(f.Events, &Event{Time: t, From: from, To: to, Message: m})
+}
+
+// Flows returns the recorded Flows in the order of their first appearance.
+func (r *Recorder) Flows() []*Flow {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	flows := make([]*Flow, len(r.order))
+	for i, k := range r.order {
+		flows[i] = r.flows[k]
+	}
+	return flows
+}
+
+// PlantUML returns all the recorded Flows in a single PlantUML sequence diagram.
+func (r *Recorder) PlantUML() string {
+	return plantUML(r.Flows()...)
+}
+
+// Mermaid returns all the recorded Flows in a single Mermaid sequence diagram.
+func (r *Recorder) Mermaid() string {
+	return mermaid(r.Flows()...)
+}
+
+// PlantUML returns the Flow as a PlantUML sequence diagram.
+func (f *Flow) PlantUML() string {
+	return plantUML(f)
+}
+
+// Mermaid returns the Flow as a Mermaid sequence diagram.
+func (f *Flow) Mermaid() string {
+	return mermaid(f)
+}
+
+func plantUML(flows ...*Flow) string {
+	ids := participants(flows...)
+
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	for _, p := range ids.names {
+		fmt.Fprintf(&sb, "participant %q as %s\n", p, ids.byName[p])
+	}
+	for _, f := range flows {
+		for _, e := range f.Events {
+			fmt.Fprintf(&sb, "%s -> %s : %s\n", ids.byName[e.From], ids.byName[e.To], eventText(e))
+		}
+	}
+	sb.WriteString("@enduml\n")
+
+	return sb.String()
+}
+
+func mermaid(flows ...*Flow) string {
+	ids := participants(flows...)
+
+	var sb strings.Builder
+	sb.WriteString("sequenceDiagram\n")
+	for _, p := range ids.names {
+		fmt.Fprintf(&sb, "    participant %s as %s\n", ids.byName[p], p)
+	}
+	for _, f := range flows {
+		for _, e := range f.Events {
+			fmt.Fprintf(&sb, "    %s->>%s: %s\n", ids.byName[e.From], ids.byName[e.To], eventText(e))
+		}
+	}
+
+	return sb.String()
+}
+
+func eventText(e *Event) string {
+	if e.Time.IsZero() {
+		return e.Label()
+	}
+	return e.Time.Format("15:04:05.000") + " " + e.Label()
+}
+
+type participantIDs struct {
+	names  []string
+	byName map[string]string
+}
+
+func participants(flows ...*Flow) *participantIDs {
+	ids := &participantIDs{byName: map[string]string{}}
+	add := func(name string) {
+		if _, ok := ids.byName[name]; ok {
+			return
+		}
+		ids.names = append(ids.names, name)
+		ids.byName[name] = fmt.Sprintf("P%d", len(ids.names))
+	}
+
+	for _, f := range flows {
+		for _, e := range f.Events {
+			add(e.From)
+			add(e.To)
+		}
+	}
+
+	return ids
+}
+
+// AddressLabel returns the human-readable label of the PartyAddress used as
+// a participant in the diagrams.
+func AddressLabel(p *params.PartyAddress) string {
+	if p == nil {
+		return "unknown"
+	}
+
+	if p.GlobalTitle != nil {
+		return "GT " + p.Address()
+	}
+
+	var labels []string
+	if p.HasPC() {
+		labels = append(labels, fmt.Sprintf("PC %s", p.SignalingPointCode))
+	}
+	if p.HasSSN() {
+		labels = append(labels, fmt.Sprintf("SSN %d", p.SubsystemNumber))
+	}
+	if len(labels) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(labels, " ")
+}
+
+func addressesOf(m sccp.Message) (cdpa, cgpa *params.PartyAddress, ref uint32, ok bool) {
+	switch msg := m.(type) {
+	case *sccp.UDT:
+		return msg.CalledPartyAddress, msg.CallingPartyAddress, 0, true
+	case *sccp.XUDT:
+		if msg.Segmentation != nil {
+			ref = msg.Segmentation.LocalReference
+		}
+		return msg.CalledPartyAddress, msg.CallingPartyAddress, ref, true
+	default:
+		return nil, nil, 0, false
+	}
+}
+
+func scmgIn(m sccp.Message) *sccp.SCMG {
+	var (
+		cdpa *params.PartyAddress
+		data *params.Data
+	)
+	switch msg := m.(type) {
+	case *sccp.UDT:
+		cdpa, data = msg.CalledPartyAddress, msg.LoadData()
+	case *sccp.XUDT:
+		cdpa, data = msg.CalledPartyAddress, msg.LoadData()
+	default:
+		return nil
+	}
+
+	if cdpa == nil || data == nil || !cdpa.HasSSN() || cdpa.SubsystemNumber != 1 {
+		return nil
+	}
+
+	scmg, err := sccp.ParseSCMG(data.Value())
+	if err != nil {
+		return nil
+	}
+	return scmg
+}
@@ -0,0 +1,60 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package diagram_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/diagram"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func TestRecorder(t *testing.T) {
+	hlr := params.NewCalledPartyAddress(0x42, 0, 6, nil)
+	vlr := params.NewCallingPartyAddress(0x42, 0, 7, nil)
+	scmgAddr := params.NewCalledPartyAddress(0x42, 0, 1, nil)
+
+	ssp, err := sccp.NewSCMG(sccp.SCMGTypeSSP, 6, 0, 0, 0).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := diagram.NewRecorder()
+	r.Add(time.Time{}, sccp.NewUDT(0, false, hlr, vlr, []byte{0x01}))
+	r.Add(time.Time{}, sccp.NewUDT(0, false, vlr, hlr, []byte{0x02}))
+	r.Add(time.Time{}, sccp.NewUDT(0, false, scmgAddr, scmgAddr, ssp))
+
+	if got, want := len(r.Flows()), 2; got != want {
+		t.Fatalf("got %d flows, want %d", got, want)
+	}
+
+	t.Run("Mermaid", func(t *testing.T) {
+		want := "sequenceDiagram\n" +
+			"    participant P1 as SSN 7\n" +
+			"    participant P2 as SSN 6\n" +
+			"    participant P3 as SSN 1\n" +
+			"    P1->>P2: UDT\n" +
+			"    P2->>P1: UDT\n" +
+			"    P3->>P3: UDT [SCMG SSP]\n"
+		if diff := cmp.Diff(r.Mermaid(), want); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("PlantUML", func(t *testing.T) {
+		want := "@startuml\n" +
+			"participant \"SSN 7\" as P1\n" +
+			"participant \"SSN 6\" as P2\n" +
+			"P1 -> P2 : UDT\n" +
+			"P2 -> P1 : UDT\n" +
+			"@enduml\n"
+		if diff := cmp.Diff(r.Flows()[0].PlantUML(), want); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Unitdata is the N-UNITDATA indication primitive defined in Q.711, which is
+// handed to the UpperLayer with the Data of the UDT or XUDT received.
+type Unitdata struct {
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	// SequenceControl is true if the message is in protocol class 1.
+	SequenceControl bool
+	// ReturnOption is the return option of the message.
+	ReturnOption bool
+	// Importance is the importance of the message, see MessageImportance.
+	Importance uint8
+	// Data is the user data, which is reassembled if the message is segmented
+	// and Dispatcher has the Reassembler.
+	Data []byte
+
+	// Message is the UDT or XUDT received, or the last segment of the data.
+	Message Message
+}
+
+// UpperLayer is the user of the SCCP connectionless service, such as a TCAP
+// implementation, which is registered to a Dispatcher for the SSN.
+type UpperLayer interface {
+	// HandleUnitdata handles the Unitdata received, and returns the data to
+	// send back to the originator, or nil if there is nothing to send.
+	HandleUnitdata(ctx context.Context, u *Unitdata) ([]byte, error)
+}
+
+// UpperLayerFunc is a function that implements UpperLayer.
+type UpperLayerFunc func(ctx context.Context, u *Unitdata) ([]byte, error)
+
+// HandleUnitdata calls f.
+func (f UpperLayerFunc) HandleUnitdata(ctx context.Context, u *Unitdata) ([]byte, error) {
+	return f(ctx, u)
+}
+
+// DispatcherConfig is the configuration of a Dispatcher. The zero values are
+// valid.
+type DispatcherConfig struct {
+	// Reassembler is used to reassemble the data in the segmented XUDTs and
+	// LUDTs before they are handed to the UpperLayer. The segments are handed
+	// as they are if nil.
+	Reassembler *Reassembler
+	// Unitdata is the options of the messages that carry the data returned by
+	// the UpperLayer. ProtocolClass and ReturnOnError are taken from the
+	// message received.
+	Unitdata UnitdataOptions
+}
+
+// Dispatcher hands the Data of the UDT and XUDT received to the UpperLayer
+// registered for the SSN in the Called Party Address, and wraps the data
+// returned by it into the unitdata messages to the originator.
+//
+// It does not implement any transport, and the caller is responsible for
+// sending the messages returned by Dispatch.
+//
+// Dispatcher is safe for concurrent use.
+type Dispatcher struct {
+	cfg DispatcherConfig
+	// build creates the messages to send back, which is BuildUnitdata by
+	// default and Endpoint.buildUnitdata in Endpoint.
+	build func(cdpa, cgpa *params.PartyAddress, data []byte, opts UnitdataOptions) ([]Message, error)
+
+	mu       sync.RWMutex
+	layers   map[uint8]UpperLayer
+	fallback UpperLayer
+}
+
+// NewDispatcher creates a new Dispatcher with cfg, which can be nil to use
+// the default values.
+func NewDispatcher(cfg *DispatcherConfig) *Dispatcher {
+	d := &Dispatcher{layers: map[uint8]UpperLayer{}, build: BuildUnitdata}
+	if cfg != nil {
+		d.cfg = *cfg
+	}
+
+	return d
+}
+
+// Register registers the UpperLayer for the SSN. Registering the same SSN
+// again overwrites the UpperLayer.
+func (d *Dispatcher) Register(ssn uint8, l UpperLayer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.layers[ssn] = l
+}
+
+// Unregister removes the UpperLayer for the SSN.
+func (d *Dispatcher) Unregister(ssn uint8) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.layers, ssn)
+}
+
+// SetDefault sets the UpperLayer that handles the messages for the SSNs that
+// have no UpperLayer registered, including the ones without SSN.
+func (d *Dispatcher) SetDefault(l UpperLayer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fallback = l
+}
+
+// upperLayer returns the UpperLayer for the Called Party Address.
+func (d *Dispatcher) upperLayer(cdpa *params.PartyAddress) UpperLayer {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if cdpa != nil && cdpa.HasSSN() {
+		if l, ok := d.layers[cdpa.SubsystemNumber]; ok {
+			return l
+		}
+	}
+	return d.fallback
+}
+
+// Dispatch hands the Data of m, which should be a UDT, XUDT or LUDT, to the
+// UpperLayer, and returns the messages to send back to the originator.
+//
+// If no UpperLayer is found for the SSN, m is returned in UDTS, XUDTS or
+// LUDTS with "unequipped user" if the return option is set, or discarded
+// otherwise. The UpperLayer is looked up before the reassembly, so that only
+// the first segment is returned and the others are discarded without being
+// held by the Reassembler (see Q.714 4.2). It returns nil without error while
+// the segments are being reassembled.
+func (d *Dispatcher) Dispatch(ctx context.Context, m Message) ([]Message, error) {
+	var (
+		pcls *params.ProtocolClass
+		cdpa *params.PartyAddress
+		cgpa *params.PartyAddress
+		seg  *params.Segmentation
+	)
+	switch m := m.(type) {
+	case *UDT:
+		pcls, cdpa, cgpa = m.ProtocolClass, m.CalledPartyAddress, m.CallingPartyAddress
+	case *XUDT:
+		pcls, cdpa, cgpa, seg = m.ProtocolClass, m.CalledPartyAddress, m.CallingPartyAddress, m.Segmentation
+	case *LUDT:
+		pcls, cdpa, cgpa, seg = m.ProtocolClass, m.CalledPartyAddress, m.CallingPartyAddress, m.Segmentation
+	default:
+		return nil, UnsupportedTypeError(m.MessageType())
+	}
+
+	l := d.upperLayer(cdpa)
+	if l == nil {
+		if seg != nil && !seg.FirstSegment {
+			return nil, nil
+		}
+
+		udts, err := NewServiceMessage(m, params.ReturnCauseUnequippedUser)
+		if errors.Is(err, ErrNoReturnOption) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []Message{udts}, nil
+	}
+
+	data, ok, err := d.reassemble(m)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	resp, err := l.HandleUnitdata(ctx, &Unitdata{
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		SequenceControl:     pcls.Class() == 1,
+		ReturnOption:        pcls.ReturnOnError(),
+		Importance:          MessageImportance(m),
+		Data:                data,
+		Message:             m,
+	})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+
+	opts := d.cfg.Unitdata
+	opts.ProtocolClass = pcls.Class()
+	opts.ReturnOnError = pcls.ReturnOnError()
+	rcdpa, rcgpa := swapAddresses(cdpa, cgpa)
+	return d.build(rcdpa, rcgpa, resp, opts)
+}
+
+// reassemble returns the Data of m, which is reassembled with the Reassembler
+// if it is set. It returns false without error while more segments are
+// expected.
+func (d *Dispatcher) reassemble(m Message) ([]byte, bool, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch m := m.(type) {
+	case *XUDT:
+		if d.cfg.Reassembler == nil {
+			return m.LoadData().Value(), true, nil
+		}
+		data, err = d.cfg.Reassembler.Add(m)
+	case *LUDT:
+		if d.cfg.Reassembler == nil {
+			return paramValue(m.Data), true, nil
+		}
+		data, err = d.cfg.Reassembler.AddLUDT(m)
+	default:
+		return m.(*UDT).LoadData().Value(), true, nil
+	}
+
+	return data, err == nil && data != nil, err
+}
@@ -0,0 +1,153 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DT1 represents a SCCP Message Data Form 1 (DT1).
+type DT1 struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SegmentingReassembling    *params.SegmentingReassembling
+	Data                      *params.Data
+
+	trailing []byte
+}
+
+// NewDT1 creates a new DT1. more is the M-bit in the Segmenting/Reassembling,
+// which is set when more data follows in the subsequent DT1.
+func NewDT1(dlr uint32, more bool, data []byte) *DT1 {
+	return &DT1{
+		Type:                      MsgTypeDT1,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SegmentingReassembling:    params.NewSegmentingReassembling(more),
+		Data:                      params.NewData(data),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a DT1 instance.
+func (d *DT1) MarshalBinary() ([]byte, error) {
+	b := make([]byte, d.MarshalLen())
+	if err := d.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (d *DT1) MarshalTo(b []byte) error {
+	return marshalSections(b, d.Type, d.fixed(), d.variable(), nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the DT1 to dst, and
+// returns the extended slice.
+func (d *DT1) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, d)
+}
+
+// WriteTo writes the byte sequence generated from the DT1 to w. It implements
+// io.WriterTo.
+func (d *DT1) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, d)
+}
+
+// fixed returns the mandatory fixed parameters of the DT1.
+func (d *DT1) fixed() []params.Parameter {
+	return []params.Parameter{d.DestinationLocalReference, d.SegmentingReassembling}
+}
+
+// variable returns the mandatory variable parameters of the DT1.
+func (d *DT1) variable() []params.Parameter {
+	return []params.Parameter{d.Data}
+}
+
+// ParseDT1 decodes given byte sequence as a SCCP DT1.
+func ParseDT1(b []byte) (*DT1, error) {
+	d := &DT1{}
+	if err := d.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP DT1.
+func (d *DT1) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	d.Type = MsgType(b[0])
+	d.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	d.SegmentingReassembling = &params.SegmentingReassembling{}
+	d.Data = &params.Data{}
+
+	_, n, err := params.UnmarshalSections(b[1:], d.fixed(), d.variable(), false)
+	if err != nil {
+		return err
+	}
+
+	d.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the DT1 that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (d *DT1) Clone() *DT1 {
+	c := *d
+	c.DestinationLocalReference = d.DestinationLocalReference.Clone()
+	c.SegmentingReassembling = clonePtr(d.SegmentingReassembling)
+	c.Data = d.Data.Clone()
+	c.trailing = bytes.Clone(d.trailing)
+
+	return &c
+}
+
+// Reset clears the DT1 to be reused with UnmarshalBinary.
+func (d *DT1) Reset() {
+	*d = DT1{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the DT1 computed
+// from the pointer and length of the Data when it is parsed, or nil if there
+// is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (d *DT1) TrailingBytes() []byte {
+	return d.trailing
+}
+
+// MarshalLen returns the serial length.
+func (d *DT1) MarshalLen() int {
+	return 1 + params.SectionsLen(d.fixed(), d.variable(), nil, false)
+}
+
+// String returns the DT1 values in human readable format.
+func (d *DT1) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SegmentingReassembling: %s, Data: %s}",
+		d.Type,
+		d.DestinationLocalReference,
+		d.SegmentingReassembling,
+		d.Data,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (d *DT1) MessageType() MsgType {
+	return MsgTypeDT1
+}
+
+// MessageTypeName returns the Message Type in string.
+func (d *DT1) MessageTypeName() string {
+	return d.MessageType().String()
+}
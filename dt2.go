@@ -0,0 +1,153 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DT2 represents a SCCP Message Data Form 2 (DT2).
+type DT2 struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SequencingSegmenting      *params.SequencingSegmenting
+	Data                      *params.Data
+
+	trailing []byte
+}
+
+// NewDT2 creates a new DT2. ps and pr are the 7-bit P(S) and P(R), and more
+// is the M-bit set when more data follows in the subsequent DT2.
+func NewDT2(dlr uint32, ps, pr uint8, more bool, data []byte) *DT2 {
+	return &DT2{
+		Type:                      MsgTypeDT2,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SequencingSegmenting:      params.NewSequencingSegmentingPSPR(ps, pr, more),
+		Data:                      params.NewData(data),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a DT2 instance.
+func (d *DT2) MarshalBinary() ([]byte, error) {
+	b := make([]byte, d.MarshalLen())
+	if err := d.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (d *DT2) MarshalTo(b []byte) error {
+	return marshalSections(b, d.Type, d.fixed(), d.variable(), nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the DT2 to dst, and
+// returns the extended slice.
+func (d *DT2) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, d)
+}
+
+// WriteTo writes the byte sequence generated from the DT2 to w. It implements
+// io.WriterTo.
+func (d *DT2) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, d)
+}
+
+// fixed returns the mandatory fixed parameters of the DT2.
+func (d *DT2) fixed() []params.Parameter {
+	return []params.Parameter{d.DestinationLocalReference, d.SequencingSegmenting}
+}
+
+// variable returns the mandatory variable parameters of the DT2.
+func (d *DT2) variable() []params.Parameter {
+	return []params.Parameter{d.Data}
+}
+
+// ParseDT2 decodes given byte sequence as a SCCP DT2.
+func ParseDT2(b []byte) (*DT2, error) {
+	d := &DT2{}
+	if err := d.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP DT2.
+func (d *DT2) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	d.Type = MsgType(b[0])
+	d.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	d.SequencingSegmenting = &params.SequencingSegmenting{}
+	d.Data = &params.Data{}
+
+	_, n, err := params.UnmarshalSections(b[1:], d.fixed(), d.variable(), false)
+	if err != nil {
+		return err
+	}
+
+	d.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the DT2 that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (d *DT2) Clone() *DT2 {
+	c := *d
+	c.DestinationLocalReference = d.DestinationLocalReference.Clone()
+	c.SequencingSegmenting = clonePtr(d.SequencingSegmenting)
+	c.Data = d.Data.Clone()
+	c.trailing = bytes.Clone(d.trailing)
+
+	return &c
+}
+
+// Reset clears the DT2 to be reused with UnmarshalBinary.
+func (d *DT2) Reset() {
+	*d = DT2{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the DT2 computed
+// from the pointer and length of the Data when it is parsed, or nil if there
+// is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (d *DT2) TrailingBytes() []byte {
+	return d.trailing
+}
+
+// MarshalLen returns the serial length.
+func (d *DT2) MarshalLen() int {
+	return 1 + params.SectionsLen(d.fixed(), d.variable(), nil, false)
+}
+
+// String returns the DT2 values in human readable format.
+func (d *DT2) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SequencingSegmenting: %s, Data: %s}",
+		d.Type,
+		d.DestinationLocalReference,
+		d.SequencingSegmenting,
+		d.Data,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (d *DT2) MessageType() MsgType {
+	return MsgTypeDT2
+}
+
+// MessageTypeName returns the Message Type in string.
+func (d *DT2) MessageTypeName() string {
+	return d.MessageType().String()
+}
@@ -0,0 +1,227 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/ssn"
+)
+
+// Dump returns the field-by-field breakdown of m in multiple lines, with the
+// bits of the indicators decoded, in the similar way as the SCCP dissector of
+// Wireshark. It is meant for troubleshooting, and the format may change.
+//
+// The parameters are listed in the order of the fields of the message, and
+// the ones that are not set are omitted.
+func Dump(m Message) string {
+	d := &dumper{}
+	d.line(0, "Signalling Connection Control Part")
+	d.line(1, "Message Type: %s (0x%02x)", m.MessageType(), uint8(m.MessageType()))
+
+	v := reflect.ValueOf(m)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return d.String()
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		switch fv := v.Field(i).Interface().(type) {
+		case params.Parameter:
+			if !v.Field(i).IsNil() {
+				d.parameter(1, fieldName(f.Name), fv)
+			}
+		case []*params.UnknownParameter:
+			for _, p := range fv {
+				d.parameter(1, "Unknown Parameter", p)
+			}
+		case []byte:
+			if fv != nil {
+				d.bytes(1, fieldName(f.Name), fv)
+			}
+		}
+	}
+
+	return d.String()
+}
+
+type dumper struct {
+	strings.Builder
+}
+
+func (d *dumper) line(depth int, format string, a ...any) {
+	for i := 0; i < depth; i++ {
+		d.WriteString("    ")
+	}
+	fmt.Fprintf(d, format, a...)
+	d.WriteByte('\n')
+}
+
+// bits writes v in the form of ".01. ...." where only the bits in mask are
+// shown, followed by the description.
+func (d *dumper) bits(depth int, v, mask uint8, format string, a ...any) {
+	var b [9]byte
+	n := 0
+	for i := 7; i >= 0; i-- {
+		if i == 3 {
+			b[n] = ' '
+			n++
+		}
+		switch {
+		case mask&(1<<i) == 0:
+			b[n] = '.'
+		case v&(1<<i) != 0:
+			b[n] = '1'
+		default:
+			b[n] = '0'
+		}
+		n++
+	}
+	d.line(depth, "%s = %s", b[:], fmt.Sprintf(format, a...))
+}
+
+func (d *dumper) bytes(depth int, name string, b []byte) {
+	d.line(depth, "%s (%d bytes)", name, len(b))
+	for i := 0; i < len(b); i += 16 {
+		d.line(depth+1, "%04x  % x", i, b[i:min(i+16, len(b))])
+	}
+}
+
+func (d *dumper) parameter(depth int, name string, p params.Parameter) {
+	switch p := p.(type) {
+	case *params.ProtocolClass:
+		d.line(depth, "%s: 0x%02x", name, p.Value())
+		d.bits(depth+1, p.Value(), 0x0f, "Class: %d", p.Class())
+		handling := "no special options"
+		if p.ReturnOnError() {
+			handling = "return message on error"
+		}
+		d.bits(depth+1, p.Value(), 0xf0, "Message handling: %s (0x%x)", handling, p.MessageHandling())
+	case *params.PartyAddress:
+		d.address(depth, name, p)
+	case *params.Data:
+		d.bytes(depth, name, p.Value())
+	case *params.Segmentation:
+		d.line(depth, "%s", name)
+		d.line(depth+1, "First Segment: %t", p.FirstSegment)
+		d.line(depth+1, "Class: %d", p.Class)
+		d.line(depth+1, "Remaining Segments: %d", p.RemainingSegments)
+		d.line(depth+1, "Segmentation Local Reference: 0x%06x", p.LocalReference)
+	case *params.UnknownParameter:
+		d.bytes(depth, fmt.Sprintf("%s (0x%02x)", name, uint8(p.Code())), p.Value())
+	case *params.EndOfOptionalParameters:
+		// always present with the optional parameters.
+	default:
+		d.line(depth, "%s: %s", name, parameterValue(p))
+	}
+}
+
+// parameterValue returns the result of the Value method of p in string, or
+// p in string if it has no Value method that returns a scalar.
+func parameterValue(p params.Parameter) string {
+	m := reflect.ValueOf(p).MethodByName("Value")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return p.String()
+	}
+
+	v := m.Call(nil)[0]
+	switch v.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return fmt.Sprintf("%s (%d)", s, v.Uint())
+		}
+		return fmt.Sprint(v.Uint())
+	case reflect.Slice:
+		if b, ok := v.Interface().([]byte); ok {
+			return fmt.Sprintf("% x", b)
+		}
+	}
+	return p.String()
+}
+
+func (d *dumper) address(depth int, name string, p *params.PartyAddress) {
+	v := p.Variant()
+	ai := p.Indicator
+
+	d.line(depth, "%s", name)
+	d.line(depth+1, "Address Indicator: 0x%02x", ai)
+	national, pcMask, ssnMask := "Reserved for national use", uint8(0b01), uint8(0b10)
+	if v == params.VariantANSI {
+		national, pcMask, ssnMask = "National indicator", 0b10, 0b01
+	}
+	d.bits(depth+2, ai, 0x80, "%s: %d", national, ai>>7)
+	ri := "route on GT"
+	if p.RouteOnSSN() {
+		ri = "route on SSN"
+	}
+	d.bits(depth+2, ai, 0x40, "Routing Indicator: %s (%d)", ri, p.RoutingIndicator())
+	d.bits(depth+2, ai, 0x3c, "Global Title Indicator: %s (0x%x)", p.GTI(), uint8(p.GTI()))
+	d.bits(depth+2, ai, ssnMask, "SSN Indicator: %t", p.HasSSN())
+	d.bits(depth+2, ai, pcMask, "Point Code Indicator: %t", p.HasPC())
+
+	if p.HasPC() {
+		d.line(depth+1, "Signalling Point Code: %s (%d)", v.FormatPointCode(p.SignalingPointCode), p.SignalingPointCode)
+	}
+	if p.HasSSN() {
+		d.line(depth+1, "Subsystem Number: %s", ssn.Format(p.SubsystemNumber))
+	}
+	if p.GlobalTitle != nil {
+		d.globalTitle(depth+1, p.GlobalTitle)
+	}
+	for _, err := range p.Diagnostics {
+		d.line(depth+1, "[Diagnostic: %v]", err)
+	}
+}
+
+func (d *dumper) globalTitle(depth int, gt params.GlobalTitle) {
+	d.line(depth, "Global Title 0x%x (%d bytes)", uint8(gt.GTI()), gt.MarshalLen())
+	switch gt := gt.(type) {
+	case *params.GTNAIOnly:
+		odd := uint8(0)
+		if gt.OddDigits {
+			odd = 0x80
+		}
+		d.bits(depth+1, odd, 0x80, "Odd/even indicator: %t", gt.OddDigits)
+		d.bits(depth+1, uint8(gt.NatureOfAddressIndicator), 0x7f, "Nature of Address Indicator: %s", gt.NatureOfAddressIndicator)
+	case *params.GTTTOnly:
+		d.line(depth+1, "Translation Type: %s", gt.TranslationType)
+	case *params.GTTTNPES:
+		d.line(depth+1, "Translation Type: %s", gt.TranslationType)
+		d.bits(depth+1, uint8(gt.NumberingPlan)<<4, 0xf0, "Numbering Plan: %s", gt.NumberingPlan)
+		d.bits(depth+1, uint8(gt.EncodingScheme), 0x0f, "Encoding Scheme: %s", gt.EncodingScheme)
+	case *params.GTTTNPESNAI:
+		d.line(depth+1, "Translation Type: %s", gt.TranslationType)
+		d.bits(depth+1, uint8(gt.NumberingPlan)<<4, 0xf0, "Numbering Plan: %s", gt.NumberingPlan)
+		d.bits(depth+1, uint8(gt.EncodingScheme), 0x0f, "Encoding Scheme: %s", gt.EncodingScheme)
+		d.bits(depth+1, uint8(gt.NatureOfAddressIndicator), 0x7f, "Nature of Address Indicator: %s", gt.NatureOfAddressIndicator)
+	}
+	d.line(depth+1, "Digits: %s", gt.Address())
+}
+
+// fieldName splits the name of a field in camel case into words, e.g.,
+// "Called Party Address" for "CalledPartyAddress".
+func fieldName(s string) string {
+	var b strings.Builder
+	rs := []rune(s)
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(rs[i-1]) || i+1 < len(rs) && unicode.IsLower(rs[i+1])) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,136 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// EA represents a SCCP Message Expedited Data Acknowledgement (EA).
+type EA struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+
+	trailing []byte
+}
+
+// NewEA creates a new EA.
+func NewEA(dlr uint32) *EA {
+	return &EA{
+		Type:                      MsgTypeEA,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an EA instance.
+func (e *EA) MarshalBinary() ([]byte, error) {
+	b := make([]byte, e.MarshalLen())
+	if err := e.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (e *EA) MarshalTo(b []byte) error {
+	return marshalSections(b, e.Type, e.fixed(), nil, nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the EA to dst, and
+// returns the extended slice.
+func (e *EA) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, e)
+}
+
+// WriteTo writes the byte sequence generated from the EA to w. It implements
+// io.WriterTo.
+func (e *EA) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, e)
+}
+
+// fixed returns the mandatory fixed parameters of the EA.
+func (e *EA) fixed() []params.Parameter {
+	return []params.Parameter{e.DestinationLocalReference}
+}
+
+// ParseEA decodes given byte sequence as a SCCP EA.
+func ParseEA(b []byte) (*EA, error) {
+	e := &EA{}
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP EA.
+func (e *EA) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	e.Type = MsgType(b[0])
+	e.DestinationLocalReference = params.NewDestinationLocalReference(0)
+
+	_, n, err := params.UnmarshalSections(b[1:], e.fixed(), nil, false)
+	if err != nil {
+		return err
+	}
+
+	e.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the EA that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (e *EA) Clone() *EA {
+	c := *e
+	c.DestinationLocalReference = e.DestinationLocalReference.Clone()
+	c.trailing = bytes.Clone(e.trailing)
+
+	return &c
+}
+
+// Reset clears the EA to be reused with UnmarshalBinary.
+func (e *EA) Reset() {
+	*e = EA{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the EA when
+// it is parsed, or nil if there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (e *EA) TrailingBytes() []byte {
+	return e.trailing
+}
+
+// MarshalLen returns the serial length.
+func (e *EA) MarshalLen() int {
+	return 1 + params.SectionsLen(e.fixed(), nil, nil, false)
+}
+
+// String returns the EA values in human readable format.
+func (e *EA) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s}",
+		e.Type,
+		e.DestinationLocalReference,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (e *EA) MessageType() MsgType {
+	return MsgTypeEA
+}
+
+// MessageTypeName returns the Message Type in string.
+func (e *EA) MessageTypeName() string {
+	return e.MessageType().String()
+}
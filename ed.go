@@ -0,0 +1,147 @@
+// Copyright 2019-2024 go-sccp authors. All rights reservee.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ED represents a SCCP Message Expedited Data (ED).
+type ED struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	Data                      *params.Data
+
+	trailing []byte
+}
+
+// NewED creates a new ED. data should be 1 to 32 octets long.
+func NewED(dlr uint32, data []byte) *ED {
+	return &ED{
+		Type:                      MsgTypeED,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		Data:                      params.NewData(data),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an ED instance.
+func (e *ED) MarshalBinary() ([]byte, error) {
+	b := make([]byte, e.MarshalLen())
+	if err := e.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (e *ED) MarshalTo(b []byte) error {
+	return marshalSections(b, e.Type, e.fixed(), e.variable(), nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the ED to dst, and
+// returns the extended slice.
+func (e *ED) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, e)
+}
+
+// WriteTo writes the byte sequence generated from the ED to w. It implements
+// io.WriterTo.
+func (e *ED) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, e)
+}
+
+// fixed returns the mandatory fixed parameters of the ED.
+func (e *ED) fixed() []params.Parameter {
+	return []params.Parameter{e.DestinationLocalReference}
+}
+
+// variable returns the mandatory variable parameters of the ED.
+func (e *ED) variable() []params.Parameter {
+	return []params.Parameter{e.Data}
+}
+
+// ParseED decodes given byte sequence as a SCCP ED.
+func ParseED(b []byte) (*ED, error) {
+	e := &ED{}
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP ED.
+func (e *ED) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	e.Type = MsgType(b[0])
+	e.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	e.Data = &params.Data{}
+
+	_, n, err := params.UnmarshalSections(b[1:], e.fixed(), e.variable(), false)
+	if err != nil {
+		return err
+	}
+
+	e.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the ED that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (e *ED) Clone() *ED {
+	c := *e
+	c.DestinationLocalReference = e.DestinationLocalReference.Clone()
+	c.Data = e.Data.Clone()
+	c.trailing = bytes.Clone(e.trailing)
+
+	return &c
+}
+
+// Reset clears the ED to be reused with UnmarshalBinary.
+func (e *ED) Reset() {
+	*e = ED{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the ED computed
+// from the pointer and length of the Data when it is parsed, or nil if there
+// is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (e *ED) TrailingBytes() []byte {
+	return e.trailing
+}
+
+// MarshalLen returns the serial length.
+func (e *ED) MarshalLen() int {
+	return 1 + params.SectionsLen(e.fixed(), e.variable(), nil, false)
+}
+
+// String returns the ED values in human readable format.
+func (e *ED) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, Data: %s}",
+		e.Type,
+		e.DestinationLocalReference,
+		e.Data,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (e *ED) MessageType() MsgType {
+	return MsgTypeED
+}
+
+// MessageTypeName returns the Message Type in string.
+func (e *ED) MessageTypeName() string {
+	return e.MessageType().String()
+}
@@ -0,0 +1,514 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// MessageHandler handles a message, which is received or to be sent by an
+// Endpoint.
+type MessageHandler func(ctx context.Context, m Message) error
+
+// Middleware wraps a MessageHandler to add the cross-cutting processing such
+// as logging, metrics, screening and rewriting the messages. It can call next
+// with m or the message rewritten, or drop the message by returning without
+// calling next.
+type Middleware func(next MessageHandler) MessageHandler
+
+// chainMiddlewares returns h wrapped by mws, where mws[0] is the outermost.
+func chainMiddlewares(mws []Middleware, h MessageHandler) MessageHandler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// ErrEndpointRunning is returned when Start is called on the running Endpoint.
+var ErrEndpointRunning = errors.New("sccp: endpoint is already running")
+
+// Transport carries the SCCP messages to and from the peer, e.g., over M3UA
+// or MTP3, which is responsible for the routing below SCCP.
+//
+// The error returned by ReadMessage stops Endpoint.Serve, so the Transport
+// should skip the messages it fails to decode rather than returning the error.
+// If the Transport implements io.Closer, it is closed by Endpoint.Stop to
+// unblock ReadMessage.
+type Transport interface {
+	ReadMessage() (Message, error)
+	WriteMessage(m Message) error
+}
+
+// Notice is the N-NOTICE indication primitive defined in Q.711, which is
+// handed to EndpointConfig.Notice with the UDTS, XUDTS or LUDTS received in
+// return to the message sent.
+type Notice struct {
+	// CalledPartyAddress is the one in the service message, i.e., the Calling
+	// Party Address of the message returned.
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	ReturnCause         params.ReturnCauseValue
+	Importance          uint8
+	// Data is the user data of the message returned.
+	Data []byte
+
+	// Message is the UDTS, XUDTS or LUDTS received.
+	Message Message
+}
+
+// EndpointConfig is the configuration of an Endpoint. The zero values are
+// valid.
+type EndpointConfig struct {
+	// Reassembler is used to reassemble the segmented XUDTs and LUDTs
+	// received. The segments are handed to the UpperLayer as they are if nil.
+	Reassembler *Reassembler
+	// SegmentationRefs generates the Segmentation Local References of the
+	// XUDTs segmented by SendXUDT and of the ones carrying the data returned
+	// by the UpperLayer. LocalReference in Unitdata is used if nil.
+	SegmentationRefs *SegmentationRefGenerator
+	// Unitdata is the options of the messages sent by SendUDT and SendXUDT,
+	// and the ones sent back with the data returned by the UpperLayer.
+	Unitdata UnitdataOptions
+	// Notice is called with the UDTS, XUDTS and LUDTS received. They are
+	// discarded if nil.
+	Notice func(ctx context.Context, n *Notice)
+
+	// Congestion tracks the congestion of the destinations. If set, the
+	// messages to the congested signalling points are discarded or deferred
+	// by their importance (see ShedStats), the SSCs received are given to
+	// it, and the backpressure from the Transport is handled as the MTP
+	// congestion of the destination.
+	Congestion *CongestionTracker
+	// DestinationPC returns the PC of the signalling point m is sent to,
+	// which is used with Congestion. The PC in the Called Party Address is
+	// used if nil, and the messages without it are never shed.
+	DestinationPC func(m Message) (params.PointCode, bool)
+	// MaxDeferred is the maximum number of the messages deferred for each
+	// congested signalling point, which is DefaultMaxDeferred if 0.
+	MaxDeferred int
+	// SLS selects the SLS of the messages if the Transport implements
+	// SLSTransport, which is HashSLS(SLSMaskITU) if nil.
+	SLS SLSSelector
+	// Workers is the number of the goroutines that handle the messages
+	// received concurrently, which are handled one by one if 0. The messages
+	// in the protocol class 1 received with the same SLS are handled in
+	// sequence on the same worker (see Sequencer), and the Messages read
+	// from the Transport must not share the buffer with each other.
+	Workers int
+}
+
+// Endpoint provides the SCCP connectionless service (N-UNITDATA) over a
+// Transport, following Q.714 section 4.
+//
+// The messages received by Serve are handed to the UpperLayer registered for
+// the SSN in the Called Party Address, and the data returned by it is sent
+// back to the originator. The UDT, XUDT and LUDT that cannot be delivered are
+// returned in UDTS, XUDTS or LUDTS if the return option is set, with:
+//
+//   - "unequipped user" if no UpperLayer is registered for the SSN,
+//   - "hop counter violation" if the Hop Counter in the XUDT or LUDT is
+//     exhausted,
+//   - "segmentation failure" if the segments fail to be reassembled, which
+//     returns the first segment, including on T(reass) expiry and eviction,
+//   - "error in local processing" if the UpperLayer returns an error.
+//
+// The Middlewares added by UseInbound and UseOutbound wrap the handling of
+// every message received and sent, respectively.
+//
+// Endpoint implements Component, where Start runs Serve in the background.
+// It is safe for concurrent use as long as the Transport is.
+type Endpoint struct {
+	transport Transport
+	cfg       EndpointConfig
+	disp      *Dispatcher
+	shed      *shedder
+
+	mwMu     sync.RWMutex
+	inbound  []Middleware
+	outbound []Middleware
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewEndpoint creates a new Endpoint on the Transport with cfg, which can be
+// nil to use the default values.
+func NewEndpoint(t Transport, cfg *EndpointConfig) *Endpoint {
+	e := &Endpoint{transport: t}
+	if cfg != nil {
+		e.cfg = *cfg
+	}
+	e.disp = NewDispatcher(&DispatcherConfig{Reassembler: e.cfg.Reassembler, Unitdata: e.cfg.Unitdata})
+	e.disp.build = e.buildUnitdata
+	if r := e.cfg.Reassembler; r != nil {
+		r.addDropped(func(rerr *ReassemblyError) {
+			if err := e.returnFirstSegment(context.Background(), rerr); err != nil {
+				logf("failed to return the first segment %d: %v", rerr.LocalReference, err)
+			}
+		})
+	}
+	e.shed = newShedder(&e.cfg)
+	if e.cfg.SLS == nil {
+		e.cfg.SLS = HashSLS(SLSMaskITU)
+	}
+
+	return e
+}
+
+// Register registers the UpperLayer for the SSN. Registering the same SSN
+// again overwrites the UpperLayer.
+func (e *Endpoint) Register(ssn uint8, l UpperLayer) {
+	e.disp.Register(ssn, l)
+}
+
+// Unregister removes the UpperLayer for the SSN. The messages for it are
+// returned with "unequipped user" after that.
+func (e *Endpoint) Unregister(ssn uint8) {
+	e.disp.Unregister(ssn)
+}
+
+// SetDefault sets the UpperLayer that handles the messages for the SSNs that
+// have no UpperLayer registered, including the ones without SSN.
+func (e *Endpoint) SetDefault(l UpperLayer) {
+	e.disp.SetDefault(l)
+}
+
+// UseInbound adds the Middlewares that wrap the handling of the messages
+// received. The ones added first are called first.
+func (e *Endpoint) UseInbound(mw ...Middleware) {
+	e.mwMu.Lock()
+	defer e.mwMu.Unlock()
+
+	e.inbound = append(e.inbound, mw...)
+}
+
+// UseOutbound adds the Middlewares that wrap the sending of the messages,
+// including the replies and the ones returned. The ones added first are
+// called first.
+func (e *Endpoint) UseOutbound(mw ...Middleware) {
+	e.mwMu.Lock()
+	defer e.mwMu.Unlock()
+
+	e.outbound = append(e.outbound, mw...)
+}
+
+// SendUDT sends data from cgpa to cdpa in a UDT, with the Protocol Class and
+// the return option in EndpointConfig.Unitdata.
+func (e *Endpoint) SendUDT(cdpa, cgpa *params.PartyAddress, data []byte) error {
+	opts := e.cfg.Unitdata
+	return e.send(context.Background(), []Message{NewUDT(opts.ProtocolClass, opts.ReturnOnError, cdpa, cgpa, data)})
+}
+
+// SendXUDT sends data from cgpa to cdpa in an XUDT with the optional
+// parameters opts added to the ones in EndpointConfig.Unitdata, which is
+// segmented into multiple XUDTs if it does not fit in one.
+//
+// The Hop Counter is set to EndpointConfig.Unitdata.HopCounter, or
+// DefaultHopCounter if it is 0.
+func (e *Endpoint) SendXUDT(cdpa, cgpa *params.PartyAddress, data []byte, opts ...params.Parameter) error {
+	uo := e.cfg.Unitdata
+	uo.Optionals = append(uo.Optionals[:len(uo.Optionals):len(uo.Optionals)], opts...)
+	uo.ForceXUDT = true
+
+	msgs, err := e.buildUnitdata(cdpa, cgpa, data, uo)
+	if err != nil {
+		return err
+	}
+	return e.send(context.Background(), msgs)
+}
+
+// Serve reads the messages from the Transport and handles them with Handle
+// until ctx is done or the Transport fails to read.
+//
+// The messages are handled one by one, or by EndpointConfig.Workers
+// concurrently with a Sequencer. The errors in handling the messages are
+// logged and do not stop Serve. It returns nil if ctx is done or ReadMessage
+// returns io.EOF, except that it returns ctx.Err() if ctx is done while the
+// message read is waiting for a worker, as the message is not handled.
+func (e *Endpoint) Serve(ctx context.Context) error {
+	var seq *Sequencer
+	if e.cfg.Workers > 0 {
+		seq = NewSequencer(&SequencerConfig{Workers: e.cfg.Workers})
+		if err := seq.Start(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if err := seq.Stop(context.Background()); err != nil {
+				logf("failed to stop sequencer: %v", err)
+			}
+		}()
+	}
+
+	var next uint32
+	for {
+		m, sls, err := e.read()
+		if ctx.Err() != nil || errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if seq == nil {
+			e.serve(ctx, m)
+			continue
+		}
+
+		// the messages that do not require the in-sequence delivery are
+		// spread over the workers.
+		key := uint32(sls)
+		if !inSequence(m) {
+			key, next = next, next+1
+		}
+		if err := seq.Submit(ctx, key, func() { e.serve(ctx, m) }); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+}
+
+// read reads a message from the Transport with the SLS it is received with,
+// or the one selected by HashSLS if the Transport is not an SLSReader.
+func (e *Endpoint) read() (Message, uint8, error) {
+	if r, ok := e.transport.(SLSReader); ok {
+		return r.ReadMessageSLS()
+	}
+
+	m, err := e.transport.ReadMessage()
+	if err != nil {
+		return nil, 0, err
+	}
+	return m, hashSLS(m), nil
+}
+
+func (e *Endpoint) serve(ctx context.Context, m Message) {
+	if err := e.Handle(ctx, m); err != nil {
+		logf("failed to handle %s: %v", m.MessageTypeName(), err)
+	}
+}
+
+// Handle handles the message received, which is used by Serve and can be
+// called directly by the caller that reads the messages by itself.
+//
+// m is passed through the inbound Middlewares first. Then the UDT, XUDT and
+// LUDT are handed to the UpperLayer, and the UDTS, XUDTS and LUDTS to
+// EndpointConfig.Notice. The other types are not supported by Endpoint.
+func (e *Endpoint) Handle(ctx context.Context, m Message) error {
+	e.mwMu.RLock()
+	h := chainMiddlewares(e.inbound, e.handle)
+	e.mwMu.RUnlock()
+
+	return h(ctx, m)
+}
+
+func (e *Endpoint) handle(ctx context.Context, m Message) error {
+	switch m := m.(type) {
+	case *UDT:
+		if e.shedInbound(m) {
+			return nil
+		}
+		e.handleSCMG(m)
+	case *XUDT:
+		if e.shedInbound(m) {
+			return nil
+		}
+		e.handleSCMG(m)
+		// the Hop Counter is decremented on each relay, and the message that
+		// reaches 0 should not have been relayed (see Q.714 2.3.4).
+		if m.HopCounter != nil && m.HopCounter.Value() == 0 {
+			return e.sendReturn(ctx, m, params.ReturnCauseHopCounterViolation)
+		}
+	case *LUDT:
+		if e.shedInbound(m) {
+			return nil
+		}
+		if m.HopCounter != nil && m.HopCounter.Value() == 0 {
+			return e.sendReturn(ctx, m, params.ReturnCauseHopCounterViolation)
+		}
+	case *UDTS:
+		e.notice(ctx, m, m.CalledPartyAddress, m.CallingPartyAddress, m.ReturnCause, paramValue(m.Data))
+		return nil
+	case *XUDTS:
+		e.notice(ctx, m, m.CalledPartyAddress, m.CallingPartyAddress, m.ReturnCause, paramValue(m.Data))
+		return nil
+	case *LUDTS:
+		e.notice(ctx, m, m.CalledPartyAddress, m.CallingPartyAddress, m.ReturnCause, paramValue(m.Data))
+		return nil
+	default:
+		return UnsupportedTypeError(m.MessageType())
+	}
+
+	msgs, err := e.disp.Dispatch(ctx, m)
+	if err != nil {
+		var rerr *ReassemblyError
+		if errors.As(err, &rerr) {
+			return errors.Join(err, e.returnFirstSegment(ctx, rerr))
+		}
+		return errors.Join(err, e.sendReturn(ctx, m, params.ReturnCauseErrorInLocalProcessing))
+	}
+
+	return e.send(ctx, msgs)
+}
+
+// Start runs Serve in the background. The values in ctx are inherited by the
+// contexts given to the UpperLayers, while its cancellation is not.
+func (e *Endpoint) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.done != nil {
+		return ErrEndpointRunning
+	}
+
+	ctx, e.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	e.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		if err := e.Serve(ctx); err != nil {
+			logf("endpoint stopped: %v", err)
+		}
+	}(e.done)
+
+	return nil
+}
+
+// Stop stops Serve started by Start, closing the Transport if it implements
+// io.Closer, and waits for the message being handled, or returns the error of
+// ctx if it is done before that.
+func (e *Endpoint) Stop(ctx context.Context) error {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel, e.done = nil, nil
+	e.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+
+	cancel()
+	var err error
+	if c, ok := e.transport.(io.Closer); ok {
+		err = c.Close()
+	}
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// write writes m to the Transport, which is the innermost outbound handler.
+func (e *Endpoint) write(_ context.Context, m Message) error {
+	return e.writeShedding(m)
+}
+
+// writeTransport writes m to the Transport, with the SLS if it is an
+// SLSTransport.
+func (e *Endpoint) writeTransport(m Message) error {
+	if t, ok := e.transport.(SLSTransport); ok {
+		return t.WriteMessageSLS(m, e.cfg.SLS.SelectSLS(m))
+	}
+	return e.transport.WriteMessage(m)
+}
+
+// buildUnitdata creates the messages with BuildUnitdata, setting the
+// Segmentation Local Reference generated by EndpointConfig.SegmentationRefs
+// if the data is segmented.
+func (e *Endpoint) buildUnitdata(cdpa, cgpa *params.PartyAddress, data []byte, opts UnitdataOptions) ([]Message, error) {
+	msgs, err := BuildUnitdata(cdpa, cgpa, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 1 || e.cfg.SegmentationRefs == nil {
+		return msgs, nil
+	}
+
+	ref, err := e.cfg.SegmentationRefs.Next(cgpa)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		m.(*XUDT).Segmentation.LocalReference = ref
+	}
+	return msgs, nil
+}
+
+// send sends msgs through the outbound Middlewares.
+func (e *Endpoint) send(ctx context.Context, msgs []Message) error {
+	e.mwMu.RLock()
+	h := chainMiddlewares(e.outbound, e.write)
+	e.mwMu.RUnlock()
+
+	for _, m := range msgs {
+		if err := h(ctx, m); err != nil {
+			return fmt.Errorf("failed to send %s: %w", m.MessageTypeName(), err)
+		}
+	}
+
+	return nil
+}
+
+// sendReturn returns m to the originator with cause, or discards it if the
+// return option is not set.
+func (e *Endpoint) sendReturn(ctx context.Context, m Message, cause params.ReturnCauseValue) error {
+	ret, err := NewServiceMessage(m, cause)
+	if errors.Is(err, ErrNoReturnOption) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, []Message{ret})
+}
+
+// returnFirstSegment returns the first segment retained in rerr with
+// "segmentation failure", or does nothing if it is not retained.
+func (e *Endpoint) returnFirstSegment(ctx context.Context, rerr *ReassemblyError) error {
+	if rerr.FirstSegment == nil {
+		return nil
+	}
+	return e.sendReturn(ctx, rerr.FirstSegment, params.ReturnCauseSegmentationFailure)
+}
+
+func (e *Endpoint) notice(ctx context.Context, m Message, cdpa, cgpa *params.PartyAddress, cause *params.ReturnCause, data []byte) {
+	if e.cfg.Notice == nil {
+		return
+	}
+
+	n := &Notice{
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		Importance:          MessageImportance(m),
+		Message:             m,
+	}
+	if cause != nil {
+		n.ReturnCause = cause.Value()
+	}
+	n.Data = data
+	e.cfg.Notice(ctx, n)
+}
+
+// paramValue returns the value of the Data or LongData, or nil if p is nil.
+func paramValue[P interface {
+	*params.Data | *params.LongData
+	Value() []byte
+}](p P) []byte {
+	if p == nil {
+		return nil
+	}
+	return p.Value()
+}
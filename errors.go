@@ -5,9 +5,18 @@
 package sccp
 
 import (
+	"errors"
 	"fmt"
 )
 
+// ErrInvalidProtocolClass indicates that the class in the Protocol Class is not
+// allowed in the message type.
+var ErrInvalidProtocolClass = errors.New("sccp: invalid protocol class")
+
+// ErrNoReturnOption is returned by NewServiceMessage when the message does not
+// have the return option set, which means it should be discarded.
+var ErrNoReturnOption = errors.New("sccp: return option is not set")
+
 // UnsupportedTypeError indicates the value in Version field is invalid.
 type UnsupportedTypeError uint8
 
@@ -15,3 +24,32 @@ type UnsupportedTypeError uint8
 func (e UnsupportedTypeError) Error() string {
 	return fmt.Sprintf("sccp: got unsupported type %d", e)
 }
+
+// ErrTooManySegments is returned by Segment when the data needs more than
+// MaxSegments segments.
+var ErrTooManySegments = errors.New("sccp: too many segments")
+
+// ErrInvalidSegmentSize is returned by Segment when the segment size is out
+// of range.
+var ErrInvalidSegmentSize = errors.New("sccp: invalid segment size")
+
+// ErrFrameTooLarge is returned by the FrameReaders returned by LengthPrefixed
+// when the length of a frame exceeds MaxFrameSize.
+var ErrFrameTooLarge = errors.New("sccp: frame too large")
+
+// ErrBuiltinMessageType is returned by RegisterMessageType when the message
+// type is defined in Q.713.
+var ErrBuiltinMessageType = errors.New("sccp: message type is built in")
+
+// ErrMissingParameter is returned by UnmarshalJSON of the messages when a
+// mandatory parameter is not given.
+var ErrMissingParameter = errors.New("sccp: missing mandatory parameter")
+
+// ErrNoSSN is returned when the address that has no SSN is given where the
+// local subsystem is identified by it.
+var ErrNoSSN = errors.New("sccp: address has no SSN")
+
+// ErrCongestion is returned by Endpoint when the message is discarded due to
+// the congestion. A Transport should return the error wrapping it when it
+// cannot accept the message due to the backpressure.
+var ErrCongestion = errors.New("sccp: message discarded due to congestion")
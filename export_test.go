@@ -0,0 +1,18 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+// ClearRawPointers clears the pointers kept by UnmarshalBinary, so that the
+// parsed messages can be compared with the ones created by the constructors.
+func ClearRawPointers(m any) {
+	switch m := m.(type) {
+	case *UDT:
+		m.ptr1, m.ptr2, m.ptr3 = 0, 0, 0
+	case *XUDT:
+		m.ptr1, m.ptr2, m.ptr3, m.ptr4 = 0, 0, 0, 0
+	case *LUDT:
+		m.ptr1, m.ptr2, m.ptr3, m.ptr4 = 0, 0, 0, 0
+	}
+}
@@ -4,7 +4,15 @@ go 1.23
 
 require (
 	github.com/google/go-cmp v0.7.0
+	github.com/gopacket/gopacket v1.3.1
 	github.com/ishidawataru/sctp v0.0.0-20250427101207-53eab83c1cf6
 	github.com/pascaldekloe/goe v0.1.1
 	github.com/wmnsk/go-m3ua v0.1.11
+	google.golang.org/protobuf v1.35.2
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
 )
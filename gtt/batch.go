@@ -0,0 +1,61 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"sync"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// BatchResult is the result of the translation of an address in the batch
+// given to TranslateBatch. Either Result or Err is set.
+type BatchResult struct {
+	Result *Result
+	Err    error
+}
+
+// TranslateBatch translates each of addrs as Translate does, and returns the
+// results in the same order, e.g., for the offline analysis of a large number
+// of addresses.
+//
+// If workers is more than 1, the addresses are split into as many contiguous
+// chunks, which are translated in parallel. Otherwise they are translated one
+// by one in the calling goroutine.
+func (t *Table) TranslateBatch(addrs []*params.PartyAddress, workers int) []BatchResult {
+	results := make([]BatchResult, len(addrs))
+	translate := func(from, to int) {
+		for i := from; i < to; i++ {
+			res, _, err := t.translate(addrs[i], 0, false)
+			results[i] = BatchResult{Result: res, Err: err}
+		}
+	}
+
+	workers = min(workers, len(addrs))
+	if workers <= 1 {
+		translate(0, len(addrs))
+		return results
+	}
+
+	var wg sync.WaitGroup
+	chunk := (len(addrs) + workers - 1) / workers
+	for from := 0; from < len(addrs); from += chunk {
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			translate(from, to)
+		}(from, min(from+chunk, len(addrs)))
+	}
+	wg.Wait()
+
+	return results
+}
+
+// TranslateBatch translates addrs with the Table in use when it is called, so
+// that all of them are translated with the same one even if it is replaced in
+// the meantime. See Table.TranslateBatch.
+func (e *Engine) TranslateBatch(addrs []*params.PartyAddress, workers int) []BatchResult {
+	return e.table.Load().TranslateBatch(addrs, workers)
+}
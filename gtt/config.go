@@ -0,0 +1,435 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/go-sccp/params"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownFormat is returned by LoadFile when the format of the file cannot
+// be determined from the extension.
+var ErrUnknownFormat = errors.New("gtt: unknown file format")
+
+// ConfigError is the error in the rule set file, with the position of the
+// rule or the syntax error in it.
+type ConfigError struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+// Error returns the error with the position in the file:line:column format.
+//
+// The position is omitted if it is unknown, e.g., for the YAML syntax errors,
+// which have the line in the message.
+func (e *ConfigError) Error() string {
+	var pos []string
+	if e.File != "" {
+		pos = append(pos, e.File)
+	}
+	if e.Line > 0 {
+		pos = append(pos, strconv.Itoa(e.Line), strconv.Itoa(e.Column))
+	}
+	if len(pos) == 0 {
+		return fmt.Sprintf("gtt: %v", e.Err)
+	}
+	return fmt.Sprintf("gtt: %s: %v", strings.Join(pos, ":"), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ruleConfig is the representation of a Rule in the rule set file.
+//
+// The rule set file has the list of the rules in "rules", e.g., in YAML:
+//
+//	rules:
+//	  - name: hlr
+//	    prefix: "4479"
+//	    np: E.164
+//	    nai: international
+//	    pc: 2-123-4
+//	    ssn: 6
+//	    routeOnSSN: true
+//	  - name: national
+//	    prefix: "81"
+//	    strip: 2
+//	    prepend: "0"
+//	    newNAI: national
+//	  - name: stp
+//	    prefix: "33"
+//	    mode: round-robin
+//	    destinations:
+//	      - pc: 1-1-1
+//	        weight: 2
+//	      - pc: 1-1-2
+type ruleConfig struct {
+	Name       string         `json:"name" yaml:"name"`
+	Prefix     string         `json:"prefix" yaml:"prefix"`
+	TT         *uint8         `json:"tt,omitempty" yaml:"tt,omitempty"`
+	NP         *numberingPlan `json:"np,omitempty" yaml:"np,omitempty"`
+	NAI        *natureOfAddr  `json:"nai,omitempty" yaml:"nai,omitempty"`
+	PC         pointCode      `json:"pc,omitempty" yaml:"pc,omitempty"`
+	SSN        uint8          `json:"ssn,omitempty" yaml:"ssn,omitempty"`
+	RouteOnSSN bool           `json:"routeOnSSN,omitempty" yaml:"routeOnSSN,omitempty"`
+	Digits     string         `json:"digits,omitempty" yaml:"digits,omitempty"`
+
+	Destinations []destinationConfig `json:"destinations,omitempty" yaml:"destinations,omitempty"`
+	Mode         LoadSharingMode     `json:"mode,omitempty" yaml:"mode,omitempty"`
+
+	Strip   int            `json:"strip,omitempty" yaml:"strip,omitempty"`
+	Prepend string         `json:"prepend,omitempty" yaml:"prepend,omitempty"`
+	NewTT   *uint8         `json:"newTT,omitempty" yaml:"newTT,omitempty"`
+	NewNP   *numberingPlan `json:"newNP,omitempty" yaml:"newNP,omitempty"`
+	NewNAI  *natureOfAddr  `json:"newNAI,omitempty" yaml:"newNAI,omitempty"`
+}
+
+// ruleConfigKeys is the set of the keys allowed in a rule.
+var ruleConfigKeys = map[string]bool{
+	"name": true, "prefix": true, "tt": true, "np": true, "nai": true,
+	"pc": true, "ssn": true, "routeOnSSN": true, "digits": true,
+	"strip": true, "prepend": true, "newTT": true, "newNP": true, "newNAI": true,
+	"destinations": true, "mode": true,
+}
+
+// destinationConfig is the representation of a Destination in the rule set
+// file.
+type destinationConfig struct {
+	PC     pointCode `json:"pc,omitempty" yaml:"pc,omitempty"`
+	SSN    uint8     `json:"ssn,omitempty" yaml:"ssn,omitempty"`
+	Weight int       `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Cost   int       `json:"cost,omitempty" yaml:"cost,omitempty"`
+}
+
+var destinationConfigKeys = map[string]bool{
+	"pc": true, "ssn": true, "weight": true, "cost": true,
+}
+
+func (c *ruleConfig) rule() Rule {
+	r := Rule{
+		Name:       c.Name,
+		Prefix:     c.Prefix,
+		PointCode:  params.PointCode(c.PC),
+		SSN:        c.SSN,
+		RouteOnSSN: c.RouteOnSSN,
+		Digits:     c.Digits,
+		Mode:       c.Mode,
+		Modification: Modification{
+			StripDigits:   c.Strip,
+			PrependDigits: c.Prepend,
+		},
+	}
+	if c.TT != nil {
+		tt := params.TranslationType(*c.TT)
+		r.TranslationType = &tt
+	}
+	if c.NP != nil {
+		np := params.NumberingPlan(*c.NP)
+		r.NumberingPlan = &np
+	}
+	if c.NAI != nil {
+		nai := params.NatureOfAddressIndicator(*c.NAI)
+		r.NatureOfAddress = &nai
+	}
+	for _, d := range c.Destinations {
+		r.Destinations = append(r.Destinations, Destination{
+			PointCode: params.PointCode(d.PC),
+			SSN:       d.SSN,
+			Weight:    d.Weight,
+			Cost:      d.Cost,
+		})
+	}
+	if c.NewTT != nil {
+		tt := params.TranslationType(*c.NewTT)
+		r.Modification.TranslationType = &tt
+	}
+	if c.NewNP != nil {
+		np := params.NumberingPlan(*c.NewNP)
+		r.Modification.NumberingPlan = &np
+	}
+	if c.NewNAI != nil {
+		nai := params.NatureOfAddressIndicator(*c.NewNAI)
+		r.Modification.NatureOfAddress = &nai
+	}
+
+	return r
+}
+
+// LoadFile loads the rule set file in JSON (.json) or YAML (.yaml or .yml)
+// and creates a Table with the rules in it.
+func LoadFile(path string) (*Table, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var t *Table
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		t, err = loadJSON(b)
+	case ".yaml", ".yml":
+		t, err = loadYAML(b)
+	default:
+		return nil, fmt.Errorf("%s: %w", path, ErrUnknownFormat)
+	}
+
+	var cerr *ConfigError
+	if errors.As(err, &cerr) {
+		cerr.File = path
+	}
+	return t, err
+}
+
+// LoadJSON reads the rule set in JSON from r and creates a Table with the
+// rules in it. The errors in the rules are returned as ConfigError.
+func LoadJSON(r io.Reader) (*Table, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadJSON(b)
+}
+
+// LoadYAML reads the rule set in YAML from r and creates a Table with the
+// rules in it. The errors in the rules are returned as ConfigError.
+func LoadYAML(r io.Reader) (*Table, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadYAML(b)
+}
+
+func loadJSON(b []byte) (*Table, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+
+	errAt := func(offset int64, err error) error {
+		var serr *json.SyntaxError
+		if errors.As(err, &serr) {
+			// Offset is after the invalid character.
+			offset = max(0, serr.Offset-1)
+		}
+		line, col := position(b, offset)
+		return &ConfigError{Line: line, Column: col, Err: err}
+	}
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, errAt(dec.InputOffset(), err)
+	}
+
+	var rules []Rule
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, errAt(dec.InputOffset(), err)
+		}
+		if key != "rules" {
+			return nil, errAt(dec.InputOffset(), fmt.Errorf("unknown key %q", key))
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, errAt(dec.InputOffset(), err)
+		}
+		for dec.More() {
+			offset := dec.InputOffset()
+			var c ruleConfig
+			if err := dec.Decode(&c); err != nil {
+				return nil, errAt(offset, err)
+			}
+			r := c.rule()
+			if err := r.validate(); err != nil {
+				return nil, errAt(offset, fmt.Errorf("rule %d (%s): %w", len(rules), r.Name, err))
+			}
+			rules = append(rules, r)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, errAt(dec.InputOffset(), err)
+		}
+	}
+
+	return NewTable(rules...)
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("got %v, want %v", tok, want)
+	}
+	return nil
+}
+
+// position returns the line and column of the first significant character
+// at or after offset in b, skipping the white spaces and the separator.
+func position(b []byte, offset int64) (line, col int) {
+	i := int(min(offset, int64(len(b))))
+	for i < len(b) && strings.IndexByte(" \t\r\n,:", b[i]) >= 0 {
+		i++
+	}
+
+	line = 1 + bytes.Count(b[:i], []byte{'\n'})
+	col = i - bytes.LastIndexByte(b[:i], '\n')
+	return line, col
+}
+
+func loadYAML(b []byte) (*Table, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+	if len(doc.Content) == 0 {
+		return NewTable()
+	}
+
+	root := doc.Content[0]
+	errAt := func(n *yaml.Node, err error) error {
+		return &ConfigError{Line: n.Line, Column: n.Column, Err: err}
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, errAt(root, errors.New("rule set must be a mapping"))
+	}
+
+	var rules []Rule
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "rules" {
+			return nil, errAt(key, fmt.Errorf("unknown key %q", key.Value))
+		}
+		if value.Kind != yaml.SequenceNode {
+			return nil, errAt(value, errors.New("rules must be a sequence"))
+		}
+
+		for _, n := range value.Content {
+			if n.Kind != yaml.MappingNode {
+				return nil, errAt(n, errors.New("rule must be a mapping"))
+			}
+			for j := 0; j+1 < len(n.Content); j += 2 {
+				k, v := n.Content[j], n.Content[j+1]
+				if !ruleConfigKeys[k.Value] {
+					return nil, errAt(k, fmt.Errorf("unknown key %q in rule", k.Value))
+				}
+				if k.Value != "destinations" || v.Kind != yaml.SequenceNode {
+					continue
+				}
+				for _, d := range v.Content {
+					for l := 0; d.Kind == yaml.MappingNode && l < len(d.Content); l += 2 {
+						if dk := d.Content[l]; !destinationConfigKeys[dk.Value] {
+							return nil, errAt(dk, fmt.Errorf("unknown key %q in destination", dk.Value))
+						}
+					}
+				}
+			}
+
+			var c ruleConfig
+			if err := n.Decode(&c); err != nil {
+				return nil, errAt(n, err)
+			}
+			r := c.rule()
+			if err := r.validate(); err != nil {
+				return nil, errAt(n, fmt.Errorf("rule %d (%s): %w", len(rules), r.Name, err))
+			}
+			rules = append(rules, r)
+		}
+	}
+
+	return NewTable(rules...)
+}
+
+// pointCode is the PointCode in the zone-area-SP format or in decimal.
+type pointCode params.PointCode
+
+func (p *pointCode) UnmarshalText(text []byte) error {
+	pc, err := params.ParsePointCode(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = pointCode(pc)
+	return nil
+}
+
+// UnmarshalJSON accepts both the number and the string.
+func (p *pointCode) UnmarshalJSON(b []byte) error {
+	return p.UnmarshalText(bytes.Trim(b, `"`))
+}
+
+// numberingPlan is the NumberingPlan in the name of the recommendation (e.g.,
+// "E.164"), the text returned by String, or in decimal.
+type numberingPlan params.NumberingPlan
+
+var numberingPlanAliases = map[string]params.NumberingPlan{
+	"E.164": params.NPE164,
+	"X.121": params.NPX121,
+	"F.69":  params.NPF69,
+	"E.210": params.NPE210,
+	"E.212": params.NPE212,
+	"E.214": params.NPE214,
+}
+
+func (n *numberingPlan) UnmarshalText(text []byte) error {
+	if np, ok := numberingPlanAliases[strings.ToUpper(string(text))]; ok {
+		*n = numberingPlan(np)
+		return nil
+	}
+
+	var np params.NumberingPlan
+	if err := np.UnmarshalText(text); err != nil {
+		return err
+	}
+	*n = numberingPlan(np)
+	return nil
+}
+
+// UnmarshalJSON accepts both the number and the string.
+func (n *numberingPlan) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalText(bytes.Trim(b, `"`))
+}
+
+// natureOfAddr is the NatureOfAddressIndicator in the short name (e.g.,
+// "international"), the text returned by String, or in decimal.
+type natureOfAddr params.NatureOfAddressIndicator
+
+var natureOfAddrAliases = map[string]params.NatureOfAddressIndicator{
+	"unknown":       params.NAIUnknown,
+	"subscriber":    params.NAISubscriberNumber,
+	"national":      params.NAINationalSignificantNumber,
+	"international": params.NAIInternationalNumber,
+}
+
+func (n *natureOfAddr) UnmarshalText(text []byte) error {
+	if nai, ok := natureOfAddrAliases[strings.ToLower(string(text))]; ok {
+		*n = natureOfAddr(nai)
+		return nil
+	}
+
+	var nai params.NatureOfAddressIndicator
+	if err := nai.UnmarshalText(text); err != nil {
+		return err
+	}
+	*n = natureOfAddr(nai)
+	return nil
+}
+
+// UnmarshalJSON accepts both the number and the string.
+func (n *natureOfAddr) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalText(bytes.Trim(b, `"`))
+}
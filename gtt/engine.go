@@ -0,0 +1,151 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Engine translates with the Table in use, which can be replaced while the
+// translations are in flight. The translations that have started with the old
+// Table finish with it, and the ones that start after Swap use the new one.
+//
+// Engine is safe for concurrent use.
+type Engine struct {
+	table atomic.Pointer[Table]
+}
+
+// NewEngine creates a new Engine that uses t.
+func NewEngine(t *Table) *Engine {
+	e := &Engine{}
+	e.table.Store(t)
+	return e
+}
+
+// Table returns the Table in use.
+func (e *Engine) Table() *Table {
+	return e.table.Load()
+}
+
+// Swap replaces the Table in use with t, and returns the Diff from the old one.
+func (e *Engine) Swap(t *Table) *Diff {
+	return e.table.Swap(t).Diff(t)
+}
+
+// ReloadFile loads the rule set file with LoadFile and replaces the Table in
+// use with it. The Table is not replaced if the file has any error.
+func (e *Engine) ReloadFile(path string) (*Diff, error) {
+	t, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return e.Swap(t), nil
+}
+
+// Translate translates addr with the Table in use. See Table.Translate.
+func (e *Engine) Translate(addr *params.PartyAddress) (*Result, error) {
+	return e.table.Load().Translate(addr)
+}
+
+// TranslateSLS translates addr with the Table in use. See Table.TranslateSLS.
+func (e *Engine) TranslateSLS(addr *params.PartyAddress, sls uint8) (*Result, error) {
+	return e.table.Load().TranslateSLS(addr, sls)
+}
+
+// Diff is the difference between two Tables.
+//
+// The rules are identified by what they match, i.e., the Prefix and the TT,
+// NP and NAI, and the rule that has the same ones in both but differs in the
+// others (e.g., the PointCode) is the one Changed.
+type Diff struct {
+	Added   []Rule
+	Removed []Rule
+	Changed []RuleChange
+}
+
+// RuleChange is a rule changed between two Tables.
+type RuleChange struct {
+	Old, New Rule
+}
+
+// Empty reports whether there is no difference.
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String returns the Diff in a human-readable format, a line for each rule.
+func (d *Diff) String() string {
+	var b strings.Builder
+	for _, r := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", &r)
+	}
+	for _, r := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", &r)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n  -> %s\n", &c.Old, &c.New)
+	}
+	return b.String()
+}
+
+// ruleKey identifies a Rule by what it matches.
+type ruleKey struct {
+	prefix string
+	nature nature
+}
+
+func (r *Rule) key() ruleKey {
+	return ruleKey{prefix: strings.ToLower(r.Prefix), nature: r.nature()}
+}
+
+// Diff returns the difference from t to newer, in the order of the rules in
+// the Table each of them is in.
+func (t *Table) Diff(newer *Table) *Diff {
+	d := &Diff{}
+
+	old := make(map[ruleKey]*Rule, len(t.rules))
+	for i := range t.rules {
+		// the first one is used in the translation.
+		if k := t.rules[i].key(); old[k] == nil {
+			old[k] = &t.rules[i]
+		}
+	}
+
+	seen := make(map[ruleKey]bool, len(newer.rules))
+	for i := range newer.rules {
+		r := &newer.rules[i]
+		k := r.key()
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		switch o, ok := old[k]; {
+		case !ok:
+			d.Added = append(d.Added, *r)
+		case !reflect.DeepEqual(o, r):
+			d.Changed = append(d.Changed, RuleChange{Old: *o, New: *r})
+		}
+	}
+
+	for i := range t.rules {
+		if r := &t.rules[i]; !seen[r.key()] && old[r.key()] == r {
+			d.Removed = append(d.Removed, *r)
+		}
+	}
+
+	return d
+}
+
+// TranslateWithTrace translates addr with the Table in use and returns the
+// Trace. See Table.TranslateWithTrace.
+func (e *Engine) TranslateWithTrace(addr *params.PartyAddress, sls uint8) (*Result, *Trace, error) {
+	return e.table.Load().TranslateWithTrace(addr, sls)
+}
@@ -0,0 +1,171 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package gtt provides the Global Title Translation (GTT), which determines the
+destination (PC, SSN and possibly a new GT) of the messages routed on GT with
+a table of rules.
+
+A Table is matched against the Called Party Address with Translate. When no
+rule matches, the error can be converted into the Return Cause of the UDTS
+or XUDTS with ReturnCause.
+
+A rule can share the load among multiple Destinations, in which case the
+Result has the alternates to fail over to when the selected one is not
+available.
+*/
+package gtt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Error definitions.
+var (
+	// ErrNoTranslationForNature is returned when no rule is configured for the
+	// combination of the TT, NP and NAI of the GT.
+	ErrNoTranslationForNature = errors.New("gtt: no translation for an address of such nature")
+	// ErrNoTranslationForAddress is returned when the rules for the TT, NP and
+	// NAI of the GT exist but none of them matches the digits.
+	ErrNoTranslationForAddress = errors.New("gtt: no translation for this specific address")
+	// ErrNoGlobalTitle is returned when the address to translate has no GT.
+	ErrNoGlobalTitle = errors.New("gtt: no global title in the address")
+	// ErrInvalidRule is returned when the rule given to the Table is invalid.
+	ErrInvalidRule = errors.New("gtt: invalid rule")
+)
+
+// ReturnCause returns the Return Cause for the error returned by Translate,
+// and reports whether err is the one caused by the lack of translation.
+func ReturnCause(err error) (params.ReturnCauseValue, bool) {
+	switch {
+	case errors.Is(err, ErrNoTranslationForAddress):
+		return params.ReturnCauseNoTranslationForThisSpecificAddress, true
+	case errors.Is(err, ErrNoTranslationForNature), errors.Is(err, ErrNoGlobalTitle):
+		return params.ReturnCauseNoTranslationForAnAddressOfSuchNature, true
+	default:
+		return 0, false
+	}
+}
+
+// Table is a set of the translation rules.
+//
+// The rule with the longest Prefix that matches the GT is used, and the one
+// given first is used if there are multiple rules with the Prefix of the same
+// length. The rules are indexed by the Prefix in a trie, and the lookup takes
+// the time proportional to the number of the digits, not the rules.
+//
+// Table is immutable after it is created, and is safe for concurrent use. Use
+// Engine to replace it with a new one at runtime.
+type Table struct {
+	rules     []Rule
+	selectors []*selector
+	trie      trie
+	natures   map[nature]struct{}
+}
+
+// nature is the TT, NP and NAI of a Rule, which are -1 if not specified.
+type nature struct {
+	tt, np, nai int
+}
+
+// NewTable creates a new Table with the rules. It returns ErrInvalidRule if
+// any of the rules is invalid.
+func NewTable(rules ...Rule) (*Table, error) {
+	t := &Table{
+		rules:     make([]Rule, len(rules)),
+		selectors: make([]*selector, len(rules)),
+		natures:   make(map[nature]struct{}),
+	}
+	for i, r := range rules {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i, r.Name, err)
+		}
+		r.Destinations = slices.Clone(r.Destinations)
+		t.rules[i] = r
+		t.selectors[i] = newSelector(&r)
+
+		// the pattern is validated above.
+		pattern, _ := parsePattern(r.Prefix)
+		t.trie.insert(pattern, i)
+		t.natures[r.nature()] = struct{}{}
+	}
+
+	return t, nil
+}
+
+// Rules returns a copy of the rules in the Table.
+func (t *Table) Rules() []Rule {
+	rules := slices.Clone(t.rules)
+	for i := range rules {
+		rules[i].Destinations = slices.Clone(rules[i].Destinations)
+	}
+	return rules
+}
+
+// Translate translates the GT in addr, which is usually the Called Party
+// Address, and returns the Result of the rule with the longest Prefix that
+// matches it.
+//
+// It returns ErrNoTranslationForNature or ErrNoTranslationForAddress if no
+// rule matches, which can be given to ReturnCause.
+//
+// The rules in ModeSLS always select the same Destination with Translate;
+// use TranslateSLS to share the load among them.
+func (t *Table) Translate(addr *params.PartyAddress) (*Result, error) {
+	return t.TranslateSLS(addr, 0)
+}
+
+// TranslateSLS is the same as Translate, but selects the Destination of the
+// rule in ModeSLS by sls, which is usually the SLS of the message.
+func (t *Table) TranslateSLS(addr *params.PartyAddress, sls uint8) (*Result, error) {
+	res, _, err := t.translate(addr, sls, false)
+	return res, err
+}
+
+// translate translates addr, with the Trace if trace is set.
+func (t *Table) translate(addr *params.PartyAddress, sls uint8, trace bool) (*Result, *Trace, error) {
+	gt, err := globalTitleOf(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tr *Trace
+	if trace {
+		tr = &Trace{Digits: gt.digits}
+	}
+
+	matched := -1
+	t.trie.lookup(gt.digits, func(i int) bool {
+		ok := t.rules[i].matchNature(gt)
+		if tr != nil {
+			tr.step(&t.rules[i], gt, ok)
+		}
+		if ok {
+			matched = i
+		}
+		return ok
+	})
+	if matched >= 0 {
+		d, alts := t.selectors[matched].selectDestination(sls)
+		res, err := t.rules[matched].result(addr, gt, d, alts)
+		if err != nil {
+			return nil, tr, err
+		}
+		if tr != nil {
+			tr.modifications(addr, gt, res.Address)
+		}
+		return res, tr, nil
+	}
+
+	for n := range t.natures {
+		if n.match(gt) {
+			return nil, tr, fmt.Errorf("%s: %w", gt, ErrNoTranslationForAddress)
+		}
+	}
+	return nil, tr, fmt.Errorf("%s: %w", gt, ErrNoTranslationForNature)
+}
@@ -0,0 +1,718 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func e164(t *testing.T, digits string) *params.PartyAddress {
+	t.Helper()
+
+	addr, err := params.NewE164Address(6, digits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func TestTranslate(t *testing.T) {
+	table, err := gtt.NewTable(
+		gtt.Rule{Name: "hlr", Prefix: "4479", NumberingPlan: ptr(params.NPE164), PointCode: 0x1234, SSN: 6, RouteOnSSN: true},
+		gtt.Rule{Name: "uk", Prefix: "44", NumberingPlan: ptr(params.NPE164), PointCode: 0x100},
+		gtt.Rule{Name: "rewrite", Prefix: "81", NumberingPlan: ptr(params.NPE164), Digits: "819012345"},
+		gtt.Rule{Name: "tt", TranslationType: ptr(params.TranslationType(9)), PointCode: 0x200},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		digits      string
+		rule        string
+		pc          params.PointCode
+		routeOnSSN  bool
+		gt          string
+		err         error
+		cause       params.ReturnCauseValue
+	}{
+		{
+			description: "longest prefix",
+			digits:      "447912345678",
+			rule:        "hlr",
+			pc:          0x1234,
+			routeOnSSN:  true,
+			gt:          "447912345678",
+		}, {
+			description: "prefix",
+			digits:      "441234",
+			rule:        "uk",
+			pc:          0x100,
+			gt:          "441234",
+		}, {
+			description: "rewrite digits",
+			digits:      "8190",
+			rule:        "rewrite",
+			gt:          "819012345",
+		}, {
+			description: "no match",
+			digits:      "33123",
+			err:         gtt.ErrNoTranslationForAddress,
+			cause:       params.ReturnCauseNoTranslationForThisSpecificAddress,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			res, err := table.Translate(e164(t, c.digits))
+			if c.err != nil {
+				if !errors.Is(err, c.err) {
+					t.Fatalf("got %v, want %v", err, c.err)
+				}
+				if cause, ok := gtt.ReturnCause(err); !ok || cause != c.cause {
+					t.Errorf("got cause %v, want %v", cause, c.cause)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Rule.Name != c.rule {
+				t.Errorf("got rule %s, want %s", res.Rule.Name, c.rule)
+			}
+			if res.PointCode != c.pc {
+				t.Errorf("got PC %s, want %s", res.PointCode, c.pc)
+			}
+			if got := res.Address.RouteOnSSN(); got != c.routeOnSSN {
+				t.Errorf("got route on SSN %v, want %v", got, c.routeOnSSN)
+			}
+			if got := res.Address.GlobalTitle.Address(); got != c.gt {
+				t.Errorf("got GT %q, want %q", got, c.gt)
+			}
+			if _, err := res.Address.Write(make([]byte, res.Address.MarshalLen())); err != nil {
+				t.Errorf("translated address cannot be encoded: %v", err)
+			}
+		})
+	}
+}
+
+func TestTranslatePattern(t *testing.T) {
+	table, err := gtt.NewTable(
+		gtt.Rule{Name: "uk", Prefix: "44", PointCode: 1},
+		gtt.Rule{Name: "range", Prefix: "4479[0-5]", PointCode: 2},
+		gtt.Rule{Name: "wildcard", Prefix: "44?1", PointCode: 3},
+		gtt.Rule{Name: "class", Prefix: "81[139]", PointCode: 4},
+		gtt.Rule{Name: "same length", Prefix: "4479[3-9]", PointCode: 5},
+		gtt.Rule{Name: "exact", Prefix: "447951", NatureOfAddress: ptr(params.NAISubscriberNumber), PointCode: 6},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		digits string
+		rule   string
+	}{
+		{"4412", "uk"},
+		{"447901", "range"},
+		{"447961", "same length"},
+		{"447951", "range"},
+		{"4471", "wildcard"},
+		{"4491", "wildcard"},
+		{"4492", "uk"},
+		{"813", "class"},
+		{"8194", "class"},
+		{"81", ""},
+		{"812", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.digits, func(t *testing.T) {
+			res, err := table.Translate(e164(t, c.digits))
+			if c.rule == "" {
+				if !errors.Is(err, gtt.ErrNoTranslationForAddress) {
+					t.Errorf("got %v, want %v", err, gtt.ErrNoTranslationForAddress)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Rule.Name != c.rule {
+				t.Errorf("got rule %s, want %s", res.Rule.Name, c.rule)
+			}
+		})
+	}
+}
+
+func TestTranslateModification(t *testing.T) {
+	table, err := gtt.NewTable(
+		gtt.Rule{Name: "national", Prefix: "81", PointCode: 1, Modification: gtt.Modification{
+			StripDigits:     2,
+			PrependDigits:   "0",
+			NatureOfAddress: ptr(params.NAINationalSignificantNumber),
+		}},
+		gtt.Rule{Name: "e214", Prefix: "23415", PointCode: 2, Modification: gtt.Modification{
+			NumberingPlan: ptr(params.NPE214),
+		}},
+		gtt.Rule{Name: "too long", Prefix: "99", PointCode: 3, Modification: gtt.Modification{
+			PrependDigits: strings.Repeat("1", params.MaxGlobalTitleDigits-10),
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := table.Translate(e164(t, "819012345678"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gt, ok := res.Address.GlobalTitle.(*params.GTTTNPESNAI)
+	if !ok {
+		t.Fatalf("got %T, want *params.GTTTNPESNAI", res.Address.GlobalTitle)
+	}
+	if got := gt.Address(); got != "09012345678" {
+		t.Errorf("got digits %q, want %q", got, "09012345678")
+	}
+	if gt.NatureOfAddressIndicator != params.NAINationalSignificantNumber || gt.NumberingPlan != params.NPE164 {
+		t.Errorf("got NAI %s, NP %s", gt.NatureOfAddressIndicator, gt.NumberingPlan)
+	}
+
+	// NP is added to the GT with TT only.
+	ttOnly, err := params.NewAddressBuilder().SSN(6).GT(params.GTITTOnly, 0, 0, 0, "23415123").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = table.Translate(ttOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Address.GlobalTitle.GTI(); got != params.GTITTNPESNAI {
+		t.Errorf("got GTI %s, want %s", got, params.GTITTNPESNAI)
+	}
+	if _, err := res.Address.Write(make([]byte, res.Address.MarshalLen())); err != nil {
+		t.Errorf("modified address cannot be encoded: %v", err)
+	}
+	if got := ttOnly.GlobalTitle.GTI(); got != params.GTITTOnly {
+		t.Errorf("original address is modified: GTI %s", got)
+	}
+
+	if _, err := table.Translate(e164(t, "991234567890123")); !errors.Is(err, params.ErrInvalidDigits) {
+		t.Errorf("too long: got %v, want %v", err, params.ErrInvalidDigits)
+	}
+}
+
+func TestLoadSharing(t *testing.T) {
+	a := gtt.Destination{PointCode: 1, SSN: 6, Weight: 2}
+	b := gtt.Destination{PointCode: 2, SSN: 6}
+	c := gtt.Destination{PointCode: 3, SSN: 6, Cost: 10}
+	d := gtt.Destination{PointCode: 4, SSN: 6, Cost: 5}
+	table, err := gtt.NewTable(
+		gtt.Rule{Name: "rr", Prefix: "1", Mode: gtt.ModeRoundRobin, Destinations: []gtt.Destination{a, b}},
+		gtt.Rule{Name: "sls", Prefix: "2", Mode: gtt.ModeSLS, Destinations: []gtt.Destination{a, b}},
+		gtt.Rule{Name: "backup", Prefix: "3", Mode: gtt.ModePrimaryBackup, Destinations: []gtt.Destination{c, d}, RouteOnSSN: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		digits      string
+		sls         uint8
+		pc          params.PointCode
+		alternates  []gtt.Destination
+	}{
+		{"round-robin 1", "1234", 0, 1, []gtt.Destination{b}},
+		{"round-robin 2", "1234", 0, 1, []gtt.Destination{b}},
+		{"round-robin 3", "1234", 0, 2, []gtt.Destination{a}},
+		{"round-robin 4", "1234", 0, 1, []gtt.Destination{b}},
+		{"SLS 0", "2345", 0, 1, []gtt.Destination{b}},
+		{"SLS 2", "2345", 2, 2, []gtt.Destination{a}},
+		{"SLS 2 again", "2345", 2, 2, []gtt.Destination{a}},
+		{"SLS 4", "2345", 4, 1, []gtt.Destination{b}},
+		{"primary", "3456", 0, 4, []gtt.Destination{c}},
+		{"primary again", "3456", 0, 4, []gtt.Destination{c}},
+	}
+
+	for _, c := range cases {
+		res, err := table.TranslateSLS(e164(t, c.digits), c.sls)
+		if err != nil {
+			t.Fatalf("%s: %v", c.description, err)
+		}
+		if res.PointCode != c.pc {
+			t.Errorf("%s: got PC %s, want %s", c.description, res.PointCode, c.pc)
+		}
+		if !verify.Values(t, c.description, res.Alternates, c.alternates) {
+			t.Fail()
+		}
+	}
+
+	res, err := table.Translate(e164(t, "3456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alt := res.Failover(res.Alternates[0])
+	if alt.PointCode != 3 || alt.Address.SignalingPointCode != 3 || !alt.Address.RouteOnSSN() {
+		t.Errorf("failover: got %v", alt)
+	}
+	if res.PointCode != 4 || res.Address.SignalingPointCode != 4 {
+		t.Errorf("failover modified the original: %v", res)
+	}
+}
+
+func TestTranslateNature(t *testing.T) {
+	table, err := gtt.NewTable(gtt.Rule{Name: "e212", NumberingPlan: ptr(params.NPE212), PointCode: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = table.Translate(e164(t, "4479"))
+	if cause, ok := gtt.ReturnCause(err); !ok || cause != params.ReturnCauseNoTranslationForAnAddressOfSuchNature {
+		t.Errorf("got %v", err)
+	}
+	if _, err := table.Translate(params.NewSSNAddress(6)); !errors.Is(err, gtt.ErrNoGlobalTitle) {
+		t.Errorf("without GT: got %v", err)
+	}
+}
+
+func TestTranslateWithTrace(t *testing.T) {
+	table, err := gtt.NewTable(
+		gtt.Rule{Name: "tt9", Prefix: "4479", TranslationType: ptr(params.TranslationType(9)), PointCode: 1},
+		gtt.Rule{Name: "e212", Prefix: "447", NumberingPlan: ptr(params.NPE212), PointCode: 2},
+		gtt.Rule{Name: "uk", Prefix: "44", PointCode: 3, SSN: 6, RouteOnSSN: true, Modification: gtt.Modification{
+			StripDigits: 2, PrependDigits: "0", TranslationType: ptr(params.TranslationType(1)),
+		}},
+		gtt.Rule{Name: "other", Prefix: "4", PointCode: 4},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, trace, err := table.TranslateWithTrace(e164(t, "447912"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rule.Name != "uk" {
+		t.Errorf("got rule %s, want uk", res.Rule.Name)
+	}
+
+	var steps []string
+	for _, s := range trace.Steps {
+		steps = append(steps, fmt.Sprintf("%s %v %s", s.Rule.Name, s.Matched, s.Reason))
+	}
+	want := []string{
+		"tt9 false TT unknown (0) != international service (9)",
+		"e212 false NP ISDN/telephony numbering plan != land mobile numbering plan",
+		"uk true ",
+	}
+	if !verify.Values(t, "steps", steps, want) {
+		t.Fail()
+	}
+	wantMods := []string{
+		"RI: route on GT -> route on SSN",
+		"PC: none -> 0-0-3",
+		"TT: unknown (0) -> international service (1)",
+		`digits: "447912" -> "07912"`,
+	}
+	if !verify.Values(t, "modifications", trace.Modifications, wantMods) {
+		t.Fail()
+	}
+
+	_, trace, err = table.TranslateWithTrace(e164(t, "5512"), 0)
+	if !errors.Is(err, gtt.ErrNoTranslationForAddress) {
+		t.Errorf("got %v", err)
+	}
+	if trace == nil || trace.Digits != "5512" || len(trace.Steps) != 0 {
+		t.Errorf("got trace %v", trace)
+	}
+	if _, trace, _ := table.TranslateWithTrace(params.NewSSNAddress(6), 0); trace != nil {
+		t.Errorf("got trace %v without GT", trace)
+	}
+}
+
+func TestNewTableInvalid(t *testing.T) {
+	if _, err := gtt.NewTable(gtt.Rule{Prefix: "44x"}); !errors.Is(err, gtt.ErrInvalidRule) {
+		t.Errorf("invalid prefix: got %v", err)
+	}
+	for _, prefix := range []string{"44[0-5", "44[5-1]", "44[]", "44[x]", "??????"} {
+		if _, err := gtt.NewTable(gtt.Rule{Prefix: prefix}); !errors.Is(err, gtt.ErrInvalidRule) {
+			t.Errorf("invalid prefix %q: got %v", prefix, err)
+		}
+	}
+	if _, err := gtt.NewTable(gtt.Rule{Digits: "81", Modification: gtt.Modification{StripDigits: 1}}); !errors.Is(err, gtt.ErrInvalidRule) {
+		t.Errorf("digits with strip: got %v", err)
+	}
+	for _, r := range []gtt.Rule{
+		{PointCode: 1, Destinations: []gtt.Destination{{PointCode: 2}}},
+		{Destinations: []gtt.Destination{{PointCode: 2, Weight: -1}}},
+		{Destinations: []gtt.Destination{{PointCode: 2}}, RouteOnSSN: true},
+		{Destinations: []gtt.Destination{{PointCode: 2}}, Mode: 10},
+	} {
+		if _, err := gtt.NewTable(r); !errors.Is(err, gtt.ErrInvalidRule) {
+			t.Errorf("invalid destinations %v: got %v", r.Destinations, err)
+		}
+	}
+	if _, err := gtt.NewTable(gtt.Rule{RouteOnSSN: true}); !errors.Is(err, gtt.ErrInvalidRule) {
+		t.Errorf("route on SSN without SSN: got %v", err)
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	table, err := gtt.LoadYAML(strings.NewReader(`
+rules:
+  - name: hlr
+    prefix: "4479"
+    np: E.164
+    nai: international
+    pc: 2-123-4
+    ssn: 6
+    routeOnSSN: true
+  - name: imsi
+    prefix: "23415"
+    np: land mobile numbering plan
+    tt: 0
+    pc: 1234
+    strip: 5
+    prepend: "44"
+    newNP: E.214
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pc, _ := params.NewITUPointCode(2, 123, 4)
+	want := []gtt.Rule{
+		{
+			Name:            "hlr",
+			Prefix:          "4479",
+			NumberingPlan:   ptr(params.NPE164),
+			NatureOfAddress: ptr(params.NAIInternationalNumber),
+			PointCode:       pc,
+			SSN:             6,
+			RouteOnSSN:      true,
+		}, {
+			Name:            "imsi",
+			Prefix:          "23415",
+			NumberingPlan:   ptr(params.NPE212),
+			TranslationType: ptr(params.TTUnknown),
+			PointCode:       1234,
+			Modification: gtt.Modification{
+				StripDigits:   5,
+				PrependDigits: "44",
+				NumberingPlan: ptr(params.NPE214),
+			},
+		},
+	}
+	if !verify.Values(t, "rules", table.Rules(), want) {
+		t.Fail()
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	table, err := gtt.LoadJSON(strings.NewReader(`{"rules": [
+		{"name": "hlr", "prefix": "4479", "np": "E.164", "pc": "2-123-4", "ssn": 6, "routeOnSSN": true},
+		{"name": "any", "pc": 1234}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(table.Rules()); got != 2 {
+		t.Errorf("got %d rules, want 2", got)
+	}
+}
+
+func TestLoadDestinations(t *testing.T) {
+	table, err := gtt.LoadYAML(strings.NewReader(`
+rules:
+  - name: backup
+    prefix: "33"
+    mode: primary-backup
+    destinations:
+      - pc: 1-1-1
+        ssn: 8
+        cost: 2
+      - pc: 1-1-2
+        ssn: 8
+        cost: 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pc1, _ := params.NewITUPointCode(1, 1, 1)
+	pc2, _ := params.NewITUPointCode(1, 1, 2)
+	want := []gtt.Rule{{
+		Name:   "backup",
+		Prefix: "33",
+		Mode:   gtt.ModePrimaryBackup,
+		Destinations: []gtt.Destination{
+			{PointCode: pc1, SSN: 8, Cost: 2},
+			{PointCode: pc2, SSN: 8, Cost: 1},
+		},
+	}}
+	if !verify.Values(t, "rules", table.Rules(), want) {
+		t.Fail()
+	}
+
+	if _, err := gtt.LoadJSON(strings.NewReader(`{"rules": [{"mode": "sls", "destinations": [{"pc": 1}, {"pc": 2}]}]}`)); err != nil {
+		t.Errorf("JSON: %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []struct {
+		description string
+		load        func(io.Reader) (*gtt.Table, error)
+		config      string
+		line        int
+		err         error
+	}{
+		{
+			description: "YAML invalid prefix",
+			load:        gtt.LoadYAML,
+			config:      "rules:\n  - name: ok\n    prefix: \"44\"\n  - name: bad\n    prefix: \"44x\"\n",
+			line:        4,
+			err:         gtt.ErrInvalidRule,
+		}, {
+			description: "YAML unknown key",
+			load:        gtt.LoadYAML,
+			config:      "rules:\n  - name: typo\n    prefx: \"44\"\n",
+			line:        3,
+		}, {
+			description: "YAML invalid NP",
+			load:        gtt.LoadYAML,
+			config:      "rules:\n  - name: np\n    np: E.999\n",
+			line:        2,
+		}, {
+			description: "YAML unknown key in destination",
+			load:        gtt.LoadYAML,
+			config:      "rules:\n  - name: typo\n    destinations:\n      - pc: 1\n        wieght: 2\n",
+			line:        5,
+		}, {
+			description: "YAML unknown mode",
+			load:        gtt.LoadYAML,
+			config:      "rules:\n  - name: mode\n    mode: random\n",
+			line:        2,
+		}, {
+			description: "JSON invalid PC",
+			load:        gtt.LoadJSON,
+			config:      "{\"rules\": [\n  {\"name\": \"ok\"},\n  {\"name\": \"pc\", \"pc\": \"9-999-9\"}\n]}",
+			line:        3,
+		}, {
+			description: "JSON unknown field",
+			load:        gtt.LoadJSON,
+			config:      "{\"rules\": [\n  {\"name\": \"typo\", \"prefx\": \"44\"}\n]}",
+			line:        2,
+		}, {
+			description: "JSON syntax",
+			load:        gtt.LoadJSON,
+			config:      "{\"rules\": [\n  {\"name\": }\n]}",
+			line:        2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			_, err := c.load(strings.NewReader(c.config))
+			var cerr *gtt.ConfigError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("got %v, want ConfigError", err)
+			}
+			if cerr.Line != c.line {
+				t.Errorf("got line %d, want %d: %v", cerr.Line, c.line, err)
+			}
+			if c.err != nil && !errors.Is(err, c.err) {
+				t.Errorf("got %v, want %v", err, c.err)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "rules.yml")
+	if err := os.WriteFile(path, []byte("rules:\n  - name: bad\n    prefix: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := gtt.LoadFile(path)
+	var cerr *gtt.ConfigError
+	if !errors.As(err, &cerr) || cerr.File != path {
+		t.Errorf("got %v, want ConfigError in %s", err, path)
+	}
+
+	if _, err := gtt.LoadFile(filepath.Join(dir, "rules.txt")); err == nil {
+		t.Error("no error for missing file")
+	}
+	path = filepath.Join(dir, "rules.toml")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gtt.LoadFile(path); !errors.Is(err, gtt.ErrUnknownFormat) {
+		t.Errorf("got %v, want %v", err, gtt.ErrUnknownFormat)
+	}
+}
+
+func TestEngine(t *testing.T) {
+	old, err := gtt.NewTable(
+		gtt.Rule{Name: "uk", Prefix: "44", PointCode: 1},
+		gtt.Rule{Name: "jp", Prefix: "81", PointCode: 2},
+		gtt.Rule{Name: "fr", Prefix: "33", PointCode: 3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := gtt.NewEngine(old)
+
+	res, err := engine.Translate(e164(t, "4412"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PointCode != 1 {
+		t.Errorf("got PC %s, want 1", res.PointCode)
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	config := `
+rules:
+  - name: uk
+    prefix: "44"
+    pc: 1
+  - name: jp
+    prefix: "81"
+    pc: 20
+  - name: de
+    prefix: "49"
+    pc: 4
+`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := engine.ReloadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &gtt.Diff{
+		Added:   []gtt.Rule{{Name: "de", Prefix: "49", PointCode: 4}},
+		Removed: []gtt.Rule{{Name: "fr", Prefix: "33", PointCode: 3}},
+		Changed: []gtt.RuleChange{{
+			Old: gtt.Rule{Name: "jp", Prefix: "81", PointCode: 2},
+			New: gtt.Rule{Name: "jp", Prefix: "81", PointCode: 20},
+		}},
+	}
+	if !verify.Values(t, "diff", diff, want) {
+		t.Fail()
+	}
+	if res, err := engine.Translate(e164(t, "8112")); err != nil || res.PointCode != 20 {
+		t.Errorf("after reload: got %v, %v, want PC 20", res, err)
+	}
+
+	// the Table in use is kept on error.
+	if err := os.WriteFile(path, []byte("rules:\n  - prefix: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.ReloadFile(path); err == nil {
+		t.Error("no error for invalid file")
+	}
+	if got := len(engine.Table().Rules()); got != 3 {
+		t.Errorf("got %d rules after failed reload, want 3", got)
+	}
+
+	if diff := engine.Swap(engine.Table()); !diff.Empty() {
+		t.Errorf("got diff with the same table: %s", diff)
+	}
+}
+
+func TestEngineConcurrentSwap(t *testing.T) {
+	tables := make([]*gtt.Table, 2)
+	for i := range tables {
+		table, err := gtt.NewTable(gtt.Rule{Prefix: "44", PointCode: params.PointCode(i + 1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables[i] = table
+	}
+	engine := gtt.NewEngine(tables[0])
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			engine.Swap(tables[i%2])
+		}
+	}()
+
+	addr := e164(t, "4412")
+	for i := 0; i < 1000; i++ {
+		res, err := engine.Translate(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pc := res.PointCode; pc != 1 && pc != 2 {
+			t.Fatalf("got PC %s", pc)
+		}
+	}
+	<-done
+}
+
+func TestTranslateBatch(t *testing.T) {
+	table, err := gtt.NewTable(
+		gtt.Rule{Name: "uk", Prefix: "44", PointCode: 1},
+		gtt.Rule{Name: "jp", Prefix: "81", PointCode: 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var addrs []*params.PartyAddress
+	var want []params.PointCode
+	for i := 0; i < 100; i++ {
+		switch i % 3 {
+		case 0:
+			addrs, want = append(addrs, e164(t, fmt.Sprintf("44%04d", i))), append(want, 1)
+		case 1:
+			addrs, want = append(addrs, e164(t, fmt.Sprintf("81%04d", i))), append(want, 2)
+		default:
+			addrs, want = append(addrs, e164(t, fmt.Sprintf("33%04d", i))), append(want, 0)
+		}
+	}
+
+	for _, workers := range []int{0, 1, 4, 200} {
+		t.Run(fmt.Sprintf("workers %d", workers), func(t *testing.T) {
+			results := gtt.NewEngine(table).TranslateBatch(addrs, workers)
+
+			got := make([]params.PointCode, len(results))
+			for i, r := range results {
+				switch {
+				case r.Err != nil && !errors.Is(r.Err, gtt.ErrNoTranslationForAddress):
+					t.Fatalf("%d: unexpected error %v", i, r.Err)
+				case r.Err == nil:
+					got[i] = r.Result.PointCode
+				}
+			}
+			if !verify.Values(t, "point codes", got, want) {
+				t.Fail()
+			}
+		})
+	}
+}
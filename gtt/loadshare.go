@@ -0,0 +1,172 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Destination is one of the destinations a Rule shares the load among.
+type Destination struct {
+	// PointCode is the DPC the message is routed to. The PC in the address
+	// is not changed if it is 0.
+	PointCode params.PointCode
+	// SSN is set in the translated address if it is not 0.
+	SSN uint8
+	// Weight is the share of the messages sent to the Destination relative
+	// to the others, which is 1 if it is 0. It is ignored in ModePrimaryBackup.
+	Weight int
+	// Cost is the preference in ModePrimaryBackup; the Destination with the
+	// lowest Cost is used while it is available.
+	Cost int
+}
+
+// String returns the Destination in a human-readable format.
+func (d Destination) String() string {
+	return fmt.Sprintf("{PC: %s, SSN: %d, Weight: %d, Cost: %d}", d.PointCode, d.SSN, d.Weight, d.Cost)
+}
+
+func (d *Destination) weight() int {
+	return max(1, d.Weight)
+}
+
+// LoadSharingMode is the way a Rule selects one of the Destinations.
+type LoadSharingMode uint8
+
+// LoadSharingMode definitions.
+const (
+	// ModeRoundRobin selects the Destinations in turn, in proportion to the
+	// Weight.
+	ModeRoundRobin LoadSharingMode = iota
+	// ModeSLS selects the Destination by the SLS given to TranslateSLS, in
+	// proportion to the Weight, so that the messages with the same SLS are
+	// sent to the same Destination and kept in sequence.
+	ModeSLS
+	// ModePrimaryBackup selects the Destination with the lowest Cost, and
+	// the others are the alternates in the order of the Cost.
+	ModePrimaryBackup
+)
+
+var loadSharingModeNames = [...]string{
+	ModeRoundRobin:    "round-robin",
+	ModeSLS:           "sls",
+	ModePrimaryBackup: "primary-backup",
+}
+
+// String returns the name of the LoadSharingMode.
+func (m LoadSharingMode) String() string {
+	if int(m) < len(loadSharingModeNames) {
+		return loadSharingModeNames[m]
+	}
+	return fmt.Sprintf("LoadSharingMode(%d)", uint8(m))
+}
+
+// MarshalText returns the name of the LoadSharingMode.
+func (m LoadSharingMode) MarshalText() ([]byte, error) {
+	if int(m) >= len(loadSharingModeNames) {
+		return nil, fmt.Errorf("unknown load sharing mode %d", uint8(m))
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText sets the LoadSharingMode from its name.
+func (m *LoadSharingMode) UnmarshalText(text []byte) error {
+	for i, name := range loadSharingModeNames {
+		if strings.EqualFold(name, string(text)) {
+			*m = LoadSharingMode(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown load sharing mode %q", text)
+}
+
+// validateDestinations checks the Destinations of the Rule.
+func (r *Rule) validateDestinations() error {
+	if len(r.Destinations) == 0 {
+		return nil
+	}
+
+	if int(r.Mode) >= len(loadSharingModeNames) {
+		return fmt.Errorf("%s: %w", r.Mode, ErrInvalidRule)
+	}
+	if r.PointCode != 0 || r.SSN != 0 {
+		return fmt.Errorf("PC or SSN with destinations: %w", ErrInvalidRule)
+	}
+	for i, d := range r.Destinations {
+		if d.Weight < 0 {
+			return fmt.Errorf("destination %d: negative weight: %w", i, ErrInvalidRule)
+		}
+		if r.RouteOnSSN && d.SSN == 0 {
+			return fmt.Errorf("destination %d: route on SSN without SSN: %w", i, ErrInvalidRule)
+		}
+	}
+	return nil
+}
+
+// selector selects the Destination of a Rule.
+type selector struct {
+	// dsts is the Destinations of the Rule, sorted by the Cost in
+	// ModePrimaryBackup.
+	dsts  []Destination
+	mode  LoadSharingMode
+	total int
+	next  atomic.Uint64
+}
+
+func newSelector(r *Rule) *selector {
+	s := &selector{mode: r.Mode}
+	if len(r.Destinations) == 0 {
+		s.dsts = []Destination{{PointCode: r.PointCode, SSN: r.SSN}}
+	} else {
+		s.dsts = slices.Clone(r.Destinations)
+	}
+
+	if s.mode == ModePrimaryBackup {
+		slices.SortStableFunc(s.dsts, func(a, b Destination) int {
+			return a.Cost - b.Cost
+		})
+	}
+	for i := range s.dsts {
+		s.total += s.dsts[i].weight()
+	}
+	return s
+}
+
+// selectDestination returns the Destination selected and the alternates in
+// the order they should be tried.
+func (s *selector) selectDestination(sls uint8) (Destination, []Destination) {
+	if len(s.dsts) == 1 {
+		return s.dsts[0], nil
+	}
+
+	i := 0
+	switch s.mode {
+	case ModeRoundRobin:
+		i = s.index(int((s.next.Add(1) - 1) % uint64(s.total)))
+	case ModeSLS:
+		i = s.index(int(sls) % s.total)
+	}
+
+	alts := make([]Destination, 0, len(s.dsts)-1)
+	alts = append(alts, s.dsts[i+1:]...)
+	alts = append(alts, s.dsts[:i]...)
+	return s.dsts[i], alts
+}
+
+// index returns the index of the Destination that n falls on when the
+// Destinations are laid out with their Weight.
+func (s *selector) index(n int) int {
+	for i := range s.dsts {
+		if n -= s.dsts[i].weight(); n < 0 {
+			return i
+		}
+	}
+	return len(s.dsts) - 1
+}
@@ -0,0 +1,209 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Result is the result of the translation.
+type Result struct {
+	// Rule is the Rule that matched the GT.
+	Rule Rule
+	// PointCode is the DPC to route the message to, which is the one in the
+	// original address if the Rule does not specify it.
+	PointCode params.PointCode
+	// Address is the translated Called Party Address to put in the message
+	// forwarded. The original address is not modified.
+	Address *params.PartyAddress
+	// Alternates are the other Destinations of the Rule in the order they
+	// should be tried when the selected one is not available, which can be
+	// given to Failover.
+	Alternates []Destination
+}
+
+// Failover returns a copy of the Result routed to d instead, which is usually
+// one of the Alternates.
+func (r *Result) Failover(d Destination) *Result {
+	a := r.Address.Clone()
+	setDestination(a, d)
+	a.SetLength()
+
+	return &Result{Rule: r.Rule, PointCode: a.SignalingPointCode, Address: a, Alternates: r.Alternates}
+}
+
+// String returns the Result in a human-readable format.
+func (r *Result) String() string {
+	return fmt.Sprintf("{Rule: %s, PointCode: %s, Address: %v}", r.Rule.Name, r.PointCode, r.Address)
+}
+
+// result builds the Result of the Rule for addr routed to d.
+func (r *Rule) result(addr *params.PartyAddress, gt *globalTitle, d Destination, alts []Destination) (*Result, error) {
+	a := addr.Clone()
+	setDestination(a, d)
+	if r.RouteOnSSN {
+		a.SetRoutingIndicator(params.RIRouteOnSSN)
+	}
+	if r.Digits != "" || !r.Modification.isZero() {
+		g, err := gt.modify(r.Digits, &r.Modification)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		a.SetGlobalTitle(g.encode())
+	}
+	a.SetLength()
+
+	return &Result{Rule: *r, PointCode: a.SignalingPointCode, Address: a, Alternates: alts}, nil
+}
+
+func setDestination(a *params.PartyAddress, d Destination) {
+	if d.PointCode != 0 {
+		a.SetHasPC(true)
+		a.SignalingPointCode = d.PointCode
+	}
+	if d.SSN != 0 {
+		a.SetHasSSN(true)
+		a.SubsystemNumber = d.SSN
+	}
+}
+
+// Modification is the changes made to the GT in the translated address, in
+// addition to the Digits of the Rule.
+type Modification struct {
+	// StripDigits is the number of the leading digits removed from the GT.
+	StripDigits int
+	// PrependDigits is added to the beginning of the digits after stripped.
+	PrependDigits string
+
+	// TranslationType, NumberingPlan and NatureOfAddress replace the ones in
+	// the GT if not nil. The GT that does not have the field in its format is
+	// converted to the one with all of them (GTI=0100).
+	TranslationType *params.TranslationType
+	NumberingPlan   *params.NumberingPlan
+	NatureOfAddress *params.NatureOfAddressIndicator
+}
+
+func (m *Modification) isZero() bool {
+	return m.StripDigits == 0 && m.PrependDigits == "" &&
+		m.TranslationType == nil && m.NumberingPlan == nil && m.NatureOfAddress == nil
+}
+
+func (m *Modification) validate() error {
+	if m.StripDigits < 0 {
+		return fmt.Errorf("negative digits to strip: %w", ErrInvalidRule)
+	}
+	if err := params.ValidateDigits(m.PrependDigits); err != nil {
+		return fmt.Errorf("digits to prepend: %w: %w", ErrInvalidRule, err)
+	}
+	return nil
+}
+
+// String returns the Modification in a human-readable format.
+func (m *Modification) String() string {
+	return fmt.Sprintf("{StripDigits: %d, PrependDigits: %q, TT: %s, NP: %s, NAI: %s}",
+		m.StripDigits, m.PrependDigits, optional(m.TranslationType), optional(m.NumberingPlan), optional(m.NatureOfAddress),
+	)
+}
+
+// globalTitle is the fields of the GT used in the translation. The fields
+// that are not in the format of the GT are nil.
+type globalTitle struct {
+	gti    params.GlobalTitleIndicator
+	tt     *params.TranslationType
+	np     *params.NumberingPlan
+	nai    *params.NatureOfAddressIndicator
+	digits string
+}
+
+func globalTitleOf(addr *params.PartyAddress) (*globalTitle, error) {
+	if addr == nil || addr.GlobalTitle == nil {
+		return nil, ErrNoGlobalTitle
+	}
+
+	g := &globalTitle{gti: addr.GlobalTitle.GTI(), digits: addr.GlobalTitle.Address()}
+	switch gt := addr.GlobalTitle.(type) {
+	case *params.GTNAIOnly:
+		g.nai = &gt.NatureOfAddressIndicator
+	case *params.GTTTOnly:
+		g.tt = &gt.TranslationType
+	case *params.GTTTNPES:
+		g.tt, g.np = &gt.TranslationType, &gt.NumberingPlan
+		if !gt.EncodingScheme.IsBCD() {
+			return nil, fmt.Errorf("encoding scheme %s: %w", gt.EncodingScheme, ErrNoTranslationForNature)
+		}
+	case *params.GTTTNPESNAI:
+		g.tt, g.np, g.nai = &gt.TranslationType, &gt.NumberingPlan, &gt.NatureOfAddressIndicator
+		if !gt.EncodingScheme.IsBCD() {
+			return nil, fmt.Errorf("encoding scheme %s: %w", gt.EncodingScheme, ErrNoTranslationForNature)
+		}
+	default:
+		return nil, fmt.Errorf("GTI %d: %w", g.gti, ErrNoTranslationForNature)
+	}
+
+	return g, nil
+}
+
+// modify returns the copy of the GT with digits, or the digits stripped and
+// prepended if it is empty, and the fields replaced by m.
+func (g *globalTitle) modify(digits string, m *Modification) (*globalTitle, error) {
+	if digits == "" {
+		digits = g.digits[min(m.StripDigits, len(g.digits)):]
+		digits = m.PrependDigits + digits
+	}
+	if err := params.ValidateDigits(digits); err != nil {
+		return nil, fmt.Errorf("modified digits: %w", err)
+	}
+
+	n := *g
+	n.digits = digits
+	if m.TranslationType != nil {
+		n.tt = m.TranslationType
+	}
+	if m.NumberingPlan != nil {
+		n.np = m.NumberingPlan
+	}
+	if m.NatureOfAddress != nil {
+		n.nai = m.NatureOfAddress
+	}
+
+	// convert to the format that has all the fields if any is added.
+	if n.tt != nil && g.tt == nil || n.np != nil && g.np == nil || n.nai != nil && g.nai == nil {
+		n.gti = params.GTITTNPESNAI
+	}
+	return &n, nil
+}
+
+// encode returns the GT as the GlobalTitle with the digits in BCD.
+func (g *globalTitle) encode() params.GlobalTitle {
+	es := params.ESBCDEven
+	if len(g.digits)%2 == 1 {
+		es = params.ESBCDOdd
+	}
+	// the digits are validated by modify.
+	addr, _ := es.EncodeAddress(g.digits)
+
+	var (
+		tt  params.TranslationType
+		np  params.NumberingPlan
+		nai params.NatureOfAddressIndicator
+	)
+	if g.tt != nil {
+		tt = *g.tt
+	}
+	if g.np != nil {
+		np = *g.np
+	}
+	if g.nai != nil {
+		nai = *g.nai
+	}
+	return params.NewGlobalTitle(g.gti, tt, np, es, nai, addr)
+}
+
+// String returns the GT in a human-readable format.
+func (g *globalTitle) String() string {
+	return fmt.Sprintf("GT %q (TT: %s, NP: %s, NAI: %s)", g.digits, optional(g.tt), optional(g.np), optional(g.nai))
+}
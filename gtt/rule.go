@@ -0,0 +1,122 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Rule is a translation rule.
+//
+// The GT matches the Rule when its digits start with Prefix and it has the
+// TranslationType, NumberingPlan and NatureOfAddress that are not nil. The
+// GT that does not have the field in its format (e.g., NP in GTI=0010) does
+// not match the Rule that specifies it.
+//
+// Prefix may have the wildcard "?" that matches any digit, and the classes in
+// brackets that match one of the digits or ranges in them, e.g., "4479[0-5]"
+// matches 447900 to 447959... and "81[13]?" matches 8110 to 813f. Each of them
+// counts as a digit in the length of the Prefix.
+type Rule struct {
+	// Name is the name of the Rule for the logs and errors.
+	Name string
+
+	Prefix          string
+	TranslationType *params.TranslationType
+	NumberingPlan   *params.NumberingPlan
+	NatureOfAddress *params.NatureOfAddressIndicator
+
+	// PointCode is the DPC the message is routed to. The PC in the address
+	// is not changed if it is 0.
+	PointCode params.PointCode
+	// SSN is set in the translated address if it is not 0.
+	SSN uint8
+	// Destinations are the destinations to share the load among in the
+	// Mode, which cannot be used with PointCode and SSN.
+	Destinations []Destination
+	Mode         LoadSharingMode
+	// RouteOnSSN sets the routing indicator of the translated address to
+	// "route on SSN", which means the destination is the final one.
+	RouteOnSSN bool
+	// Digits replaces the digits of the GT if it is not empty.
+	Digits string
+	// Modification is the changes made to the GT, e.g., stripping the
+	// country code, which cannot be used with Digits to change the digits.
+	Modification Modification
+}
+
+func (r *Rule) validate() error {
+	if _, err := parsePattern(r.Prefix); err != nil {
+		return fmt.Errorf("prefix: %w: %w", ErrInvalidRule, err)
+	}
+	if err := params.ValidateDigits(r.Digits); err != nil {
+		return fmt.Errorf("digits: %w: %w", ErrInvalidRule, err)
+	}
+	if err := r.Modification.validate(); err != nil {
+		return fmt.Errorf("modification: %w", err)
+	}
+	if r.Digits != "" && (r.Modification.StripDigits != 0 || r.Modification.PrependDigits != "") {
+		return fmt.Errorf("digits with strip or prepend: %w", ErrInvalidRule)
+	}
+	if err := r.validateDestinations(); err != nil {
+		return err
+	}
+	if r.RouteOnSSN && r.SSN == 0 && len(r.Destinations) == 0 {
+		return fmt.Errorf("route on SSN without SSN: %w", ErrInvalidRule)
+	}
+
+	return nil
+}
+
+// nature returns the TT, NP and NAI of the Rule.
+func (r *Rule) nature() nature {
+	n := nature{tt: -1, np: -1, nai: -1}
+	if r.TranslationType != nil {
+		n.tt = int(*r.TranslationType)
+	}
+	if r.NumberingPlan != nil {
+		n.np = int(*r.NumberingPlan)
+	}
+	if r.NatureOfAddress != nil {
+		n.nai = int(*r.NatureOfAddress)
+	}
+	return n
+}
+
+// matchNature reports whether the TT, NP and NAI of gt match the Rule.
+func (r *Rule) matchNature(gt *globalTitle) bool {
+	return r.nature().match(gt)
+}
+
+// match reports whether the TT, NP and NAI of gt match n.
+func (n nature) match(gt *globalTitle) bool {
+	if n.tt >= 0 && (gt.tt == nil || int(*gt.tt) != n.tt) {
+		return false
+	}
+	if n.np >= 0 && (gt.np == nil || int(*gt.np) != n.np) {
+		return false
+	}
+	if n.nai >= 0 && (gt.nai == nil || int(*gt.nai) != n.nai) {
+		return false
+	}
+	return true
+}
+
+// String returns the Rule in a human-readable format.
+func (r *Rule) String() string {
+	return fmt.Sprintf("{Name: %s, Prefix: %q, TT: %s, NP: %s, NAI: %s, PC: %s, SSN: %d, Destinations: %v, Mode: %s, RouteOnSSN: %v, Digits: %q, Modification: %s}",
+		r.Name, r.Prefix, optional(r.TranslationType), optional(r.NumberingPlan), optional(r.NatureOfAddress),
+		r.PointCode, r.SSN, r.Destinations, r.Mode, r.RouteOnSSN, r.Digits, &r.Modification,
+	)
+}
+
+func optional[T fmt.Stringer](v *T) string {
+	if v == nil {
+		return "any"
+	}
+	return (*v).String()
+}
@@ -0,0 +1,133 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Trace is how the GT was translated, which is returned by TranslateWithTrace
+// to see why the message is routed to the destination.
+type Trace struct {
+	// Digits is the digits of the GT translated.
+	Digits string
+	// Steps are the rules whose Prefix matches the Digits in the order they
+	// were considered, i.e., from the longest Prefix. The last one is the rule
+	// used if it is Matched.
+	Steps []TraceStep
+	// Modifications are the changes made to the address by the rule used,
+	// in a human-readable format, e.g., `digits: "4412" -> "04412"`.
+	Modifications []string
+}
+
+// TraceStep is a rule considered in the translation.
+type TraceStep struct {
+	Rule Rule
+	// Matched reports whether the TT, NP and NAI also match the GT.
+	Matched bool
+	// Reason is why the rule did not match, e.g., "TT 0 != 3".
+	Reason string
+}
+
+// String returns the Trace in a human-readable format, a line for each step
+// and modification.
+func (t *Trace) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digits: %q\n", t.Digits)
+	for _, s := range t.Steps {
+		if s.Matched {
+			fmt.Fprintf(&b, "  matched %s\n", &s.Rule)
+		} else {
+			fmt.Fprintf(&b, "  skipped %s: %s\n", &s.Rule, s.Reason)
+		}
+	}
+	for _, m := range t.Modifications {
+		fmt.Fprintf(&b, "  modified %s\n", m)
+	}
+	return b.String()
+}
+
+// TranslateWithTrace is the same as TranslateSLS, but also returns the Trace
+// of the translation. The Trace is also returned with the error caused by the
+// rules, e.g., ErrNoTranslationForAddress, and is nil if addr does not have
+// the GT to translate.
+//
+// It is slower than TranslateSLS, and is meant for debugging.
+func (t *Table) TranslateWithTrace(addr *params.PartyAddress, sls uint8) (*Result, *Trace, error) {
+	return t.translate(addr, sls, true)
+}
+
+// step adds the Rule r considered for gt.
+func (t *Trace) step(r *Rule, gt *globalTitle, matched bool) {
+	s := TraceStep{Rule: *r, Matched: matched}
+	if !matched {
+		s.Reason = r.nature().mismatch(gt)
+	}
+	t.Steps = append(t.Steps, s)
+}
+
+// mismatch returns why gt does not match n.
+func (n nature) mismatch(gt *globalTitle) string {
+	var reasons []string
+	if n.tt >= 0 && (gt.tt == nil || int(*gt.tt) != n.tt) {
+		reasons = append(reasons, fmt.Sprintf("TT %s != %s", optional(gt.tt), params.TranslationType(n.tt)))
+	}
+	if n.np >= 0 && (gt.np == nil || int(*gt.np) != n.np) {
+		reasons = append(reasons, fmt.Sprintf("NP %s != %s", optional(gt.np), params.NumberingPlan(n.np)))
+	}
+	if n.nai >= 0 && (gt.nai == nil || int(*gt.nai) != n.nai) {
+		reasons = append(reasons, fmt.Sprintf("NAI %s != %s", optional(gt.nai), params.NatureOfAddressIndicator(n.nai)))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// modifications adds the changes from the original address to translated,
+// whose GT was gt.
+func (t *Trace) modifications(original *params.PartyAddress, gt *globalTitle, translated *params.PartyAddress) {
+	add := func(field string, from, to any) {
+		if from != to {
+			t.Modifications = append(t.Modifications, fmt.Sprintf("%s: %v -> %v", field, from, to))
+		}
+	}
+
+	add("RI", routingOf(original), routingOf(translated))
+	add("PC", pointCodeOf(original), pointCodeOf(translated))
+	add("SSN", ssnOf(original), ssnOf(translated))
+
+	// the GT in translated is always the one that can be translated.
+	g, err := globalTitleOf(translated)
+	if err != nil {
+		return
+	}
+	add("GTI", gt.gti, g.gti)
+	add("TT", optional(gt.tt), optional(g.tt))
+	add("NP", optional(gt.np), optional(g.np))
+	add("NAI", optional(gt.nai), optional(g.nai))
+	add("digits", fmt.Sprintf("%q", gt.digits), fmt.Sprintf("%q", g.digits))
+}
+
+func routingOf(a *params.PartyAddress) string {
+	if a.RouteOnSSN() {
+		return "route on SSN"
+	}
+	return "route on GT"
+}
+
+func pointCodeOf(a *params.PartyAddress) string {
+	if !a.HasPC() {
+		return "none"
+	}
+	return a.SignalingPointCode.String()
+}
+
+func ssnOf(a *params.PartyAddress) string {
+	if !a.HasSSN() {
+		return "none"
+	}
+	return fmt.Sprint(a.SubsystemNumber)
+}
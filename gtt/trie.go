@@ -0,0 +1,165 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtt
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// digitSet is the set of the digits (0-f) that match a position of the
+// pattern, in which the n-th bit is set if the digit n matches.
+type digitSet uint16
+
+const anyDigit digitSet = 0xffff
+
+// maxExpansion is the maximum number of the digit strings a pattern can
+// match, which limits the nodes added to the trie for a rule.
+const maxExpansion = 4096
+
+// parseDigit returns the value of the BCD digit c, or -1 if it is not a digit.
+func parseDigit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	default:
+		return -1
+	}
+}
+
+// parsePattern parses the Prefix of the Rule, which consists of the digits,
+// the wildcard "?" that matches any digit, and the classes in brackets that
+// match one of the digits or ranges in them, e.g., "4479[0-5]" or "81[13]?".
+func parsePattern(p string) ([]digitSet, error) {
+	var sets []digitSet
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '?':
+			sets = append(sets, anyDigit)
+		case '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated class at %d in %q", i, p)
+			}
+			set, err := parseClass(p[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("class at %d in %q: %w", i, p, err)
+			}
+			sets = append(sets, set)
+			i += end
+		default:
+			d := parseDigit(c)
+			if d < 0 {
+				return nil, fmt.Errorf("invalid character %q at %d in %q: %w", c, i, p, params.ErrInvalidDigits)
+			}
+			sets = append(sets, 1<<d)
+		}
+	}
+
+	if len(sets) > params.MaxGlobalTitleDigits {
+		return nil, fmt.Errorf("%d digits exceed the maximum %d: %w", len(sets), params.MaxGlobalTitleDigits, params.ErrInvalidDigits)
+	}
+
+	n := 1
+	for _, set := range sets {
+		if n *= bits.OnesCount16(uint16(set)); n > maxExpansion {
+			return nil, fmt.Errorf("%q matches more than %d digit strings", p, maxExpansion)
+		}
+	}
+	return sets, nil
+}
+
+// parseClass parses the inside of the brackets, e.g., "0-5" or "139".
+func parseClass(class string) (digitSet, error) {
+	var set digitSet
+	for i := 0; i < len(class); i++ {
+		lo := parseDigit(class[i])
+		if lo < 0 {
+			return 0, fmt.Errorf("invalid character %q: %w", class[i], params.ErrInvalidDigits)
+		}
+		hi := lo
+		if i+2 < len(class) && class[i+1] == '-' {
+			if hi = parseDigit(class[i+2]); hi < lo {
+				return 0, fmt.Errorf("invalid range %q: %w", class[i:i+3], params.ErrInvalidDigits)
+			}
+			i += 2
+		}
+		for d := lo; d <= hi; d++ {
+			set |= 1 << d
+		}
+	}
+
+	if set == 0 {
+		return 0, fmt.Errorf("empty class: %w", params.ErrInvalidDigits)
+	}
+	return set, nil
+}
+
+// trie is the index of the rules by the digits of the Prefix.
+//
+// The classes and the wildcards are expanded when the rule is inserted, so
+// that the lookup only follows a single path with the digits of the GT.
+type trie struct {
+	root node
+}
+
+type node struct {
+	children [16]*node
+	// rules is the indices of the rules whose Prefix ends at the node, in
+	// the order they are given to the Table.
+	rules []int
+}
+
+// insert adds the index of the rule with the pattern to the trie.
+func (t *trie) insert(pattern []digitSet, rule int) {
+	t.root.insert(pattern, rule)
+}
+
+func (n *node) insert(pattern []digitSet, rule int) {
+	if len(pattern) == 0 {
+		n.rules = append(n.rules, rule)
+		return
+	}
+
+	for d := range n.children {
+		if pattern[0]&(1<<d) == 0 {
+			continue
+		}
+		if n.children[d] == nil {
+			n.children[d] = &node{}
+		}
+		n.children[d].insert(pattern[1:], rule)
+	}
+}
+
+// lookup calls match with the rules whose Prefix matches digits, from the
+// longest Prefix to the shortest, until it returns true.
+func (t *trie) lookup(digits string, match func(rule int) bool) bool {
+	path := []*node{&t.root}
+	for n := &t.root; len(path) <= len(digits); {
+		d := parseDigit(digits[len(path)-1])
+		if d < 0 || n.children[d] == nil {
+			break
+		}
+		n = n.children[d]
+		path = append(path, n)
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		for _, r := range path[i].rules {
+			if match(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
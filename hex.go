@@ -0,0 +1,165 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/utils"
+)
+
+// ParseHexMessage decodes the hex dump in s into Message by Message Type.
+//
+// The whitespace and the separators are ignored, and the hex dumps copied from
+// Wireshark are accepted. See utils.DecodeHexDump for the supported formats.
+func ParseHexMessage(s string, opts ...ParseOption) (Message, error) {
+	return codecOf(opts).ParseHexMessage(s)
+}
+
+// ParseHexMessage decodes the hex dump in s into Message by Message Type.
+func (c *Codec) ParseHexMessage(s string) (Message, error) {
+	b, err := utils.DecodeHexDump(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex dump: %w", err)
+	}
+
+	return c.ParseMessage(b)
+}
+
+// hexString returns the byte sequence of m in hex separated by spaces, or
+// the empty string if m cannot be serialized.
+func hexString(m interface{ MarshalBinary() ([]byte, error) }) string {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("% x", b)
+}
+
+// HexString returns the byte sequence of the CR in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (c *CR) HexString() string {
+	return hexString(c)
+}
+
+// HexString returns the byte sequence of the CC in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (c *CC) HexString() string {
+	return hexString(c)
+}
+
+// HexString returns the byte sequence of the CREF in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (c *CREF) HexString() string {
+	return hexString(c)
+}
+
+// HexString returns the byte sequence of the RLSD in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (r *RLSD) HexString() string {
+	return hexString(r)
+}
+
+// HexString returns the byte sequence of the RLC in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (r *RLC) HexString() string {
+	return hexString(r)
+}
+
+// HexString returns the byte sequence of the DT1 in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (d *DT1) HexString() string {
+	return hexString(d)
+}
+
+// HexString returns the byte sequence of the DT2 in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (d *DT2) HexString() string {
+	return hexString(d)
+}
+
+// HexString returns the byte sequence of the AK in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (a *AK) HexString() string {
+	return hexString(a)
+}
+
+// HexString returns the byte sequence of the UDT in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (u *UDT) HexString() string {
+	return hexString(u)
+}
+
+// HexString returns the byte sequence of the UDTS in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (u *UDTS) HexString() string {
+	return hexString(u)
+}
+
+// HexString returns the byte sequence of the ED in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (e *ED) HexString() string {
+	return hexString(e)
+}
+
+// HexString returns the byte sequence of the EA in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (e *EA) HexString() string {
+	return hexString(e)
+}
+
+// HexString returns the byte sequence of the RSR in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (r *RSR) HexString() string {
+	return hexString(r)
+}
+
+// HexString returns the byte sequence of the RSC in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (r *RSC) HexString() string {
+	return hexString(r)
+}
+
+// HexString returns the byte sequence of the IT in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (i *IT) HexString() string {
+	return hexString(i)
+}
+
+// HexString returns the byte sequence of the XUDT in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (x *XUDT) HexString() string {
+	return hexString(x)
+}
+
+// HexString returns the byte sequence of the XUDTS in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (x *XUDTS) HexString() string {
+	return hexString(x)
+}
+
+// HexString returns the byte sequence of the LUDT in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (l *LUDT) HexString() string {
+	return hexString(l)
+}
+
+// HexString returns the byte sequence of the LUDTS in hex separated by spaces,
+// which can be decoded by ParseHexMessage.
+func (l *LUDTS) HexString() string {
+	return hexString(l)
+}
+
+// HexString returns the byte sequence of the RawMessage in hex separated by
+// spaces, which can be decoded by ParseHexMessage if the type is registered.
+func (r *RawMessage) HexString() string {
+	return hexString(r)
+}
+
+// HexString returns the byte sequence of the SCMG in hex separated by spaces.
+func (s *SCMG) HexString() string {
+	return hexString(s)
+}
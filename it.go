@@ -0,0 +1,173 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// IT represents a SCCP Message Inactivity Test (IT).
+type IT struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+	ProtocolClass             *params.ProtocolClass
+	SequencingSegmenting      *params.SequencingSegmenting
+	Credit                    *params.Credit
+
+	trailing []byte
+}
+
+// NewIT creates a new IT.
+//
+// ps, pr and credit are the P(S), P(R) and the window size of the connection,
+// which are meaningful only in the protocol class 3 and should be 0 in the
+// protocol class 2.
+func NewIT(dlr, slr uint32, pcls int, ps, pr, credit uint8) *IT {
+	return &IT{
+		Type:                      MsgTypeIT,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+		ProtocolClass:             params.NewProtocolClass(pcls, false),
+		SequencingSegmenting:      params.NewSequencingSegmentingPSPR(ps, pr, false),
+		Credit:                    params.NewCredit(credit),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an IT instance.
+func (i *IT) MarshalBinary() ([]byte, error) {
+	b := make([]byte, i.MarshalLen())
+	if err := i.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (i *IT) MarshalTo(b []byte) error {
+	if err := validateProtocolClass(i.Type, i.ProtocolClass); err != nil {
+		return err
+	}
+
+	return marshalSections(b, i.Type, i.fixed(), nil, nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the IT to dst, and
+// returns the extended slice.
+func (i *IT) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, i)
+}
+
+// WriteTo writes the byte sequence generated from the IT to w. It implements
+// io.WriterTo.
+func (i *IT) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, i)
+}
+
+// fixed returns the mandatory fixed parameters of the IT.
+func (i *IT) fixed() []params.Parameter {
+	return []params.Parameter{
+		i.DestinationLocalReference,
+		i.SourceLocalReference,
+		i.ProtocolClass,
+		i.SequencingSegmenting,
+		i.Credit,
+	}
+}
+
+// ParseIT decodes given byte sequence as a SCCP IT.
+func ParseIT(b []byte) (*IT, error) {
+	i := &IT{}
+	if err := i.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP IT.
+func (i *IT) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	i.Type = MsgType(b[0])
+	i.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	i.SourceLocalReference = params.NewSourceLocalReference(0)
+	i.ProtocolClass = &params.ProtocolClass{}
+	i.SequencingSegmenting = &params.SequencingSegmenting{}
+	i.Credit = &params.Credit{}
+
+	_, n, err := params.UnmarshalSections(b[1:], i.fixed(), nil, false)
+	if err != nil {
+		return err
+	}
+	if err := validateProtocolClass(i.Type, i.ProtocolClass); err != nil {
+		return err
+	}
+
+	i.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the IT that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (i *IT) Clone() *IT {
+	c := *i
+	c.DestinationLocalReference = i.DestinationLocalReference.Clone()
+	c.SourceLocalReference = i.SourceLocalReference.Clone()
+	c.ProtocolClass = clonePtr(i.ProtocolClass)
+	c.SequencingSegmenting = clonePtr(i.SequencingSegmenting)
+	c.Credit = clonePtr(i.Credit)
+	c.trailing = bytes.Clone(i.trailing)
+
+	return &c
+}
+
+// Reset clears the IT to be reused with UnmarshalBinary.
+func (i *IT) Reset() {
+	*i = IT{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the IT when
+// it is parsed, or nil if there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (i *IT) TrailingBytes() []byte {
+	return i.trailing
+}
+
+// MarshalLen returns the serial length.
+func (i *IT) MarshalLen() int {
+	return 1 + params.SectionsLen(i.fixed(), nil, nil, false)
+}
+
+// String returns the IT values in human readable format.
+func (i *IT) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s, ProtocolClass: %s, SequencingSegmenting: %s, Credit: %s}",
+		i.Type,
+		i.DestinationLocalReference,
+		i.SourceLocalReference,
+		i.ProtocolClass,
+		i.SequencingSegmenting,
+		i.Credit,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (i *IT) MessageType() MsgType {
+	return MsgTypeIT
+}
+
+// MessageTypeName returns the Message Type in string.
+func (i *IT) MessageTypeName() string {
+	return i.MessageType().String()
+}
@@ -0,0 +1,896 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// MarshalText returns the name of the MsgType, or the value in decimal if
+// it is not defined in Q.713.
+func (t MsgType) MarshalText() ([]byte, error) {
+	if t < MsgTypeCR || t > MsgTypeLUDTS {
+		return strconv.AppendUint(nil, uint64(t), 10), nil
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText accepts both the name and the value in decimal.
+func (t *MsgType) UnmarshalText(b []byte) error {
+	for i := MsgTypeCR; i <= MsgTypeLUDTS; i++ {
+		if i.String() == string(b) {
+			*t = i
+			return nil
+		}
+	}
+
+	v, err := strconv.ParseUint(string(b), 10, 8)
+	if err != nil {
+		return fmt.Errorf("invalid message type %q: %w", b, err)
+	}
+
+	*t = MsgType(v)
+	return nil
+}
+
+// hexBytes is the byte sequence in the hex string in JSON.
+type hexBytes []byte
+
+func (h hexBytes) MarshalText() ([]byte, error) {
+	return hex.AppendEncode(nil, h), nil
+}
+
+func (h *hexBytes) UnmarshalText(b []byte) error {
+	v, err := hex.AppendDecode(nil, b)
+	if err != nil {
+		return err
+	}
+
+	*h = v
+	return nil
+}
+
+// messageJSON is the JSON representation of all the messages, which is the
+// set of the parameters in readable form.
+//
+// The fields of the parameters that are not in the message, or are optional
+// and not present, are omitted.
+type messageJSON struct {
+	Type                      MsgType                     `json:"type"`
+	DestinationLocalReference *uint32                     `json:"destinationLocalReference,omitempty"`
+	SourceLocalReference      *uint32                     `json:"sourceLocalReference,omitempty"`
+	ProtocolClass             *protocolClassJSON          `json:"protocolClass,omitempty"`
+	RefusalCause              *params.RefusalCauseValue   `json:"refusalCause,omitempty"`
+	ReleaseCause              *params.ReleaseCauseValue   `json:"releaseCause,omitempty"`
+	ResetCause                *params.ResetCauseValue     `json:"resetCause,omitempty"`
+	ReturnCause               *params.ReturnCauseValue    `json:"returnCause,omitempty"`
+	SegmentingReassembling    *segmentingReassemblingJSON `json:"segmentingReassembling,omitempty"`
+	ReceiveSequenceNumber     *uint8                      `json:"receiveSequenceNumber,omitempty"`
+	SequencingSegmenting      *sequencingSegmentingJSON   `json:"sequencingSegmenting,omitempty"`
+	Credit                    *uint8                      `json:"credit,omitempty"`
+	HopCounter                *uint8                      `json:"hopCounter,omitempty"`
+	CalledPartyAddress        *params.PartyAddress        `json:"calledPartyAddress,omitempty"`
+	CallingPartyAddress       *params.PartyAddress        `json:"callingPartyAddress,omitempty"`
+	Data                      *hexBytes                   `json:"data,omitempty"`
+	Segmentation              *segmentationJSON           `json:"segmentation,omitempty"`
+	Importance                *uint8                      `json:"importance,omitempty"`
+	ISNI                      *isniJSON                   `json:"isni,omitempty"`
+	UnknownParameters         []*unknownParameterJSON     `json:"unknownParameters,omitempty"`
+	Payload                   *hexBytes                   `json:"payload,omitempty"`
+}
+
+type protocolClassJSON struct {
+	Class         int  `json:"class"`
+	ReturnOnError bool `json:"returnOnError,omitempty"`
+}
+
+type segmentingReassemblingJSON struct {
+	MoreData bool `json:"moreData"`
+}
+
+// sequencingSegmentingJSON has P(S) and P(R), the sequence numbers without
+// the spare bit.
+type sequencingSegmentingJSON struct {
+	PS       uint8 `json:"ps"`
+	PR       uint8 `json:"pr"`
+	MoreData bool  `json:"moreData"`
+}
+
+type segmentationJSON struct {
+	FirstSegment      bool   `json:"firstSegment"`
+	Class             uint8  `json:"class"`
+	RemainingSegments uint8  `json:"remainingSegments"`
+	LocalReference    uint32 `json:"localReference"`
+}
+
+type isniJSON struct {
+	MarkIdentification bool                        `json:"markIdentification,omitempty"`
+	RoutingIndicator   params.ISNIRoutingIndicator `json:"routingIndicator"`
+	Counter            uint8                       `json:"counter"`
+	NetworkSpecific    *uint8                      `json:"networkSpecific,omitempty"`
+	Networks           []isniNetworkJSON           `json:"networks,omitempty"`
+}
+
+type isniNetworkJSON struct {
+	Network uint8 `json:"network"`
+	Cluster uint8 `json:"cluster"`
+}
+
+type unknownParameterJSON struct {
+	Code  params.ParameterNameCode `json:"code"`
+	Value hexBytes                 `json:"value"`
+}
+
+func localReferenceToJSON(l *params.LocalReference) *uint32 {
+	if l == nil {
+		return nil
+	}
+
+	v := l.Uint32()
+	return &v
+}
+
+func protocolClassToJSON(p *params.ProtocolClass) *protocolClassJSON {
+	if p == nil {
+		return nil
+	}
+
+	return &protocolClassJSON{Class: p.Class(), ReturnOnError: p.ReturnOnError()}
+}
+
+func causeToJSON[T ~uint8](c *params.Cause[T]) *T {
+	if c == nil {
+		return nil
+	}
+
+	v := c.Value()
+	return &v
+}
+
+func uint8ToJSON[T any, P interface {
+	*T
+	Value() uint8
+}](p P) *uint8 {
+	if p == nil {
+		return nil
+	}
+
+	v := p.Value()
+	return &v
+}
+
+func dataToJSON(d *params.Data) *hexBytes {
+	if d == nil {
+		return nil
+	}
+
+	v := hexBytes(d.Value())
+	return &v
+}
+
+func longDataToJSON(d *params.LongData) *hexBytes {
+	if d == nil {
+		return nil
+	}
+
+	v := hexBytes(d.Value())
+	return &v
+}
+
+func segmentationToJSON(s *params.Segmentation) *segmentationJSON {
+	if s == nil {
+		return nil
+	}
+
+	return &segmentationJSON{
+		FirstSegment:      s.FirstSegment,
+		Class:             s.Class,
+		RemainingSegments: s.RemainingSegments,
+		LocalReference:    s.LocalReference,
+	}
+}
+
+func isniToJSON(i *params.ISNI) *isniJSON {
+	if i == nil {
+		return nil
+	}
+
+	v := &isniJSON{
+		MarkIdentification: i.MarkIdentification,
+		RoutingIndicator:   i.RoutingIndicator,
+		Counter:            i.Counter,
+	}
+	if i.TypeIndicator {
+		ns := i.NetworkSpecific
+		v.NetworkSpecific = &ns
+	}
+	for _, n := range i.Networks {
+		v.Networks = append(v.Networks, isniNetworkJSON(n))
+	}
+
+	return v
+}
+
+func unknownParametersToJSON(ps []*params.UnknownParameter) []*unknownParameterJSON {
+	var v []*unknownParameterJSON
+	for _, p := range ps {
+		v = append(v, &unknownParameterJSON{Code: p.Code(), Value: p.Value()})
+	}
+
+	return v
+}
+
+// unmarshalMessageJSON decodes b into messageJSON, and checks if the type is
+// t and the keys of the mandatory parameters are present.
+func unmarshalMessageJSON(b []byte, t MsgType, mandatory ...string) (*messageJSON, error) {
+	v := &messageJSON{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	if v.Type != t {
+		return nil, fmt.Errorf("cannot decode %s into %s: %w", v.Type, t, UnsupportedTypeError(v.Type))
+	}
+
+	if len(mandatory) == 0 {
+		return v, nil
+	}
+
+	keys := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return nil, err
+	}
+	for _, k := range mandatory {
+		if p, ok := keys[k]; !ok || string(p) == "null" {
+			return nil, fmt.Errorf("%s in %s: %w", k, t, ErrMissingParameter)
+		}
+	}
+
+	return v, nil
+}
+
+func (v *messageJSON) destinationLocalReference() uint32 {
+	if v.DestinationLocalReference == nil {
+		return 0
+	}
+	return *v.DestinationLocalReference
+}
+
+func (v *messageJSON) sourceLocalReference() uint32 {
+	if v.SourceLocalReference == nil {
+		return 0
+	}
+	return *v.SourceLocalReference
+}
+
+func (v *messageJSON) data() []byte {
+	if v.Data == nil {
+		return nil
+	}
+	return *v.Data
+}
+
+// optionals returns the optional parameters in v, which are passed to the
+// constructors of the messages.
+//
+// The Data is not included, as it is mandatory in some messages.
+func (v *messageJSON) optionals() []params.Parameter {
+	var opts []params.Parameter
+	if v.Credit != nil {
+		opts = append(opts, params.NewCreditOptional(*v.Credit))
+	}
+	if v.CalledPartyAddress != nil {
+		opts = append(opts, v.CalledPartyAddress)
+	}
+	if v.CallingPartyAddress != nil {
+		opts = append(opts, v.CallingPartyAddress)
+	}
+	if v.HopCounter != nil {
+		opts = append(opts, params.NewHopCounterOptional(*v.HopCounter))
+	}
+	if s := v.Segmentation; s != nil {
+		opts = append(opts, params.NewSegmentationOptional(s.FirstSegment, s.Class, s.RemainingSegments, s.LocalReference))
+	}
+	if v.Importance != nil {
+		opts = append(opts, params.NewImportanceOptional(*v.Importance))
+	}
+	if i := v.ISNI; i != nil {
+		var networks []params.ISNINetwork
+		for _, n := range i.Networks {
+			networks = append(networks, params.ISNINetwork(n))
+		}
+
+		if i.NetworkSpecific != nil {
+			opts = append(opts, params.NewISNIType1(i.MarkIdentification, i.RoutingIndicator, i.Counter, *i.NetworkSpecific, networks...))
+		} else {
+			opts = append(opts, params.NewISNI(i.MarkIdentification, i.RoutingIndicator, i.Counter, networks...))
+		}
+	}
+	for _, p := range v.UnknownParameters {
+		opts = append(opts, params.NewUnknownParameter(p.Code, p.Value))
+	}
+
+	return opts
+}
+
+// optionalsWithData returns the optional parameters in v including the Data.
+func (v *messageJSON) optionalsWithData() []params.Parameter {
+	opts := v.optionals()
+	if v.Data != nil {
+		opts = append(opts, params.NewDataOptional(*v.Data))
+	}
+	return opts
+}
+
+// UnmarshalMessageJSON decodes the JSON created by MarshalJSON of the
+// messages into the Message of the "type".
+//
+// The message types that are not implemented in this package are decoded
+// into RawMessage.
+func UnmarshalMessageJSON(b []byte) (Message, error) {
+	v := &struct {
+		Type MsgType `json:"type"`
+	}{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+
+	var m interface {
+		Message
+		json.Unmarshaler
+	}
+	switch v.Type {
+	case MsgTypeCR:
+		m = &CR{}
+	case MsgTypeCC:
+		m = &CC{}
+	case MsgTypeCREF:
+		m = &CREF{}
+	case MsgTypeRLSD:
+		m = &RLSD{}
+	case MsgTypeRLC:
+		m = &RLC{}
+	case MsgTypeDT1:
+		m = &DT1{}
+	case MsgTypeDT2:
+		m = &DT2{}
+	case MsgTypeAK:
+		m = &AK{}
+	case MsgTypeUDT:
+		m = &UDT{}
+	case MsgTypeUDTS:
+		m = &UDTS{}
+	case MsgTypeED:
+		m = &ED{}
+	case MsgTypeEA:
+		m = &EA{}
+	case MsgTypeRSR:
+		m = &RSR{}
+	case MsgTypeRSC:
+		m = &RSC{}
+	case MsgTypeIT:
+		m = &IT{}
+	case MsgTypeXUDT:
+		m = &XUDT{}
+	case MsgTypeXUDTS:
+		m = &XUDTS{}
+	case MsgTypeLUDT:
+		m = &LUDT{}
+	case MsgTypeLUDTS:
+		m = &LUDTS{}
+	default:
+		m = &RawMessage{Type: v.Type}
+	}
+
+	if err := m.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MarshalJSON returns the CR in JSON.
+func (c *CR) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                 MsgTypeCR,
+		SourceLocalReference: localReferenceToJSON(c.SourceLocalReference),
+		ProtocolClass:        protocolClassToJSON(c.ProtocolClass),
+		CalledPartyAddress:   c.CalledPartyAddress,
+		Credit:               uint8ToJSON(c.Credit),
+		CallingPartyAddress:  c.CallingPartyAddress,
+		Data:                 dataToJSON(c.Data),
+		HopCounter:           uint8ToJSON(c.HopCounter),
+		Importance:           uint8ToJSON(c.Importance),
+		UnknownParameters:    unknownParametersToJSON(c.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the CR.
+func (c *CR) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeCR, "sourceLocalReference", "protocolClass", "calledPartyAddress")
+	if err != nil {
+		return err
+	}
+
+	cdpa := v.CalledPartyAddress
+	v.CalledPartyAddress = nil
+	*c = *NewCR(v.sourceLocalReference(), v.ProtocolClass.Class, cdpa, v.optionalsWithData()...)
+	return nil
+}
+
+// MarshalJSON returns the CC in JSON.
+func (c *CC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeCC,
+		DestinationLocalReference: localReferenceToJSON(c.DestinationLocalReference),
+		SourceLocalReference:      localReferenceToJSON(c.SourceLocalReference),
+		ProtocolClass:             protocolClassToJSON(c.ProtocolClass),
+		Credit:                    uint8ToJSON(c.Credit),
+		CalledPartyAddress:        c.CalledPartyAddress,
+		Data:                      dataToJSON(c.Data),
+		Importance:                uint8ToJSON(c.Importance),
+		UnknownParameters:         unknownParametersToJSON(c.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the CC.
+func (c *CC) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeCC, "destinationLocalReference", "sourceLocalReference", "protocolClass")
+	if err != nil {
+		return err
+	}
+
+	*c = *NewCC(v.destinationLocalReference(), v.sourceLocalReference(), v.ProtocolClass.Class, v.optionalsWithData()...)
+	return nil
+}
+
+// MarshalJSON returns the CREF in JSON.
+func (c *CREF) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeCREF,
+		DestinationLocalReference: localReferenceToJSON(c.DestinationLocalReference),
+		RefusalCause:              causeToJSON(c.RefusalCause),
+		CalledPartyAddress:        c.CalledPartyAddress,
+		Data:                      dataToJSON(c.Data),
+		Importance:                uint8ToJSON(c.Importance),
+		UnknownParameters:         unknownParametersToJSON(c.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the CREF.
+func (c *CREF) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeCREF, "destinationLocalReference", "refusalCause")
+	if err != nil {
+		return err
+	}
+
+	*c = *NewCREF(v.destinationLocalReference(), *v.RefusalCause, v.optionalsWithData()...)
+	return nil
+}
+
+// MarshalJSON returns the RLSD in JSON.
+func (r *RLSD) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeRLSD,
+		DestinationLocalReference: localReferenceToJSON(r.DestinationLocalReference),
+		SourceLocalReference:      localReferenceToJSON(r.SourceLocalReference),
+		ReleaseCause:              causeToJSON(r.ReleaseCause),
+		Data:                      dataToJSON(r.Data),
+		Importance:                uint8ToJSON(r.Importance),
+		UnknownParameters:         unknownParametersToJSON(r.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the RLSD.
+func (r *RLSD) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeRLSD, "destinationLocalReference", "sourceLocalReference", "releaseCause")
+	if err != nil {
+		return err
+	}
+
+	*r = *NewRLSD(v.destinationLocalReference(), v.sourceLocalReference(), *v.ReleaseCause, v.optionalsWithData()...)
+	return nil
+}
+
+// MarshalJSON returns the RLC in JSON.
+func (r *RLC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeRLC,
+		DestinationLocalReference: localReferenceToJSON(r.DestinationLocalReference),
+		SourceLocalReference:      localReferenceToJSON(r.SourceLocalReference),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the RLC.
+func (r *RLC) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeRLC, "destinationLocalReference", "sourceLocalReference")
+	if err != nil {
+		return err
+	}
+
+	*r = *NewRLC(v.destinationLocalReference(), v.sourceLocalReference())
+	return nil
+}
+
+// MarshalJSON returns the DT1 in JSON.
+func (d *DT1) MarshalJSON() ([]byte, error) {
+	v := &messageJSON{
+		Type:                      MsgTypeDT1,
+		DestinationLocalReference: localReferenceToJSON(d.DestinationLocalReference),
+		Data:                      dataToJSON(d.Data),
+	}
+	if d.SegmentingReassembling != nil {
+		v.SegmentingReassembling = &segmentingReassemblingJSON{MoreData: d.SegmentingReassembling.MoreData()}
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the DT1.
+func (d *DT1) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeDT1, "destinationLocalReference", "segmentingReassembling", "data")
+	if err != nil {
+		return err
+	}
+
+	*d = *NewDT1(v.destinationLocalReference(), v.SegmentingReassembling.MoreData, v.data())
+	return nil
+}
+
+// MarshalJSON returns the DT2 in JSON.
+func (d *DT2) MarshalJSON() ([]byte, error) {
+	v := &messageJSON{
+		Type:                      MsgTypeDT2,
+		DestinationLocalReference: localReferenceToJSON(d.DestinationLocalReference),
+		Data:                      dataToJSON(d.Data),
+	}
+	if s := d.SequencingSegmenting; s != nil {
+		v.SequencingSegmenting = &sequencingSegmentingJSON{PS: s.PS(), PR: s.PR(), MoreData: s.More()}
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the DT2.
+func (d *DT2) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeDT2, "destinationLocalReference", "sequencingSegmenting", "data")
+	if err != nil {
+		return err
+	}
+
+	s := v.SequencingSegmenting
+	*d = *NewDT2(v.destinationLocalReference(), s.PS, s.PR, s.MoreData, v.data())
+	return nil
+}
+
+// MarshalJSON returns the AK in JSON.
+func (a *AK) MarshalJSON() ([]byte, error) {
+	v := &messageJSON{
+		Type:                      MsgTypeAK,
+		DestinationLocalReference: localReferenceToJSON(a.DestinationLocalReference),
+		Credit:                    uint8ToJSON(a.Credit),
+	}
+	if a.ReceiveSequenceNumber != nil {
+		pr := a.ReceiveSequenceNumber.PR()
+		v.ReceiveSequenceNumber = &pr
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the AK.
+func (a *AK) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeAK, "destinationLocalReference", "receiveSequenceNumber", "credit")
+	if err != nil {
+		return err
+	}
+
+	*a = *NewAK(v.destinationLocalReference(), *v.ReceiveSequenceNumber, *v.Credit)
+	return nil
+}
+
+// MarshalJSON returns the UDT in JSON.
+func (u *UDT) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                MsgTypeUDT,
+		ProtocolClass:       protocolClassToJSON(u.ProtocolClass),
+		CalledPartyAddress:  u.CalledPartyAddress,
+		CallingPartyAddress: u.CallingPartyAddress,
+		Data:                dataToJSON(u.peekData()),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the UDT.
+func (u *UDT) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeUDT, "protocolClass", "calledPartyAddress", "callingPartyAddress", "data")
+	if err != nil {
+		return err
+	}
+
+	*u = *NewUDT(v.ProtocolClass.Class, v.ProtocolClass.ReturnOnError, v.CalledPartyAddress, v.CallingPartyAddress, v.data())
+	return nil
+}
+
+// MarshalJSON returns the UDTS in JSON.
+func (u *UDTS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                MsgTypeUDTS,
+		ReturnCause:         causeToJSON(u.ReturnCause),
+		CalledPartyAddress:  u.CalledPartyAddress,
+		CallingPartyAddress: u.CallingPartyAddress,
+		Data:                dataToJSON(u.Data),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the UDTS.
+func (u *UDTS) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeUDTS, "returnCause", "calledPartyAddress", "callingPartyAddress", "data")
+	if err != nil {
+		return err
+	}
+
+	*u = *NewUDTS(*v.ReturnCause, v.CalledPartyAddress, v.CallingPartyAddress, v.data())
+	return nil
+}
+
+// MarshalJSON returns the ED in JSON.
+func (e *ED) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeED,
+		DestinationLocalReference: localReferenceToJSON(e.DestinationLocalReference),
+		Data:                      dataToJSON(e.Data),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the ED.
+func (e *ED) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeED, "destinationLocalReference", "data")
+	if err != nil {
+		return err
+	}
+
+	*e = *NewED(v.destinationLocalReference(), v.data())
+	return nil
+}
+
+// MarshalJSON returns the EA in JSON.
+func (e *EA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeEA,
+		DestinationLocalReference: localReferenceToJSON(e.DestinationLocalReference),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the EA.
+func (e *EA) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeEA, "destinationLocalReference")
+	if err != nil {
+		return err
+	}
+
+	*e = *NewEA(v.destinationLocalReference())
+	return nil
+}
+
+// MarshalJSON returns the RSR in JSON.
+func (r *RSR) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeRSR,
+		DestinationLocalReference: localReferenceToJSON(r.DestinationLocalReference),
+		SourceLocalReference:      localReferenceToJSON(r.SourceLocalReference),
+		ResetCause:                causeToJSON(r.ResetCause),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the RSR.
+func (r *RSR) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeRSR, "destinationLocalReference", "sourceLocalReference", "resetCause")
+	if err != nil {
+		return err
+	}
+
+	*r = *NewRSR(v.destinationLocalReference(), v.sourceLocalReference(), *v.ResetCause)
+	return nil
+}
+
+// MarshalJSON returns the RSC in JSON.
+func (r *RSC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                      MsgTypeRSC,
+		DestinationLocalReference: localReferenceToJSON(r.DestinationLocalReference),
+		SourceLocalReference:      localReferenceToJSON(r.SourceLocalReference),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the RSC.
+func (r *RSC) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeRSC, "destinationLocalReference", "sourceLocalReference")
+	if err != nil {
+		return err
+	}
+
+	*r = *NewRSC(v.destinationLocalReference(), v.sourceLocalReference())
+	return nil
+}
+
+// MarshalJSON returns the IT in JSON.
+func (i *IT) MarshalJSON() ([]byte, error) {
+	v := &messageJSON{
+		Type:                      MsgTypeIT,
+		DestinationLocalReference: localReferenceToJSON(i.DestinationLocalReference),
+		SourceLocalReference:      localReferenceToJSON(i.SourceLocalReference),
+		ProtocolClass:             protocolClassToJSON(i.ProtocolClass),
+		Credit:                    uint8ToJSON(i.Credit),
+	}
+	if s := i.SequencingSegmenting; s != nil {
+		v.SequencingSegmenting = &sequencingSegmentingJSON{PS: s.PS(), PR: s.PR(), MoreData: s.More()}
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the IT.
+func (i *IT) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeIT,
+		"destinationLocalReference", "sourceLocalReference", "protocolClass", "sequencingSegmenting", "credit",
+	)
+	if err != nil {
+		return err
+	}
+
+	s := v.SequencingSegmenting
+	*i = *NewIT(v.destinationLocalReference(), v.sourceLocalReference(), v.ProtocolClass.Class, s.PS, s.PR, *v.Credit)
+	i.SequencingSegmenting.SetMore(s.MoreData)
+	return nil
+}
+
+// MarshalJSON returns the XUDT in JSON.
+func (x *XUDT) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                MsgTypeXUDT,
+		ProtocolClass:       protocolClassToJSON(x.ProtocolClass),
+		HopCounter:          uint8ToJSON(x.HopCounter),
+		CalledPartyAddress:  x.CalledPartyAddress,
+		CallingPartyAddress: x.CallingPartyAddress,
+		Data:                dataToJSON(x.peekData()),
+		Segmentation:        segmentationToJSON(x.Segmentation),
+		Importance:          uint8ToJSON(x.Importance),
+		ISNI:                isniToJSON(x.ISNI),
+		UnknownParameters:   unknownParametersToJSON(x.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the XUDT.
+//
+// The End of Optional Parameters is added if there is any optional parameter.
+func (x *XUDT) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeXUDT,
+		"protocolClass", "hopCounter", "calledPartyAddress", "callingPartyAddress", "data",
+	)
+	if err != nil {
+		return err
+	}
+
+	hc, cdpa, cgpa := *v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress
+	v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress = nil, nil, nil
+	*x = *NewXUDT(v.ProtocolClass.Class, v.ProtocolClass.ReturnOnError, hc, cdpa, cgpa, v.data(), v.optionals()...)
+	return nil
+}
+
+// MarshalJSON returns the XUDTS in JSON.
+func (x *XUDTS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                MsgTypeXUDTS,
+		ReturnCause:         causeToJSON(x.ReturnCause),
+		HopCounter:          uint8ToJSON(x.HopCounter),
+		CalledPartyAddress:  x.CalledPartyAddress,
+		CallingPartyAddress: x.CallingPartyAddress,
+		Data:                dataToJSON(x.Data),
+		Segmentation:        segmentationToJSON(x.Segmentation),
+		Importance:          uint8ToJSON(x.Importance),
+		ISNI:                isniToJSON(x.ISNI),
+		UnknownParameters:   unknownParametersToJSON(x.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the XUDTS.
+func (x *XUDTS) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeXUDTS,
+		"returnCause", "hopCounter", "calledPartyAddress", "callingPartyAddress", "data",
+	)
+	if err != nil {
+		return err
+	}
+
+	hc, cdpa, cgpa := *v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress
+	v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress = nil, nil, nil
+	*x = *NewXUDTS(*v.ReturnCause, hc, cdpa, cgpa, v.data(), v.optionals()...)
+	return nil
+}
+
+// MarshalJSON returns the LUDT in JSON.
+func (l *LUDT) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                MsgTypeLUDT,
+		ProtocolClass:       protocolClassToJSON(l.ProtocolClass),
+		HopCounter:          uint8ToJSON(l.HopCounter),
+		CalledPartyAddress:  l.CalledPartyAddress,
+		CallingPartyAddress: l.CallingPartyAddress,
+		Data:                longDataToJSON(l.Data),
+		Segmentation:        segmentationToJSON(l.Segmentation),
+		Importance:          uint8ToJSON(l.Importance),
+		UnknownParameters:   unknownParametersToJSON(l.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the LUDT.
+func (l *LUDT) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeLUDT,
+		"protocolClass", "hopCounter", "calledPartyAddress", "callingPartyAddress", "data",
+	)
+	if err != nil {
+		return err
+	}
+
+	hc, cdpa, cgpa := *v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress
+	v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress = nil, nil, nil
+	*l = *NewLUDT(v.ProtocolClass.Class, v.ProtocolClass.ReturnOnError, hc, cdpa, cgpa, v.data(), v.optionals()...)
+	return nil
+}
+
+// MarshalJSON returns the LUDTS in JSON.
+func (l *LUDTS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&messageJSON{
+		Type:                MsgTypeLUDTS,
+		ReturnCause:         causeToJSON(l.ReturnCause),
+		HopCounter:          uint8ToJSON(l.HopCounter),
+		CalledPartyAddress:  l.CalledPartyAddress,
+		CallingPartyAddress: l.CallingPartyAddress,
+		Data:                longDataToJSON(l.Data),
+		Segmentation:        segmentationToJSON(l.Segmentation),
+		Importance:          uint8ToJSON(l.Importance),
+		UnknownParameters:   unknownParametersToJSON(l.UnknownParameters),
+	})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the LUDTS.
+func (l *LUDTS) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalMessageJSON(b, MsgTypeLUDTS,
+		"returnCause", "hopCounter", "calledPartyAddress", "callingPartyAddress", "data",
+	)
+	if err != nil {
+		return err
+	}
+
+	hc, cdpa, cgpa := *v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress
+	v.HopCounter, v.CalledPartyAddress, v.CallingPartyAddress = nil, nil, nil
+	*l = *NewLUDTS(*v.ReturnCause, hc, cdpa, cgpa, v.data(), v.optionals()...)
+	return nil
+}
+
+// MarshalJSON returns the RawMessage in JSON with the Payload in hex.
+func (r *RawMessage) MarshalJSON() ([]byte, error) {
+	p := hexBytes(r.Payload)
+	return json.Marshal(&messageJSON{Type: r.Type, Payload: &p})
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the
+// RawMessage. The Type is taken from the JSON.
+func (r *RawMessage) UnmarshalJSON(b []byte) error {
+	v := &messageJSON{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+
+	var p []byte
+	if v.Payload != nil {
+		p = *v.Payload
+	}
+	*r = *NewRawMessage(v.Type, p)
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package layer provides the SCCP layer for gopacket, so that SCCP can be decoded
+and serialized in the gopacket pipelines.
+
+gopacket has no layers for MTP3 or M3UA, so the layer that carries SCCP should
+give LayerTypeSCCP as its NextLayerType, or the SCCP payload can be decoded
+directly with gopacket.NewPacket or a gopacket.DecodingLayerParser starting at
+LayerTypeSCCP.
+*/
+package layer
+
+import (
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// LayerTypeSCCP is the gopacket.LayerType of SCCP, which decodes the
+// messages in params.VariantITU.
+var LayerTypeSCCP = gopacket.RegisterLayerType(1713, gopacket.LayerTypeMetadata{
+	Name:    "SCCP",
+	Decoder: NewDecoder(nil),
+})
+
+// SCCP is the gopacket layer of a SCCP message. It implements
+// gopacket.DecodingLayer and gopacket.SerializableLayer.
+//
+// The payload of the layer is the Data in the Message if it has any, which
+// is decoded as gopacket.LayerTypePayload.
+type SCCP struct {
+	layers.BaseLayer
+
+	Message sccp.Message
+
+	// Codec is used to decode the Message. DefaultCodec is used if nil.
+	Codec *sccp.Codec
+}
+
+// NewDecoder returns a gopacket.Decoder that decodes the SCCP layer with
+// codec, which is useful for the pipelines of the Variants other than
+// params.VariantITU. sccp.DefaultCodec is used if codec is nil.
+func NewDecoder(codec *sccp.Codec) gopacket.Decoder {
+	return gopacket.DecodeFunc(func(data []byte, p gopacket.PacketBuilder) error {
+		s := &SCCP{Codec: codec}
+		if err := s.DecodeFromBytes(data, p); err != nil {
+			return err
+		}
+		p.AddLayer(s)
+
+		next := s.NextLayerType()
+		if next == gopacket.LayerTypeZero {
+			return nil
+		}
+		return p.NextDecoder(next)
+	})
+}
+
+// LayerType returns LayerTypeSCCP.
+func (s *SCCP) LayerType() gopacket.LayerType {
+	return LayerTypeSCCP
+}
+
+// CanDecode returns LayerTypeSCCP.
+func (s *SCCP) CanDecode() gopacket.LayerClass {
+	return LayerTypeSCCP
+}
+
+// NextLayerType returns gopacket.LayerTypePayload if the Message has Data,
+// or gopacket.LayerTypeZero otherwise.
+func (s *SCCP) NextLayerType() gopacket.LayerType {
+	if len(s.Payload) == 0 {
+		return gopacket.LayerTypeZero
+	}
+	return gopacket.LayerTypePayload
+}
+
+// DecodeFromBytes decodes data as a SCCP message.
+//
+// The Message and the payload refer to data.
+func (s *SCCP) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	codec := s.Codec
+	if codec == nil {
+		codec = sccp.DefaultCodec
+	}
+
+	m, err := codec.ParseMessage(data)
+	if err != nil {
+		df.SetTruncated()
+		return err
+	}
+
+	s.Message = m
+	s.Contents = data
+	s.Payload = userData(m)
+	return nil
+}
+
+// SerializeTo prepends the Message to b.
+//
+// The Message contains the Data in itself, so the payload in b is not
+// included in it; SCCP should be the last layer to be serialized.
+func (s *SCCP) SerializeTo(b gopacket.SerializeBuffer, opts gopacket.SerializeOptions) error {
+	buf, err := b.PrependBytes(s.Message.MarshalLen())
+	if err != nil {
+		return err
+	}
+
+	clear(buf) // MarshalTo expects the zeroed buffer.
+	return s.Message.MarshalTo(buf)
+}
+
+// userData returns the value of the Data in m, or nil if m has no Data.
+func userData(m sccp.Message) []byte {
+	var d *params.Data
+	switch m := m.(type) {
+	case *sccp.UDT:
+		d = m.LoadData()
+	case *sccp.XUDT:
+		d = m.LoadData()
+	case *sccp.UDTS:
+		d = m.Data
+	case *sccp.XUDTS:
+		d = m.Data
+	case *sccp.CR:
+		d = m.Data
+	case *sccp.CC:
+		d = m.Data
+	case *sccp.CREF:
+		d = m.Data
+	case *sccp.RLSD:
+		d = m.Data
+	case *sccp.DT1:
+		d = m.Data
+	case *sccp.DT2:
+		d = m.Data
+	case *sccp.ED:
+		d = m.Data
+	}
+
+	if d == nil {
+		return nil
+	}
+	return d.Value()
+}
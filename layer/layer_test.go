@@ -0,0 +1,105 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package layer_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gopacket/gopacket"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/layer"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func TestLayer(t *testing.T) {
+	cdpa := params.NewCalledPartyAddress(0x42, 0, 6, nil)
+	cgpa := params.NewCallingPartyAddress(0x42, 0, 7, nil)
+	payload := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	b, err := sccp.NewUDT(1, true, cdpa, cgpa, payload).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("NewPacket", func(t *testing.T) {
+		pkt := gopacket.NewPacket(b, layer.LayerTypeSCCP, gopacket.Default)
+		if err := pkt.ErrorLayer(); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		s, ok := pkt.Layer(layer.LayerTypeSCCP).(*layer.SCCP)
+		if !ok {
+			t.Fatalf("no SCCP layer in %s", pkt)
+		}
+		if got, want := s.Message.MessageType(), sccp.MsgTypeUDT; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+
+		p := pkt.Layer(gopacket.LayerTypePayload)
+		if p == nil {
+			t.Fatalf("no payload in %s", pkt)
+		}
+		if diff := cmp.Diff(p.LayerContents(), payload); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("DecodingLayerParser", func(t *testing.T) {
+		var (
+			s       layer.SCCP
+			p       gopacket.Payload
+			decoded []gopacket.LayerType
+		)
+		parser := gopacket.NewDecodingLayerParser(layer.LayerTypeSCCP, &s, &p)
+		if err := parser.DecodeLayers(b, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(decoded, []gopacket.LayerType{layer.LayerTypeSCCP, gopacket.LayerTypePayload}); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]byte(p), payload); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("SerializeLayers", func(t *testing.T) {
+		s := &layer.SCCP{}
+		if err := s.DecodeFromBytes(b, gopacket.NilDecodeFeedback); err != nil {
+			t.Fatal(err)
+		}
+
+		buf := gopacket.NewSerializeBuffer()
+		if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{}, s); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(buf.Bytes(), b); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("NewDecoder", func(t *testing.T) {
+		codec := sccp.NewCodec(sccp.WithVariant(params.VariantANSI))
+		addr, err := codec.AddressBuilder().PC(params.NewANSIPointCode(1, 2, 3)).SSN(6).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := sccp.NewUDT(0, false, addr, addr, payload).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pkt := gopacket.NewPacket(b, layer.NewDecoder(codec), gopacket.Default)
+		s, ok := pkt.Layer(layer.LayerTypeSCCP).(*layer.SCCP)
+		if !ok {
+			t.Fatalf("no SCCP layer in %s", pkt)
+		}
+		udt := s.Message.(*sccp.UDT)
+		if got, want := udt.CalledPartyAddress.SignalingPointCode, params.NewANSIPointCode(1, 2, 3); got != want {
+			t.Errorf("got %d, want %d", got, want)
+		}
+	})
+}
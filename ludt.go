@@ -0,0 +1,263 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// MaxLongDataSize is the maximum length of the Long Data in a LUDT or LUDTS
+// defined in Q.713 3.20.
+const MaxLongDataSize = 3952
+
+// LUDT represents a SCCP Message Long unitdata (LUDT).
+//
+// Unlike XUDT, the pointers and the length of the Long Data are two octets
+// long, which allows the LUDT to carry up to MaxLongDataSize octets of data
+// over the MTP that supports the long messages, such as M3UA.
+type LUDT struct {
+	Type                MsgType
+	ProtocolClass       *params.ProtocolClass
+	HopCounter          *params.HopCounter
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	Data                *params.LongData
+	Segmentation        *params.Segmentation
+	Importance          *params.Importance
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	ptr1, ptr2, ptr3, ptr4 uint16 // as parsed, see RawPointers.
+	trailing               []byte
+	opts                   parseOptions
+}
+
+// NewLUDT creates a new LUDT.
+func NewLUDT(pcls int, retOnErr bool, hc uint8, cdpa, cgpa *params.PartyAddress, data []byte, opts ...params.Parameter) *LUDT {
+	l := &LUDT{
+		Type:                MsgTypeLUDT,
+		ProtocolClass:       params.NewProtocolClass(pcls, retOnErr),
+		HopCounter:          params.NewHopCounter(hc),
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		Data:                params.NewLongData(data),
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			l.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			l.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			// always appended when there is any optional parameter.
+		default:
+			if takeUnknownParameter(&l.UnknownParameters, opt) {
+				continue
+			}
+			logf("unexpected parameter: %s in NewLUDT", opt.Code())
+		}
+	}
+
+	return l
+}
+
+// MarshalBinary returns the byte sequence generated from a LUDT instance.
+func (l *LUDT) MarshalBinary() ([]byte, error) {
+	b := make([]byte, l.MarshalLen())
+	if err := l.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+// The pointers are computed from the parameters set at the time of calling it,
+// and the pointer to the optional part is 0 if there is no optional parameter.
+func (l *LUDT) MarshalTo(b []byte) error {
+	if err := validateProtocolClass(l.Type, l.ProtocolClass); err != nil {
+		return err
+	}
+
+	fixed, variable, optional := l.sections()
+	return marshalLongSections(b, l.Type, fixed, variable, optional)
+}
+
+// AppendTo appends the byte sequence generated from the LUDT to dst, and
+// returns the extended slice.
+func (l *LUDT) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, l)
+}
+
+// WriteTo writes the byte sequence generated from the LUDT to w. It implements
+// io.WriterTo.
+func (l *LUDT) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, l)
+}
+
+// sections returns the parameters in each section of the LUDT.
+func (l *LUDT) sections() (fixed, variable, optional []params.Parameter) {
+	fixed = []params.Parameter{l.ProtocolClass, l.HopCounter}
+	variable = []params.Parameter{l.CalledPartyAddress, l.CallingPartyAddress, l.Data}
+
+	if param := l.Segmentation; param != nil {
+		optional = append(optional, param)
+	}
+	if param := l.Importance; param != nil {
+		optional = append(optional, param)
+	}
+	for _, param := range l.UnknownParameters {
+		optional = append(optional, param)
+	}
+
+	return fixed, variable, optional
+}
+
+// RawPointers returns the pointers to the Called Party Address, the Calling
+// Party Address, the Long Data and the optional part as they were in the byte
+// sequence the LUDT is parsed from. They are zero if the LUDT is not parsed,
+// and not used by MarshalTo.
+func (l *LUDT) RawPointers() [4]uint16 {
+	return [4]uint16{l.ptr1, l.ptr2, l.ptr3, l.ptr4}
+}
+
+// ParseLUDT decodes given byte sequence as a SCCP LUDT.
+func ParseLUDT(b []byte, opts ...ParseOption) (*LUDT, error) {
+	l := &LUDT{opts: *newParseOptions(opts)}
+	if err := l.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP LUDT.
+func (l *LUDT) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	l.Type = MsgType(b[0])
+	l.ProtocolClass = &params.ProtocolClass{}
+	l.HopCounter = &params.HopCounter{}
+	l.CalledPartyAddress = params.NewCalledPartyAddress(0, 0, 0, nil)
+	l.CallingPartyAddress = params.NewCallingPartyAddress(0, 0, 0, nil)
+	l.Data = &params.LongData{}
+	l.Segmentation, l.Importance = nil, nil
+	l.UnknownParameters = nil
+
+	opts, ptrs, n, err := l.opts.unmarshalLongSections(
+		b[1:],
+		[]params.Parameter{l.ProtocolClass, l.HopCounter},
+		[]params.Parameter{l.CalledPartyAddress, l.CallingPartyAddress, l.Data},
+	)
+	if len(ptrs) == 4 {
+		l.ptr1, l.ptr2, l.ptr3, l.ptr4 = ptrs[0], ptrs[1], ptrs[2], ptrs[3]
+	}
+	if err != nil {
+		return err
+	}
+	if err := validateProtocolClass(l.Type, l.ProtocolClass); err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			l.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			l.Importance = opt.(*params.Importance)
+		default:
+			takeUnknownParameter(&l.UnknownParameters, opt)
+		}
+	}
+
+	l.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the LUDT that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (l *LUDT) Clone() *LUDT {
+	c := *l
+	c.ProtocolClass = clonePtr(l.ProtocolClass)
+	c.HopCounter = clonePtr(l.HopCounter)
+	c.CalledPartyAddress = l.CalledPartyAddress.Clone()
+	c.CallingPartyAddress = l.CallingPartyAddress.Clone()
+	c.Data = l.Data.Clone()
+	c.Segmentation = clonePtr(l.Segmentation)
+	c.Importance = clonePtr(l.Importance)
+	c.UnknownParameters = cloneUnknownParameters(l.UnknownParameters)
+	c.trailing = bytes.Clone(l.trailing)
+
+	return &c
+}
+
+// Reset clears the LUDT to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (l *LUDT) Reset() {
+	*l = LUDT{opts: l.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the LUDT computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (l *LUDT) TrailingBytes() []byte {
+	return l.trailing
+}
+
+// MarshalLen returns the serial length.
+func (l *LUDT) MarshalLen() int {
+	return longSectionsLen(l.sections())
+}
+
+// String returns the LUDT values in human readable format.
+func (l *LUDT) String() string {
+	return fmt.Sprintf("%s: {ProtocolClass: %s, HopCounter: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, Data: %s, Segmentation: %s, Importance: %s}",
+		l.Type,
+		l.ProtocolClass,
+		l.HopCounter,
+		l.CalledPartyAddress,
+		l.CallingPartyAddress,
+		l.Data,
+		l.Segmentation,
+		l.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (l *LUDT) MessageType() MsgType {
+	return MsgTypeLUDT
+}
+
+// MessageTypeName returns the Message Type in string.
+func (l *LUDT) MessageTypeName() string {
+	return l.MessageType().String()
+}
+
+// CdGT returns the GT in CalledPartyAddress in human readable string.
+func (l *LUDT) CdGT() string {
+	if l.CalledPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return l.CalledPartyAddress.Address()
+}
+
+// CgGT returns the GT in CallingPartyAddress in human readable string.
+func (l *LUDT) CgGT() string {
+	if l.CallingPartyAddress.GlobalTitle == nil {
+		return ""
+	}
+	return l.CallingPartyAddress.Address()
+}
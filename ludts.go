@@ -0,0 +1,224 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// LUDTS represents a SCCP Message Long Unitdata Service (LUDTS).
+type LUDTS struct {
+	Type                MsgType
+	ReturnCause         *params.ReturnCause
+	HopCounter          *params.HopCounter
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	Data                *params.LongData
+	Segmentation        *params.Segmentation
+	Importance          *params.Importance
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	trailing []byte
+	opts     parseOptions
+}
+
+// NewLUDTS creates a new LUDTS.
+//
+// The data should be the one in the LUDT to be returned, up to MaxLongDataSize
+// octets.
+//
+// The optional parameters given as opts should be the optional ones, e.g.,
+// created with params.NewImportanceOptional.
+func NewLUDTS(cause params.ReturnCauseValue, hc uint8, cdpa, cgpa *params.PartyAddress, data []byte, opts ...params.Parameter) *LUDTS {
+	l := &LUDTS{
+		Type:                MsgTypeLUDTS,
+		ReturnCause:         params.NewCause(cause),
+		HopCounter:          params.NewHopCounter(hc),
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		Data:                params.NewLongData(data),
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			l.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			l.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			// always appended when there is any optional parameter.
+		default:
+			if takeUnknownParameter(&l.UnknownParameters, opt) {
+				continue
+			}
+			logf("unexpected parameter: %s in NewLUDTS", opt.Code())
+		}
+	}
+
+	return l
+}
+
+// MarshalBinary returns the byte sequence generated from a LUDTS instance.
+func (l *LUDTS) MarshalBinary() ([]byte, error) {
+	b := make([]byte, l.MarshalLen())
+	if err := l.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (l *LUDTS) MarshalTo(b []byte) error {
+	fixed, variable, optional := l.sections()
+	return marshalLongSections(b, l.Type, fixed, variable, optional)
+}
+
+// AppendTo appends the byte sequence generated from the LUDTS to dst, and
+// returns the extended slice.
+func (l *LUDTS) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, l)
+}
+
+// WriteTo writes the byte sequence generated from the LUDTS to w. It implements
+// io.WriterTo.
+func (l *LUDTS) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, l)
+}
+
+// sections returns the parameters in each section of the LUDTS.
+func (l *LUDTS) sections() (fixed, variable, optional []params.Parameter) {
+	fixed = []params.Parameter{l.ReturnCause, l.HopCounter}
+	variable = []params.Parameter{l.CalledPartyAddress, l.CallingPartyAddress, l.Data}
+
+	if param := l.Segmentation; param != nil {
+		optional = append(optional, param)
+	}
+	if param := l.Importance; param != nil {
+		optional = append(optional, param)
+	}
+	for _, param := range l.UnknownParameters {
+		optional = append(optional, param)
+	}
+
+	return fixed, variable, optional
+}
+
+// ParseLUDTS decodes given byte sequence as a SCCP LUDTS.
+func ParseLUDTS(b []byte, opts ...ParseOption) (*LUDTS, error) {
+	l := &LUDTS{opts: *newParseOptions(opts)}
+	if err := l.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP LUDTS.
+func (l *LUDTS) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	l.Type = MsgType(b[0])
+	l.ReturnCause = &params.ReturnCause{}
+	l.HopCounter = &params.HopCounter{}
+	l.CalledPartyAddress = params.NewCalledPartyAddress(0, 0, 0, nil)
+	l.CallingPartyAddress = params.NewCallingPartyAddress(0, 0, 0, nil)
+	l.Data = &params.LongData{}
+	l.Segmentation, l.Importance = nil, nil
+	l.UnknownParameters = nil
+
+	opts, _, n, err := l.opts.unmarshalLongSections(
+		b[1:],
+		[]params.Parameter{l.ReturnCause, l.HopCounter},
+		[]params.Parameter{l.CalledPartyAddress, l.CallingPartyAddress, l.Data},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			l.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			l.Importance = opt.(*params.Importance)
+		default:
+			takeUnknownParameter(&l.UnknownParameters, opt)
+		}
+	}
+
+	l.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the LUDTS that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (l *LUDTS) Clone() *LUDTS {
+	c := *l
+	c.ReturnCause = clonePtr(l.ReturnCause)
+	c.HopCounter = clonePtr(l.HopCounter)
+	c.CalledPartyAddress = l.CalledPartyAddress.Clone()
+	c.CallingPartyAddress = l.CallingPartyAddress.Clone()
+	c.Data = l.Data.Clone()
+	c.Segmentation = clonePtr(l.Segmentation)
+	c.Importance = clonePtr(l.Importance)
+	c.UnknownParameters = cloneUnknownParameters(l.UnknownParameters)
+	c.trailing = bytes.Clone(l.trailing)
+
+	return &c
+}
+
+// Reset clears the LUDTS to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (l *LUDTS) Reset() {
+	*l = LUDTS{opts: l.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the LUDTS computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (l *LUDTS) TrailingBytes() []byte {
+	return l.trailing
+}
+
+// MarshalLen returns the serial length.
+func (l *LUDTS) MarshalLen() int {
+	return longSectionsLen(l.sections())
+}
+
+// String returns the LUDTS values in human readable format.
+func (l *LUDTS) String() string {
+	return fmt.Sprintf("%s: {ReturnCause: %s, HopCounter: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, Data: %s, Segmentation: %v, Importance: %v}",
+		l.Type,
+		l.ReturnCause,
+		l.HopCounter,
+		l.CalledPartyAddress,
+		l.CallingPartyAddress,
+		l.Data,
+		l.Segmentation,
+		l.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (l *LUDTS) MessageType() MsgType {
+	return MsgTypeLUDTS
+}
+
+// MessageTypeName returns the Message Type in string.
+func (l *LUDTS) MessageTypeName() string {
+	return l.MessageType().String()
+}
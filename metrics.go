@@ -0,0 +1,78 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// The causes of the parse errors given to Metrics.ParseError.
+const (
+	ParseErrorTruncated            = "truncated"
+	ParseErrorUnsupportedType      = "unsupported_type"
+	ParseErrorInvalidProtocolClass = "invalid_protocol_class"
+	ParseErrorMalformed            = "malformed"
+)
+
+// Metrics receives the events in the package to be counted, e.g., by the
+// Prometheus implementation in the github.com/wmnsk/go-sccp/sccpprom module.
+//
+// The methods are called synchronously in the goroutine that causes the
+// event, so they should return quickly and be safe for concurrent use.
+type Metrics interface {
+	// MessageParsed is called when a message is decoded by ParseMessage or
+	// Codec.ParseMessage.
+	MessageParsed(t MsgType)
+	// MessageMarshalled is called when a message is serialized, e.g., by
+	// MarshalBinary or AppendTo.
+	MessageMarshalled(t MsgType)
+	// ParseError is called when ParseMessage or Codec.ParseMessage fails,
+	// with one of the ParseError* causes.
+	ParseError(cause string)
+	// MessageReturned is called when a UDTS or XUDTS is created by
+	// NewServiceMessage to return a message with the cause.
+	MessageReturned(cause params.ReturnCauseValue)
+	// ReassemblyTimeout is called when T(reass) of a Reassembler expires.
+	ReassemblyTimeout()
+}
+
+// metricsHolder holds the Metrics so that atomic.Value always stores the same
+// concrete type.
+type metricsHolder struct {
+	Metrics
+}
+
+var metrics atomic.Value
+
+// SetMetrics sets the Metrics that receives the events in the package, which
+// is nil (no metrics) by default. nil disables it again.
+func SetMetrics(m Metrics) {
+	metrics.Store(metricsHolder{m})
+}
+
+// currentMetrics returns the Metrics set by SetMetrics, or nil.
+func currentMetrics() Metrics {
+	h, _ := metrics.Load().(metricsHolder)
+	return h.Metrics
+}
+
+// parseErrorCause classifies the error returned by ParseMessage.
+func parseErrorCause(err error) string {
+	var ute UnsupportedTypeError
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return ParseErrorTruncated
+	case errors.As(err, &ute):
+		return ParseErrorUnsupportedType
+	case errors.Is(err, ErrInvalidProtocolClass):
+		return ParseErrorInvalidProtocolClass
+	default:
+		return ParseErrorMalformed
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package mtp3 encodes and decodes the MTP3 routing label and the Message
+Signal Unit (MSU) that carries the SCCP messages, defined in Q.704 and T1.111.
+
+It is meant for carrying SCCP over the links that has no M3UA, such as E1/TDM
+or M2PA, and does not implement any of the MTP3 procedures.
+*/
+package mtp3
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ServiceIndicator values defined in Q.704 14.2.1.
+const (
+	ServiceIndicatorSNM  uint8 = 0 // Signalling network management messages
+	ServiceIndicatorSNT  uint8 = 1 // Signalling network testing and maintenance messages
+	ServiceIndicatorSCCP uint8 = 3
+	ServiceIndicatorTUP  uint8 = 4
+	ServiceIndicatorISUP uint8 = 5
+)
+
+// NetworkIndicator values defined in Q.704 14.2.2.
+const (
+	NetworkIndicatorInternational      uint8 = 0
+	NetworkIndicatorInternationalSpare uint8 = 1
+	NetworkIndicatorNational           uint8 = 2
+	NetworkIndicatorNationalSpare      uint8 = 3
+)
+
+// ErrInvalidPointCode indicates that the point code does not fit in the
+// routing label of the Variant.
+var ErrInvalidPointCode = errors.New("mtp3: point code out of range")
+
+// RoutingLabel is the MTP3 routing label.
+//
+// The length and the layout depend on the Variant: 4 octets with 14-bit point
+// codes and 4-bit SLS in ITU, 7 octets with 24-bit point codes and 8-bit SLS
+// in ANSI and China (4-bit SLS in China), and 5 octets with 16-bit point
+// codes and 4-bit SLS in TTC.
+type RoutingLabel struct {
+	DPC, OPC params.PointCode
+	SLS      uint8 // Signalling Link Selection
+}
+
+// LabelLen returns the length of the routing label in the Variant.
+func LabelLen(v params.Variant) int {
+	switch v {
+	case params.VariantANSI, params.VariantChina:
+		return 7
+	case params.VariantTTC:
+		return 5
+	default:
+		return 4
+	}
+}
+
+// ParseRoutingLabel decodes the routing label at the beginning of b in the
+// format of the Variant, and returns it with the length of it.
+func ParseRoutingLabel(v params.Variant, b []byte) (*RoutingLabel, int, error) {
+	l := &RoutingLabel{}
+	n, err := l.Read(v, b)
+	if err != nil {
+		return nil, n, err
+	}
+
+	return l, n, nil
+}
+
+// Read sets the values retrieved from byte sequence in the format of the
+// Variant in a RoutingLabel.
+func (l *RoutingLabel) Read(v params.Variant, b []byte) (int, error) {
+	n := LabelLen(v)
+	if len(b) < n {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	switch v {
+	case params.VariantANSI, params.VariantChina:
+		l.DPC = params.PointCode(b[0]) | params.PointCode(b[1])<<8 | params.PointCode(b[2])<<16
+		l.OPC = params.PointCode(b[3]) | params.PointCode(b[4])<<8 | params.PointCode(b[5])<<16
+		l.SLS = b[6]
+		if v == params.VariantChina {
+			l.SLS &= 0x0f
+		}
+	case params.VariantTTC:
+		l.DPC = params.PointCode(binary.LittleEndian.Uint16(b[0:2]))
+		l.OPC = params.PointCode(binary.LittleEndian.Uint16(b[2:4]))
+		l.SLS = b[4] & 0x0f
+	default:
+		u := binary.LittleEndian.Uint32(b[0:4])
+		l.DPC = params.PointCode(u & 0x3fff)
+		l.OPC = params.PointCode(u >> 14 & 0x3fff)
+		l.SLS = uint8(u >> 28)
+	}
+
+	return n, nil
+}
+
+// Write serializes the RoutingLabel in the format of the Variant into b.
+//
+// It returns ErrInvalidPointCode if the DPC or OPC does not fit in the
+// Variant. The SLS is truncated to the bits available.
+func (l *RoutingLabel) Write(v params.Variant, b []byte) (int, error) {
+	n := LabelLen(v)
+	if len(b) < n {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	var max params.PointCode
+	switch v {
+	case params.VariantANSI, params.VariantChina:
+		max = 0xffffff
+	case params.VariantTTC:
+		max = 0xffff
+	default:
+		max = 0x3fff
+	}
+	if l.DPC > max || l.OPC > max {
+		return 0, fmt.Errorf("DPC %d, OPC %d in %s: %w", l.DPC, l.OPC, v, ErrInvalidPointCode)
+	}
+
+	switch v {
+	case params.VariantANSI, params.VariantChina:
+		b[0], b[1], b[2] = uint8(l.DPC), uint8(l.DPC>>8), uint8(l.DPC>>16)
+		b[3], b[4], b[5] = uint8(l.OPC), uint8(l.OPC>>8), uint8(l.OPC>>16)
+		b[6] = l.SLS
+		if v == params.VariantChina {
+			b[6] &= 0x0f
+		}
+	case params.VariantTTC:
+		binary.LittleEndian.PutUint16(b[0:2], uint16(l.DPC))
+		binary.LittleEndian.PutUint16(b[2:4], uint16(l.OPC))
+		b[4] = l.SLS & 0x0f
+	default:
+		binary.LittleEndian.PutUint32(b[0:4], uint32(l.DPC)|uint32(l.OPC)<<14|uint32(l.SLS&0x0f)<<28)
+	}
+
+	return n, nil
+}
+
+// AppendTo appends the RoutingLabel in the format of the Variant to dst.
+func (l *RoutingLabel) AppendTo(v params.Variant, dst []byte) ([]byte, error) {
+	n := len(dst)
+	dst = append(dst, make([]byte, LabelLen(v))...)
+	if _, err := l.Write(v, dst[n:]); err != nil {
+		return dst[:n], err
+	}
+
+	return dst, nil
+}
+
+// String returns the RoutingLabel in human readable format.
+func (l *RoutingLabel) String() string {
+	return fmt.Sprintf("{DPC: %d, OPC: %d, SLS: %d}", l.DPC, l.OPC, l.SLS)
+}
+
+// MSU is the Message Signal Unit without the MTP2 header and the check bits,
+// which consists of the Service Information Octet (SIO), the routing label
+// and the user part message.
+type MSU struct {
+	// Variant is the format of the routing label.
+	Variant params.Variant
+
+	NetworkIndicator uint8
+	// Priority is the message priority in ANSI, or the spare bits in ITU.
+	Priority         uint8
+	ServiceIndicator uint8
+	RoutingLabel
+
+	// Payload is the user part message, e.g., the SCCP message if the
+	// ServiceIndicator is ServiceIndicatorSCCP.
+	Payload []byte
+}
+
+// NewSCCP creates a new MSU that carries the SCCP message in b, with the
+// national network indicator.
+func NewSCCP(v params.Variant, dpc, opc params.PointCode, sls uint8, b []byte) *MSU {
+	return &MSU{
+		Variant:          v,
+		NetworkIndicator: NetworkIndicatorNational,
+		ServiceIndicator: ServiceIndicatorSCCP,
+		RoutingLabel:     RoutingLabel{DPC: dpc, OPC: opc, SLS: sls},
+		Payload:          b,
+	}
+}
+
+// ParseMSU decodes the byte sequence starting with the SIO as a MSU, with
+// the routing label in the format of the Variant.
+//
+// The Payload refers to b.
+func ParseMSU(v params.Variant, b []byte) (*MSU, error) {
+	m := &MSU{Variant: v}
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a MSU in
+// the format of the Variant set in it.
+func (m *MSU) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	m.NetworkIndicator = b[0] >> 6
+	m.Priority = b[0] >> 4 & 0b11
+	m.ServiceIndicator = b[0] & 0x0f
+
+	n, err := m.RoutingLabel.Read(m.Variant, b[1:])
+	if err != nil {
+		return err
+	}
+
+	m.Payload = b[1+n:]
+	return nil
+}
+
+// MarshalBinary returns the byte sequence generated from a MSU.
+func (m *MSU) MarshalBinary() ([]byte, error) {
+	return m.AppendTo(make([]byte, 0, m.MarshalLen()))
+}
+
+// AppendTo appends the byte sequence of the MSU to dst.
+func (m *MSU) AppendTo(dst []byte) ([]byte, error) {
+	n := len(dst)
+	dst = append(dst, m.NetworkIndicator<<6|m.Priority&0b11<<4|m.ServiceIndicator&0x0f)
+
+	dst, err := m.RoutingLabel.AppendTo(m.Variant, dst)
+	if err != nil {
+		return dst[:n], err
+	}
+
+	return append(dst, m.Payload...), nil
+}
+
+// MarshalLen returns the serial length of the MSU.
+func (m *MSU) MarshalLen() int {
+	return 1 + LabelLen(m.Variant) + len(m.Payload)
+}
+
+// String returns the MSU in human readable format.
+func (m *MSU) String() string {
+	return fmt.Sprintf("{Variant: %s, NI: %d, Priority: %d, SI: %d, RoutingLabel: %v, Payload: %x}",
+		m.Variant, m.NetworkIndicator, m.Priority, m.ServiceIndicator, &m.RoutingLabel, m.Payload,
+	)
+}
@@ -0,0 +1,101 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mtp3_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/go-sccp/mtp3"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+func TestMSU(t *testing.T) {
+	payload := []byte{0x09, 0x81}
+	cases := []struct {
+		description string
+		msu         *mtp3.MSU
+		serialized  []byte
+	}{
+		{
+			"ITU",
+			mtp3.NewSCCP(params.VariantITU, 0x0413, 0x102e, 7, payload),
+			[]byte{0x83, 0x13, 0x84, 0x0b, 0x74, 0x09, 0x81},
+		},
+		{
+			"ANSI",
+			&mtp3.MSU{
+				Variant:          params.VariantANSI,
+				NetworkIndicator: mtp3.NetworkIndicatorNational,
+				Priority:         1,
+				ServiceIndicator: mtp3.ServiceIndicatorSCCP,
+				RoutingLabel:     mtp3.RoutingLabel{DPC: 0x010203, OPC: 0x040506, SLS: 0x1f},
+				Payload:          payload,
+			},
+			[]byte{0x93, 0x03, 0x02, 0x01, 0x06, 0x05, 0x04, 0x1f, 0x09, 0x81},
+		},
+		{
+			"China",
+			mtp3.NewSCCP(params.VariantChina, 0x010203, 0x040506, 0x0a, payload),
+			[]byte{0x83, 0x03, 0x02, 0x01, 0x06, 0x05, 0x04, 0x0a, 0x09, 0x81},
+		},
+		{
+			"TTC",
+			mtp3.NewSCCP(params.VariantTTC, 0x1234, 0x5678, 0x0c, payload),
+			[]byte{0x83, 0x34, 0x12, 0x78, 0x56, 0x0c, 0x09, 0x81},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			b, err := c.msu.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(b, c.serialized); diff != "" {
+				t.Error(diff)
+			}
+			if got, want := c.msu.MarshalLen(), len(c.serialized); got != want {
+				t.Errorf("got %d, want %d", got, want)
+			}
+
+			got, err := mtp3.ParseMSU(c.msu.Variant, c.serialized)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, c.msu); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRoutingLabel(t *testing.T) {
+	l := &mtp3.RoutingLabel{DPC: 0x4000, OPC: 1}
+	if _, err := l.AppendTo(params.VariantITU, nil); !errors.Is(err, mtp3.ErrInvalidPointCode) {
+		t.Errorf("got %v, want ErrInvalidPointCode", err)
+	}
+
+	b, err := l.AppendTo(params.VariantTTC, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, n, err := mtp3.ParseRoutingLabel(params.VariantTTC, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != mtp3.LabelLen(params.VariantTTC) {
+		t.Errorf("got %d octets, want %d", n, mtp3.LabelLen(params.VariantTTC))
+	}
+	if diff := cmp.Diff(got, l); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, _, err := mtp3.ParseRoutingLabel(params.VariantANSI, b); err == nil {
+		t.Error("got no error with the short routing label")
+	}
+}
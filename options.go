@@ -0,0 +1,311 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ParseOption is an option to change the behavior of the parsing functions
+// such as ParseMessage.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	variant  params.Variant
+	lenient  bool
+	lazyData bool
+}
+
+func newParseOptions(opts []ParseOption) *parseOptions {
+	o := &parseOptions{variant: params.VariantITU}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithVariant makes the parser decode the messages in the format of the given
+// Variant. The default is params.VariantITU.
+//
+// The Variant is kept in the parsed PartyAddresses, so that they are marshaled
+// in the same format.
+func WithVariant(v params.Variant) ParseOption {
+	return func(o *parseOptions) {
+		o.variant = v
+	}
+}
+
+// WithLenient makes the parser tolerate the malformed digits of the GlobalTitle
+// in the PartyAddresses, which are recorded in the Diagnostics of them instead.
+// It does not change the handling of the spare bits and the reserved
+// indicators, which are recorded in Diagnostics without it too.
+//
+// See params.ParsePartyAddressLenient for details.
+func WithLenient() ParseOption {
+	return func(o *parseOptions) {
+		o.lenient = true
+	}
+}
+
+// WithLazyData makes the parser leave the Data in UDT and XUDT undecoded
+// until it is needed, which saves the work of the applications that only look
+// at the other parameters, such as the routers on the Called Party Address.
+//
+// The Data field is nil in the parsed messages, and LoadData should be used
+// to access it instead. The messages are still marshaled with the Data as it
+// is, and the length of it is checked on parsing.
+func WithLazyData() ParseOption {
+	return func(o *parseOptions) {
+		o.lazyData = true
+	}
+}
+
+// parsePartyAddress parses b as a PartyAddress with the given code in the
+// way specified by the options.
+func (o parseOptions) parsePartyAddress(code params.ParameterNameCode, b []byte) (*params.PartyAddress, error) {
+	var (
+		p   *params.PartyAddress
+		err error
+	)
+	if o.lenient {
+		p, _, err = params.ParsePartyAddressLenient(o.variant, code, b)
+	} else {
+		p, _, err = params.ParsePartyAddressVariant(o.variant, code, b)
+	}
+
+	return p, err
+}
+
+// readPartyAddress is the same as parsePartyAddress, but reads into p if it
+// is not nil, which should be the one parsed with the same options before.
+func (o parseOptions) readPartyAddress(p *params.PartyAddress, code params.ParameterNameCode, b []byte) (*params.PartyAddress, error) {
+	if p == nil {
+		return o.parsePartyAddress(code, b)
+	}
+
+	p.Reset()
+	if _, err := p.Read(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// parseOptionalPartyAddress parses b as an optional PartyAddress with the given
+// code in the way specified by the options.
+func (o parseOptions) parseOptionalPartyAddress(code params.ParameterNameCode, b []byte) (*params.PartyAddress, int, error) {
+	if o.lenient {
+		return params.ParsePartyAddressOptionalLenient(o.variant, code, b)
+	}
+	return params.ParsePartyAddressOptionalVariant(o.variant, code, b)
+}
+
+// parseOptionalParameters parses the optional part in the same way as
+// params.ParseOptionalParameters, but the PartyAddresses in it are decoded
+// in the way specified by the options.
+func (o parseOptions) parseOptionalParameters(b []byte) ([]params.Parameter, int, error) {
+	var ps []params.Parameter
+	offset := 0
+	for {
+		if len(b) <= offset {
+			return nil, offset, io.ErrUnexpectedEOF
+		}
+
+		var (
+			p   params.Parameter
+			n   int
+			err error
+		)
+		switch code := params.ParameterNameCode(b[offset]); code {
+		case params.PCodeCalledPartyAddress, params.PCodeCallingPartyAddress:
+			p, n, err = o.parseOptionalPartyAddress(code, b[offset:])
+		default:
+			p, n, err = params.ParseOptionalParameter(b[offset:])
+		}
+		if err != nil {
+			return nil, offset, err
+		}
+
+		ps = append(ps, p)
+		offset += n
+		if p.Code() == params.PCodeEndOfOptionalParameters {
+			return ps, offset, nil
+		}
+	}
+}
+
+// unmarshalSections decodes the parameter sections in the same way as
+// params.UnmarshalSections, but the PartyAddresses in variable and in the
+// optional part are decoded in the way specified by the options.
+//
+// The PartyAddresses in variable are overwritten with the parsed ones, so
+// they only need to have the code set.
+func (o parseOptions) unmarshalSections(b []byte, fixed, variable []params.Parameter, hasOptionalPart bool) ([]params.Parameter, int, error) {
+	raw := make([]params.Parameter, len(variable))
+	for i, p := range variable {
+		raw[i] = p
+		if _, ok := p.(*params.PartyAddress); ok {
+			raw[i] = &rawParameter{code: p.Code()}
+		}
+	}
+
+	_, end, err := params.UnmarshalSections(b, fixed, raw, false)
+	if err != nil {
+		return nil, end, err
+	}
+
+	for i, p := range variable {
+		addr, ok := p.(*params.PartyAddress)
+		if !ok {
+			continue
+		}
+
+		parsed, err := o.parsePartyAddress(addr.Code(), raw[i].(*rawParameter).b)
+		if err != nil {
+			return nil, end, err
+		}
+		*addr = *parsed
+	}
+
+	if !hasOptionalPart {
+		return nil, end, nil
+	}
+
+	// the pointer to the optional part follows the ones to the variable parameters.
+	at := len(variable)
+	for _, p := range fixed {
+		at += p.MarshalLen()
+	}
+	if len(b) < at+1 {
+		return nil, end, io.ErrUnexpectedEOF
+	}
+	end = max(end, at+1)
+	if b[at] == 0 {
+		return nil, end, nil
+	}
+
+	start := at + int(b[at])
+	if len(b) < start+1 {
+		return nil, end, io.ErrUnexpectedEOF
+	}
+	opts, m, err := o.parseOptionalParameters(b[start:])
+	if err != nil {
+		return nil, end, err
+	}
+
+	return opts, max(end, start+m), nil
+}
+
+// unmarshalLongSections decodes the parameter sections of the LUDT or LUDTS
+// in the same way as unmarshalSections, but with the two-octet pointers, the
+// two-octet length of the LongData and the optional part always pointed to.
+//
+// The pointers are returned in ptrs, which has the ones to the parameters in
+// variable and the one to the optional part in order.
+func (o parseOptions) unmarshalLongSections(b []byte, fixed, variable []params.Parameter) (opts []params.Parameter, ptrs []uint16, end int, err error) {
+	n := 0
+	for _, p := range fixed {
+		m, err := p.Read(b[n:])
+		if err != nil {
+			return nil, nil, n, err
+		}
+		n += m
+	}
+
+	nptr := len(variable) + 1
+	if len(b) < n+2*nptr {
+		return nil, nil, n, io.ErrUnexpectedEOF
+	}
+
+	ptrs = make([]uint16, nptr)
+	for i := range ptrs {
+		ptrs[i] = binary.LittleEndian.Uint16(b[n+2*i:])
+	}
+
+	end = n + 2*nptr
+	for i, p := range variable {
+		lenOctets := 1
+		if _, ok := p.(*params.LongData); ok {
+			lenOctets = 2
+		}
+
+		start := n + 2*i + int(ptrs[i])
+		if ptrs[i] == 0 || len(b) < start+lenOctets {
+			return nil, ptrs, end, io.ErrUnexpectedEOF
+		}
+		pend := start + lenOctets + int(b[start])
+		if lenOctets == 2 {
+			pend = start + lenOctets + int(binary.LittleEndian.Uint16(b[start:]))
+		}
+		if len(b) < pend {
+			return nil, ptrs, end, io.ErrUnexpectedEOF
+		}
+
+		if addr, ok := p.(*params.PartyAddress); ok {
+			parsed, err := o.parsePartyAddress(addr.Code(), b[start:pend])
+			if err != nil {
+				return nil, ptrs, end, err
+			}
+			*addr = *parsed
+		} else if _, err := p.Read(b[start:pend]); err != nil {
+			return nil, ptrs, end, err
+		}
+		end = max(end, pend)
+	}
+
+	if ptrs[len(variable)] == 0 {
+		return nil, ptrs, end, nil
+	}
+
+	start := n + 2*len(variable) + int(ptrs[len(variable)])
+	if len(b) < start+1 {
+		return nil, ptrs, end, io.ErrUnexpectedEOF
+	}
+	opts, m, err := o.parseOptionalParameters(b[start:])
+	if err != nil {
+		return nil, ptrs, end, err
+	}
+
+	return opts, ptrs, max(end, start+m), nil
+}
+
+// rawParameter holds the bytes of a mandatory variable parameter as they are,
+// to be decoded afterwards.
+type rawParameter struct {
+	code params.ParameterNameCode
+	b    []byte
+}
+
+func (r *rawParameter) Read(b []byte) (int, error) {
+	r.b = b
+	return len(b), nil
+}
+
+func (r *rawParameter) Write(b []byte) (int, error) {
+	if len(b) < len(r.b) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return copy(b, r.b), nil
+}
+
+func (r *rawParameter) MarshalLen() int {
+	return len(r.b)
+}
+
+func (r *rawParameter) AppendTo(dst []byte) ([]byte, error) {
+	return append(dst, r.b...), nil
+}
+
+func (r *rawParameter) Code() params.ParameterNameCode {
+	return r.code
+}
+
+func (r *rawParameter) String() string {
+	return fmt.Sprintf("{%s: %x}", r.code, r.b)
+}
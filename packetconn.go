@@ -0,0 +1,246 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DefaultPacketQueueSize is the default number of the data received and not
+// read yet by PacketConn.ReadFrom.
+const DefaultPacketQueueSize = 64
+
+// PacketConnConfig is the configuration of a PacketConn. The zero values are
+// valid.
+type PacketConnConfig struct {
+	// Unitdata is the options of the messages sent by WriteTo, where
+	// ProtocolClass must be 0 or 1.
+	Unitdata UnitdataOptions
+	// QueueSize is the number of the data received and not read yet, which
+	// is DefaultPacketQueueSize if 0. The data received over it is dropped.
+	QueueSize int
+}
+
+// packet is the data received and the address it is received from.
+type packet struct {
+	data []byte
+	from *params.PartyAddress
+}
+
+// PacketConn is the connectionless SCCP service of a local subsystem that can
+// be used like net.PacketConn, where the addresses are *params.PartyAddress.
+//
+// ReadFrom returns the data received by the Endpoint for the SSN with the
+// Calling Party Address, and WriteTo sends the data to the Called Party
+// Address in UDT or XUDT in the protocol class 0 or 1. As a UDP socket, each
+// call to ReadFrom returns the data of a message, and the rest of it is
+// discarded if the buffer is too short.
+type PacketConn struct {
+	ep    *Endpoint
+	local *params.PartyAddress
+	opts  UnitdataOptions
+
+	rx      chan packet
+	closed  chan struct{}
+	closeMu sync.Once
+
+	readDeadline *deadline
+}
+
+// ListenPacket creates a new PacketConn for the local subsystem identified by
+// the SSN in local, which is registered to ep in place of the UpperLayer
+// registered for it. local is used as the Calling Party Address in the
+// messages sent.
+//
+// cfg can be nil to use the default values.
+func ListenPacket(ep *Endpoint, local *params.PartyAddress, cfg *PacketConnConfig) (*PacketConn, error) {
+	if local == nil || !local.HasSSN() {
+		return nil, ErrNoSSN
+	}
+
+	var c PacketConnConfig
+	if cfg != nil {
+		c = *cfg
+	}
+	if pcls := c.Unitdata.ProtocolClass; pcls != 0 && pcls != 1 {
+		return nil, fmt.Errorf("class %d in PacketConn: %w", pcls, ErrInvalidProtocolClass)
+	}
+	if c.QueueSize == 0 {
+		c.QueueSize = DefaultPacketQueueSize
+	}
+
+	p := &PacketConn{
+		ep:           ep,
+		local:        local,
+		opts:         c.Unitdata,
+		rx:           make(chan packet, c.QueueSize),
+		closed:       make(chan struct{}),
+		readDeadline: newDeadline(),
+	}
+	ep.Register(local.SubsystemNumber, UpperLayerFunc(p.received))
+
+	return p, nil
+}
+
+// received queues the data received, which is copied as the message may
+// refer to the buffer of the Transport.
+func (p *PacketConn) received(_ context.Context, u *Unitdata) ([]byte, error) {
+	pkt := packet{data: append([]byte(nil), u.Data...)}
+	if u.CallingPartyAddress != nil {
+		pkt.from = u.CallingPartyAddress.Clone()
+	}
+
+	select {
+	case <-p.closed:
+	case p.rx <- pkt:
+	default:
+		logf("PacketConn for SSN %d: dropped %d octets from %v as the queue is full", p.local.SubsystemNumber, len(pkt.data), pkt.from)
+	}
+	return nil, nil
+}
+
+// ReadFrom reads the data of a message received into b, and returns the
+// number of octets copied and the Calling Party Address of the message.
+func (p *PacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	if isClosedChan(p.closed) {
+		return 0, nil, net.ErrClosed
+	}
+
+	select {
+	case <-p.closed:
+		return 0, nil, net.ErrClosed
+	case <-p.readDeadline.wait():
+		return 0, nil, os.ErrDeadlineExceeded
+	case pkt := <-p.rx:
+		return copy(b, pkt.data), pkt.from, nil
+	}
+}
+
+// WriteTo sends b to addr, which must be a *params.PartyAddress. The Called
+// Party Address of the messages is a copy of addr with the parameter name
+// code replaced, so the address returned by ReadFrom can be given as it is.
+func (p *PacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	if isClosedChan(p.closed) {
+		return 0, net.ErrClosed
+	}
+
+	to, ok := addr.(*params.PartyAddress)
+	if !ok {
+		return 0, &net.AddrError{Err: "not a SCCP address", Addr: addr.String()}
+	}
+
+	// the addresses are swapped as if the message is a reply from local.
+	cdpa, cgpa := swapAddresses(p.local, to)
+	msgs, err := p.ep.buildUnitdata(cdpa, cgpa, b, p.opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.ep.send(context.Background(), msgs); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Close unregisters the local subsystem from the Endpoint. The pending
+// ReadFrom is unblocked with net.ErrClosed.
+func (p *PacketConn) Close() error {
+	p.closeMu.Do(func() {
+		p.ep.Unregister(p.local.SubsystemNumber)
+		close(p.closed)
+	})
+
+	return nil
+}
+
+// LocalAddr returns the address of the local subsystem.
+func (p *PacketConn) LocalAddr() net.Addr {
+	return p.local
+}
+
+// SetDeadline sets the read deadline, as WriteTo does not block.
+func (p *PacketConn) SetDeadline(t time.Time) error {
+	return p.SetReadDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for the pending and future ReadFrom. A
+// zero value disables the deadline.
+func (p *PacketConn) SetReadDeadline(t time.Time) error {
+	p.readDeadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline does nothing, as WriteTo does not block.
+func (p *PacketConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// deadline is a channel closed when the deadline passes, which can be reset
+// while it is waited for.
+type deadline struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+}
+
+func newDeadline() *deadline {
+	return &deadline{cancel: make(chan struct{})}
+}
+
+func (d *deadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil && !d.timer.Stop() {
+		<-d.cancel // wait for the timer to close it
+	}
+	d.timer = nil
+
+	closed := isClosedChan(d.cancel)
+	if t.IsZero() {
+		if closed {
+			d.cancel = make(chan struct{})
+		}
+		return
+	}
+
+	if dur := time.Until(t); dur > 0 {
+		if closed {
+			d.cancel = make(chan struct{})
+		}
+		cancel := d.cancel
+		d.timer = time.AfterFunc(dur, func() {
+			close(cancel)
+		})
+		return
+	}
+
+	if !closed {
+		close(d.cancel)
+	}
+}
+
+func (d *deadline) wait() chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.cancel
+}
+
+func isClosedChan(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import "fmt"
+
+// AddressBuilder builds a PartyAddress with a fluent API, computing the
+// Address Indicator bits automatically from the values given.
+//
+// Example:
+//
+//	cdpa, err := params.NewAddressBuilder().
+//		SSN(6).
+//		GT(params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.NAIInternationalNumber, "1234567890").
+//		RouteOnGT().
+//		Build()
+type AddressBuilder struct {
+	paramType ParameterType
+	code      ParameterNameCode
+	variant   Variant
+
+	hasPC, hasSSN bool
+	spc           PointCode
+	ssn           uint8
+
+	// nil means the routing indicator is decided by the existence of GT.
+	routeOnSSN *bool
+
+	gt  GlobalTitle
+	err error
+}
+
+// NewAddressBuilder creates a new AddressBuilder.
+// By default, it builds a mandatory variable length Called Party Address.
+func NewAddressBuilder() *AddressBuilder {
+	return &AddressBuilder{
+		paramType: PTypeV,
+		code:      PCodeCalledPartyAddress,
+	}
+}
+
+// Called makes the builder build a Called Party Address.
+func (a *AddressBuilder) Called() *AddressBuilder {
+	a.code = PCodeCalledPartyAddress
+	return a
+}
+
+// Calling makes the builder build a Calling Party Address.
+func (a *AddressBuilder) Calling() *AddressBuilder {
+	a.code = PCodeCallingPartyAddress
+	return a
+}
+
+// Optional makes the builder build the PartyAddress as an optional parameter.
+func (a *AddressBuilder) Optional() *AddressBuilder {
+	a.paramType = PTypeO
+	return a
+}
+
+// Variant makes the builder build the PartyAddress in the format of the given
+// Variant. The default is VariantITU.
+func (a *AddressBuilder) Variant(v Variant) *AddressBuilder {
+	a.variant = v
+	return a
+}
+
+// PC sets the Signalling Point Code and the PC indicator.
+func (a *AddressBuilder) PC(spc PointCode) *AddressBuilder {
+	a.hasPC = true
+	a.spc = spc
+	return a
+}
+
+// SSN sets the Subsystem Number and the SSN indicator.
+func (a *AddressBuilder) SSN(ssn uint8) *AddressBuilder {
+	a.hasSSN = true
+	a.ssn = ssn
+	return a
+}
+
+// GT sets the Global Title with the digits given in string.
+//
+// The Encoding Scheme (or the odd/even indicator for GTI=0001) is set
+// according to the number of digits. The fields that are not used by the
+// given GTI are ignored.
+func (a *AddressBuilder) GT(
+	gti GlobalTitleIndicator,
+	tt TranslationType,
+	np NumberingPlan,
+	nai NatureOfAddressIndicator,
+	digits string,
+) *AddressBuilder {
+	if gti == GTINoGT || gti > GTITTNPESNAI {
+		a.err = fmt.Errorf("invalid GTI %d for GT: %w", gti, ErrInvalidAddress)
+		return a
+	}
+
+	es := ESBCDEven
+	if len(digits)%2 == 1 {
+		es = ESBCDOdd
+		if gti == GTINAIOnly {
+			nai = nai.Odd()
+		}
+	}
+
+	addr, err := es.EncodeAddress(digits)
+	if err != nil {
+		a.err = fmt.Errorf("invalid GT digits %q: %w", digits, err)
+		return a
+	}
+
+	a.gt = NewGlobalTitle(gti, tt, np, es, nai, addr)
+	return a
+}
+
+// RouteOnGT sets the routing indicator to "route on GT".
+func (a *AddressBuilder) RouteOnGT() *AddressBuilder {
+	v := false
+	a.routeOnSSN = &v
+	return a
+}
+
+// RouteOnSSN sets the routing indicator to "route on PC + SSN".
+func (a *AddressBuilder) RouteOnSSN() *AddressBuilder {
+	v := true
+	a.routeOnSSN = &v
+	return a
+}
+
+// Build builds a PartyAddress with the values given to the builder.
+//
+// If neither RouteOnGT nor RouteOnSSN is called, the routing indicator is
+// set to "route on GT" when GT is given, otherwise "route on SSN".
+func (a *AddressBuilder) Build() (*PartyAddress, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	routeOnSSN := a.gt == nil
+	if a.routeOnSSN != nil {
+		routeOnSSN = *a.routeOnSSN
+	}
+
+	if !routeOnSSN && a.gt == nil {
+		return nil, fmt.Errorf("route on GT requested without GT: %w", ErrInvalidAddress)
+	}
+	if routeOnSSN && !a.hasSSN {
+		return nil, fmt.Errorf("route on SSN requested without SSN: %w", ErrInvalidAddress)
+	}
+
+	if a.variant.ansiAddress() && a.gt != nil {
+		switch a.gt.(type) {
+		case *GTTTNPES, *GTTTOnly:
+		default:
+			return nil, fmt.Errorf("GTI %d is not defined in %v: %w", a.gt.GTI(), a.variant, ErrInvalidAddress)
+		}
+	}
+
+	ai := a.variant.NewAddressIndicator(a.hasPC, a.hasSSN, routeOnSSN, a.variant.GTI(a.gt))
+
+	p := NewPartyAddressVariant(a.variant, a.code, ai, a.spc, a.ssn, a.gt)
+	if a.paramType == PTypeO {
+		p.paramType = PTypeO
+	}
+	return p, nil
+}
+
+// NewSSNAddress creates a new Called Party Address that has only the Subsystem
+// Number and is routed on SSN, which is encoded in the minimal form of two
+// octets (Address Indicator and SSN) and commonly used within a national network.
+//
+// Use AsCalling to use it as a Calling Party Address.
+func NewSSNAddress(ssn uint8) *PartyAddress {
+	return NewCalledPartyAddress(NewAddressIndicator(false, true, true, GTINoGT), 0, ssn, nil)
+}
+
+// NewE164Address creates a new Called Party Address with the GT of the E.164
+// number (e.g., MSISDN or the number of a network element) that is routed on GT.
+//
+// The GT has GTI=0100, TT=0, NP=E.164 and NAI=international number, which is the
+// most common form in the international networks. Use AsCalling to use it as a
+// Calling Party Address, or AddressBuilder for the other combinations.
+func NewE164Address(ssn int, msisdn string) (*PartyAddress, error) {
+	return newGTAddress(ssn, NPE164, msisdn)
+}
+
+// NewE212Address creates a new Called Party Address with the GT of the E.212
+// number (IMSI) that is routed on GT, in the same way as NewE164Address.
+func NewE212Address(ssn int, imsi string) (*PartyAddress, error) {
+	return newGTAddress(ssn, NPE212, imsi)
+}
+
+// NewE214Address creates a new Called Party Address with the GT of the E.214
+// number (Mobile Global Title) that is routed on GT, in the same way as NewE164Address.
+func NewE214Address(ssn int, mgt string) (*PartyAddress, error) {
+	return newGTAddress(ssn, NPE214, mgt)
+}
+
+func newGTAddress(ssn int, np NumberingPlan, digits string) (*PartyAddress, error) {
+	if ssn < 0 || ssn > 0xff {
+		return nil, fmt.Errorf("invalid SSN %d: %w", ssn, ErrInvalidAddress)
+	}
+
+	return NewAddressBuilder().
+		SSN(uint8(ssn)).
+		GT(GTITTNPESNAI, TTUnknown, np, NAIInternationalNumber, digits).
+		RouteOnGT().
+		Build()
+}
@@ -0,0 +1,211 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import (
+	"fmt"
+	"io"
+)
+
+// SectionsLen returns the serial length of the parameter sections of a message,
+// that is, everything after the Message Type.
+//
+// The meaning of the arguments is the same as MarshalSections.
+func SectionsLen(fixed, variable, optional []Parameter, hasOptionalPart bool) int {
+	l := len(variable) // pointers
+	if hasOptionalPart {
+		l++
+	}
+
+	for _, p := range fixed {
+		l += p.MarshalLen()
+	}
+	for _, p := range variable {
+		l += p.MarshalLen()
+	}
+
+	if hasOptionalPart && len(optional) > 0 {
+		l += OptionalParametersLen(optional)
+	}
+
+	return l
+}
+
+// OptionalParametersLen returns the serial length of the optional part that
+// consists of the given parameters, including the End of Optional Parameters
+// even if it is not in optional.
+func OptionalParametersLen(optional []Parameter) int {
+	l := 0
+	for _, p := range optional {
+		l += p.MarshalLen()
+	}
+	if !endsWithEOP(optional) {
+		l++
+	}
+
+	return l
+}
+
+// MarshalOptionalParameters serializes the optional part that consists of the
+// given parameters into b, and returns the number of bytes written.
+//
+// The End of Optional Parameters is appended if the last one in optional is not,
+// so that the optional part is always terminated. It should not be called when
+// there is no optional part, in which case the pointer should be set to 0 instead.
+func MarshalOptionalParameters(b []byte, optional []Parameter) (int, error) {
+	n := 0
+	for _, p := range optional {
+		m, err := p.Write(b[n:])
+		if err != nil {
+			return n, err
+		}
+		n += m
+	}
+
+	if !endsWithEOP(optional) {
+		if len(b) < n+1 {
+			return n, io.ErrUnexpectedEOF
+		}
+		b[n] = uint8(PCodeEndOfOptionalParameters)
+		n++
+	}
+
+	return n, nil
+}
+
+func endsWithEOP(optional []Parameter) bool {
+	return len(optional) > 0 && optional[len(optional)-1].Code() == PCodeEndOfOptionalParameters
+}
+
+// MarshalSections serializes the mandatory fixed, mandatory variable and optional
+// parameter sections of a message into b, which should start right after the
+// Message Type. It returns the number of bytes written.
+//
+// The pointers to the mandatory variable parameters and to the start of the
+// optional part are computed from the length of each parameter. If hasOptionalPart
+// is true, the pointer to the optional part is written even if optional is empty
+// (in that case it is set to 0), and the End of Optional Parameters is appended
+// if the last one in optional is not.
+//
+// This is meant for implementing the message types that are not supported by
+// this package, so that they get the same pointer and length handling as the
+// built-in ones.
+func MarshalSections(b []byte, fixed, variable, optional []Parameter, hasOptionalPart bool) (int, error) {
+	n := 0
+	for _, p := range fixed {
+		m, err := p.Write(b[n:])
+		if err != nil {
+			return n, err
+		}
+		n += m
+	}
+
+	nptr := len(variable)
+	if hasOptionalPart {
+		nptr++
+	}
+	if len(b) < n+nptr {
+		return n, io.ErrUnexpectedEOF
+	}
+
+	offset := n + nptr
+	for i, p := range variable {
+		if err := putPointer(b, n+i, offset, p.Code()); err != nil {
+			return offset, err
+		}
+
+		m, err := p.Write(b[offset:])
+		if err != nil {
+			return offset, err
+		}
+		offset += m
+	}
+
+	if !hasOptionalPart {
+		return offset, nil
+	}
+
+	if len(optional) == 0 {
+		b[n+len(variable)] = 0
+		return offset, nil
+	}
+
+	if err := putPointer(b, n+len(variable), offset, PCodeEndOfOptionalParameters); err != nil {
+		return offset, err
+	}
+	m, err := MarshalOptionalParameters(b[offset:], optional)
+	return offset + m, err
+}
+
+// UnmarshalSections decodes the mandatory fixed, mandatory variable and optional
+// parameter sections of a message from b, which should start right after the
+// Message Type.
+//
+// The values are set in the parameters given as fixed and variable in order,
+// which should be the instances of the expected types. If hasOptionalPart is
+// true, the optional parameters are parsed with ParseOptionalParameters and
+// returned. The returned int is the end of the furthest parameter, which is
+// the length of the sections when the pointers are contiguous.
+func UnmarshalSections(b []byte, fixed, variable []Parameter, hasOptionalPart bool) ([]Parameter, int, error) {
+	n := 0
+	for _, p := range fixed {
+		m, err := p.Read(b[n:])
+		if err != nil {
+			return nil, n, err
+		}
+		n += m
+	}
+
+	nptr := len(variable)
+	if hasOptionalPart {
+		nptr++
+	}
+	if len(b) < n+nptr {
+		return nil, n, io.ErrUnexpectedEOF
+	}
+
+	end := n + nptr
+	for i, p := range variable {
+		start := n + i + int(b[n+i])
+		if b[n+i] == 0 || len(b) < start+1 {
+			return nil, end, io.ErrUnexpectedEOF
+		}
+		pend := start + 1 + int(b[start]) // +1 is the length itself
+		if len(b) < pend {
+			return nil, end, io.ErrUnexpectedEOF
+		}
+
+		if _, err := p.Read(b[start:pend]); err != nil {
+			return nil, end, err
+		}
+		end = max(end, pend)
+	}
+
+	if !hasOptionalPart || b[n+len(variable)] == 0 {
+		return nil, end, nil
+	}
+
+	start := n + len(variable) + int(b[n+len(variable)])
+	if len(b) < start+1 {
+		return nil, end, io.ErrUnexpectedEOF
+	}
+	opts, m, err := ParseOptionalParameters(b[start:])
+	if err != nil {
+		return nil, end, err
+	}
+
+	return opts, max(end, start+m), nil
+}
+
+// putPointer puts the pointer at b[at] that points to b[to].
+func putPointer(b []byte, at, to int, code ParameterNameCode) error {
+	ptr := to - at
+	if ptr > 0xff {
+		return fmt.Errorf("pointer to %s is too far (%d): %w", code, ptr, ErrValueTooLong)
+	}
+
+	b[at] = uint8(ptr)
+	return nil
+}
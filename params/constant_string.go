@@ -28,17 +28,27 @@ func _() {
 	_ = x[PCodeHopCounter-17]
 	_ = x[PCodeImportance-18]
 	_ = x[PCodeLongData-19]
+	_ = x[PCodeISNI-250]
 }
 
-const _ParameterNameCode_name = "End of optional parametersDestination local referenceSource local referenceCalled party addressCalling party addressProtocol classSegmenting/reassemblingReceive sequence numberSequencing/segmentingCreditRelease causeReturn causeReset causeError causeRefusal causeDataSegmentationHop CounterImportanceLong data"
+const (
+	_ParameterNameCode_name_0 = "End of optional parametersDestination local referenceSource local referenceCalled party addressCalling party addressProtocol classSegmenting/reassemblingReceive sequence numberSequencing/segmentingCreditRelease causeReturn causeReset causeError causeRefusal causeDataSegmentationHop CounterImportanceLong data"
+	_ParameterNameCode_name_1 = "Intermediate signaling network identification"
+)
 
-var _ParameterNameCode_index = [...]uint16{0, 26, 53, 75, 95, 116, 130, 153, 176, 197, 203, 216, 228, 239, 250, 263, 267, 279, 290, 300, 309}
+var (
+	_ParameterNameCode_index_0 = [...]uint16{0, 26, 53, 75, 95, 116, 130, 153, 176, 197, 203, 216, 228, 239, 250, 263, 267, 279, 290, 300, 309}
+)
 
 func (i ParameterNameCode) String() string {
-	if i >= ParameterNameCode(len(_ParameterNameCode_index)-1) {
+	switch {
+	case i <= 19:
+		return _ParameterNameCode_name_0[_ParameterNameCode_index_0[i]:_ParameterNameCode_index_0[i+1]]
+	case i == 250:
+		return _ParameterNameCode_name_1
+	default:
 		return "ParameterNameCode(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _ParameterNameCode_name[_ParameterNameCode_index[i]:_ParameterNameCode_index[i+1]]
 }
 func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
@@ -264,30 +274,20 @@ func _() {
 	var x [1]struct{}
 	_ = x[NAIUnknown-0]
 	_ = x[NAISubscriberNumber-1]
+	_ = x[NAIReservedForNationalUse-2]
 	_ = x[NAINationalSignificantNumber-3]
 	_ = x[NAIInternationalNumber-4]
 }
 
-const (
-	_NatureOfAddressIndicator_name_0 = "unknownsubscriber number"
-	_NatureOfAddressIndicator_name_1 = "national significant numberinternational number"
-)
+const _NatureOfAddressIndicator_name = "unknownsubscriber numberreserved for national usenational significant numberinternational number"
 
-var (
-	_NatureOfAddressIndicator_index_0 = [...]uint8{0, 7, 24}
-	_NatureOfAddressIndicator_index_1 = [...]uint8{0, 27, 47}
-)
+var _NatureOfAddressIndicator_index = [...]uint8{0, 7, 24, 49, 76, 96}
 
 func (i NatureOfAddressIndicator) String() string {
-	switch {
-	case i <= 1:
-		return _NatureOfAddressIndicator_name_0[_NatureOfAddressIndicator_index_0[i]:_NatureOfAddressIndicator_index_0[i+1]]
-	case 3 <= i && i <= 4:
-		i -= 3
-		return _NatureOfAddressIndicator_name_1[_NatureOfAddressIndicator_index_1[i]:_NatureOfAddressIndicator_index_1[i+1]]
-	default:
+	if i >= NatureOfAddressIndicator(len(_NatureOfAddressIndicator_index)-1) {
 		return "NatureOfAddressIndicator(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
+	return _NatureOfAddressIndicator_name[_NatureOfAddressIndicator_index[i]:_NatureOfAddressIndicator_index[i+1]]
 }
 func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
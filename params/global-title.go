@@ -1,22 +1,43 @@
 package params
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/wmnsk/go-sccp/utils"
 )
 
 // GlobalTitle is a GlobalTitle inside the Called/Calling Party Address.
-type GlobalTitle struct {
-	// GTI is included in the Address Indicator which is not a part of
-	// Global Title itself, but necessary to encode/decode it properly.
-	GTI GlobalTitleIndicator
-	TranslationType
-	NumberingPlan
-	EncodingScheme
-	NatureOfAddressIndicator
-	AddressInformation []byte
+//
+// The format of the GlobalTitle depends on the Global Title Indicator (GTI)
+// in the Address Indicator of the parent PartyAddress, and each format is
+// implemented as a specific type: GTNAIOnly (0001), GTTTOnly (0010),
+// GTTTNPES (0011), and GTTTNPESNAI (0100). The GTIs that are spare or
+// reserved for national use are represented as GTUnknown.
+type GlobalTitle interface {
+	// GTI returns the Global Title Indicator that corresponds to the format.
+	GTI() GlobalTitleIndicator
+	// Read sets the values retrieved from byte sequence in a GlobalTitle.
+	//
+	// Since GlobalTitle is a part of PartyAddress, and it does not know the length of
+	// the address information, it reads until the end of the given byte sequence.
+	// Thus, the caller should take care of the length of the byte sequence.
+	Read(b []byte) (int, error)
+	// Write serializes GlobalTitle to the given byte sequence.
+	Write(b []byte) (int, error)
+	// MarshalLen returns the serial length of a GlobalTitle.
+	MarshalLen() int
+	// AddressInfo returns the address information (digits) in raw bytes.
+	AddressInfo() []byte
+	// IsOddDigits reports whether the address information has odd number of digits.
+	IsOddDigits() bool
+	// Address returns the address information in a human-friendly string.
+	Address() string
+	fmt.Stringer
 }
 
 // GlobalTitleIndicator is a type of Global Title Indicator.
@@ -39,11 +60,28 @@ type NatureOfAddressIndicator uint8
 const (
 	NAIUnknown                   NatureOfAddressIndicator = 0b00000000 // unknown
 	NAISubscriberNumber          NatureOfAddressIndicator = 0b00000001 // subscriber number
-	_                            NatureOfAddressIndicator = 0b00000010 // reserved for national use
+	NAIReservedForNationalUse    NatureOfAddressIndicator = 0b00000010 // reserved for national use
 	NAINationalSignificantNumber NatureOfAddressIndicator = 0b00000011 // national significant number
 	NAIInternationalNumber       NatureOfAddressIndicator = 0b00000100 // international number
 )
 
+// MarshalText returns the NatureOfAddressIndicator in the same text as String.
+func (nai NatureOfAddressIndicator) MarshalText() ([]byte, error) {
+	return []byte(nai.String()), nil
+}
+
+// UnmarshalText sets the NatureOfAddressIndicator from the text returned by String,
+// or from the value in decimal.
+func (nai *NatureOfAddressIndicator) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnumText[NatureOfAddressIndicator](text, 0xff)
+	if err != nil {
+		return err
+	}
+
+	*nai = v
+	return nil
+}
+
 // Even returns the NatureOfAddressIndicator with the last bit set to 0.
 func (nai NatureOfAddressIndicator) Even() NatureOfAddressIndicator {
 	return nai & 0b01111111
@@ -54,9 +92,6 @@ func (nai NatureOfAddressIndicator) Odd() NatureOfAddressIndicator {
 	return nai | 0b10000000
 }
 
-// TranslationType is a type of Translation Type.
-type TranslationType uint8
-
 // NumberingPlan is a type of Numbering Plan.
 type NumberingPlan uint8
 
@@ -73,6 +108,33 @@ const (
 	NPPrivate        NumberingPlan = 0b1110 // private network or network-specific numbering plan
 )
 
+// NumberingPlan values named after the corresponding recommendations.
+const (
+	NPE164 = NPISDNTelephony  // E.164
+	NPX121 = NPData           // X.121
+	NPF69  = NPTelex          // F.69
+	NPE210 = NPMaritimeMobile // E.210, E.211
+	NPE212 = NPLandMobile     // E.212
+	NPE214 = NPISDNMobile     // E.214
+)
+
+// MarshalText returns the NumberingPlan in the same text as String.
+func (np NumberingPlan) MarshalText() ([]byte, error) {
+	return []byte(np.String()), nil
+}
+
+// UnmarshalText sets the NumberingPlan from the text returned by String,
+// or from the value in decimal.
+func (np *NumberingPlan) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnumText[NumberingPlan](text, 0b1111)
+	if err != nil {
+		return err
+	}
+
+	*np = v
+	return nil
+}
+
 // EncodingScheme is a type of Encoding Scheme.
 type EncodingScheme uint8
 
@@ -84,10 +146,112 @@ const (
 	ESNationalSpecific EncodingScheme = 0b0011 // national specific
 )
 
-// NewGlobalTitle creates a new GlobalTitle.
+// MaxGlobalTitleDigits is the maximum number of digits in a GlobalTitle, which
+// is limited by the one-octet length of the PartyAddress minus the Address
+// Indicator, PC, SSN and the GlobalTitle header.
+const MaxGlobalTitleDigits = (255 - 7) * 2
+
+// ValidateDigits checks that the digits can be encoded into a GlobalTitle in BCD:
+// they consist of hex characters only, are no longer than MaxGlobalTitleDigits,
+// and have the filler or ST ("f") only at the end.
+func ValidateDigits(digits string) error {
+	if len(digits) > MaxGlobalTitleDigits {
+		return fmt.Errorf("%d digits exceed the maximum %d: %w", len(digits), MaxGlobalTitleDigits, ErrInvalidDigits)
+	}
+
+	for i, c := range digits {
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'e', c >= 'A' && c <= 'E':
+		case c == 'f' || c == 'F':
+			if i != len(digits)-1 {
+				return fmt.Errorf("filler at %d in %q is not at the end: %w", i, digits, ErrInvalidDigits)
+			}
+		default:
+			return fmt.Errorf("invalid character %q at %d in %q: %w", c, i, digits, ErrInvalidDigits)
+		}
+	}
+
+	return nil
+}
+
+// validateGlobalTitle checks the address information of the GlobalTitle if it
+// is encoded in BCD.
+func validateGlobalTitle(g GlobalTitle) error {
+	switch g := g.(type) {
+	case *GTNAIOnly, *GTTTOnly:
+		return validateBCD(g.IsOddDigits(), g.AddressInfo())
+	case *GTTTNPES:
+		if g.EncodingScheme.IsBCD() {
+			return validateBCD(g.IsOddDigits(), g.AddressInformation)
+		}
+	case *GTTTNPESNAI:
+		if g.EncodingScheme.IsBCD() {
+			return validateBCD(g.IsOddDigits(), g.AddressInformation)
+		}
+	}
+
+	return nil
+}
+
+func validateBCD(isOdd bool, addr []byte) error {
+	if isOdd && len(addr) == 0 {
+		return fmt.Errorf("odd number of digits without address information: %w", ErrInvalidDigits)
+	}
+
+	return ValidateDigits(utils.BCDDecode(isOdd, addr))
+}
+
+// IsBCD reports whether the EncodingScheme is BCD with either odd or even
+// number of digits.
+func (es EncodingScheme) IsBCD() bool {
+	return es == ESBCDOdd || es == ESBCDEven
+}
+
+// EncodeAddress encodes the digits into the address information in the
+// EncodingScheme.
 //
-// The first argument is a Global Title Indicator, which is included in the Address Indicator
-// in the parent PartyAddress.
+// It returns error if the EncodingScheme is not BCD, or if the number of
+// digits does not match the odd/even of the EncodingScheme. The address
+// information in the other schemes should be given as raw bytes.
+func (es EncodingScheme) EncodeAddress(digits string) ([]byte, error) {
+	if !es.IsBCD() {
+		return nil, fmt.Errorf("cannot encode digits in %s: %w", es, ErrInvalidAddress)
+	}
+	if odd := len(digits)%2 == 1; odd != (es == ESBCDOdd) {
+		return nil, fmt.Errorf("%d digits cannot be encoded in %s: %w", len(digits), es, ErrInvalidAddress)
+	}
+	if err := ValidateDigits(digits); err != nil {
+		return nil, err
+	}
+
+	return utils.BCDEncode(digits)
+}
+
+// DecodeAddress decodes the address information in the EncodingScheme.
+//
+// For the schemes other than BCD, such as national specific, the address
+// information is not decoded but returned as it is in hex string.
+func (es EncodingScheme) DecodeAddress(addr []byte) string {
+	if addr == nil {
+		return ""
+	}
+	if !es.IsBCD() {
+		return hex.EncodeToString(addr)
+	}
+	return utils.BCDDecode(es == ESBCDOdd, addr)
+}
+
+// NewGlobalTitle creates a new GlobalTitle in the format specified by the given
+// Global Title Indicator, which is included in the Address Indicator in the parent
+// PartyAddress.
+//
+// The values that are not used in the format are ignored. For GTI=0001, the odd/even
+// indicator is set if the NatureOfAddressIndicator has the odd bit (see
+// NatureOfAddressIndicator.Odd) or the EncodingScheme is ESBCDOdd.
+// For the spare or national GTIs, the addr is treated as the whole GlobalTitle.
+//
+// The addr is expected to be encoded properly, e.g., with EncodingScheme.EncodeAddress.
+// If the digits in BCD are malformed, it is logged but the GlobalTitle is still created.
 func NewGlobalTitle(
 	gti GlobalTitleIndicator,
 	tt TranslationType,
@@ -95,198 +259,558 @@ func NewGlobalTitle(
 	es EncodingScheme,
 	nai NatureOfAddressIndicator,
 	addr []byte,
-) *GlobalTitle {
-	gt := &GlobalTitle{GTI: gti}
+) GlobalTitle {
+	g := newGlobalTitle(gti, tt, np, es, nai, addr)
+	if err := validateGlobalTitle(g); err != nil {
+		logf("%v", err)
+	}
 
+	return g
+}
+
+func newGlobalTitle(
+	gti GlobalTitleIndicator,
+	tt TranslationType,
+	np NumberingPlan,
+	es EncodingScheme,
+	nai NatureOfAddressIndicator,
+	addr []byte,
+) GlobalTitle {
 	switch gti {
 	case GTINAIOnly:
-		gt.NatureOfAddressIndicator = nai
+		return &GTNAIOnly{
+			OddDigits:                nai&0b10000000 != 0 || es == ESBCDOdd,
+			NatureOfAddressIndicator: nai & 0b01111111,
+			AddressInformation:       addr,
+		}
 	case GTITTOnly:
-		gt.TranslationType = tt
+		return &GTTTOnly{
+			TranslationType:    tt,
+			AddressInformation: addr,
+		}
 	case GTITTNPES:
-		gt.TranslationType = tt
-		gt.NumberingPlan = np
-		gt.EncodingScheme = es
+		return &GTTTNPES{
+			TranslationType:    tt,
+			NumberingPlan:      np,
+			EncodingScheme:     es,
+			AddressInformation: addr,
+		}
 	case GTITTNPESNAI:
-		gt.TranslationType = tt
-		gt.NumberingPlan = np
-		gt.EncodingScheme = es
-		gt.NatureOfAddressIndicator = nai
+		return &GTTTNPESNAI{
+			TranslationType:          tt,
+			NumberingPlan:            np,
+			EncodingScheme:           es,
+			NatureOfAddressIndicator: nai,
+			AddressInformation:       addr,
+		}
+	default:
+		return &GTUnknown{Indicator: gti, Value: addr}
+	}
+}
+
+// ParseGlobalTitle decodes given byte sequence as a GlobalTitle in the format
+// specified by the given Global Title Indicator.
+//
+// The given byte sequence should not include the excess bytes for the parent PartyAddress.
+// otherwise, the address information will include them.
+func ParseGlobalTitle(gti GlobalTitleIndicator, b []byte) (GlobalTitle, error) {
+	g := newGlobalTitleByGTI(VariantITU, gti)
+	if _, err := g.Read(b); err != nil {
+		return nil, err
 	}
 
-	gt.AddressInformation = addr
-	return gt
+	return g, nil
 }
 
-// Write serializes GlobalTitle to the given byte sequence.
-func (g *GlobalTitle) Write(b []byte) (int, error) {
-	l := g.MarshalLen()
-	if len(b) < l {
-		return 0, io.ErrUnexpectedEOF
+// The Global Title Indicators in ANSI, which differ from the ones in ITU.
+const (
+	ansiGTITTNPES GlobalTitleIndicator = 0b0001
+	ansiGTITTOnly GlobalTitleIndicator = 0b0010
+)
+
+func newGlobalTitleByGTI(v Variant, gti GlobalTitleIndicator) GlobalTitle {
+	if v.ansiAddress() {
+		switch gti {
+		case ansiGTITTNPES:
+			return &GTTTNPES{}
+		case ansiGTITTOnly:
+			return &GTTTOnly{}
+		default:
+			return &GTUnknown{Indicator: gti}
+		}
 	}
 
-	n := 0
-	switch g.GTI {
+	switch gti {
 	case GTINAIOnly:
-		b[n] = uint8(g.NatureOfAddressIndicator)
-		n++
+		return &GTNAIOnly{}
 	case GTITTOnly:
-		b[n] = uint8(g.TranslationType)
-		n++
+		return &GTTTOnly{}
 	case GTITTNPES:
-		b[n] = uint8(g.TranslationType)
-		b[n+1] = uint8(g.NumberingPlan)<<4 | uint8(g.EncodingScheme)
-		n += 2
+		return &GTTTNPES{}
 	case GTITTNPESNAI:
-		b[n] = uint8(g.TranslationType)
-		b[n+1] = uint8(g.NumberingPlan)<<4 | uint8(g.EncodingScheme)
-		b[n+2] = uint8(g.NatureOfAddressIndicator)
-		n += 3
+		return &GTTTNPESNAI{}
+	default:
+		return &GTUnknown{Indicator: gti}
+	}
+}
+
+// unmarshalEnumText finds the value in [0, max] whose String matches the text.
+// The value in decimal is also accepted.
+func unmarshalEnumText[T interface {
+	~uint8
+	String() string
+}](text []byte, max T) (T, error) {
+	s := string(text)
+	if n, err := strconv.ParseUint(s, 10, 8); err == nil && T(n) <= max {
+		return T(n), nil
+	}
+
+	for v := T(0); ; v++ {
+		if v.String() == s {
+			return v, nil
+		}
+		if v == max {
+			break
+		}
 	}
 
-	copy(b[n:l], g.AddressInformation)
-	return n, nil
+	var zero T
+	return zero, fmt.Errorf("invalid %T: %q", zero, s)
 }
 
-// MarshalBinary returns the byte sequence generated from a GlobalTitle.
-func (g *GlobalTitle) MarshalBinary() []byte {
-	b := make([]byte, g.MarshalLen())
-	if err := g.MarshalTo(b); err != nil {
-		panic(err)
+// CloneGlobalTitle returns a deep copy of the GlobalTitle that does not share
+// the address information with the original.
+func CloneGlobalTitle(g GlobalTitle) GlobalTitle {
+	switch g := g.(type) {
+	case nil:
+		return nil
+	case *GTNAIOnly:
+		c := *g
+		c.AddressInformation = bytes.Clone(g.AddressInformation)
+		return &c
+	case *GTTTOnly:
+		c := *g
+		c.AddressInformation = bytes.Clone(g.AddressInformation)
+		return &c
+	case *GTTTNPES:
+		c := *g
+		c.AddressInformation = bytes.Clone(g.AddressInformation)
+		return &c
+	case *GTTTNPESNAI:
+		c := *g
+		c.AddressInformation = bytes.Clone(g.AddressInformation)
+		return &c
+	case *GTUnknown:
+		c := *g
+		c.Value = bytes.Clone(g.Value)
+		return &c
+	default:
+		// the GlobalTitle implemented outside of this package.
+		return g
 	}
+}
 
-	return b
+// EqualGlobalTitle reports whether a and b are semantically the same GlobalTitle.
+//
+// The GTI and the fields in the format must be the same, and the digits are
+// compared after removing the trailing filler, so that "123" in BCD odd and
+// "123f" in BCD even are considered equal. The odd/even indicator and the
+// EncodingScheme are not compared as long as they are BCD. The address
+// information in the other encodings and the GTUnknown are compared as raw bytes.
+func EqualGlobalTitle(a, b GlobalTitle) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.GTI() != b.GTI() {
+		return false
+	}
+
+	switch x := a.(type) {
+	case *GTNAIOnly:
+		y, ok := b.(*GTNAIOnly)
+		return ok && x.NatureOfAddressIndicator.Even() == y.NatureOfAddressIndicator.Even() &&
+			trimFiller(x.Address()) == trimFiller(y.Address())
+	case *GTTTOnly:
+		y, ok := b.(*GTTTOnly)
+		return ok && x.TranslationType == y.TranslationType &&
+			trimFiller(x.Address()) == trimFiller(y.Address())
+	case *GTTTNPES:
+		y, ok := b.(*GTTTNPES)
+		return ok && x.TranslationType == y.TranslationType && x.NumberingPlan == y.NumberingPlan &&
+			equalAddress(x.EncodingScheme, y.EncodingScheme, x.AddressInformation, y.AddressInformation)
+	case *GTTTNPESNAI:
+		y, ok := b.(*GTTTNPESNAI)
+		return ok && x.TranslationType == y.TranslationType && x.NumberingPlan == y.NumberingPlan &&
+			x.NatureOfAddressIndicator == y.NatureOfAddressIndicator &&
+			equalAddress(x.EncodingScheme, y.EncodingScheme, x.AddressInformation, y.AddressInformation)
+	default:
+		return bytes.Equal(a.AddressInfo(), b.AddressInfo())
+	}
 }
 
-// MarshalTo puts the byte sequence in the byte array given as b.
-func (g *GlobalTitle) MarshalTo(b []byte) error {
-	if _, err := g.Write(b); err != nil {
-		return err
+func equalAddress(esA, esB EncodingScheme, a, b []byte) bool {
+	if esA.IsBCD() && esB.IsBCD() {
+		return trimFiller(esA.DecodeAddress(a)) == trimFiller(esB.DecodeAddress(b))
 	}
-	return nil
+	return esA == esB && bytes.Equal(a, b)
 }
 
-// ParseGlobalTitle decodes given byte sequence as a GlobalTitle.
-// The given byte sequence should not include the excess bytes for the parent PartyAddress.
-// otherwise, AddressInformation will include them.
-func ParseGlobalTitle(gti GlobalTitleIndicator, b []byte) (*GlobalTitle, error) {
-	g := &GlobalTitle{GTI: gti}
-	if err := g.UnmarshalBinary(b); err != nil {
-		return nil, err
+func trimFiller(digits string) string {
+	return strings.TrimRight(digits, "fF")
+}
+
+func decodeAddress(isOdd bool, addr []byte) string {
+	if addr == nil {
+		return ""
 	}
+	return utils.BCDDecode(isOdd, addr)
+}
 
-	return g, nil
+// GTNAIOnly is a GlobalTitle with GTI=0001, which includes the odd/even indicator
+// and the nature of address indicator only.
+type GTNAIOnly struct {
+	OddDigits bool
+	NatureOfAddressIndicator
+	AddressInformation []byte
 }
 
-// Read sets the values retrieved from byte sequence in a GlobalTitle.
-//
-// Since GlobalTitle is a part of PartyAddress, and it does not know the length of the
-// AddressInformation, it reads until the end of the given byte sequence. Thus, the
-// caller should take care of the length of the byte sequence.
-func (g *GlobalTitle) Read(b []byte) (int, error) {
-	if len(b) < g.lenByGTI() {
+// GTI returns GTINAIOnly.
+func (g *GTNAIOnly) GTI() GlobalTitleIndicator {
+	return GTINAIOnly
+}
+
+// Read sets the values retrieved from byte sequence in a GTNAIOnly.
+func (g *GTNAIOnly) Read(b []byte) (int, error) {
+	if len(b) < 1 {
 		return 0, io.ErrUnexpectedEOF
 	}
 
-	n := 0
-	switch g.GTI {
-	case GTINAIOnly:
-		g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[n])
-		n++
-	case GTITTOnly:
-		g.TranslationType = TranslationType(b[n])
-		n++
-	case GTITTNPES:
-		g.TranslationType = TranslationType(b[n])
-		g.NumberingPlan = NumberingPlan(b[n+1] >> 4)
-		g.EncodingScheme = EncodingScheme(b[n+1] & 0x0F)
-		n += 2
-	case GTITTNPESNAI:
-		g.TranslationType = TranslationType(b[n])
-		g.NumberingPlan = NumberingPlan(b[n+1] >> 4)
-		g.EncodingScheme = EncodingScheme(b[n+1] & 0x0F)
-		g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[n+2])
-		n += 3
+	g.OddDigits = b[0]&0b10000000 != 0
+	g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[0] & 0b01111111)
+	g.AddressInformation = b[1:]
+	return len(b), validateGlobalTitle(g)
+}
+
+// Write serializes GTNAIOnly to the given byte sequence.
+func (g *GTNAIOnly) Write(b []byte) (int, error) {
+	l := g.MarshalLen()
+	if len(b) < l {
+		return 0, io.ErrUnexpectedEOF
 	}
 
-	g.AddressInformation = b[n:]
-	return len(b), nil
+	b[0] = uint8(g.NatureOfAddressIndicator) & 0b01111111
+	if g.OddDigits {
+		b[0] |= 0b10000000
+	}
+	copy(b[1:l], g.AddressInformation)
+	return l, nil
 }
 
-// UnmarshalBinary sets the values retrieved from byte sequence in a GlobalTitle.
-// The given byte sequence should not include the excess bytes for the parent PartyAddress.
-// otherwise, AddressInformation will include them.
-func (g *GlobalTitle) UnmarshalBinary(b []byte) error {
-	if len(b) < g.lenByGTI() {
-		return io.ErrUnexpectedEOF
+// MarshalLen returns the serial length of a GTNAIOnly.
+func (g *GTNAIOnly) MarshalLen() int {
+	return 1 + len(g.AddressInformation)
+}
+
+// AddressInfo returns the address information in raw bytes.
+func (g *GTNAIOnly) AddressInfo() []byte {
+	return g.AddressInformation
+}
+
+// IsOddDigits reports whether the odd/even indicator is set to odd.
+func (g *GTNAIOnly) IsOddDigits() bool {
+	return g.OddDigits
+}
+
+// Address returns the AddressInformation in a human-friendly string.
+func (g *GTNAIOnly) Address() string {
+	return decodeAddress(g.IsOddDigits(), g.AddressInformation)
+}
+
+// String returns the GTNAIOnly in a human-readable format.
+func (g *GTNAIOnly) String() string {
+	return fmt.Sprint(g)
+}
+
+// Format implements fmt.Formatter.
+func (g *GTNAIOnly) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{GTI: %#04b, OddDigits: %v, NatureOfAddressIndicator: %s, AddressInformation: %s}",
+		g.GTI(), g.OddDigits, g.NatureOfAddressIndicator, g.Address(),
+	)
+}
+
+// GTTTOnly is a GlobalTitle with GTI=0010, which includes the translation type only.
+type GTTTOnly struct {
+	TranslationType
+	AddressInformation []byte
+}
+
+// GTI returns GTITTOnly.
+func (g *GTTTOnly) GTI() GlobalTitleIndicator {
+	return GTITTOnly
+}
+
+// Read sets the values retrieved from byte sequence in a GTTTOnly.
+func (g *GTTTOnly) Read(b []byte) (int, error) {
+	if len(b) < 1 {
+		return 0, io.ErrUnexpectedEOF
 	}
 
-	offset := 0
-	switch g.GTI {
-	case GTINAIOnly:
-		g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[offset])
-		offset++
-	case GTITTOnly:
-		g.TranslationType = TranslationType(b[offset])
-		offset++
-	case GTITTNPES:
-		g.TranslationType = TranslationType(b[offset])
-		g.NumberingPlan = NumberingPlan(b[offset+1] >> 4)
-		g.EncodingScheme = EncodingScheme(b[offset+1] & 0x0F)
-		offset += 2
-	case GTITTNPESNAI:
-		g.TranslationType = TranslationType(b[offset])
-		g.NumberingPlan = NumberingPlan(b[offset+1] >> 4)
-		g.EncodingScheme = EncodingScheme(b[offset+1] & 0x0F)
-		g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[offset+2])
-		offset += 3
+	g.TranslationType = TranslationType(b[0])
+	g.AddressInformation = b[1:]
+	return len(b), validateGlobalTitle(g)
+}
+
+// Write serializes GTTTOnly to the given byte sequence.
+func (g *GTTTOnly) Write(b []byte) (int, error) {
+	l := g.MarshalLen()
+	if len(b) < l {
+		return 0, io.ErrUnexpectedEOF
 	}
 
-	g.AddressInformation = b[offset:]
-	return nil
+	b[0] = uint8(g.TranslationType)
+	copy(b[1:l], g.AddressInformation)
+	return l, nil
 }
 
-// MarshalLen returns the serial length of a GlobalTitle.
-func (g *GlobalTitle) MarshalLen() int {
-	return g.lenByGTI()
+// MarshalLen returns the serial length of a GTTTOnly.
+func (g *GTTTOnly) MarshalLen() int {
+	return 1 + len(g.AddressInformation)
 }
 
-func (g *GlobalTitle) lenByGTI() int {
-	var l int
-	switch g.GTI {
-	case GTINAIOnly:
-		l += 1
-	case GTITTOnly:
-		l += 1
-	case GTITTNPES:
-		l += 2
-	case GTITTNPESNAI:
-		l += 3
+// AddressInfo returns the address information in raw bytes.
+func (g *GTTTOnly) AddressInfo() []byte {
+	return g.AddressInformation
+}
+
+// IsOddDigits always returns false, as GTI=0010 has no information on the
+// number of digits.
+func (g *GTTTOnly) IsOddDigits() bool {
+	return false
+}
+
+// Address returns the AddressInformation in a human-friendly string.
+func (g *GTTTOnly) Address() string {
+	return decodeAddress(g.IsOddDigits(), g.AddressInformation)
+}
+
+// String returns the GTTTOnly in a human-readable format.
+func (g *GTTTOnly) String() string {
+	return fmt.Sprint(g)
+}
+
+// Format implements fmt.Formatter.
+func (g *GTTTOnly) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{GTI: %#04b, TranslationType: %s, AddressInformation: %s}",
+		g.GTI(), g.TranslationType, g.Address(),
+	)
+}
+
+// GTTTNPES is a GlobalTitle with GTI=0011, which includes the translation type,
+// numbering plan and encoding scheme.
+type GTTTNPES struct {
+	TranslationType
+	NumberingPlan
+	EncodingScheme
+	AddressInformation []byte
+}
+
+// GTI returns GTITTNPES.
+func (g *GTTTNPES) GTI() GlobalTitleIndicator {
+	return GTITTNPES
+}
+
+// Read sets the values retrieved from byte sequence in a GTTTNPES.
+func (g *GTTTNPES) Read(b []byte) (int, error) {
+	if len(b) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	g.TranslationType = TranslationType(b[0])
+	g.NumberingPlan = NumberingPlan(b[1] >> 4)
+	g.EncodingScheme = EncodingScheme(b[1] & 0x0F)
+	g.AddressInformation = b[2:]
+	return len(b), validateGlobalTitle(g)
+}
+
+// Write serializes GTTTNPES to the given byte sequence.
+func (g *GTTTNPES) Write(b []byte) (int, error) {
+	l := g.MarshalLen()
+	if len(b) < l {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(g.TranslationType)
+	b[1] = uint8(g.NumberingPlan)<<4 | uint8(g.EncodingScheme)&0x0F
+	copy(b[2:l], g.AddressInformation)
+	return l, nil
+}
+
+// MarshalLen returns the serial length of a GTTTNPES.
+func (g *GTTTNPES) MarshalLen() int {
+	return 2 + len(g.AddressInformation)
+}
+
+// AddressInfo returns the address information in raw bytes.
+func (g *GTTTNPES) AddressInfo() []byte {
+	return g.AddressInformation
+}
+
+// IsOddDigits reports whether AddressInformation is odd number or not.
+func (g *GTTTNPES) IsOddDigits() bool {
+	return g.EncodingScheme == ESBCDOdd
+}
+
+// Address returns the AddressInformation decoded in the EncodingScheme.
+// See EncodingScheme.DecodeAddress for the schemes other than BCD.
+func (g *GTTTNPES) Address() string {
+	return g.EncodingScheme.DecodeAddress(g.AddressInformation)
+}
+
+// String returns the GTTTNPES in a human-readable format.
+func (g *GTTTNPES) String() string {
+	return fmt.Sprint(g)
+}
+
+// Format implements fmt.Formatter.
+func (g *GTTTNPES) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{GTI: %#04b, TranslationType: %s, NumberingPlan: %s, EncodingScheme: %s, AddressInformation: %s}",
+		g.GTI(), g.TranslationType, g.NumberingPlan, g.EncodingScheme, g.Address(),
+	)
+}
+
+// GTTTNPESNAI is a GlobalTitle with GTI=0100, which includes the translation type,
+// numbering plan, encoding scheme and nature of address indicator.
+type GTTTNPESNAI struct {
+	TranslationType
+	NumberingPlan
+	EncodingScheme
+	NatureOfAddressIndicator
+	AddressInformation []byte
+}
+
+// GTI returns GTITTNPESNAI.
+func (g *GTTTNPESNAI) GTI() GlobalTitleIndicator {
+	return GTITTNPESNAI
+}
+
+// Read sets the values retrieved from byte sequence in a GTTTNPESNAI.
+func (g *GTTTNPESNAI) Read(b []byte) (int, error) {
+	if len(b) < 3 {
+		return 0, io.ErrUnexpectedEOF
 	}
 
-	if g.AddressInformation != nil {
-		l += len(g.AddressInformation)
+	g.TranslationType = TranslationType(b[0])
+	g.NumberingPlan = NumberingPlan(b[1] >> 4)
+	g.EncodingScheme = EncodingScheme(b[1] & 0x0F)
+	g.NatureOfAddressIndicator = NatureOfAddressIndicator(b[2])
+	g.AddressInformation = b[3:]
+	return len(b), validateGlobalTitle(g)
+}
+
+// Write serializes GTTTNPESNAI to the given byte sequence.
+func (g *GTTTNPESNAI) Write(b []byte) (int, error) {
+	l := g.MarshalLen()
+	if len(b) < l {
+		return 0, io.ErrUnexpectedEOF
 	}
 
-	return l
+	b[0] = uint8(g.TranslationType)
+	b[1] = uint8(g.NumberingPlan)<<4 | uint8(g.EncodingScheme)&0x0F
+	b[2] = uint8(g.NatureOfAddressIndicator)
+	copy(b[3:l], g.AddressInformation)
+	return l, nil
+}
+
+// MarshalLen returns the serial length of a GTTTNPESNAI.
+func (g *GTTTNPESNAI) MarshalLen() int {
+	return 3 + len(g.AddressInformation)
+}
+
+// AddressInfo returns the address information in raw bytes.
+func (g *GTTTNPESNAI) AddressInfo() []byte {
+	return g.AddressInformation
 }
 
 // IsOddDigits reports whether AddressInformation is odd number or not.
-func (g *GlobalTitle) IsOddDigits() bool {
+func (g *GTTTNPESNAI) IsOddDigits() bool {
 	return g.EncodingScheme == ESBCDOdd
 }
 
-// String returns the GlobalTitle in a human-readable format.
-func (g *GlobalTitle) String() string {
-	return fmt.Sprintf("{GTI: %#04b, TransationType: %d, NumberingPlan: %s, EncodingScheme: %s, NatureOfAddressIndicator: %s, AddressInformation: %s}",
-		g.GTI, g.TranslationType, g.NumberingPlan, g.EncodingScheme, g.NatureOfAddressIndicator, g.Address(),
+// Address returns the AddressInformation decoded in the EncodingScheme.
+// See EncodingScheme.DecodeAddress for the schemes other than BCD.
+func (g *GTTTNPESNAI) Address() string {
+	return g.EncodingScheme.DecodeAddress(g.AddressInformation)
+}
+
+// String returns the GTTTNPESNAI in a human-readable format.
+func (g *GTTTNPESNAI) String() string {
+	return fmt.Sprint(g)
+}
+
+// Format implements fmt.Formatter.
+func (g *GTTTNPESNAI) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{GTI: %#04b, TranslationType: %s, NumberingPlan: %s, EncodingScheme: %s, NatureOfAddressIndicator: %s, AddressInformation: %s}",
+		g.GTI(), g.TranslationType, g.NumberingPlan, g.EncodingScheme, g.NatureOfAddressIndicator, g.Address(),
 	)
 }
 
-// Address returns the AddressInformation in a human-friendly string.
-func (g *GlobalTitle) Address() string {
-	if g.AddressInformation == nil {
-		return ""
+// GTUnknown is a GlobalTitle with the GTI that is spare or reserved for
+// national use. As its format is not known, the whole GlobalTitle is kept
+// as it is in Value.
+type GTUnknown struct {
+	Indicator GlobalTitleIndicator
+	Value     []byte
+}
+
+// GTI returns the Global Title Indicator of the GTUnknown.
+func (g *GTUnknown) GTI() GlobalTitleIndicator {
+	return g.Indicator
+}
+
+// Read sets the values retrieved from byte sequence in a GTUnknown.
+func (g *GTUnknown) Read(b []byte) (int, error) {
+	g.Value = b
+	return len(b), nil
+}
+
+// Write serializes GTUnknown to the given byte sequence.
+func (g *GTUnknown) Write(b []byte) (int, error) {
+	l := g.MarshalLen()
+	if len(b) < l {
+		return 0, io.ErrUnexpectedEOF
 	}
-	return utils.BCDDecode(g.IsOddDigits(), g.AddressInformation)
+
+	copy(b[:l], g.Value)
+	return l, nil
+}
+
+// MarshalLen returns the serial length of a GTUnknown.
+func (g *GTUnknown) MarshalLen() int {
+	return len(g.Value)
+}
+
+// AddressInfo returns the whole GlobalTitle in raw bytes, as the position of
+// the address information is unknown.
+func (g *GTUnknown) AddressInfo() []byte {
+	return g.Value
+}
+
+// IsOddDigits always returns false.
+func (g *GTUnknown) IsOddDigits() bool {
+	return false
+}
+
+// Address returns the Value in hex string.
+func (g *GTUnknown) Address() string {
+	return fmt.Sprintf("%x", g.Value)
+}
+
+// String returns the GTUnknown in a human-readable format.
+func (g *GTUnknown) String() string {
+	return fmt.Sprint(g)
+}
+
+// Format implements fmt.Formatter.
+func (g *GTUnknown) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{GTI: %#04b, Value: %x}", g.GTI(), g.Value)
 }
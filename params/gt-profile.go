@@ -0,0 +1,32 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+// GTProfile is a combination of the fields of the GlobalTitle used for a kind
+// of numbers in a network, so that the PartyAddresses for them are built in
+// the same form.
+type GTProfile struct {
+	GTI GlobalTitleIndicator
+	TT  TranslationType
+	NP  NumberingPlan
+	NAI NatureOfAddressIndicator
+}
+
+// GT sets the GlobalTitle with digits in the form of the GTProfile to the
+// AddressBuilder, and returns it.
+func (p GTProfile) GT(a *AddressBuilder, digits string) *AddressBuilder {
+	return a.GT(p.GTI, p.TT, p.NP, p.NAI, digits)
+}
+
+// The GTProfiles commonly used in the Chinese national networks, where the
+// GT is GTI=0100 with TT=0, and the MSISDN and the numbers of the network
+// elements are in the international format with the country code 86 on the
+// inter-network links, and the national format within a network.
+var (
+	GTProfileChinaE164International = GTProfile{GTITTNPESNAI, TTUnknown, NPE164, NAIInternationalNumber}
+	GTProfileChinaE164National      = GTProfile{GTITTNPESNAI, TTUnknown, NPE164, NAINationalSignificantNumber}
+	GTProfileChinaE212              = GTProfile{GTITTNPESNAI, TTUnknown, NPE212, NAIInternationalNumber}
+	GTProfileChinaE214              = GTProfile{GTITTNPESNAI, TTUnknown, NPE214, NAIInternationalNumber}
+)
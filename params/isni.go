@@ -0,0 +1,212 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ISNIRoutingIndicator is the ISNI Routing Indicator (IRI) in the ISNI routing control.
+type ISNIRoutingIndicator uint8
+
+// ISNIRoutingIndicator values defined in T1.112 3.20.
+const (
+	IRINeitherConstrainedNorSuggested ISNIRoutingIndicator = 0b00
+	IRIConstrained                    ISNIRoutingIndicator = 0b01
+	IRISuggested                      ISNIRoutingIndicator = 0b10
+)
+
+// ISNINetwork is a network identification in the ISNI, which consists of the
+// network and cluster identification of the ANSI point code.
+type ISNINetwork struct {
+	Network uint8
+	Cluster uint8
+}
+
+// ISNI represents the Intermediate Signaling Network Identification, the
+// optional parameter of the ANSI XUDT and XUDTS defined in T1.112 3.20.
+//
+// It carries the list of the networks the message has traversed or should
+// traverse. NetworkSpecific is present only if TypeIndicator is set (type 1).
+type ISNI struct {
+	paramType ParameterType
+	code      ParameterNameCode
+	length    int
+
+	MarkIdentification bool
+	RoutingIndicator   ISNIRoutingIndicator
+	TypeIndicator      bool
+	Counter            uint8
+	NetworkSpecific    uint8
+	Networks           []ISNINetwork
+}
+
+// NewISNI creates a new type 0 ISNI.
+func NewISNI(mi bool, iri ISNIRoutingIndicator, counter uint8, networks ...ISNINetwork) *ISNI {
+	i := &ISNI{
+		paramType:          PTypeO,
+		code:               PCodeISNI,
+		MarkIdentification: mi,
+		RoutingIndicator:   iri & 0b11,
+		Counter:            counter & 0b111,
+		Networks:           networks,
+	}
+
+	i.SetLength()
+	return i
+}
+
+// NewISNIType1 creates a new type 1 ISNI, which has the network specific octet.
+func NewISNIType1(mi bool, iri ISNIRoutingIndicator, counter, netSpecific uint8, networks ...ISNINetwork) *ISNI {
+	i := NewISNI(mi, iri, counter, networks...)
+	i.TypeIndicator = true
+	i.NetworkSpecific = netSpecific
+
+	i.SetLength()
+	return i
+}
+
+// ParseISNI parses the given byte sequence as an ISNI.
+func ParseISNI(b []byte) (*ISNI, int, error) {
+	i := &ISNI{}
+	n, err := i.Read(b)
+	if err != nil {
+		return nil, n, err
+	}
+
+	return i, n, nil
+}
+
+// Read sets the values retrieved from byte sequence in an ISNI.
+func (i *ISNI) Read(b []byte) (int, error) {
+	i.paramType = PTypeO
+
+	if len(b) < 3 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	i.code = ParameterNameCode(b[0])
+	if i.code != PCodeISNI {
+		logf("invalid parameter code: expected %d, got %d", PCodeISNI, i.code)
+	}
+
+	i.length = int(b[1])
+	n := 2 + i.length
+	if len(b) < n || i.length < 1 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	rc := b[2]
+	i.MarkIdentification = rc&0b1 != 0
+	i.RoutingIndicator = ISNIRoutingIndicator(rc >> 1 & 0b11)
+	i.TypeIndicator = rc&0b10000 != 0
+	i.Counter = rc >> 5
+
+	offset := 3
+	i.NetworkSpecific = 0
+	if i.TypeIndicator {
+		if offset >= n {
+			return offset, io.ErrUnexpectedEOF
+		}
+		i.NetworkSpecific = b[offset]
+		offset++
+	}
+
+	if (n-offset)%2 != 0 {
+		logf("%s: odd length of network identifications: %d", PCodeISNI, n-offset)
+	}
+
+	i.Networks = nil
+	for ; offset+1 < n; offset += 2 {
+		i.Networks = append(i.Networks, ISNINetwork{Network: b[offset], Cluster: b[offset+1]})
+	}
+
+	return n, nil
+}
+
+// Write serializes the ISNI parameter and returns it as a byte slice.
+func (i *ISNI) Write(b []byte) (int, error) {
+	n := i.MarshalLen()
+	if len(b) < n {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(i.code)
+	b[1] = uint8(i.length)
+
+	rc := uint8(i.RoutingIndicator&0b11)<<1 | (i.Counter&0b111)<<5
+	if i.MarkIdentification {
+		rc |= 0b1
+	}
+	if i.TypeIndicator {
+		rc |= 0b10000
+	}
+	b[2] = rc
+
+	offset := 3
+	if i.TypeIndicator {
+		b[offset] = i.NetworkSpecific
+		offset++
+	}
+
+	for _, nw := range i.Networks {
+		b[offset] = nw.Network
+		b[offset+1] = nw.Cluster
+		offset += 2
+	}
+
+	return n, nil
+}
+
+// MarshalLen returns the serial length of ISNI.
+func (i *ISNI) MarshalLen() int {
+	l := 3 + 2*len(i.Networks) // Parameter Name + length + routing control
+	if i.TypeIndicator {
+		l++
+	}
+	return l
+}
+
+// AppendTo appends the serialized ISNI to dst and returns the extended slice.
+func (i *ISNI) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, i)
+}
+
+// SetLength sets the length in length field.
+// This should be called after changing the values in ISNI.
+func (i *ISNI) SetLength() {
+	i.length = i.MarshalLen() - 2
+}
+
+// Code returns the ISNI in ParameterNameCode.
+func (i *ISNI) Code() ParameterNameCode {
+	return i.code
+}
+
+// Clone returns a deep copy of the ISNI.
+func (i *ISNI) Clone() *ISNI {
+	if i == nil {
+		return nil
+	}
+
+	c := *i
+	c.Networks = slices.Clone(i.Networks)
+	return &c
+}
+
+// String returns the ISNI in string.
+func (i *ISNI) String() string {
+	return fmt.Sprint(i)
+}
+
+// Format implements fmt.Formatter.
+func (i *ISNI) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f,
+		"{%s (%s): {MarkIdentification: %v, RoutingIndicator: %d, TypeIndicator: %v, Counter: %d, NetworkSpecific: %d, Networks: %v}}",
+		i.code, i.paramType, i.MarkIdentification, i.RoutingIndicator, i.TypeIndicator, i.Counter, i.NetworkSpecific, i.Networks,
+	)
+}
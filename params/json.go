@@ -0,0 +1,276 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// partyAddressJSON is the JSON representation of PartyAddress.
+type partyAddressJSON struct {
+	Type       string           `json:"type"`
+	Optional   bool             `json:"optional,omitempty"`
+	Variant    string           `json:"variant,omitempty"`
+	RouteOnSSN bool             `json:"routeOnSSN"`
+	National   bool             `json:"national,omitempty"`
+	PC         *PointCode       `json:"pc,omitempty"`
+	SSN        *uint8           `json:"ssn,omitempty"`
+	GT         *globalTitleJSON `json:"gt,omitempty"`
+}
+
+// globalTitleJSON is the JSON representation of GlobalTitle.
+//
+// The fields that are not used in the format specified by GTI are omitted.
+type globalTitleJSON struct {
+	GTI    GlobalTitleIndicator      `json:"gti"`
+	TT     *TranslationType          `json:"tt,omitempty"`
+	NP     *NumberingPlan            `json:"np,omitempty"`
+	ES     *EncodingScheme           `json:"es,omitempty"`
+	NAI    *NatureOfAddressIndicator `json:"nai,omitempty"`
+	Digits string                    `json:"digits"`
+}
+
+// MarshalJSON returns the PartyAddress in JSON, with the PC, SSN and GlobalTitle
+// in readable form instead of the raw bytes.
+//
+// The NumberingPlan and NatureOfAddressIndicator are represented with their
+// names (see MarshalText), and the digits of the GlobalTitle are decoded in
+// the EncodingScheme (see EncodingScheme.DecodeAddress).
+func (p *PartyAddress) MarshalJSON() ([]byte, error) {
+	v := &partyAddressJSON{
+		Type:       "called",
+		Optional:   p.paramType == PTypeO,
+		RouteOnSSN: p.RouteOnSSN(),
+		National:   p.Indicator&0b10000000 != 0,
+	}
+	if p.code == PCodeCallingPartyAddress {
+		v.Type = "calling"
+	}
+	if p.variant != VariantITU {
+		v.Variant = p.variant.String()
+	}
+	if p.HasPC() {
+		pc := p.SignalingPointCode
+		v.PC = &pc
+	}
+	if p.HasSSN() {
+		ssn := p.SubsystemNumber
+		v.SSN = &ssn
+	}
+	if p.GlobalTitle != nil {
+		v.GT = globalTitleToJSON(p.GlobalTitle)
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the PartyAddress.
+//
+// The Address Indicator and the length are computed from the values.
+func (p *PartyAddress) UnmarshalJSON(b []byte) error {
+	v := &partyAddressJSON{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+
+	code := PCodeCalledPartyAddress
+	switch v.Type {
+	case "called", "":
+	case "calling":
+		code = PCodeCallingPartyAddress
+	default:
+		return fmt.Errorf("invalid PartyAddress type %q: %w", v.Type, ErrInvalidAddress)
+	}
+
+	variant := VariantITU
+	if v.Variant != "" {
+		var err error
+		if variant, err = ParseVariant(v.Variant); err != nil {
+			return fmt.Errorf("invalid variant %q: %w", v.Variant, ErrInvalidAddress)
+		}
+	}
+
+	var (
+		gt  GlobalTitle
+		gti GlobalTitleIndicator
+		pc  PointCode
+		ssn uint8
+	)
+	if v.GT != nil {
+		var err error
+		if gt, err = v.GT.globalTitle(); err != nil {
+			return err
+		}
+		gti = variant.GTI(gt)
+	}
+	if v.PC != nil {
+		pc = *v.PC
+	}
+	if v.SSN != nil {
+		ssn = *v.SSN
+	}
+
+	ai := variant.NewAddressIndicator(v.PC != nil, v.SSN != nil, v.RouteOnSSN, gti)
+	if v.National {
+		ai |= 0b10000000
+	} else {
+		ai &= 0b01111111
+	}
+
+	*p = *NewPartyAddressVariant(variant, code, ai, pc, ssn, gt)
+	if v.Optional {
+		p.paramType = PTypeO
+	}
+
+	return nil
+}
+
+func globalTitleToJSON(g GlobalTitle) *globalTitleJSON {
+	v := &globalTitleJSON{GTI: g.GTI(), Digits: g.Address()}
+	switch g := g.(type) {
+	case *GTNAIOnly:
+		nai := g.NatureOfAddressIndicator.Even()
+		v.NAI = &nai
+	case *GTTTOnly:
+		v.TT = &g.TranslationType
+	case *GTTTNPES:
+		v.TT, v.NP, v.ES = &g.TranslationType, &g.NumberingPlan, &g.EncodingScheme
+	case *GTTTNPESNAI:
+		v.TT, v.NP, v.ES, v.NAI = &g.TranslationType, &g.NumberingPlan, &g.EncodingScheme, &g.NatureOfAddressIndicator
+	}
+
+	return v
+}
+
+func (v *globalTitleJSON) globalTitle() (GlobalTitle, error) {
+	var (
+		tt  TranslationType
+		np  NumberingPlan
+		nai NatureOfAddressIndicator
+	)
+	if v.TT != nil {
+		tt = *v.TT
+	}
+	if v.NP != nil {
+		np = *v.NP
+	}
+	if v.NAI != nil {
+		nai = *v.NAI
+	}
+
+	// the EncodingScheme is derived from the number of digits if not given.
+	es := ESBCDEven
+	if len(v.Digits)%2 == 1 {
+		es = ESBCDOdd
+	}
+	if v.ES != nil {
+		es = *v.ES
+	}
+
+	var (
+		addr []byte
+		err  error
+	)
+	switch {
+	case v.GTI == GTINoGT:
+		return nil, fmt.Errorf("GTI %d given with GT: %w", v.GTI, ErrInvalidAddress)
+	case v.GTI > GTITTNPESNAI, !es.IsBCD():
+		addr, err = hex.DecodeString(v.Digits)
+	case v.GTI == GTITTOnly && es == ESBCDOdd:
+		// GTI=0010 has no odd/even indication; the filler is kept in the digits.
+		addr, err = ESBCDEven.EncodeAddress(v.Digits + "f")
+	default:
+		addr, err = es.EncodeAddress(v.Digits)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid GT digits %q: %w", v.Digits, err)
+	}
+
+	if v.GTI == GTINAIOnly && es == ESBCDOdd {
+		nai = nai.Odd()
+	}
+	return NewGlobalTitle(v.GTI, tt, np, es, nai, addr), nil
+}
+
+// UnmarshalGlobalTitleJSON decodes the JSON created by MarshalJSON of the
+// GlobalTitle types into the GlobalTitle in the format specified by "gti".
+func UnmarshalGlobalTitleJSON(b []byte) (GlobalTitle, error) {
+	v := &globalTitleJSON{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+
+	return v.globalTitle()
+}
+
+func unmarshalGlobalTitleJSON[T any, PT interface {
+	*T
+	GlobalTitle
+}](b []byte, dst PT) error {
+	gt, err := UnmarshalGlobalTitleJSON(b)
+	if err != nil {
+		return err
+	}
+
+	g, ok := gt.(PT)
+	if !ok {
+		return fmt.Errorf("GTI %d cannot be decoded into %T: %w", gt.GTI(), dst, ErrInvalidAddress)
+	}
+
+	*dst = *g
+	return nil
+}
+
+// MarshalJSON returns the GTNAIOnly in JSON with the digits in readable form.
+func (g *GTNAIOnly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(globalTitleToJSON(g))
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the GTNAIOnly.
+func (g *GTNAIOnly) UnmarshalJSON(b []byte) error {
+	return unmarshalGlobalTitleJSON(b, g)
+}
+
+// MarshalJSON returns the GTTTOnly in JSON with the digits in readable form.
+func (g *GTTTOnly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(globalTitleToJSON(g))
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the GTTTOnly.
+func (g *GTTTOnly) UnmarshalJSON(b []byte) error {
+	return unmarshalGlobalTitleJSON(b, g)
+}
+
+// MarshalJSON returns the GTTTNPES in JSON with the digits in readable form.
+func (g *GTTTNPES) MarshalJSON() ([]byte, error) {
+	return json.Marshal(globalTitleToJSON(g))
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the GTTTNPES.
+func (g *GTTTNPES) UnmarshalJSON(b []byte) error {
+	return unmarshalGlobalTitleJSON(b, g)
+}
+
+// MarshalJSON returns the GTTTNPESNAI in JSON with the digits in readable form.
+func (g *GTTTNPESNAI) MarshalJSON() ([]byte, error) {
+	return json.Marshal(globalTitleToJSON(g))
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the GTTTNPESNAI.
+func (g *GTTTNPESNAI) UnmarshalJSON(b []byte) error {
+	return unmarshalGlobalTitleJSON(b, g)
+}
+
+// MarshalJSON returns the GTUnknown in JSON with the digits in readable form.
+func (g *GTUnknown) MarshalJSON() ([]byte, error) {
+	return json.Marshal(globalTitleToJSON(g))
+}
+
+// UnmarshalJSON sets the values in the JSON created by MarshalJSON to the GTUnknown.
+func (g *GTUnknown) UnmarshalJSON(b []byte) error {
+	return unmarshalGlobalTitleJSON(b, g)
+}
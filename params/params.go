@@ -4,10 +4,14 @@
 package params
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 
+	"github.com/wmnsk/go-sccp/ssn"
 	"github.com/wmnsk/go-sccp/utils"
 )
 
@@ -19,14 +23,72 @@ func (e UnsupportedParameterError) Error() string {
 	return fmt.Sprintf("sccp: got unsupported type %d", e)
 }
 
+// ErrValueTooLong indicates that the value of a parameter cannot be represented
+// within the length field of the parameter.
+var ErrValueTooLong = errors.New("sccp: value too long for the length field")
+
+// ErrInvalidAddress indicates that the combination of the values in a
+// PartyAddress is invalid.
+var ErrInvalidAddress = errors.New("sccp: invalid address")
+
+// ErrInvalidDigits indicates that the digits of a GlobalTitle are malformed.
+var ErrInvalidDigits = errors.New("sccp: invalid GT digits")
+
+// ErrInvalidVariant indicates that the name of a Variant is unknown.
+var ErrInvalidVariant = errors.New("sccp: invalid variant")
+
 // Parameter is an interface that all SCCP parameters have to implement.
+//
+// Read decodes the parameter from b and Write encodes it into b, both returning
+// the number of bytes including the Parameter Name and length octets if the
+// ParameterType of the parameter has them. MarshalLen returns the number of
+// bytes Write writes, and AppendTo appends them to dst, growing it only if it
+// does not have enough capacity. Code identifies the parameter in the optional
+// part.
 type Parameter interface {
 	io.ReadWriter
 	MarshalLen() int
+	AppendTo(dst []byte) ([]byte, error)
 	Code() ParameterNameCode
 	fmt.Stringer
 }
 
+// appendParameter appends p serialized with Write to dst, growing it only if
+// it does not have enough capacity.
+func appendParameter(dst []byte, p Parameter) ([]byte, error) {
+	n := p.MarshalLen()
+	dst = slices.Grow(dst, n)
+	b := dst[len(dst) : len(dst)+n]
+	clear(b) // Write expects the zeroed buffer.
+	if _, err := p.Write(b); err != nil {
+		return dst, err
+	}
+
+	return dst[:len(dst)+n], nil
+}
+
+var (
+	_ Parameter = (*EndOfOptionalParameters)(nil)
+	_ Parameter = (*LocalReference)(nil)
+	_ Parameter = (*PartyAddress)(nil)
+	_ Parameter = (*ProtocolClass)(nil)
+	_ Parameter = (*SegmentingReassembling)(nil)
+	_ Parameter = (*ReceiveSequenceNumber)(nil)
+	_ Parameter = (*SequencingSegmenting)(nil)
+	_ Parameter = (*Credit)(nil)
+	_ Parameter = (*ReleaseCause)(nil)
+	_ Parameter = (*ReturnCause)(nil)
+	_ Parameter = (*ResetCause)(nil)
+	_ Parameter = (*ErrorCause)(nil)
+	_ Parameter = (*RefusalCause)(nil)
+	_ Parameter = (*Data)(nil)
+	_ Parameter = (*Segmentation)(nil)
+	_ Parameter = (*HopCounter)(nil)
+	_ Parameter = (*Importance)(nil)
+	_ Parameter = (*LongData)(nil)
+	_ Parameter = (*ISNI)(nil)
+)
+
 // ParameterType is a type for Parameter described in the tables in section 4 of Q.713.
 type ParameterType uint8
 
@@ -85,27 +147,34 @@ const (
 	PCodeImportance ParameterNameCode = 0b00010010 // Importance
 	// V
 	PCodeLongData ParameterNameCode = 0b00010011 // Long data
+	// O (ANSI T1.112)
+	PCodeISNI ParameterNameCode = 0b11111010 // Intermediate signaling network identification
 )
 
-// ParseOptionalParameters parses optional parameters from the given byte sequence.
+// ParseOptionalParameters parses optional parameters from the given byte sequence
+// until the End of Optional Parameters is found.
+//
+// The returned int is the number of bytes consumed, including the End of Optional Parameters.
 func ParseOptionalParameters(b []byte) ([]Parameter, int, error) {
 	var params []Parameter
 	var offset int
-	for len(b) > 0 {
+	for {
 		p, n, err := ParseOptionalParameter(b[offset:])
 		if err != nil {
 			return nil, offset, err
 		}
 		params = append(params, p)
+		offset += n
 		if p.Code() == PCodeEndOfOptionalParameters {
-			break
+			return params, offset, nil
 		}
-		offset += n
 	}
-	return params, offset, nil
 }
 
 // ParseOptionalParameter parses a single optional parameter from the given byte sequence.
+//
+// The parameter whose Parameter Name is not known is returned as an
+// UnknownParameter.
 func ParseOptionalParameter(b []byte) (Parameter, int, error) {
 	if len(b) < 1 {
 		return nil, 0, io.ErrUnexpectedEOF
@@ -135,8 +204,10 @@ func ParseOptionalParameter(b []byte) (Parameter, int, error) {
 		p = &HopCounter{paramType: PTypeO}
 	case PCodeImportance:
 		p = &Importance{paramType: PTypeO}
+	case PCodeISNI:
+		p = &ISNI{paramType: PTypeO}
 	default:
-		return nil, 0, UnsupportedParameterError(b[0])
+		p = &UnknownParameter{}
 	}
 
 	n, err := p.Read(b)
@@ -225,6 +296,11 @@ func (e *EndOfOptionalParameters) MarshalLen() int {
 	return e.length
 }
 
+// AppendTo appends the serialized EndOfOptionalParameters to dst and returns the extended slice.
+func (e *EndOfOptionalParameters) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, e)
+}
+
 // Code returns the EndOfOptionalParameters in ParameterNameCode.
 func (e *EndOfOptionalParameters) Code() ParameterNameCode {
 	return e.code
@@ -237,7 +313,12 @@ func (e *EndOfOptionalParameters) Value() uint8 {
 
 // String returns the EndOfOptionalParameters in string.
 func (e *EndOfOptionalParameters) String() string {
-	return fmt.Sprintf("{%s (%s): %d}", e.code, e.paramType, e.value)
+	return fmt.Sprint(e)
+}
+
+// Format implements fmt.Formatter.
+func (e *EndOfOptionalParameters) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %d}", e.code, e.paramType, e.value)
 }
 
 // LocalReference represents the Destination/Source Local Reference.
@@ -322,6 +403,11 @@ func (l *LocalReference) MarshalLen() int {
 	return l.length
 }
 
+// AppendTo appends the serialized LocalReference to dst and returns the extended slice.
+func (l *LocalReference) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, l)
+}
+
 // Code returns the LocalReference in ParameterNameCode.
 func (l *LocalReference) Code() ParameterNameCode {
 	return l.code
@@ -334,10 +420,27 @@ func (l *LocalReference) Value() []byte {
 
 // String returns the LocalReference in string.
 func (l *LocalReference) String() string {
+	return fmt.Sprint(l)
+}
+
+// Format implements fmt.Formatter.
+func (l *LocalReference) Format(f fmt.State, verb rune) {
 	if l.code == PCodeDestinationLocalReference || l.code == PCodeSourceLocalReference {
-		return fmt.Sprintf("{%s (%s): %d}", l.code, l.paramType, l.Uint32())
+		fmt.Fprintf(f, "{%s (%s): %d}", l.code, l.paramType, l.Uint32())
+		return
+	}
+	fmt.Fprintf(f, "{%s (%s): %d}", "(Destination or Source) local reference", l.paramType, l.Uint32())
+}
+
+// Clone returns a copy of the LocalReference that does not share the value with the original.
+func (l *LocalReference) Clone() *LocalReference {
+	if l == nil {
+		return nil
 	}
-	return fmt.Sprintf("{%s (%s): %d}", "(Destination or Source) local reference", l.paramType, l.Uint32())
+
+	c := *l
+	c.value = bytes.Clone(l.value)
+	return &c
 }
 
 // Uint32 returns the LocalReference in uint32.
@@ -352,11 +455,35 @@ type PartyAddress struct {
 	length    int
 
 	Indicator          uint8
-	SignalingPointCode uint16
+	SignalingPointCode PointCode
 	SubsystemNumber    uint8
-	*GlobalTitle
+	GlobalTitle
+
+	// Diagnostics is the list of the anomalies found on parsing, such as the
+	// non-zero spare bits or the reserved combination of the indicators, that
+	// do not prevent the PartyAddress from being decoded.
+	//
+	// They are recorded in both the strict and the lenient modes, as the
+	// addresses with them have always been accepted. The lenient mode of
+	// ParsePartyAddressLenient differs only in that the malformed digits of
+	// the GlobalTitle are also recorded here instead of being returned as an
+	// error.
+	Diagnostics []error
+
+	variant Variant
+	pcCodec PointCodeCodec
+	lenient bool
 }
 
+// RoutingIndicator is a type of Routing Indicator in the Address Indicator.
+type RoutingIndicator uint8
+
+// RoutingIndicator values.
+const (
+	RIRouteOnGT  RoutingIndicator = 0 // route on GT
+	RIRouteOnSSN RoutingIndicator = 1 // route on DPC + SSN
+)
+
 // NewAddressIndicator creates a new AddressIndicator, which is meant to be used in
 // NewCalled/CallingPartyAddress as the first argument.
 //
@@ -386,7 +513,15 @@ func NewAddressIndicator(hasPC, hasSSN, routeOnSSN bool, gti GlobalTitleIndicato
 // When you are aware of the type of PartyAddress you are creating, you can use
 // NewCalled/CallingPartyAddress to create a PartyAddress with the correct code.
 // Otherwise, you can use AsCalled/Calling to set the code after creating a PartyAddress.
-func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc PointCode, ssn uint8, gt GlobalTitle) *PartyAddress {
+	return NewPartyAddressVariant(VariantITU, cdcg, ai, spc, ssn, gt)
+}
+
+// NewPartyAddressVariant creates a new PartyAddress in the format of the given Variant.
+//
+// The AddressIndicator should be in the format of the Variant, e.g., the one created by
+// NewANSIAddressIndicator for VariantANSI.
+func NewPartyAddressVariant(v Variant, cdcg ParameterNameCode, ai uint8, spc PointCode, ssn uint8, gt GlobalTitle) *PartyAddress {
 	if cdcg != PCodeCalledPartyAddress && cdcg != PCodeCallingPartyAddress {
 		logf("invalid parameter code: expected %v or %v, got %v", PCodeCalledPartyAddress, PCodeCallingPartyAddress, cdcg)
 	}
@@ -396,6 +531,7 @@ func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt
 		code:        cdcg,
 		Indicator:   ai,
 		GlobalTitle: gt,
+		variant:     v,
 	}
 
 	if p.HasPC() {
@@ -411,29 +547,29 @@ func NewPartyAddress(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt
 }
 
 // NewPartyAddressOptional creates a new PartyAddress from properly-typed values.
-func NewPartyAddressOptional(cdcg ParameterNameCode, ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewPartyAddressOptional(cdcg ParameterNameCode, ai uint8, spc PointCode, ssn uint8, gt GlobalTitle) *PartyAddress {
 	p := NewPartyAddress(cdcg, ai, spc, ssn, gt)
 	p.paramType = PTypeO
 	return p
 }
 
 // NewCalledPartyAddress creates a new PartyAddress for Called Party Address.
-func NewCalledPartyAddress(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCalledPartyAddress(ai uint8, spc PointCode, ssn uint8, gt GlobalTitle) *PartyAddress {
 	return NewPartyAddress(PCodeCalledPartyAddress, ai, spc, ssn, gt)
 }
 
 // NewCallingPartyAddress creates a new PartyAddress for Calling Party Address.
-func NewCallingPartyAddress(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCallingPartyAddress(ai uint8, spc PointCode, ssn uint8, gt GlobalTitle) *PartyAddress {
 	return NewPartyAddress(PCodeCallingPartyAddress, ai, spc, ssn, gt)
 }
 
 // NewCalledPartyAddressOptional creates a new PartyAddress for Called Party Address as an optional parameter.
-func NewCalledPartyAddressOptional(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCalledPartyAddressOptional(ai uint8, spc PointCode, ssn uint8, gt GlobalTitle) *PartyAddress {
 	return NewPartyAddressOptional(PCodeCalledPartyAddress, ai, spc, ssn, gt)
 }
 
 // NewCallingPartyAddressOptional creates a new PartyAddress for Calling Party Address as an optional parameter.
-func NewCallingPartyAddressOptional(ai uint8, spc uint16, ssn uint8, gt *GlobalTitle) *PartyAddress {
+func NewCallingPartyAddressOptional(ai uint8, spc PointCode, ssn uint8, gt GlobalTitle) *PartyAddress {
 	return NewPartyAddressOptional(PCodeCallingPartyAddress, ai, spc, ssn, gt)
 }
 
@@ -461,10 +597,68 @@ func ParseCallingPartyAddressOptional(b []byte) (*PartyAddress, int, error) {
 	return parsePartyAddress(PTypeO, PCodeCallingPartyAddress, b)
 }
 
+// ParsePartyAddressVariant parses the given byte sequence as a mandatory variable length
+// PartyAddress with the given code in the format of the given Variant.
+func ParsePartyAddressVariant(v Variant, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
+	return parsePartyAddressVariant(v, PTypeV, code, b)
+}
+
+// ParsePartyAddressOptionalVariant parses the given byte sequence as an optional
+// PartyAddress with the given code in the format of the given Variant.
+func ParsePartyAddressOptionalVariant(v Variant, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
+	return parsePartyAddressVariant(v, PTypeO, code, b)
+}
+
+// ParsePartyAddressLenient parses the given byte sequence in the same way as
+// ParsePartyAddressVariant, but it records the malformed digits of the GlobalTitle
+// in Diagnostics instead of returning error, so that the traffic from the legacy
+// nodes that do not strictly follow the specification can still be decoded.
+//
+// The digit validation is the only difference from ParsePartyAddressVariant;
+// the spare bits and the reserved indicators are recorded in Diagnostics
+// without error by both.
+func ParsePartyAddressLenient(v Variant, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
+	p := &PartyAddress{
+		paramType: PTypeV,
+		code:      code,
+		variant:   v,
+		lenient:   true,
+	}
+
+	n, err := p.Read(b)
+	if err != nil {
+		return nil, n, err
+	}
+
+	return p, n, nil
+}
+
+// ParsePartyAddressOptionalLenient is the optional version of ParsePartyAddressLenient.
+func ParsePartyAddressOptionalLenient(v Variant, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
+	p := &PartyAddress{
+		paramType: PTypeO,
+		code:      code,
+		variant:   v,
+		lenient:   true,
+	}
+
+	n, err := p.Read(b)
+	if err != nil {
+		return nil, n, err
+	}
+
+	return p, n, nil
+}
+
 func parsePartyAddress(ptype ParameterType, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
+	return parsePartyAddressVariant(VariantITU, ptype, code, b)
+}
+
+func parsePartyAddressVariant(v Variant, ptype ParameterType, code ParameterNameCode, b []byte) (*PartyAddress, int, error) {
 	p := &PartyAddress{
 		paramType: ptype,
 		code:      code,
+		variant:   v,
 	}
 
 	n, err := p.Read(b)
@@ -494,40 +688,78 @@ func (p *PartyAddress) read(b []byte) (int, error) {
 
 	p.length = int(b[0])
 	p.Indicator = b[1]
+	p.GlobalTitle = nil
+	p.Diagnostics = nil
 
 	if int(p.length) != len(b)-1 {
 		return n, io.ErrUnexpectedEOF
 	}
 
+	// ANSI puts SSN before PC.
+	if p.variant.ansiAddress() && p.HasSSN() {
+		if n >= len(b) {
+			return n, io.ErrUnexpectedEOF
+		}
+		p.SubsystemNumber = b[n]
+		n++
+	}
+
 	if p.HasPC() {
-		end := n + 2
-		if end >= len(b) {
+		end := n + p.PointCodeCodec().Len()
+		if end > len(b) {
 			return n, io.ErrUnexpectedEOF
 		}
-		p.SignalingPointCode = binary.LittleEndian.Uint16(b[n:end])
+		p.SignalingPointCode = p.PointCodeCodec().Decode(b[n:end])
 		n = end
 	}
 
-	if p.HasSSN() {
+	if !p.variant.ansiAddress() && p.HasSSN() {
+		if n >= len(b) {
+			return n, io.ErrUnexpectedEOF
+		}
 		p.SubsystemNumber = b[n]
 		n++
 	}
 
 	gti := p.GTI()
 	if gti == 0 {
+		p.diagnose()
 		return n, nil
 	}
 
-	p.GlobalTitle = &GlobalTitle{GTI: gti}
+	p.GlobalTitle = newGlobalTitleByGTI(p.variant, gti)
 	m, err := p.GlobalTitle.Read(b[n : int(p.length)+1])
 	if err != nil {
-		return n + m, err
+		if !p.lenient || !errors.Is(err, ErrInvalidDigits) {
+			return n + m, err
+		}
+		p.Diagnostics = append(p.Diagnostics, err)
 	}
 	n += m
 
+	p.diagnose()
 	return n, nil
 }
 
+// diagnose records the anomalies in the PartyAddress that are tolerated on parsing.
+func (p *PartyAddress) diagnose() {
+	if p.RouteOnGT() && p.GTI() == GTINoGT {
+		p.Diagnostics = append(p.Diagnostics, fmt.Errorf("route on GT without GT: %w", ErrInvalidAddress))
+	}
+	if p.variant == VariantTTC && !p.IsNational() {
+		p.Diagnostics = append(p.Diagnostics, errors.New("national indicator is not set in TTC address"))
+	}
+
+	switch g := p.GlobalTitle.(type) {
+	case *GTUnknown:
+		p.Diagnostics = append(p.Diagnostics, fmt.Errorf("GTI %d is spare or reserved for national use", g.Indicator))
+	case *GTTTNPESNAI:
+		if g.NatureOfAddressIndicator&0b10000000 != 0 {
+			p.Diagnostics = append(p.Diagnostics, fmt.Errorf("spare bit is set in NAI %#02x", uint8(g.NatureOfAddressIndicator)))
+		}
+	}
+}
+
 func (p *PartyAddress) readOptional(b []byte) (int, error) {
 	n := 3
 	if len(b) < n {
@@ -542,7 +774,15 @@ func (p *PartyAddress) readOptional(b []byte) (int, error) {
 		)
 	}
 
-	return p.read(b[1:])
+	// the length is checked against the slice in read, so cut off the rest
+	// of the optional part.
+	end := 2 + int(b[1])
+	if len(b) < end {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	m, err := p.read(b[1:end])
+	return m + 1, err
 }
 
 // Write serializes the PartyAddress parameter and returns it as a byte slice.
@@ -554,7 +794,11 @@ func (p *PartyAddress) Write(b []byte) (int, error) {
 }
 
 func (p *PartyAddress) write(b []byte) (int, error) {
-	if len(b) < p.MarshalLen() {
+	l := p.MarshalLen()
+	if p.paramType == PTypeO {
+		l-- // Parameter Name is written by writeOptional
+	}
+	if len(b) < l {
 		return 0, io.ErrUnexpectedEOF
 	}
 
@@ -562,12 +806,20 @@ func (p *PartyAddress) write(b []byte) (int, error) {
 	b[1] = p.Indicator
 
 	var n = 2
+	// ANSI puts SSN before PC.
+	if p.variant.ansiAddress() && p.HasSSN() {
+		b[n] = p.SubsystemNumber
+		n++
+	}
+
 	if p.HasPC() {
-		binary.LittleEndian.PutUint16(b[n:n+2], p.SignalingPointCode)
-		n += 2
+		if err := p.PointCodeCodec().Encode(b[n:], p.SignalingPointCode); err != nil {
+			return n, err
+		}
+		n += p.PointCodeCodec().Len()
 	}
 
-	if p.HasSSN() {
+	if !p.variant.ansiAddress() && p.HasSSN() {
 		b[n] = p.SubsystemNumber
 		n++
 	}
@@ -599,9 +851,12 @@ func (p *PartyAddress) writeOptional(b []byte) (int, error) {
 
 // MarshalLen returns the serial length.
 func (p *PartyAddress) MarshalLen() int {
-	l := 2
+	l := 2 // length + Address Indicator
+	if p.paramType == PTypeO {
+		l++ // Parameter Name
+	}
 	if p.HasPC() {
-		l += 2
+		l += p.PointCodeCodec().Len()
 	}
 
 	if p.HasSSN() {
@@ -615,6 +870,11 @@ func (p *PartyAddress) MarshalLen() int {
 	return l
 }
 
+// AppendTo appends the serialized PartyAddress to dst and returns the extended slice.
+func (p *PartyAddress) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, p)
+}
+
 // Code returns the PartyAddress in ParameterNameCode.
 func (p *PartyAddress) Code() ParameterNameCode {
 	return p.code
@@ -627,8 +887,18 @@ func (p *PartyAddress) Value() *PartyAddress {
 
 // String returns the PartyAddress values in human readable format.
 func (p *PartyAddress) String() string {
-	return fmt.Sprintf("{%s (%s): {length: %d, Indicator: %#08b, SignalingPointCode: %d, SubsystemNumber: %d, GlobalTitle: %v}}",
-		p.code, p.paramType, p.length, p.Indicator, p.SignalingPointCode, p.SubsystemNumber, p.GlobalTitle,
+	return fmt.Sprint(p)
+}
+
+// Network returns "sccp", which makes PartyAddress a net.Addr.
+func (p *PartyAddress) Network() string {
+	return "sccp"
+}
+
+// Format implements fmt.Formatter.
+func (p *PartyAddress) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): {length: %d, Indicator: %#08b, SignalingPointCode: %s, SubsystemNumber: %s, GlobalTitle: %v}}",
+		p.code, p.paramType, p.length, p.Indicator, p.variant.FormatPointCode(p.SignalingPointCode), ssn.Format(p.SubsystemNumber), p.GlobalTitle,
 	)
 }
 
@@ -642,7 +912,8 @@ func (p *PartyAddress) RouteOnSSN() bool {
 	return !p.RouteOnGT()
 }
 
-// GTI returns GlobalTitleIndicator value retrieved from Indicator.
+// GTI returns GlobalTitleIndicator value retrieved from Indicator, which is
+// in the format of the Variant. See Variant.GTI.
 func (p *PartyAddress) GTI() GlobalTitleIndicator {
 	return gti(int(p.Indicator))
 }
@@ -653,21 +924,248 @@ func gti(ai int) GlobalTitleIndicator {
 
 // HasSSN reports whether PartyAddress has a Subsystem Number.
 func (p *PartyAddress) HasSSN() bool {
-	return (int(p.Indicator) >> 1 & 0b1) == 1
+	if p.variant.ansiAddress() {
+		return p.Indicator&0b01 != 0
+	}
+	return p.Indicator&0b10 != 0
 }
 
 // HasPC reports whether PartyAddress has a Signaling Point Code.
 func (p *PartyAddress) HasPC() bool {
-	return (int(p.Indicator) & 0b1) == 1
+	if p.variant.ansiAddress() {
+		return p.Indicator&0b10 != 0
+	}
+	return p.Indicator&0b01 != 0
+}
+
+// IsSSNOnly reports whether PartyAddress has only the Subsystem Number and is
+// routed on SSN, that is, the minimal address created by NewSSNAddress.
+func (p *PartyAddress) IsSSNOnly() bool {
+	return p.HasSSN() && !p.HasPC() && p.GTI() == GTINoGT && p.RouteOnSSN()
+}
+
+// RoutingIndicator returns the Routing Indicator retrieved from Indicator.
+func (p *PartyAddress) RoutingIndicator() RoutingIndicator {
+	return RoutingIndicator(p.Indicator >> 6 & 0b1)
+}
+
+// SetRoutingIndicator sets the Routing Indicator in Indicator.
+func (p *PartyAddress) SetRoutingIndicator(ri RoutingIndicator) {
+	p.Indicator = p.Indicator&^0b01000000 | uint8(ri&0b1)<<6
+}
+
+// SetGTI sets the Global Title Indicator in Indicator.
+//
+// The GlobalTitle is not changed; use SetGlobalTitle to set both at once.
+func (p *PartyAddress) SetGTI(gti GlobalTitleIndicator) {
+	p.Indicator = p.Indicator&^0b00111100 | uint8(gti&0b1111)<<2
+}
+
+// SetGlobalTitle sets the GlobalTitle, and the Global Title Indicator and the
+// length accordingly. Setting nil removes the GlobalTitle.
+func (p *PartyAddress) SetGlobalTitle(gt GlobalTitle) {
+	p.GlobalTitle = gt
+	if gt == nil {
+		p.SetGTI(GTINoGT)
+	} else {
+		p.SetGTI(p.variant.GTI(gt))
+	}
+	p.SetLength()
+}
+
+// SetHasSSN sets or clears the SSN indicator in Indicator, and updates the
+// length accordingly. The SubsystemNumber is set to 0 when cleared.
+func (p *PartyAddress) SetHasSSN(has bool) {
+	bit := uint8(0b10)
+	if p.variant.ansiAddress() {
+		bit = 0b01
+	}
+	p.setIndicatorBit(bit, has)
+	if !has {
+		p.SubsystemNumber = 0
+	}
+	p.SetLength()
+}
+
+// SetHasPC sets or clears the PC indicator in Indicator, and updates the
+// length accordingly. The SignalingPointCode is set to 0 when cleared.
+func (p *PartyAddress) SetHasPC(has bool) {
+	bit := uint8(0b01)
+	if p.variant.ansiAddress() {
+		bit = 0b10
+	}
+	p.setIndicatorBit(bit, has)
+	if !has {
+		p.SignalingPointCode = 0
+	}
+	p.SetLength()
+}
+
+// IsNational reports whether the bit reserved for national use (ITU) or the
+// national indicator (ANSI, TTC) is set in Indicator.
+func (p *PartyAddress) IsNational() bool {
+	return p.Indicator&0b10000000 != 0
+}
+
+// SetNational sets or clears the bit reserved for national use (ITU) or the
+// national indicator (ANSI, TTC) in Indicator.
+func (p *PartyAddress) SetNational(national bool) {
+	p.setIndicatorBit(0b10000000, national)
+}
+
+func (p *PartyAddress) setIndicatorBit(bit uint8, set bool) {
+	if set {
+		p.Indicator |= bit
+	} else {
+		p.Indicator &^= bit
+	}
+}
+
+// Variant returns the Variant of the format of the PartyAddress.
+func (p *PartyAddress) Variant() Variant {
+	return p.variant
+}
+
+// SetVariant sets the Variant of the format of the PartyAddress, and updates
+// the length accordingly.
+//
+// Note that the Indicator is not converted, as its layout also depends on the Variant.
+func (p *PartyAddress) SetVariant(v Variant) {
+	p.variant = v
+	p.SetLength()
+}
+
+// PointCodeCodec returns the PointCodeCodec used to encode and decode the
+// Signalling Point Code. Unless set otherwise, it is the one of the Variant.
+func (p *PartyAddress) PointCodeCodec() PointCodeCodec {
+	if p.pcCodec != nil {
+		return p.pcCodec
+	}
+	return p.variant.PointCodeCodec()
+}
+
+// SetPointCodeCodec sets the PointCodeCodec used to encode and decode the
+// Signalling Point Code, and updates the length accordingly.
+//
+// To decode the PartyAddress with the codec, set it to a PartyAddress before
+// calling Read.
+func (p *PartyAddress) SetPointCodeCodec(c PointCodeCodec) {
+	p.pcCodec = c
+	p.SetLength()
+}
+
+// Equal reports whether p and other are semantically the same address.
+//
+// The PC and SSN are compared only when they are indicated, and the GlobalTitle
+// is compared with EqualGlobalTitle, which ignores the filler digits. The bit
+// reserved for national use in the Indicator, the parameter type and code (i.e.,
+// whether it is Called or Calling Party Address) are not compared.
+//
+// Use EqualBytes to compare the addresses byte by byte.
+func (p *PartyAddress) Equal(other *PartyAddress) bool {
+	if p == nil || other == nil {
+		return p == nil && other == nil
+	}
+
+	if p.Indicator&0b01111111 != other.Indicator&0b01111111 || p.variant != other.variant {
+		return false
+	}
+	if p.HasPC() && p.SignalingPointCode != other.SignalingPointCode {
+		return false
+	}
+	if p.HasSSN() && p.SubsystemNumber != other.SubsystemNumber {
+		return false
+	}
+
+	return EqualGlobalTitle(p.GlobalTitle, other.GlobalTitle)
+}
+
+// EqualBytes reports whether p and other are serialized into the same bytes,
+// ignoring the parameter type and code.
+func (p *PartyAddress) EqualBytes(other *PartyAddress) bool {
+	if p == nil || other == nil {
+		return p == nil && other == nil
+	}
+
+	a, b := *p, *other
+	a.paramType, b.paramType = PTypeV, PTypeV
+
+	ba, errA := marshalPartyAddress(&a)
+	bb, errB := marshalPartyAddress(&b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(ba, bb)
+}
+
+func marshalPartyAddress(p *PartyAddress) ([]byte, error) {
+	p.SetLength()
+	b := make([]byte, p.MarshalLen())
+	if _, err := p.Write(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AsCalled sets the parameter code to Called Party Address and returns the PartyAddress.
+func (p *PartyAddress) AsCalled() *PartyAddress {
+	p.code = PCodeCalledPartyAddress
+	return p
+}
+
+// AsCalling sets the parameter code to Calling Party Address and returns the PartyAddress.
+func (p *PartyAddress) AsCalling() *PartyAddress {
+	p.code = PCodeCallingPartyAddress
+	return p
+}
+
+// Clone returns a deep copy of the PartyAddress, including the GlobalTitle,
+// that does not share any memory with the original.
+func (p *PartyAddress) Clone() *PartyAddress {
+	if p == nil {
+		return nil
+	}
+
+	c := *p
+	c.GlobalTitle = CloneGlobalTitle(p.GlobalTitle)
+	c.Diagnostics = slices.Clone(p.Diagnostics)
+	return &c
+}
+
+// Reset clears the values in the PartyAddress to be read again with Read, so
+// that the allocated one can be reused. The parameter type and code, and the
+// way it is decoded, e.g., the Variant, are kept.
+func (p *PartyAddress) Reset() {
+	*p = PartyAddress{
+		paramType: p.paramType,
+		code:      p.code,
+		variant:   p.variant,
+		pcCodec:   p.pcCodec,
+		lenient:   p.lenient,
+	}
 }
 
 // SetLength sets the length in length field.
 // This should be called after changing the values in PartyAddress.
 func (p *PartyAddress) SetLength() {
 	p.length = p.MarshalLen() - 1
+	if p.paramType == PTypeO {
+		p.length-- // Parameter Name
+	}
 }
 
+// Protocol Class definitions in Q.713 3.6.
+const (
+	ClassBasicConnectionless           int = 0
+	ClassSequencedConnectionless       int = 1
+	ClassBasicConnectionOriented       int = 2
+	ClassFlowControlConnectionOriented int = 3
+)
+
 // ProtocolClass is a Protocol Class SCCP parameter.
+//
+// The bits 5-8 are the message handling for the connectionless classes (0 and
+// 1), and are spare for the connection-oriented classes (2 and 3).
 type ProtocolClass struct {
 	paramType ParameterType
 	code      ParameterNameCode
@@ -685,6 +1183,10 @@ func NewProtocolClass(cls int, returnOnError bool) *ProtocolClass {
 	}
 
 	if returnOnError {
+		if p.IsConnectionOriented() {
+			logf("return on error is not applicable to class %d, ignored", cls)
+			return p
+		}
 		p.value = uint8(cls | 0x80)
 	}
 
@@ -731,6 +1233,11 @@ func (p *ProtocolClass) MarshalLen() int {
 	return p.length
 }
 
+// AppendTo appends the serialized ProtocolClass to dst and returns the extended slice.
+func (p *ProtocolClass) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, p)
+}
+
 // Code returns the ProtocolClass in ParameterNameCode.
 func (p *ProtocolClass) Code() ParameterNameCode {
 	return p.code
@@ -743,7 +1250,12 @@ func (p *ProtocolClass) Value() uint8 {
 
 // String returns the ProtocolClass in string.
 func (p *ProtocolClass) String() string {
-	return fmt.Sprintf(
+	return fmt.Sprint(p)
+}
+
+// Format implements fmt.Formatter.
+func (p *ProtocolClass) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f,
 		"{%s (%s): {Class: %d, ReturnOnError: %v}}",
 		p.code, p.paramType, p.Class(), p.ReturnOnError(),
 	)
@@ -755,10 +1267,37 @@ func (p *ProtocolClass) Class() int {
 }
 
 // ReturnOnError judges if ProtocolClass has "Return Message On Error" option.
+//
+// It is always false for the connection-oriented classes, as the bits are spare.
 func (p *ProtocolClass) ReturnOnError() bool {
+	if p.IsConnectionOriented() {
+		return false
+	}
 	return (int(p.value) >> 7) == 1
 }
 
+// MessageHandling returns the message handling part (bits 5-8) of the
+// ProtocolClass, or 0 for the connection-oriented classes, as the bits are
+// spare and should be ignored on receipt.
+func (p *ProtocolClass) MessageHandling() uint8 {
+	if p.IsConnectionOriented() {
+		return 0
+	}
+	return p.value >> 4
+}
+
+// IsConnectionless reports whether the class is 0 or 1.
+func (p *ProtocolClass) IsConnectionless() bool {
+	c := p.Class()
+	return c == ClassBasicConnectionless || c == ClassSequencedConnectionless
+}
+
+// IsConnectionOriented reports whether the class is 2 or 3.
+func (p *ProtocolClass) IsConnectionOriented() bool {
+	c := p.Class()
+	return c == ClassBasicConnectionOriented || c == ClassFlowControlConnectionOriented
+}
+
 // SegmentingReassembling represents the Segmenting/Reassembling.
 type SegmentingReassembling struct {
 	paramType ParameterType
@@ -803,7 +1342,7 @@ func (s *SegmentingReassembling) Read(b []byte) (int, error) {
 	s.paramType = PTypeF
 	s.code = PCodeSegmentingReassembling
 	s.length = n
-	s.value = b[0]
+	s.value = b[0] & 0b1
 
 	return n, nil
 }
@@ -823,6 +1362,11 @@ func (s *SegmentingReassembling) MarshalLen() int {
 	return s.length
 }
 
+// AppendTo appends the serialized SegmentingReassembling to dst and returns the extended slice.
+func (s *SegmentingReassembling) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, s)
+}
+
 // Code returns the SegmentingReassembling in ParameterNameCode.
 func (s *SegmentingReassembling) Code() ParameterNameCode {
 	return s.code
@@ -835,14 +1379,34 @@ func (s *SegmentingReassembling) Value() uint8 {
 
 // String returns the SegmentingReassembling in string.
 func (s *SegmentingReassembling) String() string {
-	return fmt.Sprintf("{%s (%s): %d}", s.code, s.paramType, s.value)
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter.
+func (s *SegmentingReassembling) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): {More: %v}}", s.code, s.paramType, s.More())
 }
 
 // MoreData judges if the message has more data.
 func (s *SegmentingReassembling) MoreData() bool {
+	return s.More()
+}
+
+// More reports whether the M-bit is set, i.e., more data follows in the subsequent DT1.
+func (s *SegmentingReassembling) More() bool {
 	return s.value&0b1 == 1
 }
 
+// SetMore sets or clears the M-bit in the SegmentingReassembling.
+// The other bits are spare and always set to 0.
+func (s *SegmentingReassembling) SetMore(more bool) {
+	if more {
+		s.value = 1
+		return
+	}
+	s.value = 0
+}
+
 // ReceiveSequenceNumber represents the Receive Sequence Number.
 type ReceiveSequenceNumber struct {
 	paramType ParameterType
@@ -853,6 +1417,9 @@ type ReceiveSequenceNumber struct {
 
 // NewReceiveSequenceNumber creates a new ReceiveSequenceNumber.
 // The value is masked out to 0b11111110 since the LSB is spare.
+//
+// The given value is the whole octet; use NewReceiveSequenceNumberPR to
+// create one from the 7-bit P(R) value.
 func NewReceiveSequenceNumber(v uint8) *ReceiveSequenceNumber {
 	return &ReceiveSequenceNumber{
 		paramType: PTypeF,
@@ -862,6 +1429,12 @@ func NewReceiveSequenceNumber(v uint8) *ReceiveSequenceNumber {
 	}
 }
 
+// NewReceiveSequenceNumberPR creates a new ReceiveSequenceNumber from the
+// 7-bit P(R) value. The values exceeding 7 bits are cut off.
+func NewReceiveSequenceNumberPR(pr uint8) *ReceiveSequenceNumber {
+	return NewReceiveSequenceNumber(pr << 1)
+}
+
 // ParseReceiveSequenceNumber parses the given byte sequence as a ReceiveSequenceNumber.
 func ParseReceiveSequenceNumber(b []byte) (*ReceiveSequenceNumber, int, error) {
 	r := &ReceiveSequenceNumber{}
@@ -903,6 +1476,11 @@ func (r *ReceiveSequenceNumber) MarshalLen() int {
 	return r.length
 }
 
+// AppendTo appends the serialized ReceiveSequenceNumber to dst and returns the extended slice.
+func (r *ReceiveSequenceNumber) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, r)
+}
+
 // Code returns the ReceiveSequenceNumber in ParameterNameCode.
 func (r *ReceiveSequenceNumber) Code() ParameterNameCode {
 	return r.code
@@ -915,7 +1493,24 @@ func (r *ReceiveSequenceNumber) Value() uint8 {
 
 // String returns the ReceiveSequenceNumber in string.
 func (r *ReceiveSequenceNumber) String() string {
-	return fmt.Sprintf("{%s (%s): %d}", r.code, r.paramType, r.value)
+	return fmt.Sprint(r)
+}
+
+// Format implements fmt.Formatter.
+func (r *ReceiveSequenceNumber) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %d}", r.code, r.paramType, r.PR())
+}
+
+// PR returns the P(R), the next send sequence number expected, which is
+// carried in bits 8-2 of the octet.
+func (r *ReceiveSequenceNumber) PR() uint8 {
+	return r.value >> 1
+}
+
+// SetPR sets the P(R) in the ReceiveSequenceNumber, leaving the spare bit as 0.
+// The values exceeding 7 bits are cut off.
+func (r *ReceiveSequenceNumber) SetPR(pr uint8) {
+	r.value = pr << 1
 }
 
 // SequencingSegmenting represents the Sequencing/Segmenting.
@@ -929,17 +1524,27 @@ type SequencingSegmenting struct {
 }
 
 // NewSequencingSegmenting creates a new SequencingSegmenting.
+//
+// The sequence numbers are given as the whole octets, and the LSBs are masked
+// out since they are spare (first octet) or used for the More Data bit (second octet).
+// Use NewSequencingSegmentingPSPR to create one from the 7-bit P(S) and P(R) values.
 func NewSequencingSegmenting(snd, rcv uint8, moreData bool) *SequencingSegmenting {
 	return &SequencingSegmenting{
 		paramType:             PTypeF,
 		code:                  PCodeSequencingSegmenting,
 		length:                2,
-		SendSequenceNumber:    snd & 0b01111111,
-		ReceiveSequenceNumber: rcv & 0b01111111,
+		SendSequenceNumber:    snd & 0b11111110,
+		ReceiveSequenceNumber: rcv & 0b11111110,
 		MoreData:              moreData,
 	}
 }
 
+// NewSequencingSegmentingPSPR creates a new SequencingSegmenting from the 7-bit
+// P(S) and P(R) values. The values exceeding 7 bits are cut off.
+func NewSequencingSegmentingPSPR(ps, pr uint8, moreData bool) *SequencingSegmenting {
+	return NewSequencingSegmenting(ps<<1, pr<<1, moreData)
+}
+
 // ParseSequencingSegmenting parses the given byte sequence as a SequencingSegmenting.
 func ParseSequencingSegmenting(b []byte) (*SequencingSegmenting, int, error) {
 	s := &SequencingSegmenting{}
@@ -990,6 +1595,11 @@ func (s *SequencingSegmenting) MarshalLen() int {
 	return s.length
 }
 
+// AppendTo appends the serialized SequencingSegmenting to dst and returns the extended slice.
+func (s *SequencingSegmenting) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, s)
+}
+
 // Code returns the SequencingSegmenting in ParameterNameCode.
 func (s *SequencingSegmenting) Code() ParameterNameCode {
 	return s.code
@@ -1002,12 +1612,49 @@ func (s *SequencingSegmenting) Value() *SequencingSegmenting {
 
 // String returns the SequencingSegmenting in string.
 func (s *SequencingSegmenting) String() string {
-	return fmt.Sprintf(
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter.
+func (s *SequencingSegmenting) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f,
 		"{%s: {SendSequenceNumber=%d, ReceiveSequenceNumber=%d, MoreData=%t}}",
-		s.code, s.SendSequenceNumber, s.ReceiveSequenceNumber, s.MoreData,
+		s.code, s.PS(), s.PR(), s.MoreData,
 	)
 }
 
+// PS returns the P(S), the send sequence number carried in bits 8-2 of the first octet.
+func (s *SequencingSegmenting) PS() uint8 {
+	return s.SendSequenceNumber >> 1
+}
+
+// SetPS sets the P(S) in the SequencingSegmenting, leaving the spare bit as 0.
+// The values exceeding 7 bits are cut off.
+func (s *SequencingSegmenting) SetPS(ps uint8) {
+	s.SendSequenceNumber = ps << 1
+}
+
+// PR returns the P(R), the receive sequence number carried in bits 8-2 of the second octet.
+func (s *SequencingSegmenting) PR() uint8 {
+	return s.ReceiveSequenceNumber >> 1
+}
+
+// SetPR sets the P(R) in the SequencingSegmenting.
+// The values exceeding 7 bits are cut off.
+func (s *SequencingSegmenting) SetPR(pr uint8) {
+	s.ReceiveSequenceNumber = pr << 1
+}
+
+// More reports whether the More Data bit is set in the SequencingSegmenting.
+func (s *SequencingSegmenting) More() bool {
+	return s.MoreData
+}
+
+// SetMore sets the More Data bit in the SequencingSegmenting.
+func (s *SequencingSegmenting) SetMore(more bool) {
+	s.MoreData = more
+}
+
 // Credit represents the Credit.
 type Credit struct {
 	paramType ParameterType
@@ -1081,7 +1728,7 @@ func (c *Credit) read(b []byte) (int, error) {
 func (c *Credit) readOptional(b []byte) (int, error) {
 	n := 3
 	if len(b) < n {
-		return 0, nil
+		return 0, io.ErrUnexpectedEOF
 	}
 
 	c.code = ParameterNameCode(b[0])
@@ -1117,7 +1764,7 @@ func (c *Credit) write(b []byte) (int, error) {
 }
 
 func (c *Credit) writeOptional(b []byte) (int, error) {
-	if len(b) < c.length {
+	if len(b) < c.length+2 {
 		return 0, io.ErrUnexpectedEOF
 	}
 
@@ -1125,7 +1772,7 @@ func (c *Credit) writeOptional(b []byte) (int, error) {
 	b[1] = uint8(c.length)
 	b[2] = c.value
 
-	return c.length, nil
+	return c.length + 2, nil
 }
 
 // MarshalLen returns the serial length of Credit.
@@ -1136,6 +1783,11 @@ func (c *Credit) MarshalLen() int {
 	return c.length
 }
 
+// AppendTo appends the serialized Credit to dst and returns the extended slice.
+func (c *Credit) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, c)
+}
+
 // Code returns the Credit in ParameterNameCode.
 func (c *Credit) Code() ParameterNameCode {
 	return c.code
@@ -1148,7 +1800,12 @@ func (c *Credit) Value() uint8 {
 
 // String returns the Credit in string.
 func (c *Credit) String() string {
-	return fmt.Sprintf("{%s (%s): %d}", c.code, c.paramType, c.value)
+	return fmt.Sprint(c)
+}
+
+// Format implements fmt.Formatter.
+func (c *Credit) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %d}", c.code, c.paramType, c.value)
 }
 
 // Cause represents a common structure for all Cause types.
@@ -1230,6 +1887,11 @@ func (c *Cause[T]) MarshalLen() int {
 	return c.length
 }
 
+// AppendTo appends the serialized Cause to dst and returns the extended slice.
+func (c *Cause[T]) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, c)
+}
+
 // Code returns the code in the Cause.
 func (c *Cause[T]) Code() ParameterNameCode {
 	return c.code
@@ -1242,7 +1904,12 @@ func (c *Cause[T]) Value() T {
 
 // String returns the Cause as a string.
 func (c *Cause[T]) String() string {
-	return fmt.Sprintf("{%s (%s): %v}", c.code, c.paramType, c.value)
+	return fmt.Sprint(c)
+}
+
+// Format implements fmt.Formatter.
+func (c *Cause[T]) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %v}", c.code, c.paramType, c.value)
 }
 
 // ReleaseCauseValue is a type for ReleaseCause.
@@ -1428,6 +2095,17 @@ type Data struct {
 	value     []byte
 }
 
+// Clone returns a copy of the Data that does not share the value with the original.
+func (d *Data) Clone() *Data {
+	if d == nil {
+		return nil
+	}
+
+	c := *d
+	c.value = bytes.Clone(d.value)
+	return &c
+}
+
 // NewData creates a new Data.
 func NewData(v []byte) *Data {
 	return &Data{
@@ -1500,11 +2178,13 @@ func (d *Data) read(b []byte) (int, error) {
 
 	d.value = b[1 : d.length+1]
 
-	return n, nil
+	return d.length + 1, nil
 }
 
 func (d *Data) readOptional(b []byte) (int, error) {
-	n := len(b)
+	if len(b) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
 
 	d.code = ParameterNameCode(b[0])
 	if d.code != PCodeData {
@@ -1512,12 +2192,7 @@ func (d *Data) readOptional(b []byte) (int, error) {
 	}
 
 	m, err := d.read(b[1:])
-	n += m
-	if err != nil {
-		return n, err
-	}
-
-	return n, nil
+	return m + 1, err
 }
 
 // Write serializes the Data parameter and returns it as a byte slice.
@@ -1540,18 +2215,18 @@ func (d *Data) write(b []byte) (int, error) {
 	}
 
 	copy(b[1:d.length+1], d.value)
-	return d.length, nil
+	return d.length + 1, nil
 }
 
 func (d *Data) writeOptional(b []byte) (int, error) {
-	if len(b) < d.length {
+	if len(b) < d.length+2 {
 		return 0, io.ErrUnexpectedEOF
 	}
 
 	b[0] = uint8(d.code)
 	b[1] = uint8(d.length)
 	copy(b[2:], d.value)
-	return d.length, nil
+	return d.length + 2, nil
 }
 
 // MarshalLen returns the serial length of Data.
@@ -1562,6 +2237,11 @@ func (d *Data) MarshalLen() int {
 	return 1 + len(d.value)
 }
 
+// AppendTo appends the serialized Data to dst and returns the extended slice.
+func (d *Data) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, d)
+}
+
 // Code returns the Data in ParameterNameCode.
 func (d *Data) Code() ParameterNameCode {
 	return d.code
@@ -1574,7 +2254,12 @@ func (d *Data) Value() []byte {
 
 // String returns the Data in string.
 func (d *Data) String() string {
-	return fmt.Sprintf("{%s (%s): %x}", d.code, d.paramType, d.value)
+	return fmt.Sprint(d)
+}
+
+// Format implements fmt.Formatter.
+func (d *Data) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %x}", d.code, d.paramType, d.value)
 }
 
 // Segmentation represents the Segmentation.
@@ -1670,7 +2355,7 @@ func (s *Segmentation) Write(b []byte) (int, error) {
 	}
 
 	b[2] |= s.Class & 0b1 << 6
-	b[2] |= s.RemainingSegments & 0b111
+	b[2] |= s.RemainingSegments & 0b1111
 
 	copy(b[3:], utils.Uint32To24(s.LocalReference))
 
@@ -1682,6 +2367,11 @@ func (s *Segmentation) MarshalLen() int {
 	return s.length + 2
 }
 
+// AppendTo appends the serialized Segmentation to dst and returns the extended slice.
+func (s *Segmentation) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, s)
+}
+
 // Code returns the Segmentation in ParameterNameCode.
 func (s *Segmentation) Code() ParameterNameCode {
 	return s.code
@@ -1694,7 +2384,12 @@ func (s *Segmentation) Value() *Segmentation {
 
 // String returns the Segmentation in string.
 func (s *Segmentation) String() string {
-	return fmt.Sprintf(
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter.
+func (s *Segmentation) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f,
 		"{%s (%s): {FirstSegment=%t, Class=%d, RemainingSegments=%d, LocalReference=%d}}",
 		s.code, s.paramType, s.FirstSegment, s.Class, s.RemainingSegments, s.LocalReference,
 	)
@@ -1808,7 +2503,7 @@ func (h *HopCounter) write(b []byte) (int, error) {
 }
 
 func (h *HopCounter) writeOptional(b []byte) (int, error) {
-	if len(b) < h.length {
+	if len(b) < h.length+2 {
 		return 0, io.ErrUnexpectedEOF
 	}
 
@@ -1816,7 +2511,7 @@ func (h *HopCounter) writeOptional(b []byte) (int, error) {
 	b[1] = uint8(h.length)
 	b[2] = h.value
 
-	return h.length, nil
+	return h.length + 2, nil
 }
 
 // MarshalLen returns the serial length of HopCounter.
@@ -1827,6 +2522,11 @@ func (h *HopCounter) MarshalLen() int {
 	return h.length
 }
 
+// AppendTo appends the serialized HopCounter to dst and returns the extended slice.
+func (h *HopCounter) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, h)
+}
+
 // Code returns the HopCounter in ParameterNameCode.
 func (h *HopCounter) Code() ParameterNameCode {
 	return h.code
@@ -1839,7 +2539,12 @@ func (h *HopCounter) Value() uint8 {
 
 // String returns the HopCounter in string.
 func (h *HopCounter) String() string {
-	return fmt.Sprintf("{%s (%s): %d}", h.code, h.paramType, h.value)
+	return fmt.Sprint(h)
+}
+
+// Format implements fmt.Formatter.
+func (h *HopCounter) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %d}", h.code, h.paramType, h.value)
 }
 
 // Importance represents the Importance.
@@ -1930,6 +2635,11 @@ func (i *Importance) MarshalLen() int {
 	return i.length + 2
 }
 
+// AppendTo appends the serialized Importance to dst and returns the extended slice.
+func (i *Importance) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, i)
+}
+
 // Code returns the Importance in ParameterNameCode.
 func (i *Importance) Code() ParameterNameCode {
 	return i.code
@@ -1942,10 +2652,19 @@ func (i *Importance) Value() uint8 {
 
 // String returns the Importance in string.
 func (i *Importance) String() string {
-	return fmt.Sprintf("{%s (%s): %d}", i.code, i.paramType, i.value)
+	return fmt.Sprint(i)
+}
+
+// Format implements fmt.Formatter.
+func (i *Importance) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %d}", i.code, i.paramType, i.value)
 }
 
 // LongData represents the Long Data.
+//
+// Unlike Data, the length indicator of LongData is two octets long with the
+// least significant octet first (Q.713 3.20), which allows it to carry up to
+// 65535 octets of value (while Q.713 limits it to 3952 octets in LUDT and LUDTS).
 type LongData struct {
 	paramType ParameterType
 	code      ParameterNameCode
@@ -1954,6 +2673,9 @@ type LongData struct {
 }
 
 // NewLongData creates a new LongData.
+//
+// The given byte slice is not copied, so the caller should not modify it
+// until the LongData is serialized.
 func NewLongData(v []byte) *LongData {
 	return &LongData{
 		paramType: PTypeV,
@@ -1975,30 +2697,43 @@ func ParseLongData(b []byte) (*LongData, int, error) {
 }
 
 // Read sets the values retrieved from byte sequence in a LongData.
+//
+// The value is not copied from b but refers to the same underlying array
+// (zero-copy view), so b should not be modified while the LongData is in use.
 func (l *LongData) Read(b []byte) (int, error) {
-	n := len(b)
+	if len(b) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
 
 	l.paramType = PTypeV
 	l.code = PCodeLongData
 
-	l.length = int(binary.BigEndian.Uint16(b[:2]))
-	if n < l.length+2 {
-		return n, io.ErrUnexpectedEOF
+	l.length = int(binary.LittleEndian.Uint16(b[:2]))
+	n := l.length + 2
+	if len(b) < n {
+		return 2, io.ErrUnexpectedEOF
 	}
 
-	l.value = b[2 : l.length+2]
+	l.value = b[2:n:n]
 	return n, nil
 }
 
 // Write serializes the LongData parameter and returns it as a byte slice.
+//
+// It returns ErrValueTooLong if the value exceeds the range of the two-octet length.
 func (l *LongData) Write(b []byte) (int, error) {
-	if len(b) < l.length+2 {
+	if l.length > 0xffff || len(l.value) > 0xffff {
+		return 0, ErrValueTooLong
+	}
+
+	n := l.length + 2
+	if len(b) < n {
 		return 0, io.ErrUnexpectedEOF
 	}
 
-	binary.BigEndian.PutUint16(b, uint16(l.length))
-	copy(b[2:], l.value)
-	return l.length, nil
+	binary.LittleEndian.PutUint16(b, uint16(l.length))
+	copy(b[2:n], l.value)
+	return n, nil
 }
 
 // MarshalLen returns the serial length of LongData.
@@ -2006,17 +2741,155 @@ func (l *LongData) MarshalLen() int {
 	return l.length + 2
 }
 
+// AppendTo appends the serialized LongData to dst and returns the extended slice.
+func (l *LongData) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, l)
+}
+
 // Code returns the LongData in ParameterNameCode.
 func (l *LongData) Code() ParameterNameCode {
 	return l.code
 }
 
 // Value returns the LongData in []byte.
+//
+// The returned slice is a view of the underlying buffer, not a copy.
+// Use CopyValue to get a copy that is safe to keep or modify.
 func (l *LongData) Value() []byte {
 	return l.value
 }
 
+// CopyValue returns a copy of the value in LongData.
+func (l *LongData) CopyValue() []byte {
+	if l.value == nil {
+		return nil
+	}
+
+	v := make([]byte, len(l.value))
+	copy(v, l.value)
+	return v
+}
+
+// Clone returns a copy of the LongData that does not share the value with the original.
+func (l *LongData) Clone() *LongData {
+	if l == nil {
+		return nil
+	}
+
+	c := *l
+	c.value = bytes.Clone(l.value)
+	return &c
+}
+
 // String returns the LongData in string.
 func (l *LongData) String() string {
-	return fmt.Sprintf("{%s (%s): %x}", l.code, l.paramType, l.value)
+	return fmt.Sprint(l)
+}
+
+// Format implements fmt.Formatter.
+func (l *LongData) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %x}", l.code, l.paramType, l.value)
+}
+
+// UnknownParameter represents an optional parameter whose Parameter Name is
+// not known by this package, such as the national extensions. It is kept as
+// it is by ParseOptionalParameter, so that it can be re-emitted on marshaling.
+type UnknownParameter struct {
+	code  ParameterNameCode
+	value []byte
+}
+
+// NewUnknownParameter creates a new UnknownParameter.
+func NewUnknownParameter(code ParameterNameCode, v []byte) *UnknownParameter {
+	return &UnknownParameter{
+		code:  code,
+		value: v,
+	}
+}
+
+// ParseUnknownParameter parses the given byte sequence as an UnknownParameter.
+func ParseUnknownParameter(b []byte) (*UnknownParameter, int, error) {
+	u := &UnknownParameter{}
+	n, err := u.Read(b)
+	if err != nil {
+		return nil, n, err
+	}
+
+	return u, n, nil
+}
+
+// Read sets the values retrieved from byte sequence in an UnknownParameter.
+//
+// The value refers to b.
+func (u *UnknownParameter) Read(b []byte) (int, error) {
+	if len(b) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	l := int(b[1])
+	if len(b) < l+2 {
+		return 2, io.ErrUnexpectedEOF
+	}
+
+	u.code = ParameterNameCode(b[0])
+	u.value = b[2 : l+2]
+	return l + 2, nil
+}
+
+// Write serializes the UnknownParameter and returns it as a byte slice.
+func (u *UnknownParameter) Write(b []byte) (int, error) {
+	if len(u.value) > 0xff {
+		return 0, fmt.Errorf("%s: %w", u.code, ErrValueTooLong)
+	}
+	if len(b) < len(u.value)+2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(u.code)
+	b[1] = uint8(len(u.value))
+	copy(b[2:], u.value)
+	return len(u.value) + 2, nil
+}
+
+// MarshalLen returns the serial length of UnknownParameter.
+func (u *UnknownParameter) MarshalLen() int {
+	return 2 + len(u.value)
+}
+
+// AppendTo appends the serialized UnknownParameter to dst and returns the
+// extended slice.
+func (u *UnknownParameter) AppendTo(dst []byte) ([]byte, error) {
+	return appendParameter(dst, u)
+}
+
+// Code returns the UnknownParameter in ParameterNameCode.
+func (u *UnknownParameter) Code() ParameterNameCode {
+	return u.code
+}
+
+// Value returns the value of the UnknownParameter in []byte.
+func (u *UnknownParameter) Value() []byte {
+	return u.value
+}
+
+// Clone returns a copy of the UnknownParameter that does not share the value
+// with the original.
+func (u *UnknownParameter) Clone() *UnknownParameter {
+	if u == nil {
+		return nil
+	}
+
+	c := *u
+	c.value = bytes.Clone(u.value)
+	return &c
+}
+
+// String returns the UnknownParameter in string.
+func (u *UnknownParameter) String() string {
+	return fmt.Sprint(u)
+}
+
+// Format implements fmt.Formatter.
+func (u *UnknownParameter) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "{%s (%s): %x}", u.code, PTypeO, u.value)
 }
@@ -4,6 +4,10 @@
 package params_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"testing"
 
@@ -13,6 +17,7 @@ import (
 
 type serializable interface {
 	io.ReadWriter
+	AppendTo([]byte) ([]byte, error)
 }
 
 var cases = []struct {
@@ -86,6 +91,42 @@ var cases = []struct {
 		parseFunc: func(b []byte) (serializable, int, error) {
 			return params.ParseCallingPartyAddress(b)
 		},
+	}, {
+		description: "CalledPartyAddress w/ GlobalTitle NAI only",
+		structured: params.NewCalledPartyAddress(
+			params.NewAddressIndicator(false, true, false, params.GTINAIOnly),
+			0, 6, // SPC, SSN
+			params.NewGlobalTitle(
+				params.GTINAIOnly,
+				0, 0, 0, // TT, NP, ES: not used
+				params.NAIInternationalNumber.Odd(),
+				[]byte{0x21, 0x43, 0x05},
+			),
+		),
+		serialized: []byte{
+			0x06, 0x06, 0x06, 0x84, 0x21, 0x43, 0x05,
+		},
+		parseFunc: func(b []byte) (serializable, int, error) {
+			return params.ParseCalledPartyAddress(b)
+		},
+	}, {
+		description: "CalledPartyAddress w/ GlobalTitle TT only",
+		structured: params.NewCalledPartyAddress(
+			params.NewAddressIndicator(false, true, false, params.GTITTOnly),
+			0, 8, // SPC, SSN
+			params.NewGlobalTitle(
+				params.GTITTOnly,
+				params.TranslationType(0x11),
+				0, 0, 0, // NP, ES, NAI: not used
+				[]byte{0x21, 0x43},
+			),
+		),
+		serialized: []byte{
+			0x05, 0x0a, 0x08, 0x11, 0x21, 0x43,
+		},
+		parseFunc: func(b []byte) (serializable, int, error) {
+			return params.ParseCalledPartyAddress(b)
+		},
 	}, {
 		description: "CalledPartyAddress/2-bytes",
 		structured: params.NewCalledPartyAddress(
@@ -98,6 +139,13 @@ var cases = []struct {
 		parseFunc: func(b []byte) (serializable, int, error) {
 			return params.ParseCalledPartyAddress(b)
 		},
+	}, {
+		description: "CallingPartyAddress/SSN only",
+		structured:  params.NewSSNAddress(7).AsCalling(),
+		serialized:  []byte{0x02, 0x42, 0x07},
+		parseFunc: func(b []byte) (serializable, int, error) {
+			return params.ParseCallingPartyAddress(b)
+		},
 	}, {
 		description: "ProtocolClass/Class 1, no ReturnOnError",
 		structured:  params.NewProtocolClass(1, false),
@@ -133,6 +181,13 @@ var cases = []struct {
 		parseFunc: func(b []byte) (serializable, int, error) {
 			return params.ParseReceiveSequenceNumber(b)
 		},
+	}, {
+		description: "ReceiveSequenceNumber/P(R)",
+		structured:  params.NewReceiveSequenceNumberPR(0x3b),
+		serialized:  []byte{0x76},
+		parseFunc: func(b []byte) (serializable, int, error) {
+			return params.ParseReceiveSequenceNumber(b)
+		},
 	}, {
 		description: "SequencingSegmenting/More data",
 		structured:  params.NewSequencingSegmenting(0x76, 0x78, true),
@@ -147,6 +202,13 @@ var cases = []struct {
 		parseFunc: func(b []byte) (serializable, int, error) {
 			return params.ParseSequencingSegmenting(b)
 		},
+	}, {
+		description: "SequencingSegmenting/P(S) and P(R)",
+		structured:  params.NewSequencingSegmentingPSPR(0x3b, 0x3c, true),
+		serialized:  []byte{0x76, 0x79},
+		parseFunc: func(b []byte) (serializable, int, error) {
+			return params.ParseSequencingSegmenting(b)
+		},
 	}, {
 		description: "Credit/Fixed",
 		structured:  params.NewCredit(0x77),
@@ -238,10 +300,30 @@ var cases = []struct {
 		parseFunc: func(b []byte) (serializable, int, error) {
 			return params.ParseImportance(b)
 		},
+	}, {
+		description: "ISNI/Type 0",
+		structured: params.NewISNI(
+			false, params.IRIConstrained, 1,
+			params.ISNINetwork{Network: 1, Cluster: 2}, params.ISNINetwork{Network: 3, Cluster: 4},
+		),
+		serialized: []byte{0xfa, 0x05, 0x22, 0x01, 0x02, 0x03, 0x04},
+		parseFunc: func(b []byte) (serializable, int, error) {
+			return params.ParseISNI(b)
+		},
+	}, {
+		description: "ISNI/Type 1",
+		structured: params.NewISNIType1(
+			true, params.IRINeitherConstrainedNorSuggested, 0, 0x03,
+			params.ISNINetwork{Network: 5, Cluster: 6},
+		),
+		serialized: []byte{0xfa, 0x04, 0x11, 0x03, 0x05, 0x06},
+		parseFunc: func(b []byte) (serializable, int, error) {
+			return params.ParseISNI(b)
+		},
 	}, {
 		description: "LongData/512 bytes",
 		structured:  params.NewLongData([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f, 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f, 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x7b, 0x7c, 0x7d, 0x7e, 0x7f, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x8d, 0x8e, 0x8f, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0x9b, 0x9c, 0x9d, 0x9e, 0x9f, 0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0xb0, 0xb1, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xbb, 0xbc, 0xbd, 0xbe, 0xbf, 0xc0, 0xc1, 0xc2, 0xc3, 0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xcb, 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xdb, 0xdc, 0xdd, 0xde, 0xdf, 0xe0, 0xe1, 0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xeb, 0xec, 0xed, 0xee, 0xef, 0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f, 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f, 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x7b, 0x7c, 0x7d, 0x7e, 0x7f, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x8d, 0x8e, 0x8f, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0x9b, 0x9c, 0x9d, 0x9e, 0x9f, 0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0xb0, 0xb1, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xbb, 0xbc, 0xbd, 0xbe, 0xbf, 0xc0, 0xc1, 0xc2, 0xc3, 0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xcb, 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xdb, 0xdc, 0xdd, 0xde, 0xdf, 0xe0, 0xe1, 0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xeb, 0xec, 0xed, 0xee, 0xef, 0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff}),
-		serialized:  []byte{0x02, 0x00, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f, 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f, 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x7b, 0x7c, 0x7d, 0x7e, 0x7f, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x8d, 0x8e, 0x8f, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0x9b, 0x9c, 0x9d, 0x9e, 0x9f, 0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0xb0, 0xb1, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xbb, 0xbc, 0xbd, 0xbe, 0xbf, 0xc0, 0xc1, 0xc2, 0xc3, 0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xcb, 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xdb, 0xdc, 0xdd, 0xde, 0xdf, 0xe0, 0xe1, 0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xeb, 0xec, 0xed, 0xee, 0xef, 0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f, 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f, 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x7b, 0x7c, 0x7d, 0x7e, 0x7f, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x8d, 0x8e, 0x8f, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0x9b, 0x9c, 0x9d, 0x9e, 0x9f, 0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0xb0, 0xb1, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xbb, 0xbc, 0xbd, 0xbe, 0xbf, 0xc0, 0xc1, 0xc2, 0xc3, 0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xcb, 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xdb, 0xdc, 0xdd, 0xde, 0xdf, 0xe0, 0xe1, 0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xeb, 0xec, 0xed, 0xee, 0xef, 0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff},
+		serialized:  []byte{0x00, 0x02, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f, 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f, 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x7b, 0x7c, 0x7d, 0x7e, 0x7f, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x8d, 0x8e, 0x8f, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0x9b, 0x9c, 0x9d, 0x9e, 0x9f, 0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0xb0, 0xb1, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xbb, 0xbc, 0xbd, 0xbe, 0xbf, 0xc0, 0xc1, 0xc2, 0xc3, 0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xcb, 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xdb, 0xdc, 0xdd, 0xde, 0xdf, 0xe0, 0xe1, 0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xeb, 0xec, 0xed, 0xee, 0xef, 0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f, 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f, 0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x7b, 0x7c, 0x7d, 0x7e, 0x7f, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x8d, 0x8e, 0x8f, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0x9b, 0x9c, 0x9d, 0x9e, 0x9f, 0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0xb0, 0xb1, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xbb, 0xbc, 0xbd, 0xbe, 0xbf, 0xc0, 0xc1, 0xc2, 0xc3, 0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xcb, 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xdb, 0xdc, 0xdd, 0xde, 0xdf, 0xe0, 0xe1, 0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xeb, 0xec, 0xed, 0xee, 0xef, 0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff},
 		parseFunc: func(b []byte) (serializable, int, error) {
 			return params.ParseLongData(b)
 		},
@@ -274,6 +356,738 @@ func TestParams(t *testing.T) {
 					t.Errorf("got: %v, want: %v", got, want)
 				}
 			})
+
+			t.Run("AppendTo", func(t *testing.T) {
+				// the spare capacity is not zeroed to check it is cleared.
+				dst := bytes.Repeat([]byte{0xff}, 512)[:2]
+				b, err := c.structured.AppendTo(dst)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got, want := b, append([]byte{0xff, 0xff}, c.serialized...); !verify.Values(t, "", got, want) {
+					t.Errorf("got: %v, want: %v", got, want)
+				}
+			})
 		})
 	}
 }
+
+func TestLongDataOverflow(t *testing.T) {
+	l := params.NewLongData(make([]byte, 0x10000))
+
+	b := make([]byte, l.MarshalLen())
+	if _, err := l.Write(b); !errors.Is(err, params.ErrValueTooLong) {
+		t.Errorf("got error %v, want %v", err, params.ErrValueTooLong)
+	}
+
+	if _, _, err := params.ParseLongData([]byte{0x00}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got error %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestAddressBuilder(t *testing.T) {
+	t.Run("GT", func(t *testing.T) {
+		got, err := params.NewAddressBuilder().
+			SSN(6).
+			GT(params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.NAIInternationalNumber, "1234567890").
+			RouteOnGT().
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := params.NewCalledPartyAddress(
+			params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+			0, 6, // SPC, SSN
+			params.NewGlobalTitle(
+				params.GTITTNPESNAI,
+				params.TranslationType(0),
+				params.NPISDNTelephony,
+				params.ESBCDEven,
+				params.NAIInternationalNumber,
+				[]byte{0x21, 0x43, 0x65, 0x87, 0x09},
+			),
+		)
+		if !verify.Values(t, "", got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+
+	t.Run("SSN only", func(t *testing.T) {
+		got, err := params.NewAddressBuilder().Calling().PC(1).SSN(7).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := params.NewCallingPartyAddress(
+			params.NewAddressIndicator(true, true, true, params.GTINoGT),
+			1, 7, nil, // SPC, SSN, GT
+		)
+		if !verify.Values(t, "", got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := params.NewAddressBuilder().SSN(6).RouteOnGT().Build(); !errors.Is(err, params.ErrInvalidAddress) {
+			t.Errorf("got error %v, want %v", err, params.ErrInvalidAddress)
+		}
+		if _, err := params.NewAddressBuilder().GT(params.GTITTOnly, 0, 0, 0, "12x").Build(); err == nil {
+			t.Error("got no error with invalid digits")
+		}
+	})
+}
+
+func TestTranslationTypeRegistry(t *testing.T) {
+	tt := params.TranslationType(0x80)
+	if got, want := tt.String(), "national network specific (128)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	params.RegisterTranslationType(tt, "number portability")
+	defer params.UnregisterTranslationType(tt)
+
+	if got, want := tt.String(), "number portability (128)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	gt := params.NewGlobalTitle(params.GTITTOnly, tt, 0, 0, 0, []byte{0x21, 0x43})
+	if got, want := gt.String(), "{GTI: 0b0010, TranslationType: number portability (128), AddressInformation: 1234}"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEnumText(t *testing.T) {
+	b, err := params.NPE212.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var np params.NumberingPlan
+	if err := np.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+	if np != params.NPLandMobile {
+		t.Errorf("got %v, want %v", np, params.NPLandMobile)
+	}
+
+	var nai params.NatureOfAddressIndicator
+	if err := nai.UnmarshalText([]byte("4")); err != nil {
+		t.Fatal(err)
+	}
+	if nai != params.NAIInternationalNumber {
+		t.Errorf("got %v, want %v", nai, params.NAIInternationalNumber)
+	}
+
+	if err := np.UnmarshalText([]byte("no such plan")); err == nil {
+		t.Error("got no error with invalid text")
+	}
+}
+
+func TestEncodingScheme(t *testing.T) {
+	if _, err := params.ESBCDEven.EncodeAddress("123"); !errors.Is(err, params.ErrInvalidAddress) {
+		t.Errorf("got error %v, want %v", err, params.ErrInvalidAddress)
+	}
+
+	addr, err := params.ESBCDOdd.EncodeAddress("123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := params.ESBCDOdd.DecodeAddress(addr), "123"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// national specific address information is kept as it is.
+	b := []byte{0x00, 0x13, 0x04, 0xab, 0xcd}
+	gt, err := params.ParseGlobalTitle(params.GTITTNPESNAI, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := gt.(*params.GTTTNPESNAI)
+	if g.EncodingScheme != params.ESNationalSpecific {
+		t.Fatalf("got %v, want %v", g.EncodingScheme, params.ESNationalSpecific)
+	}
+	if got, want := g.Address(), "abcd"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSections(t *testing.T) {
+	ai := params.NewAddressIndicator(true, true, true, params.GTINoGT)
+	cases := []struct {
+		description     string
+		fixed, variable []params.Parameter
+		optional        []params.Parameter
+		hasOptionalPart bool
+		serialized      []byte
+	}{
+		{
+			"UDT-like",
+			[]params.Parameter{params.NewProtocolClass(0, false)},
+			[]params.Parameter{
+				params.NewCalledPartyAddress(ai, 1, 6, nil),
+				params.NewCallingPartyAddress(ai, 1, 7, nil),
+				params.NewData([]byte{0xaa, 0xbb}),
+			},
+			nil, false,
+			[]byte{
+				0x00, 0x03, 0x07, 0x0b,
+				0x04, 0x43, 0x01, 0x00, 0x06,
+				0x04, 0x43, 0x01, 0x00, 0x07,
+				0x02, 0xaa, 0xbb,
+			},
+		}, {
+			"With optional part",
+			nil,
+			[]params.Parameter{params.NewData([]byte{0xaa, 0xbb})},
+			[]params.Parameter{params.NewImportanceOptional(3)},
+			true,
+			[]byte{0x02, 0x04, 0x02, 0xaa, 0xbb, 0x12, 0x01, 0x03, 0x00},
+		}, {
+			"Empty optional part",
+			nil,
+			[]params.Parameter{params.NewData([]byte{0xaa, 0xbb})},
+			nil, true,
+			[]byte{0x02, 0x00, 0x02, 0xaa, 0xbb},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			l := params.SectionsLen(c.fixed, c.variable, c.optional, c.hasOptionalPart)
+			if l != len(c.serialized) {
+				t.Errorf("got length %d, want %d", l, len(c.serialized))
+			}
+
+			b := make([]byte, l)
+			n, err := params.MarshalSections(b, c.fixed, c.variable, c.optional, c.hasOptionalPart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !verify.Values(t, "", b[:n], c.serialized) {
+				t.Fail()
+			}
+
+			variable := make([]params.Parameter, len(c.variable))
+			for i := range variable {
+				variable[i] = &params.Data{}
+			}
+			opts, n, err := params.UnmarshalSections(c.serialized, []params.Parameter{&params.ProtocolClass{}}[:len(c.fixed)], variable, c.hasOptionalPart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(c.serialized) {
+				t.Errorf("got end %d, want %d", n, len(c.serialized))
+			}
+			if c.optional != nil && len(opts) != len(c.optional)+1 {
+				t.Errorf("got %d optional parameters, want %d", len(opts), len(c.optional)+1)
+			}
+		})
+	}
+}
+
+func TestPointCodeCodec(t *testing.T) {
+	// a national network that uses 16-bit point code in network byte order.
+	codec := params.PointCodeFormat{Octets: 2, Bits: 16, BigEndian: true}
+
+	p := params.NewCalledPartyAddress(params.NewAddressIndicator(true, true, true, params.GTINoGT), 0xabcd, 6, nil)
+	p.SetPointCodeCodec(codec)
+
+	b := make([]byte, p.MarshalLen())
+	if _, err := p.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x04, 0x43, 0xab, 0xcd, 0x06}; !verify.Values(t, "", b, want) {
+		t.Fail()
+	}
+
+	got := &params.PartyAddress{}
+	got.SetPointCodeCodec(codec)
+	if _, err := got.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	if got.SignalingPointCode != 0xabcd {
+		t.Errorf("got %#x, want %#x", got.SignalingPointCode, 0xabcd)
+	}
+
+	// 0xabcd does not fit in the ITU 14-bit point code.
+	p.SetPointCodeCodec(params.ITUPointCodeCodec)
+	if _, err := p.Write(b); !errors.Is(err, params.ErrInvalidAddress) {
+		t.Errorf("got error %v, want %v", err, params.ErrInvalidAddress)
+	}
+}
+
+func TestPointCode(t *testing.T) {
+	pc, err := params.ParsePointCode("2-123-4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pc, params.PointCode(2<<11|123<<3|4); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if got, want := pc.String(), "2-123-4"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, s := range []string{"8-0-0", "0-256-0", "16384", "1-2"} {
+		if _, err := params.ParsePointCode(s); !errors.Is(err, params.ErrInvalidAddress) {
+			t.Errorf("%s: got error %v, want %v", s, err, params.ErrInvalidAddress)
+		}
+	}
+
+	p := params.NewCalledPartyAddress(params.NewAddressIndicator(true, true, true, params.GTINoGT), pc, 6, nil)
+	b := make([]byte, p.MarshalLen())
+	if _, err := p.Write(b); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := params.ParseCalledPartyAddress(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SignalingPointCode != pc {
+		t.Errorf("got %s, want %s", got.SignalingPointCode, pc)
+	}
+}
+
+func TestPartyAddressEqual(t *testing.T) {
+	ai := params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI)
+	odd := params.NewCalledPartyAddress(ai, 0, 6, params.NewGlobalTitle(
+		params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.ESBCDOdd, params.NAIInternationalNumber,
+		[]byte{0x21, 0xf3},
+	))
+	even := params.NewCallingPartyAddress(ai|0b10000000, 0, 6, params.NewGlobalTitle(
+		params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.ESBCDEven, params.NAIInternationalNumber,
+		[]byte{0x21, 0xf3},
+	))
+	other := params.NewCalledPartyAddress(ai, 0, 7, odd.GlobalTitle)
+
+	if !odd.Equal(even) {
+		t.Errorf("%v and %v should be equal", odd, even)
+	}
+	if odd.EqualBytes(even) {
+		t.Errorf("%v and %v should not be equal in bytes", odd, even)
+	}
+	if odd.Equal(other) {
+		t.Errorf("%v and %v should not be equal", odd, other)
+	}
+	if !odd.EqualBytes(params.NewCallingPartyAddress(ai, 0, 6, odd.GlobalTitle)) {
+		t.Error("Called and Calling Party Address with the same values should be equal in bytes")
+	}
+}
+
+func TestPartyAddressJSON(t *testing.T) {
+	p, err := params.NewAddressBuilder().Calling().PC(0x1234).SSN(6).
+		GT(params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.NAIInternationalNumber, "819012345").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"type":"calling","routeOnSSN":false,"pc":4660,"ssn":6,"gt":{"gti":4,"tt":0,` +
+		`"np":"ISDN/telephony numbering plan","es":1,"nai":"international number","digits":"819012345"}}`
+	if got := string(b); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	got := &params.PartyAddress{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !verify.Values(t, "", got, p) {
+		t.Fail()
+	}
+
+	gt, err := params.UnmarshalGlobalTitleJSON([]byte(`{"gti":2,"tt":128,"digits":"12345"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gt.Address(), "12345f"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestValidateDigits(t *testing.T) {
+	for _, d := range []string{"", "0123456789", "12345f", "1a2b"} {
+		if err := params.ValidateDigits(d); err != nil {
+			t.Errorf("%q: got error %v", d, err)
+		}
+	}
+	for _, d := range []string{"12x4", "12f4", "+8190"} {
+		if err := params.ValidateDigits(d); !errors.Is(err, params.ErrInvalidDigits) {
+			t.Errorf("%q: got error %v, want %v", d, err, params.ErrInvalidDigits)
+		}
+	}
+
+	// BCD odd with the filler in the middle.
+	if _, err := params.ParseGlobalTitle(params.GTITTNPESNAI, []byte{0x00, 0x11, 0x04, 0xf1, 0xf3}); !errors.Is(err, params.ErrInvalidDigits) {
+		t.Errorf("got error %v, want %v", err, params.ErrInvalidDigits)
+	}
+}
+
+func TestNewE164Address(t *testing.T) {
+	got, err := params.NewE164Address(6, "819012345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+		0, 6,
+		params.NewGlobalTitle(
+			params.GTITTNPESNAI, params.TTUnknown, params.NPE164, params.ESBCDEven, params.NAIInternationalNumber,
+			[]byte{0x18, 0x09, 0x21, 0x43, 0x65, 0x87},
+		),
+	)
+	if !verify.Values(t, "", got, want) {
+		t.Fail()
+	}
+
+	if _, err := params.NewE212Address(256, "440101234567890"); !errors.Is(err, params.ErrInvalidAddress) {
+		t.Errorf("got error %v, want %v", err, params.ErrInvalidAddress)
+	}
+}
+
+func TestNewSSNAddress(t *testing.T) {
+	p := params.NewSSNAddress(6)
+	if !p.IsSSNOnly() {
+		t.Errorf("not SSN only: %v", p)
+	}
+	if got, want := p.MarshalLen(), 3; got != want {
+		t.Errorf("got length %d, want %d", got, want)
+	}
+
+	p.SetGlobalTitle(params.NewGlobalTitle(params.GTITTOnly, 0, 0, 0, 0, []byte{0x21, 0x43}))
+	if p.IsSSNOnly() {
+		t.Errorf("unexpectedly SSN only: %v", p)
+	}
+}
+
+func TestAddressIndicatorAccessors(t *testing.T) {
+	p := params.NewCalledPartyAddress(params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 6, nil)
+
+	p.SetHasPC(true)
+	p.SignalingPointCode = 0x42
+	p.SetGlobalTitle(params.NewGlobalTitle(params.GTITTOnly, 0, 0, 0, 0, []byte{0x21, 0x43}))
+	p.SetRoutingIndicator(params.RIRouteOnGT)
+	p.SetNational(true)
+
+	want := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(true, true, false, params.GTITTOnly)|0b10000000,
+		0x42, 6,
+		params.NewGlobalTitle(params.GTITTOnly, 0, 0, 0, 0, []byte{0x21, 0x43}),
+	)
+	if !verify.Values(t, "", p, want) {
+		t.Fail()
+	}
+	if !p.IsNational() || p.RoutingIndicator() != params.RIRouteOnGT {
+		t.Errorf("unexpected indicator %#08b", p.Indicator)
+	}
+
+	p.SetHasSSN(false)
+	if p.HasSSN() || p.SubsystemNumber != 0 {
+		t.Errorf("SSN is not cleared: %v", p)
+	}
+}
+
+func TestProtocolClass(t *testing.T) {
+	cases := []struct {
+		description     string
+		serialized      []byte
+		class           int
+		returnOnError   bool
+		messageHandling uint8
+		connOriented    bool
+	}{
+		{"Class 0, ReturnOnError", []byte{0x80}, 0, true, 0x8, false},
+		{"Class 1", []byte{0x01}, 1, false, 0, false},
+		{"Class 2", []byte{0x02}, 2, false, 0, true},
+		{"Class 3, spare bits set", []byte{0x83}, 3, false, 0, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			p, _, err := params.ParseProtocolClass(c.serialized)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := p.Class(); got != c.class {
+				t.Errorf("Class: got %d, want %d", got, c.class)
+			}
+			if got := p.ReturnOnError(); got != c.returnOnError {
+				t.Errorf("ReturnOnError: got %v, want %v", got, c.returnOnError)
+			}
+			if got := p.MessageHandling(); got != c.messageHandling {
+				t.Errorf("MessageHandling: got %d, want %d", got, c.messageHandling)
+			}
+			if p.IsConnectionOriented() != c.connOriented || p.IsConnectionless() == c.connOriented {
+				t.Errorf("unexpected connection type for class %d", p.Class())
+			}
+		})
+	}
+
+	// the return option is not set in the spare bits of the connection-oriented classes.
+	if got := params.NewProtocolClass(params.ClassBasicConnectionOriented, true).Value(); got != 0x02 {
+		t.Errorf("got %#x, want %#x", got, 0x02)
+	}
+}
+
+func TestPartyAddressDiagnostics(t *testing.T) {
+	// GTI=0010 with the filler in the middle of the digits.
+	malformed := []byte{0x04, 0x0a, 0x06, 0x00, 0x1f}
+	if _, _, err := params.ParseCalledPartyAddress(malformed); !errors.Is(err, params.ErrInvalidDigits) {
+		t.Errorf("got error %v, want %v", err, params.ErrInvalidDigits)
+	}
+
+	p, _, err := params.ParsePartyAddressLenient(params.VariantITU, params.PCodeCalledPartyAddress, malformed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Diagnostics) != 1 || !errors.Is(p.Diagnostics[0], params.ErrInvalidDigits) {
+		t.Errorf("unexpected diagnostics: %v", p.Diagnostics)
+	}
+
+	// route on GT without GT is decoded in both modes.
+	p, _, err = params.ParseCalledPartyAddress([]byte{0x02, 0x02, 0x06})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Diagnostics) != 1 || !errors.Is(p.Diagnostics[0], params.ErrInvalidAddress) {
+		t.Errorf("unexpected diagnostics: %v", p.Diagnostics)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	addr := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI),
+		0, 6, // SPC, SSN
+		params.NewGlobalTitle(
+			params.GTITTNPESNAI,
+			params.TranslationType(0),
+			params.NPISDNTelephony,
+			params.ESBCDEven,
+			params.NAIInternationalNumber,
+			[]byte{0x21, 0x43, 0x65},
+		),
+	)
+
+	want := "{Called party address (V): {length: 8, Indicator: 0b00010010, SignalingPointCode: 0-0-0, SubsystemNumber: HLR (6), GlobalTitle: {GTI: 0b0100, TranslationType: unknown (0), NumberingPlan: ISDN/telephony numbering plan, EncodingScheme: BCD, even number of digits, NatureOfAddressIndicator: international number, AddressInformation: 123456}}}"
+	if got := addr.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// formatted in the same way when nested.
+	if got, want := fmt.Sprintf("[%v]", addr), "["+want+"]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// the point code is formatted in the way of the variant.
+	ansi, err := params.NewAddressBuilder().Variant(params.VariantANSI).PC(params.NewANSIPointCode(1, 2, 3)).SSN(6).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "{Called party address (V): {length: 5, Indicator: 0b11000011, SignalingPointCode: 1-2-3, SubsystemNumber: HLR (6), GlobalTitle: <nil>}}"
+	if got := ansi.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var data *params.Data
+	if got, want := fmt.Sprintf("%v", data), "<nil>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestVariant(t *testing.T) {
+	for _, v := range []params.Variant{params.VariantITU, params.VariantANSI, params.VariantChina, params.VariantTTC} {
+		got, err := params.ParseVariant(v.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v {
+			t.Errorf("got %s, want %s", got, v)
+		}
+	}
+	if _, err := params.ParseVariant("unknown"); !errors.Is(err, params.ErrInvalidVariant) {
+		t.Errorf("got error %v, want %v", err, params.ErrInvalidVariant)
+	}
+
+	if got, want := params.VariantANSI.NewAddressIndicator(true, true, false, params.GTINoGT), uint8(0b10000011); got != want {
+		t.Errorf("got %#08b, want %#08b", got, want)
+	}
+	if got, want := params.VariantITU.PointCodeCodec().Len(), 2; got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if got, want := params.VariantANSI.FormatPointCode(params.NewANSIPointCode(1, 2, 3)), "1-2-3"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestChinaVariant(t *testing.T) {
+	p, err := params.GTProfileChinaE164International.GT(
+		params.NewAddressBuilder().Variant(params.VariantChina).PC(params.NewANSIPointCode(1, 2, 3)).SSN(6),
+		"8613800138000",
+	).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, p.MarshalLen())
+	if _, err := p.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0x0f, 0x13, // ITU layout of the Address Indicator with PC and SSN
+		0x03, 0x02, 0x01, // 24-bit PC
+		0x06,
+		0x00, 0x11, 0x04, 0x68, 0x31, 0x08, 0x10, 0x83, 0x00, 0xf0,
+	}
+	if !verify.Values(t, "", b, want) {
+		t.Fail()
+	}
+
+	parsed, _, err := params.ParsePartyAddressVariant(params.VariantChina, params.PCodeCalledPartyAddress, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := params.VariantChina.FormatPointCode(parsed.SignalingPointCode), "1-2-3"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := parsed.SubsystemNumber, uint8(6); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+func TestANSIGlobalTitle(t *testing.T) {
+	cases := []struct {
+		description string
+		serialized  []byte
+		gt          params.GlobalTitle
+	}{
+		{
+			"GTI=0001 with TT, NP and ES",
+			[]byte{0x05, 0x84, 0x0a, 0x12, 0x21, 0x43},
+			&params.GTTTNPES{
+				TranslationType:    0x0a,
+				NumberingPlan:      params.NPISDNTelephony,
+				EncodingScheme:     params.ESBCDEven,
+				AddressInformation: []byte{0x21, 0x43},
+			},
+		}, {
+			"GTI=0010 with TT only",
+			[]byte{0x04, 0x88, 0x0a, 0x21, 0x43},
+			&params.GTTTOnly{
+				TranslationType:    0x0a,
+				AddressInformation: []byte{0x21, 0x43},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			p, _, err := params.ParsePartyAddressVariant(params.VariantANSI, params.PCodeCalledPartyAddress, c.serialized)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !verify.Values(t, "GlobalTitle", p.GlobalTitle, c.gt) {
+				t.Fail()
+			}
+			if got, want := p.GlobalTitle.Address(), "1234"; got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+
+			b := make([]byte, p.MarshalLen())
+			if _, err := p.Write(b); err != nil {
+				t.Fatal(err)
+			}
+			if !verify.Values(t, "serialized", b, c.serialized) {
+				t.Fail()
+			}
+
+			// the GTI is set in the ANSI format when the GlobalTitle is set.
+			built := params.NewPartyAddressVariant(params.VariantANSI, params.PCodeCalledPartyAddress, 0x80, 0, 0, nil)
+			built.SetGlobalTitle(c.gt)
+			if !verify.Values(t, "Indicator", built.Indicator, c.serialized[1]) {
+				t.Fail()
+			}
+
+			j, err := json.Marshal(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := &params.PartyAddress{}
+			if err := json.Unmarshal(j, decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !verify.Values(t, "JSON", decoded.Indicator, c.serialized[1]) {
+				t.Fail()
+			}
+		})
+	}
+
+	p, err := params.NewAddressBuilder().Variant(params.VariantANSI).
+		GT(params.GTITTNPES, 0x0a, params.NPISDNTelephony, 0, "1234").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, p.MarshalLen())
+	if _, err := p.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if !verify.Values(t, "built", b, cases[0].serialized) {
+		t.Fail()
+	}
+
+	// GTI=0100 is not defined in ANSI.
+	if _, err := params.NewAddressBuilder().Variant(params.VariantANSI).
+		GT(params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.NAIInternationalNumber, "1234").Build(); !errors.Is(err, params.ErrInvalidAddress) {
+		t.Errorf("got %v, want %v", err, params.ErrInvalidAddress)
+	}
+}
+
+func TestTTCVariant(t *testing.T) {
+	// national indicator set, routed on SSN, with PC and SSN
+	b := []byte{0x04, 0xc3, 0x03, 0x09, 0x06}
+
+	p, _, err := params.ParsePartyAddressVariant(params.VariantTTC, params.PCodeCalledPartyAddress, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.SignalingPointCode, params.NewTTCPointCode(1, 2, 3); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if got, want := params.VariantTTC.FormatPointCode(p.SignalingPointCode), "1-2-3"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := p.SubsystemNumber, uint8(6); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if !p.IsNational() || len(p.Diagnostics) != 0 {
+		t.Errorf("got national %v with %v, want national without diagnostics", p.IsNational(), p.Diagnostics)
+	}
+	if got, want := params.VariantTTC.NewAddressIndicator(true, true, true, params.GTINoGT), b[1]; got != want {
+		t.Errorf("got %#08b, want %#08b", got, want)
+	}
+
+	got := make([]byte, p.MarshalLen())
+	if _, err := p.Write(got); err != nil {
+		t.Fatal(err)
+	}
+	if !verify.Values(t, "", got, b) {
+		t.Fail()
+	}
+
+	// the national indicator is cleared.
+	p, _, err = params.ParsePartyAddressVariant(params.VariantTTC, params.PCodeCalledPartyAddress, []byte{0x04, 0x43, 0x03, 0x09, 0x06})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.IsNational() || len(p.Diagnostics) != 1 {
+		t.Errorf("got national %v with %v, want not national with a diagnostic", p.IsNational(), p.Diagnostics)
+	}
+}
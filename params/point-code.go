@@ -0,0 +1,203 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PointCode is a Signalling Point Code.
+//
+// The ITU point code is 14 bits long, and is formatted as zone-area-SP
+// (3-8-3 bits) in String.
+type PointCode uint32
+
+// MaxITUPointCode is the largest value of the ITU 14-bit point code.
+const MaxITUPointCode PointCode = 0x3fff
+
+// NewITUPointCode creates a new PointCode from the zone (3 bits), area (8 bits)
+// and signalling point (3 bits) identification.
+func NewITUPointCode(zone, area, sp uint8) (PointCode, error) {
+	if zone > 0b111 || sp > 0b111 {
+		return 0, fmt.Errorf("invalid point code %d-%d-%d: %w", zone, area, sp, ErrInvalidAddress)
+	}
+
+	return PointCode(zone)<<11 | PointCode(area)<<3 | PointCode(sp), nil
+}
+
+// ParsePointCode parses the point code in the zone-area-SP format, e.g.,
+// "2-123-4", or in decimal.
+//
+// It returns error if the point code is not in the ITU 14-bit range.
+func ParsePointCode(s string) (PointCode, error) {
+	parts := strings.Split(s, "-")
+	switch len(parts) {
+	case 1:
+		v, err := strconv.ParseUint(s, 10, 32)
+		if err != nil || PointCode(v) > MaxITUPointCode {
+			return 0, fmt.Errorf("invalid point code %q: %w", s, ErrInvalidAddress)
+		}
+		return PointCode(v), nil
+	case 3:
+		var v [3]uint8
+		for i, p := range parts {
+			n, err := strconv.ParseUint(p, 10, 8)
+			if err != nil {
+				return 0, fmt.Errorf("invalid point code %q: %w", s, ErrInvalidAddress)
+			}
+			v[i] = uint8(n)
+		}
+		return NewITUPointCode(v[0], v[1], v[2])
+	default:
+		return 0, fmt.Errorf("invalid point code %q: %w", s, ErrInvalidAddress)
+	}
+}
+
+// NewANSIPointCode creates a new 24-bit PointCode from the network, cluster
+// and member identification.
+func NewANSIPointCode(network, cluster, member uint8) PointCode {
+	return PointCode(network)<<16 | PointCode(cluster)<<8 | PointCode(member)
+}
+
+// ANSIString returns the PointCode in the network-cluster-member format.
+func (pc PointCode) ANSIString() string {
+	return fmt.Sprintf("%d-%d-%d", uint8(pc>>16), uint8(pc>>8), uint8(pc))
+}
+
+// NewTTCPointCode creates a new 16-bit PointCode in the TTC format from the
+// main area (5 bits), the sub area (4 bits) and the signalling point (7 bits).
+// The values exceeding the bits are cut off.
+func NewTTCPointCode(main, sub, sp uint8) PointCode {
+	return PointCode(main&0x1f)<<11 | PointCode(sub&0x0f)<<7 | PointCode(sp&0x7f)
+}
+
+// TTCString returns the PointCode in the main-sub-SP format of TTC.
+func (pc PointCode) TTCString() string {
+	return fmt.Sprintf("%d-%d-%d", uint8(pc>>11)&0x1f, uint8(pc>>7)&0x0f, uint8(pc)&0x7f)
+}
+
+// IsValidITU reports whether the PointCode is in the ITU 14-bit range.
+func (pc PointCode) IsValidITU() bool {
+	return pc <= MaxITUPointCode
+}
+
+// Zone returns the zone identification of the ITU point code.
+func (pc PointCode) Zone() uint8 {
+	return uint8(pc>>11) & 0b111
+}
+
+// Area returns the area/network identification of the ITU point code.
+func (pc PointCode) Area() uint8 {
+	return uint8(pc >> 3)
+}
+
+// SP returns the signalling point identification of the ITU point code.
+func (pc PointCode) SP() uint8 {
+	return uint8(pc) & 0b111
+}
+
+// String returns the PointCode in the zone-area-SP format, or in decimal if
+// it is out of the ITU 14-bit range.
+func (pc PointCode) String() string {
+	if !pc.IsValidITU() {
+		return strconv.FormatUint(uint64(pc), 10)
+	}
+	b := make([]byte, 0, len("7-255-7"))
+	b = strconv.AppendUint(b, uint64(pc.Zone()), 10)
+	b = append(b, '-')
+	b = strconv.AppendUint(b, uint64(pc.Area()), 10)
+	b = append(b, '-')
+	b = strconv.AppendUint(b, uint64(pc.SP()), 10)
+	return string(b)
+}
+
+// PointCodeCodec encodes and decodes the Signalling Point Code in a PartyAddress.
+//
+// The codec can be set to each PartyAddress with SetPointCodeCodec to support
+// the national networks that use non-standard width or bit layout of the
+// point code. ITUPointCodeCodec is used if nothing is set.
+type PointCodeCodec interface {
+	// Len returns the number of octets the point code occupies in the address.
+	Len() int
+	// Decode decodes the point code from the first Len() octets of b.
+	Decode(b []byte) PointCode
+	// Encode encodes pc into the first Len() octets of b.
+	Encode(b []byte, pc PointCode) error
+}
+
+// PointCodeFormat is a PointCodeCodec that handles the point code as an
+// unsigned integer with the given number of octets, significant bits and
+// byte order. The bits that are not significant are ignored on decoding,
+// and set to 0 on encoding.
+type PointCodeFormat struct {
+	Octets    int
+	Bits      int
+	BigEndian bool
+}
+
+// ITUPointCodeCodec is the PointCodeCodec for the 14-bit point code in two
+// octets, least significant octet first, defined in Q.713 3.4.2.1.
+var ITUPointCodeCodec PointCodeCodec = PointCodeFormat{Octets: 2, Bits: 14}
+
+// ANSIPointCodeCodec is the PointCodeCodec for the 24-bit point code in three
+// octets, in the order of member, cluster and network, defined in T1.112.
+var ANSIPointCodeCodec PointCodeCodec = PointCodeFormat{Octets: 3, Bits: 24}
+
+// ChinaPointCodeCodec is the PointCodeCodec for the 24-bit point code in the
+// Chinese national network, which is in three octets, least significant octet
+// first, in the same way as ANSIPointCodeCodec.
+var ChinaPointCodeCodec PointCodeCodec = PointCodeFormat{Octets: 3, Bits: 24}
+
+// TTCPointCodeCodec is the PointCodeCodec for the 16-bit point code in two
+// octets, least significant octet first, defined in JT-Q713.
+var TTCPointCodeCodec PointCodeCodec = PointCodeFormat{Octets: 2, Bits: 16}
+
+// Len returns the number of octets.
+func (f PointCodeFormat) Len() int {
+	return f.Octets
+}
+
+// Decode decodes the point code from the first Len() octets of b.
+func (f PointCodeFormat) Decode(b []byte) PointCode {
+	var pc uint32
+	for i := 0; i < f.Octets; i++ {
+		if f.BigEndian {
+			pc = pc<<8 | uint32(b[i])
+		} else {
+			pc |= uint32(b[i]) << (8 * i)
+		}
+	}
+
+	return PointCode(pc & f.mask())
+}
+
+// Encode encodes pc into the first Len() octets of b.
+//
+// It returns error if pc does not fit in the significant bits.
+func (f PointCodeFormat) Encode(b []byte, pc PointCode) error {
+	if uint32(pc)&^f.mask() != 0 {
+		return fmt.Errorf("point code %d exceeds %d bits: %w", pc, f.Bits, ErrInvalidAddress)
+	}
+
+	for i := 0; i < f.Octets; i++ {
+		v := uint8(pc >> (8 * i))
+		if f.BigEndian {
+			b[f.Octets-1-i] = v
+		} else {
+			b[i] = v
+		}
+	}
+
+	return nil
+}
+
+func (f PointCodeFormat) mask() uint32 {
+	if f.Bits >= 32 {
+		return 0xffffffff
+	}
+	return 1<<f.Bits - 1
+}
@@ -0,0 +1,97 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import (
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// logRedaction is the number of the leading digits kept in LogValue, or
+// negative if the redaction is disabled.
+var logRedaction atomic.Int64
+
+func init() {
+	logRedaction.Store(-1)
+}
+
+// SetLogRedaction enables or disables the redaction of the Global Title
+// digits in the values returned by LogValue, which is disabled by default.
+//
+// When enabled, the digits after the first keep digits are replaced with "*",
+// e.g., "81901****" for "819012345" with keep 5, so that the subscriber
+// numbers do not appear in the logs.
+func SetLogRedaction(enabled bool, keep int) {
+	if !enabled {
+		logRedaction.Store(-1)
+		return
+	}
+
+	logRedaction.Store(int64(max(keep, 0)))
+}
+
+// redactDigits returns the digits redacted as configured by SetLogRedaction.
+func redactDigits(digits string) string {
+	keep := int(logRedaction.Load())
+	if keep < 0 || keep >= len(digits) {
+		return digits
+	}
+
+	return digits[:keep] + strings.Repeat("*", len(digits)-keep)
+}
+
+// LogValue implements slog.LogValuer, which returns the PartyAddress as a
+// group of the routing indicator, the point code, the SSN and the Global
+// Title that are present.
+//
+// The digits of the Global Title are redacted if enabled by SetLogRedaction.
+func (p *PartyAddress) LogValue() slog.Value {
+	if p == nil {
+		return slog.Value{}
+	}
+
+	ri := "GT"
+	if p.RouteOnSSN() {
+		ri = "SSN"
+	}
+
+	attrs := []slog.Attr{slog.String("ri", ri)}
+	if p.variant != VariantITU {
+		attrs = append(attrs, slog.String("variant", p.variant.String()))
+	}
+	if p.HasPC() {
+		attrs = append(attrs, slog.String("pc", p.variant.FormatPointCode(p.SignalingPointCode)))
+	}
+	if p.HasSSN() {
+		attrs = append(attrs, slog.Int("ssn", int(p.SubsystemNumber)))
+	}
+	if p.GlobalTitle != nil {
+		attrs = append(attrs, slog.Any("gt", globalTitleLogValue(p.GlobalTitle)))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+func globalTitleLogValue(g GlobalTitle) slog.Value {
+	attrs := []slog.Attr{slog.Int("gti", int(g.GTI()))}
+	switch g := g.(type) {
+	case *GTNAIOnly:
+		attrs = append(attrs, slog.String("nai", g.NatureOfAddressIndicator.Even().String()))
+	case *GTTTOnly:
+		attrs = append(attrs, slog.Int("tt", int(g.TranslationType)))
+	case *GTTTNPES:
+		attrs = append(attrs, slog.Int("tt", int(g.TranslationType)), slog.String("np", g.NumberingPlan.String()))
+	case *GTTTNPESNAI:
+		attrs = append(attrs,
+			slog.Int("tt", int(g.TranslationType)),
+			slog.String("np", g.NumberingPlan.String()),
+			slog.String("nai", g.NatureOfAddressIndicator.String()),
+		)
+	}
+	attrs = append(attrs, slog.String("digits", redactDigits(g.Address())))
+
+	return slog.GroupValue(attrs...)
+}
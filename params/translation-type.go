@@ -0,0 +1,102 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import (
+	"strconv"
+	"sync"
+)
+
+// TranslationType is a type of Translation Type.
+// See Q.713 3.4.2.3.2 for more details.
+type TranslationType uint8
+
+// TranslationType values and ranges.
+const (
+	TTUnknown          TranslationType = 0b00000000 // unknown
+	TTInternationalMin TranslationType = 0b00000001 // first value for international services
+	TTInternationalMax TranslationType = 0b00111111 // last value for international services
+	TTSpareMin         TranslationType = 0b01000000 // first spare value
+	TTSpareMax         TranslationType = 0b01111111 // last spare value
+	TTNationalMin      TranslationType = 0b10000000 // first value for national network specific
+	TTNationalMax      TranslationType = 0b11111110 // last value for national network specific
+	TTReserved         TranslationType = 0b11111111 // reserved
+)
+
+// IsInternational reports whether the TranslationType is in the range for
+// international services.
+func (tt TranslationType) IsInternational() bool {
+	return tt >= TTInternationalMin && tt <= TTInternationalMax
+}
+
+// IsSpare reports whether the TranslationType is in the spare range.
+func (tt TranslationType) IsSpare() bool {
+	return tt >= TTSpareMin && tt <= TTSpareMax
+}
+
+// IsNationalSpecific reports whether the TranslationType is in the range for
+// national network specific use.
+func (tt TranslationType) IsNationalSpecific() bool {
+	return tt >= TTNationalMin && tt <= TTNationalMax
+}
+
+// String returns the description of the TranslationType registered with
+// RegisterTranslationType, or the name of the range it belongs to.
+func (tt TranslationType) String() string {
+	desc, ok := LookupTranslationType(tt)
+	if !ok {
+		switch {
+		case tt == TTUnknown:
+			return "unknown (0)"
+		case tt.IsInternational():
+			desc = "international service"
+		case tt.IsSpare():
+			desc = "spare"
+		case tt.IsNationalSpecific():
+			desc = "national network specific"
+		default:
+			desc = "reserved"
+		}
+	}
+
+	return desc + " (" + strconv.Itoa(int(tt)) + ")"
+}
+
+var (
+	ttRegistry   = map[TranslationType]string{}
+	ttRegistryMu sync.RWMutex
+)
+
+// RegisterTranslationType registers the description of the TranslationType,
+// which is used in the String method of TranslationType (and thus in the
+// String method of GlobalTitle and PartyAddress).
+//
+// This is useful to give names to the network-specific values used in a
+// specific network. Registering the same value again overwrites the description.
+func RegisterTranslationType(tt TranslationType, desc string) {
+	ttRegistryMu.Lock()
+	defer ttRegistryMu.Unlock()
+
+	ttRegistry[tt] = desc
+}
+
+// UnregisterTranslationType removes the description of the TranslationType
+// registered with RegisterTranslationType.
+func UnregisterTranslationType(tt TranslationType) {
+	ttRegistryMu.Lock()
+	defer ttRegistryMu.Unlock()
+
+	delete(ttRegistry, tt)
+}
+
+// LookupTranslationType returns the description of the TranslationType
+// registered with RegisterTranslationType.
+func LookupTranslationType(tt TranslationType) (string, bool) {
+	ttRegistryMu.RLock()
+	defer ttRegistryMu.RUnlock()
+
+	desc, ok := ttRegistry[tt]
+	return desc, ok
+}
@@ -0,0 +1,141 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package params
+
+import "fmt"
+
+// Variant is a variant of SCCP, which affects the format of some parameters.
+type Variant uint8
+
+// Variant values.
+const (
+	VariantITU   Variant = iota // ITU-T Q.713
+	VariantANSI                 // ANSI T1.112
+	VariantChina                // Chinese national SCCP (YD/T 1127)
+	VariantTTC                  // TTC JT-Q713
+)
+
+// String returns the name of the Variant.
+func (v Variant) String() string {
+	switch v {
+	case VariantITU:
+		return "ITU"
+	case VariantANSI:
+		return "ANSI"
+	case VariantChina:
+		return "China"
+	case VariantTTC:
+		return "TTC"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseVariant returns the Variant with the name returned by String.
+func ParseVariant(name string) (Variant, error) {
+	for v := VariantITU; v.String() != "unknown"; v++ {
+		if v.String() == name {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid variant %q: %w", name, ErrInvalidVariant)
+}
+
+// PointCodeCodec returns the PointCodeCodec for the point code in the
+// PartyAddress of the Variant, which is used unless another one is set to it.
+func (v Variant) PointCodeCodec() PointCodeCodec {
+	switch v {
+	case VariantANSI:
+		return ANSIPointCodeCodec
+	case VariantChina:
+		return ChinaPointCodeCodec
+	case VariantTTC:
+		return TTCPointCodeCodec
+	default:
+		return ITUPointCodeCodec
+	}
+}
+
+// FormatPointCode returns pc in the text format commonly used in the Variant,
+// e.g., "1-2-3" of zone-area-SP in ITU and of network-cluster-member in ANSI.
+// The 24-bit point code in China is in the same form as ANSI, i.e., main
+// signalling area, sub signalling area and signalling point, and the 16-bit
+// one in TTC is main area, sub area and signalling point.
+func (v Variant) FormatPointCode(pc PointCode) string {
+	switch v {
+	case VariantANSI, VariantChina:
+		return pc.ANSIString()
+	case VariantTTC:
+		return pc.TTCString()
+	default:
+		return pc.String()
+	}
+}
+
+// NewAddressIndicator creates a new AddressIndicator in the format of the
+// Variant. See NewAddressIndicator and NewANSIAddressIndicator.
+//
+// In TTC, the bit reserved for national use in ITU is the national indicator,
+// which is set as the addresses are always national ones in JT-Q713.
+//
+// gti should be the one in the format of the Variant, e.g., by Variant.GTI.
+func (v Variant) NewAddressIndicator(hasPC, hasSSN, routeOnSSN bool, gti GlobalTitleIndicator) uint8 {
+	switch {
+	case v.ansiAddress():
+		return NewANSIAddressIndicator(hasPC, hasSSN, routeOnSSN, gti)
+	case v == VariantTTC:
+		return NewAddressIndicator(hasPC, hasSSN, routeOnSSN, gti) | 0b10000000
+	default:
+		return NewAddressIndicator(hasPC, hasSSN, routeOnSSN, gti)
+	}
+}
+
+// GTI returns the Global Title Indicator of gt in the format of the Variant,
+// or GTINoGT if gt is nil.
+//
+// In ANSI, GTTTNPES is indicated by 0001 and GTTTOnly by 0010. The other types
+// are not defined in ANSI, and their indicators in ITU are returned as is.
+func (v Variant) GTI(gt GlobalTitle) GlobalTitleIndicator {
+	if gt == nil {
+		return GTINoGT
+	}
+	if v.ansiAddress() {
+		switch gt.(type) {
+		case *GTTTNPES:
+			return ansiGTITTNPES
+		case *GTTTOnly:
+			return ansiGTITTOnly
+		}
+	}
+	return gt.GTI()
+}
+
+// ansiAddress reports whether the PartyAddress of the Variant is in the ANSI
+// layout, where the SSN indicator is the first bit of the AddressIndicator
+// and the SSN is put before the PC.
+func (v Variant) ansiAddress() bool {
+	return v == VariantANSI
+}
+
+// NewANSIAddressIndicator creates a new AddressIndicator in the ANSI format,
+// where the SSN indicator is the first bit and the PC indicator is the second.
+//
+// The last bit, which is the national/international indicator, is set to 1 (national).
+// gti is the one in ANSI, i.e., 0001 for TT, NP and ES, and 0010 for TT only.
+func NewANSIAddressIndicator(hasPC, hasSSN, routeOnSSN bool, gti GlobalTitleIndicator) uint8 {
+	ai := uint8(0b10000000)
+	if hasSSN {
+		ai |= 0b00000001
+	}
+	if hasPC {
+		ai |= 0b00000010
+	}
+	if routeOnSSN {
+		ai |= 0b01000000
+	}
+	ai |= uint8(gti) << 2
+
+	return ai
+}
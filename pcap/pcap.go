@@ -0,0 +1,264 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package pcap extracts the SCCP messages from the pcap and pcapng files.
+
+Reader walks the SCTP DATA chunks in each frame, and decodes the ones carrying
+M3UA DATA or M2PA User Data with the Service Indicator of SCCP. The SCTP
+chunks that are fragmented are not reassembled and skipped.
+*/
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/gopacket/gopacket/pcapgo"
+	"github.com/wmnsk/go-m3ua/messages"
+	m3params "github.com/wmnsk/go-m3ua/messages/params"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/mtp3"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// SCTP Payload Protocol Identifiers, which are not defined in gopacket.
+const (
+	ppidM3UA = 3
+	ppidM2PA = 5
+)
+
+// ErrNotSCCP indicates that the payload of a SCTP DATA chunk does not carry a
+// SCCP message. It is not returned by Reader.Next, which skips such chunks.
+var ErrNotSCCP = errors.New("pcap: not a SCCP message")
+
+// Packet is a SCCP message found in a capture file.
+type Packet struct {
+	// Timestamp is the time the frame was captured.
+	Timestamp time.Time
+	// Frame is the 1-origin index of the frame in the file.
+	Frame int
+
+	// OPC, DPC and SLS are the ones in the MTP3 routing label or in the M3UA
+	// Protocol Data.
+	OPC, DPC params.PointCode
+	SLS      uint8
+
+	Message sccp.Message
+}
+
+// packetSource is implemented by pcapgo.Reader and pcapgo.NgReader.
+type packetSource interface {
+	ReadPacketData() ([]byte, gopacket.CaptureInfo, error)
+	LinkType() layers.LinkType
+}
+
+// Reader reads the SCCP messages from a pcap or pcapng file.
+type Reader struct {
+	src     packetSource
+	codec   *sccp.Codec
+	frame   int
+	pending []result
+}
+
+type result struct {
+	p   *Packet
+	err error
+}
+
+// NewReader creates a new Reader that reads the capture from r, which can be
+// either pcap or pcapng.
+//
+// The SCCP messages and the MTP3 routing labels are decoded in the Variant
+// given with sccp.WithVariant, which is params.VariantITU by default.
+func NewReader(r io.Reader, opts ...sccp.ParseOption) (*Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file header: %w", err)
+	}
+
+	var src packetSource
+	if binary.BigEndian.Uint32(magic) == 0x0a0d0d0a { // Section Header Block
+		src, err = pcapgo.NewNgReader(br, pcapgo.DefaultNgReaderOptions)
+	} else {
+		src, err = pcapgo.NewReader(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{src: src, codec: sccp.NewCodec(opts...)}, nil
+}
+
+// Next returns the next SCCP message in the capture. It returns io.EOF at the
+// end of the capture.
+//
+// The error in decoding a SCCP message is returned with the frame number, and
+// Next can be called again to continue from the next one.
+func (r *Reader) Next() (*Packet, error) {
+	for len(r.pending) == 0 {
+		data, ci, err := r.src.ReadPacketData()
+		if err != nil {
+			return nil, err
+		}
+		r.frame++
+		r.readFrame(data, ci)
+	}
+
+	res := r.pending[0]
+	r.pending = r.pending[1:]
+	return res.p, res.err
+}
+
+// ReadFile reads all the SCCP messages in the file.
+//
+// It stops at the first error, and returns the messages read until then.
+func ReadFile(name string, opts ...sccp.ParseOption) ([]*Packet, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := NewReader(f, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var ps []*Packet
+	for {
+		p, err := r.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return ps, nil
+			}
+			return ps, err
+		}
+		ps = append(ps, p)
+	}
+}
+
+// readFrame decodes the SCTP DATA chunks in a frame and queues the results.
+func (r *Reader) readFrame(data []byte, ci gopacket.CaptureInfo) {
+	pkt := gopacket.NewPacket(data, r.src.LinkType(), gopacket.DecodeOptions{Lazy: true, NoCopy: true})
+	for _, l := range pkt.Layers() {
+		chunk, ok := l.(*layers.SCTPData)
+		if !ok || !chunk.BeginFragment || !chunk.EndFragment {
+			continue
+		}
+
+		p, err := r.decodeChunk(chunk)
+		if errors.Is(err, ErrNotSCCP) {
+			continue
+		}
+		if err != nil {
+			r.pending = append(r.pending, result{err: fmt.Errorf("frame %d: %w", r.frame, err)})
+			continue
+		}
+
+		p.Timestamp = ci.Timestamp
+		p.Frame = r.frame
+		r.pending = append(r.pending, result{p: p})
+	}
+}
+
+// decodeChunk decodes the payload of a SCTP DATA chunk as M3UA or M2PA.
+func (r *Reader) decodeChunk(chunk *layers.SCTPData) (*Packet, error) {
+	var (
+		p   *Packet
+		msg []byte
+		err error
+	)
+	switch chunk.PayloadProtocol {
+	case ppidM3UA:
+		p, msg, err = decodeM3UA(chunk.Payload)
+	case ppidM2PA:
+		p, msg, err = r.decodeM2PA(chunk.Payload)
+	default:
+		return nil, ErrNotSCCP
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.Message, err = r.codec.ParseMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// decodeM3UA decodes b as a M3UA DATA, and returns the Packet with the values
+// in the Protocol Data and the SCCP message in it.
+func decodeM3UA(b []byte) (*Packet, []byte, error) {
+	m, err := messages.Parse(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, ok := m.(*messages.Data)
+	if !ok || data.ProtocolData == nil {
+		return nil, nil, ErrNotSCCP
+	}
+
+	pd, err := data.ProtocolData.ProtocolData()
+	if err != nil {
+		return nil, nil, err
+	}
+	if pd.ServiceIndicator != m3params.ServiceIndSCCP {
+		return nil, nil, ErrNotSCCP
+	}
+
+	return &Packet{
+		OPC: params.PointCode(pd.OriginatingPointCode),
+		DPC: params.PointCode(pd.DestinationPointCode),
+		SLS: pd.SignalingLinkSelection,
+	}, pd.Data, nil
+}
+
+// decodeM2PA decodes b as a M2PA User Data defined in RFC 4165, and returns
+// the Packet with the values in the MTP3 routing label and the SCCP message
+// in it.
+func (r *Reader) decodeM2PA(b []byte) (*Packet, []byte, error) {
+	// common header (8), BSN (4), FSN (4) and priority (1).
+	const hdrLen = 17
+	if len(b) < hdrLen {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	if b[2] != 11 || b[3] != 1 { // Message Class M2PA, Message Type User Data
+		return nil, nil, ErrNotSCCP
+	}
+
+	l := int(binary.BigEndian.Uint32(b[4:8]))
+	if l < hdrLen {
+		// Link Status or the User Data without MSU.
+		return nil, nil, ErrNotSCCP
+	}
+	if len(b) < l {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	return r.decodeMTP3(b[hdrLen:l])
+}
+
+// decodeMTP3 decodes b as a MTP3 MSU starting with the SIO.
+func (r *Reader) decodeMTP3(b []byte) (*Packet, []byte, error) {
+	msu, err := mtp3.ParseMSU(r.codec.Variant(), b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if msu.ServiceIndicator != mtp3.ServiceIndicatorSCCP {
+		return nil, nil, ErrNotSCCP
+	}
+
+	return &Packet{OPC: msu.OPC, DPC: msu.DPC, SLS: msu.SLS}, msu.Payload, nil
+}
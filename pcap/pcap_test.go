@@ -0,0 +1,198 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pcap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/gopacket/gopacket/pcapgo"
+	"github.com/wmnsk/go-m3ua/messages"
+	m3params "github.com/wmnsk/go-m3ua/messages/params"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/pcap"
+)
+
+type chunk struct {
+	ppid    layers.SCTPPayloadProtocol
+	payload []byte
+}
+
+func frame(t *testing.T, chunks ...chunk) []byte {
+	t.Helper()
+
+	ls := []gopacket.SerializableLayer{
+		&layers.Ethernet{
+			SrcMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 1},
+			DstMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 2},
+			EthernetType: layers.EthernetTypeIPv4,
+		},
+		&layers.IPv4{
+			Version:  4,
+			TTL:      64,
+			Protocol: layers.IPProtocolSCTP,
+			SrcIP:    net.IP{192, 0, 2, 1},
+			DstIP:    net.IP{192, 0, 2, 2},
+		},
+		&layers.SCTP{SrcPort: 2905, DstPort: 2905},
+	}
+	for i, c := range chunks {
+		ls = append(ls, &layers.SCTPData{
+			SCTPChunk:       layers.SCTPChunk{Type: layers.SCTPChunkTypeData, Length: uint16(16 + len(c.payload))},
+			BeginFragment:   true,
+			EndFragment:     true,
+			TSN:             uint32(i),
+			PayloadProtocol: c.ppid,
+			Payload:         c.payload,
+		})
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, ls...); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func m3uaData(t *testing.T, si uint8, opc, dpc uint32, sls uint8, msg []byte) chunk {
+	t.Helper()
+
+	b, err := messages.NewData(
+		nil, nil, m3params.NewProtocolData(opc, dpc, si, 2, 0, sls, msg), nil,
+	).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chunk{ppid: 3, payload: b}
+}
+
+func m2paUserData(label, msg []byte) chunk {
+	mtp3 := append([]byte{0x83}, label...) // NI national, SI SCCP
+	mtp3 = append(mtp3, msg...)
+
+	b := make([]byte, 17, 17+len(mtp3))
+	b[0], b[2], b[3] = 1, 11, 1
+	binary.BigEndian.PutUint32(b[4:8], uint32(17+len(mtp3)))
+	return chunk{ppid: 5, payload: append(b, mtp3...)}
+}
+
+func TestReader(t *testing.T) {
+	cdpa := params.NewCalledPartyAddress(0x42, 0, 6, nil)
+	cgpa := params.NewCallingPartyAddress(0x42, 0, 7, nil)
+	udt := sccp.NewUDT(1, true, cdpa, cgpa, []byte{0xde, 0xad, 0xbe, 0xef})
+	msg, err := udt.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DPC 1-2-3, OPC 4-5-6 and SLS 7 in the ITU routing label.
+	dpc, _ := params.NewITUPointCode(1, 2, 3)
+	opc, _ := params.NewITUPointCode(4, 5, 6)
+	label := binary.LittleEndian.AppendUint32(nil, uint32(dpc)|uint32(opc)<<14|7<<28)
+
+	frames := [][]byte{
+		frame(t, m3uaData(t, 3, 100, 200, 5, msg)),
+		frame(t, m3uaData(t, 5, 100, 200, 5, []byte{0x01})), // ISUP
+		frame(t, m2paUserData(label, msg), m3uaData(t, 3, 300, 400, 9, []byte{0xff})),
+		frame(t, m3uaData(t, 3, 200, 100, 6, msg)),
+	}
+
+	want := []*pcap.Packet{
+		{Frame: 1, OPC: 100, DPC: 200, SLS: 5},
+		{Frame: 3, OPC: opc, DPC: dpc, SLS: 7},
+		nil, // unsupported message type
+		{Frame: 4, OPC: 200, DPC: 100, SLS: 6},
+	}
+
+	check := func(t *testing.T, r *pcap.Reader, start time.Time) {
+		t.Helper()
+		for i, w := range want {
+			got, err := r.Next()
+			if w == nil {
+				var e sccp.UnsupportedTypeError
+				if !errors.As(err, &e) {
+					t.Errorf("#%d: got %v, want UnsupportedTypeError", i, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("#%d: %v", i, err)
+			}
+
+			if diff := cmp.Diff(got.Message.MessageType(), sccp.MsgTypeUDT); diff != "" {
+				t.Error(diff)
+			}
+			got.Message = nil
+			w.Timestamp = start.Add(time.Duration(w.Frame-1) * time.Second)
+			if diff := cmp.Diff(got, w); diff != "" {
+				t.Errorf("#%d: %s", i, diff)
+			}
+		}
+
+		if _, err := r.Next(); !errors.Is(err, io.EOF) {
+			t.Errorf("got %v, want io.EOF", err)
+		}
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	capture := func(i int, f []byte) gopacket.CaptureInfo {
+		return gopacket.CaptureInfo{
+			Timestamp:     start.Add(time.Duration(i) * time.Second),
+			CaptureLength: len(f),
+			Length:        len(f),
+		}
+	}
+
+	t.Run("pcap", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := pcapgo.NewWriter(&buf)
+		if err := w.WriteFileHeader(65535, layers.LinkTypeEthernet); err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range frames {
+			if err := w.WritePacket(capture(i, f), f); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		r, err := pcap.NewReader(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, r, start)
+	})
+
+	t.Run("pcapng", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := pcapgo.NewNgWriter(&buf, layers.LinkTypeEthernet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range frames {
+			if err := w.WritePacket(capture(i, f), f); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := pcap.NewReader(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, r, start)
+	})
+}
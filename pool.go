@@ -0,0 +1,180 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"sync"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Pool is a pool of the UDTs and XUDTs to be reused, so that the applications
+// that handle a lot of them, such as an STP, parse them without allocating
+// the messages and the parameters in them every time.
+//
+// The message returned by Pool should be given back with Put when it is no
+// longer used. The message, and the parameters taken from it, must not be used
+// after Put, as they are overwritten when the message is reused. Use Clone to
+// keep them.
+//
+// Pool is safe for concurrent use.
+type Pool struct {
+	opts parseOptions
+	udt  sync.Pool
+	xudt sync.Pool
+}
+
+// NewPool creates a new Pool that parses the messages with opts.
+func NewPool(opts ...ParseOption) *Pool {
+	p := &Pool{opts: *newParseOptions(opts)}
+	p.udt.New = func() any { return &UDT{opts: p.opts, reuse: &reusable{}} }
+	p.xudt.New = func() any { return &XUDT{opts: p.opts, reuse: &reusable{}} }
+	return p
+}
+
+// Parse decodes b in the same way as ParseMessage, but the UDT and XUDT are
+// taken from the Pool.
+func (p *Pool) Parse(b []byte) (Message, error) {
+	if len(b) > 0 {
+		switch MsgType(b[0]) {
+		case MsgTypeUDT:
+			return p.ParseUDT(b)
+		case MsgTypeXUDT:
+			return p.ParseXUDT(b)
+		}
+	}
+
+	return (&Codec{opts: p.opts}).ParseMessage(b)
+}
+
+// ParseUDT decodes b as a UDT taken from the Pool.
+func (p *Pool) ParseUDT(b []byte) (*UDT, error) {
+	u := p.udt.Get().(*UDT)
+	if err := u.UnmarshalBinary(b); err != nil {
+		p.Put(u)
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// ParseXUDT decodes b as a XUDT taken from the Pool.
+func (p *Pool) ParseXUDT(b []byte) (*XUDT, error) {
+	x := p.xudt.Get().(*XUDT)
+	if err := x.UnmarshalBinary(b); err != nil {
+		p.Put(x)
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// Put resets m and puts it back to the Pool. The messages other than UDT and
+// XUDT are ignored.
+func (p *Pool) Put(m Message) {
+	switch m := m.(type) {
+	case *UDT:
+		m.Reset()
+		m.opts = p.opts
+		if m.reuse == nil {
+			m.reuse = &reusable{}
+		}
+		p.udt.Put(m)
+	case *XUDT:
+		m.Reset()
+		m.opts = p.opts
+		if m.reuse == nil {
+			m.reuse = &reusable{}
+		}
+		p.xudt.Put(m)
+	}
+}
+
+// reusable is the parameters allocated by UnmarshalBinary of the UDT and
+// XUDT taken from Pool, which are kept by Reset to be reused by the next
+// UnmarshalBinary. It is nil in the other messages.
+//
+// They are reused only after Reset, as the ones parsed before may still be
+// referred to by the caller otherwise. The ones replaced by the caller are
+// not reused, as they may be shared with the other messages.
+type reusable struct {
+	pcls       *params.ProtocolClass
+	hc         *params.HopCounter
+	cdpa, cgpa *params.PartyAddress
+	data       *params.Data
+
+	spare bool
+}
+
+// set records the parameters allocated by UnmarshalBinary.
+func (r *reusable) set(parsed reusable) {
+	if r != nil {
+		*r = parsed
+	}
+}
+
+// keep keeps the parameters that are still set in the message to be reused.
+func (r *reusable) keep(pcls *params.ProtocolClass, hc *params.HopCounter, cdpa, cgpa *params.PartyAddress, data *params.Data) {
+	if r == nil {
+		return
+	}
+
+	k := reusable{spare: true}
+	if r.pcls == pcls {
+		k.pcls = pcls
+	}
+	if r.hc == hc {
+		k.hc = hc
+	}
+	if r.cdpa == cdpa {
+		k.cdpa = cdpa
+	}
+	if r.cgpa == cgpa {
+		k.cgpa = cgpa
+	}
+	if r.data == data {
+		k.data = data
+	}
+	*r = k
+}
+
+// take returns the spare parameters, which are nil if not reusable, and
+// clears r.
+func (r *reusable) take() reusable {
+	if r == nil {
+		return reusable{}
+	}
+
+	t := *r
+	*r = reusable{}
+	if !t.spare {
+		return reusable{}
+	}
+	return t
+}
+
+func (r reusable) protocolClass() *params.ProtocolClass {
+	if r.pcls == nil {
+		return &params.ProtocolClass{}
+	}
+	*r.pcls = params.ProtocolClass{}
+	return r.pcls
+}
+
+func (r reusable) hopCounter() *params.HopCounter {
+	if r.hc == nil {
+		return &params.HopCounter{}
+	}
+	*r.hc = params.HopCounter{}
+	return r.hc
+}
+
+func (r reusable) dataParam() *params.Data {
+	if r.data == nil {
+		return &params.Data{}
+	}
+	*r.data = params.Data{}
+	return r.data
+}
@@ -0,0 +1,348 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Default values of the Reassembler.
+const (
+	// DefaultReassemblyTimeout is the reassembly timer T(reass), which is 10
+	// to 20 seconds in Q.714 Annex A.
+	DefaultReassemblyTimeout = 10 * time.Second
+	// DefaultMaxReassemblies is the maximum number of the reassemblies in
+	// progress at the same time.
+	DefaultMaxReassemblies = 1024
+	// DefaultMaxReassemblyBytes is the maximum total size of the data held by
+	// all the reassemblies in progress.
+	DefaultMaxReassemblyBytes = 1024 * 1024
+)
+
+// Errors in the reassembly, which are wrapped in ReassemblyError.
+var (
+	// ErrReassemblyTimeout is the error given when T(reass) expires before
+	// all the segments are received.
+	ErrReassemblyTimeout = errors.New("sccp: reassembly timeout")
+	// ErrReassemblyEvicted is the error given when the oldest reassembly is
+	// discarded to start a new one over ReassemblerConfig.MaxReassemblies.
+	ErrReassemblyEvicted = errors.New("sccp: reassembly evicted")
+	// ErrReassemblyTooLarge is the error given when the data held exceeds
+	// ReassemblerConfig.MaxBytes.
+	ErrReassemblyTooLarge = errors.New("sccp: reassembly exceeds the size limit")
+	// ErrReassemblyOutOfSequence is the error given when the segment is not
+	// the one expected, e.g., a segment is lost (see Q.714 4.1.1.3.2).
+	ErrReassemblyOutOfSequence = errors.New("sccp: segment out of sequence")
+)
+
+// ReassemblyError is the error in the reassembly of the segments with the
+// LocalReference from the CallingPartyAddress. Data is the partial data
+// received before the error.
+//
+// FirstSegment is the first segment retained by the Reassembler, the *XUDT
+// or *LUDT, which is returned in the XUDTS or LUDTS with "segmentation
+// failure" if its return option is set (see Q.714 4.1.1.3.3). It is nil if
+// the first segment is not received.
+type ReassemblyError struct {
+	CallingPartyAddress *params.PartyAddress
+	LocalReference      uint32
+	Data                []byte
+	FirstSegment        Message
+	Err                 error
+}
+
+// Error returns the type of receiver and some additional message.
+func (e *ReassemblyError) Error() string {
+	return fmt.Sprintf(
+		"sccp: failed to reassemble segments %d from %v with %d octets received: %v",
+		e.LocalReference, e.CallingPartyAddress, len(e.Data), e.Err,
+	)
+}
+
+// Unwrap returns the cause of the error.
+func (e *ReassemblyError) Unwrap() error {
+	return e.Err
+}
+
+// ReassemblerConfig is the configuration of a Reassembler. The zero values
+// are replaced with the defaults.
+type ReassemblerConfig struct {
+	// Timeout is T(reass), started on the first segment.
+	Timeout time.Duration
+	// MaxReassemblies is the maximum number of the reassemblies in progress.
+	// The oldest one is discarded to start a new one over it.
+	MaxReassemblies int
+	// MaxBytes is the maximum total size of the data held by the reassemblies
+	// in progress. The reassembly that would exceed it is discarded.
+	MaxBytes int
+
+	// Dropped is called with the ReassemblyError when a reassembly is
+	// discarded without the error returned from Add, i.e., on the timeout,
+	// the eviction and the new first segment with the same key. It is called
+	// in its own goroutine on the timeout.
+	Dropped func(*ReassemblyError)
+}
+
+func (c *ReassemblerConfig) withDefaults() ReassemblerConfig {
+	var cfg ReassemblerConfig
+	if c != nil {
+		cfg = *c
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultReassemblyTimeout
+	}
+	if cfg.MaxReassemblies == 0 {
+		cfg.MaxReassemblies = DefaultMaxReassemblies
+	}
+	if cfg.MaxBytes == 0 {
+		cfg.MaxBytes = DefaultMaxReassemblyBytes
+	}
+	return cfg
+}
+
+// Reassembler reassembles the payloads from the XUDTs or LUDTs segmented by
+// the peers, keyed by the Calling Party Address and the Segmentation Local
+// Reference (see Q.714 4.1.1.3).
+//
+// The segments of a payload should be received in sequence. The reassembly
+// fails if a segment is lost or received out of sequence.
+//
+// Reassembler is safe for concurrent use.
+type Reassembler struct {
+	cfg ReassemblerConfig
+
+	mu      sync.Mutex
+	pending map[reassemblyKey]*reassembly
+	bytes   int
+	seq     uint64
+	// onDropped is called after ReassemblerConfig.Dropped, which is set by
+	// Endpoint to return the first segments.
+	onDropped []func(*ReassemblyError)
+}
+
+type reassemblyKey struct {
+	cgpa string
+	ref  uint32
+}
+
+type reassembly struct {
+	cgpa      *params.PartyAddress
+	ref       uint32
+	first     Message
+	data      []byte
+	remaining uint8 // the Remaining Segments expected in the next one
+	seq       uint64
+	timer     *time.Timer
+}
+
+// NewReassembler creates a new Reassembler with cfg, which can be nil to use
+// the defaults.
+func NewReassembler(cfg *ReassemblerConfig) *Reassembler {
+	return &Reassembler{
+		cfg:     cfg.withDefaults(),
+		pending: map[reassemblyKey]*reassembly{},
+	}
+}
+
+// Add adds the segment in x, and returns the reassembled payload if x is the
+// last one. It returns nil without error if more segments are expected.
+//
+// The data of the XUDT without the Segmentation, or with the one that has no
+// Remaining Segments in the first segment, is returned as it is.
+//
+// It returns ReassemblyError if x causes the reassembly to fail, which wraps
+// ErrReassemblyOutOfSequence or ErrReassemblyTooLarge.
+func (r *Reassembler) Add(x *XUDT) ([]byte, error) {
+	return r.add(x.CallingPartyAddress, x.Segmentation, x.LoadData().Value(), func() Message { return x.Clone() })
+}
+
+// AddLUDT adds the segment in l in the same way as Add. The LUDT is retained
+// as the first segment to be returned in the LUDTS.
+func (r *Reassembler) AddLUDT(l *LUDT) ([]byte, error) {
+	return r.add(l.CallingPartyAddress, l.Segmentation, paramValue(l.Data), func() Message { return l.Clone() })
+}
+
+// add adds the segment with cgpa, seg and data. clone is called to retain
+// the first segment.
+func (r *Reassembler) add(cgpa *params.PartyAddress, seg *params.Segmentation, data []byte, clone func() Message) ([]byte, error) {
+	if seg == nil || seg.FirstSegment && seg.RemainingSegments == 0 {
+		return data, nil
+	}
+
+	key := reassemblyKey{cgpa: addressKey(cgpa), ref: seg.LocalReference}
+
+	var dropped *ReassemblyError
+	defer func() {
+		if dropped != nil {
+			r.dropped(dropped)
+		}
+	}()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ra, ok := r.pending[key]
+	if seg.FirstSegment {
+		if ok {
+			// the new one replaces the one that is not completed.
+			r.remove(key, ra)
+			dropped = ra.error(ErrReassemblyOutOfSequence)
+		} else if len(r.pending) >= r.cfg.MaxReassemblies {
+			dropped = r.evictOldest()
+		}
+
+		r.seq++
+		ra = &reassembly{
+			cgpa:  cgpa.Clone(),
+			ref:   seg.LocalReference,
+			first: clone(),
+			seq:   r.seq,
+		}
+		ra.timer = time.AfterFunc(r.cfg.Timeout, func() { r.expire(key, ra) })
+		r.pending[key] = ra
+	} else {
+		if !ok {
+			return nil, &ReassemblyError{
+				CallingPartyAddress: cgpa,
+				LocalReference:      seg.LocalReference,
+				Err:                 ErrReassemblyOutOfSequence,
+			}
+		}
+		if seg.RemainingSegments != ra.remaining {
+			r.remove(key, ra)
+			return nil, ra.error(ErrReassemblyOutOfSequence)
+		}
+	}
+
+	if r.bytes+len(data) > r.cfg.MaxBytes {
+		r.remove(key, ra)
+		return nil, ra.error(ErrReassemblyTooLarge)
+	}
+	ra.data = append(ra.data, data...)
+	r.bytes += len(data)
+
+	if seg.RemainingSegments == 0 {
+		r.remove(key, ra)
+		return ra.data, nil
+	}
+	ra.remaining = seg.RemainingSegments - 1
+	return nil, nil
+}
+
+// Len returns the number of the reassemblies in progress.
+func (r *Reassembler) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.pending)
+}
+
+// Close discards all the reassemblies in progress and stops the timers.
+// Dropped is not called for them.
+func (r *Reassembler) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, ra := range r.pending {
+		r.remove(key, ra)
+	}
+}
+
+// expire discards ra on T(reass) expiry.
+func (r *Reassembler) expire(key reassemblyKey, ra *reassembly) {
+	r.mu.Lock()
+	if r.pending[key] != ra {
+		// completed or discarded already.
+		r.mu.Unlock()
+		return
+	}
+	r.remove(key, ra)
+	r.mu.Unlock()
+
+	if mt := currentMetrics(); mt != nil {
+		mt.ReassemblyTimeout()
+	}
+	r.dropped(ra.error(ErrReassemblyTimeout))
+}
+
+// dropped calls ReassemblerConfig.Dropped and the functions added by
+// addDropped with err. It must be called without r.mu held.
+func (r *Reassembler) dropped(err *ReassemblyError) {
+	if r.cfg.Dropped != nil {
+		r.cfg.Dropped(err)
+	}
+
+	r.mu.Lock()
+	fns := r.onDropped
+	r.mu.Unlock()
+	for _, fn := range fns {
+		fn(err)
+	}
+}
+
+// addDropped adds fn to be called with the reassemblies dropped.
+func (r *Reassembler) addDropped(fn func(*ReassemblyError)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onDropped = append(r.onDropped, fn)
+}
+
+// evictOldest discards the reassembly started first. It must be called with
+// r.mu held.
+func (r *Reassembler) evictOldest() *ReassemblyError {
+	var (
+		oldestKey reassemblyKey
+		oldest    *reassembly
+	)
+	for key, ra := range r.pending {
+		if oldest == nil || ra.seq < oldest.seq {
+			oldestKey, oldest = key, ra
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+
+	r.remove(oldestKey, oldest)
+	return oldest.error(ErrReassemblyEvicted)
+}
+
+// remove removes ra and stops its timer. It must be called with r.mu held.
+func (r *Reassembler) remove(key reassemblyKey, ra *reassembly) {
+	ra.timer.Stop()
+	delete(r.pending, key)
+	r.bytes -= len(ra.data)
+}
+
+func (ra *reassembly) error(err error) *ReassemblyError {
+	return &ReassemblyError{
+		CallingPartyAddress: ra.cgpa,
+		LocalReference:      ra.ref,
+		Data:                ra.data,
+		FirstSegment:        ra.first,
+		Err:                 err,
+	}
+}
+
+// addressKey returns the encoded address to be used as a map key.
+func addressKey(p *params.PartyAddress) string {
+	if p == nil {
+		return ""
+	}
+
+	b := make([]byte, p.MarshalLen())
+	n, err := p.Write(b)
+	if err != nil {
+		return p.String()
+	}
+	return string(b[:n])
+}
@@ -0,0 +1,164 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MessageParser decodes the byte sequence of a message, including the Message
+// Type, into a Message. It is registered with RegisterMessageType.
+//
+// The returned Message is marshaled with its own methods, so the parser is
+// expected to return the type that implements the format of the message type.
+type MessageParser func(b []byte) (Message, error)
+
+var (
+	msgRegistry   = map[MsgType]MessageParser{}
+	msgRegistryMu sync.RWMutex
+)
+
+// RegisterMessageType registers the MessageParser for the Message Type, which
+// is used by ParseMessage (and Codec.ParseMessage) instead of returning
+// UnsupportedTypeError.
+//
+// This is useful to handle the national or reserved message types used by
+// specific equipment. The message types defined in Q.713 cannot be registered,
+// and ErrBuiltinMessageType is returned. Registering the same value again
+// overwrites the MessageParser.
+//
+// ParseRawMessage can be registered to keep the messages as they are.
+func RegisterMessageType(t MsgType, parse MessageParser) error {
+	if t >= MsgTypeCR && t <= MsgTypeLUDTS {
+		return fmt.Errorf("cannot register %s: %w", t, ErrBuiltinMessageType)
+	}
+
+	msgRegistryMu.Lock()
+	defer msgRegistryMu.Unlock()
+
+	msgRegistry[t] = parse
+	return nil
+}
+
+// UnregisterMessageType removes the MessageParser registered with
+// RegisterMessageType.
+func UnregisterMessageType(t MsgType) {
+	msgRegistryMu.Lock()
+	defer msgRegistryMu.Unlock()
+
+	delete(msgRegistry, t)
+}
+
+// lookupMessageParser returns the MessageParser registered for t, or nil.
+func lookupMessageParser(t MsgType) MessageParser {
+	msgRegistryMu.RLock()
+	defer msgRegistryMu.RUnlock()
+
+	return msgRegistry[t]
+}
+
+// RawMessage is a Message of any Message Type whose contents are kept as they
+// are, without being decoded.
+type RawMessage struct {
+	Type    MsgType
+	Payload []byte // octets that follow the Message Type
+}
+
+// NewRawMessage creates a new RawMessage.
+func NewRawMessage(typ MsgType, payload []byte) *RawMessage {
+	return &RawMessage{
+		Type:    typ,
+		Payload: payload,
+	}
+}
+
+// ParseRawMessage decodes given byte sequence as a RawMessage. It can be
+// registered with RegisterMessageType as it is.
+func ParseRawMessage(b []byte) (Message, error) {
+	r := &RawMessage{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// MarshalBinary returns the byte sequence generated from a RawMessage instance.
+func (r *RawMessage) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RawMessage) MarshalTo(b []byte) error {
+	if len(b) < r.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(r.Type)
+	copy(b[1:], r.Payload)
+	return nil
+}
+
+// AppendTo appends the byte sequence generated from the RawMessage to dst, and
+// returns the extended slice.
+func (r *RawMessage) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, r)
+}
+
+// WriteTo writes the byte sequence generated from the RawMessage to w. It
+// implements io.WriterTo.
+func (r *RawMessage) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, r)
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a RawMessage.
+//
+// The Payload refers to b.
+func (r *RawMessage) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+	r.Payload = b[1:]
+	return nil
+}
+
+// Clone returns a copy of the RawMessage that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (r *RawMessage) Clone() *RawMessage {
+	return &RawMessage{
+		Type:    r.Type,
+		Payload: bytes.Clone(r.Payload),
+	}
+}
+
+// MarshalLen returns the serial length.
+func (r *RawMessage) MarshalLen() int {
+	return 1 + len(r.Payload)
+}
+
+// String returns the RawMessage values in human readable format.
+func (r *RawMessage) String() string {
+	return fmt.Sprintf("%s: {Payload: %x}", r.Type, r.Payload)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RawMessage) MessageType() MsgType {
+	return r.Type
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RawMessage) MessageTypeName() string {
+	return r.Type.String()
+}
@@ -0,0 +1,87 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DefaultHopCounter is the Hop Counter of the XUDTS and LUDTS created by
+// NewServiceMessage, which is the maximum value defined in Q.713 3.18.
+const DefaultHopCounter = 15
+
+// NewServiceMessage creates the UDTS, XUDTS or LUDTS that returns m, which is
+// the UDT, XUDT or LUDT that cannot be delivered, to the originator with cause,
+// following Q.714 4.2.
+//
+// The Called and Calling Party Addresses of m are swapped, and the data and
+// the optional parameters are copied. The Hop Counter of the XUDTS and LUDTS
+// is set to DefaultHopCounter.
+//
+// It returns ErrNoReturnOption if the return option is not set in the
+// Protocol Class of m, in which case m should be discarded.
+func NewServiceMessage(m Message, cause params.ReturnCauseValue) (Message, error) {
+	ret, err := newServiceMessage(m, cause)
+	if err != nil {
+		return nil, err
+	}
+
+	if mt := currentMetrics(); mt != nil {
+		mt.MessageReturned(cause)
+	}
+	return ret, nil
+}
+
+func newServiceMessage(m Message, cause params.ReturnCauseValue) (Message, error) {
+	switch m := m.(type) {
+	case *UDT:
+		if !m.ProtocolClass.ReturnOnError() {
+			return nil, fmt.Errorf("%s: %w", m.Type, ErrNoReturnOption)
+		}
+		cdpa, cgpa := swapAddresses(m.CalledPartyAddress, m.CallingPartyAddress)
+		return NewUDTS(cause, cdpa, cgpa, m.LoadData().Clone().Value()), nil
+	case *XUDT:
+		if !m.ProtocolClass.ReturnOnError() {
+			return nil, fmt.Errorf("%s: %w", m.Type, ErrNoReturnOption)
+		}
+
+		var opts []params.Parameter
+		if param := m.Segmentation; param != nil {
+			opts = append(opts, clonePtr(param))
+		}
+		if param := m.Importance; param != nil {
+			opts = append(opts, params.NewImportanceOptional(param.Value()))
+		}
+		if param := m.ISNI; param != nil {
+			opts = append(opts, param.Clone())
+		}
+		cdpa, cgpa := swapAddresses(m.CalledPartyAddress, m.CallingPartyAddress)
+		return NewXUDTS(cause, DefaultHopCounter, cdpa, cgpa, m.LoadData().Clone().Value(), opts...), nil
+	case *LUDT:
+		if !m.ProtocolClass.ReturnOnError() {
+			return nil, fmt.Errorf("%s: %w", m.Type, ErrNoReturnOption)
+		}
+
+		var opts []params.Parameter
+		if param := m.Segmentation; param != nil {
+			opts = append(opts, clonePtr(param))
+		}
+		if param := m.Importance; param != nil {
+			opts = append(opts, params.NewImportanceOptional(param.Value()))
+		}
+		cdpa, cgpa := swapAddresses(m.CalledPartyAddress, m.CallingPartyAddress)
+		return NewLUDTS(cause, DefaultHopCounter, cdpa, cgpa, m.Data.CopyValue(), opts...), nil
+	default:
+		return nil, UnsupportedTypeError(m.MessageType())
+	}
+}
+
+// swapAddresses returns the copies of the addresses of the original message
+// to be used as the Called and Calling Party Addresses of the one in reply.
+func swapAddresses(cdpa, cgpa *params.PartyAddress) (*params.PartyAddress, *params.PartyAddress) {
+	return cgpa.Clone().AsCalled(), cdpa.Clone().AsCalling()
+}
@@ -0,0 +1,141 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RLC represents a SCCP Message Release Complete (RLC).
+type RLC struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+
+	trailing []byte
+}
+
+// NewRLC creates a new RLC.
+func NewRLC(dlr, slr uint32) *RLC {
+	return &RLC{
+		Type:                      MsgTypeRLC,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a RLC instance.
+func (r *RLC) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RLC) MarshalTo(b []byte) error {
+	return marshalSections(b, r.Type, r.fixed(), nil, nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the RLC to dst, and
+// returns the extended slice.
+func (r *RLC) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, r)
+}
+
+// WriteTo writes the byte sequence generated from the RLC to w. It implements
+// io.WriterTo.
+func (r *RLC) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, r)
+}
+
+// fixed returns the mandatory fixed parameters of the RLC.
+func (r *RLC) fixed() []params.Parameter {
+	return []params.Parameter{r.DestinationLocalReference, r.SourceLocalReference}
+}
+
+// ParseRLC decodes given byte sequence as a SCCP RLC.
+func ParseRLC(b []byte) (*RLC, error) {
+	r := &RLC{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RLC.
+func (r *RLC) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+	r.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	r.SourceLocalReference = params.NewSourceLocalReference(0)
+
+	_, n, err := params.UnmarshalSections(b[1:], r.fixed(), nil, false)
+	if err != nil {
+		return err
+	}
+
+	r.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the RLC that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (r *RLC) Clone() *RLC {
+	c := *r
+	c.DestinationLocalReference = r.DestinationLocalReference.Clone()
+	c.SourceLocalReference = r.SourceLocalReference.Clone()
+	c.trailing = bytes.Clone(r.trailing)
+
+	return &c
+}
+
+// Reset clears the RLC to be reused with UnmarshalBinary.
+func (r *RLC) Reset() {
+	*r = RLC{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the RLC when
+// it is parsed, or nil if there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (r *RLC) TrailingBytes() []byte {
+	return r.trailing
+}
+
+// MarshalLen returns the serial length.
+func (r *RLC) MarshalLen() int {
+	return 1 + params.SectionsLen(r.fixed(), nil, nil, false)
+}
+
+// String returns the RLC values in human readable format.
+func (r *RLC) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RLC) MessageType() MsgType {
+	return MsgTypeRLC
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RLC) MessageTypeName() string {
+	return r.MessageType().String()
+}
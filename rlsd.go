@@ -0,0 +1,212 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RLSD represents a SCCP Message Released (RLSD).
+type RLSD struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+	ReleaseCause              *params.ReleaseCause
+	Data                      *params.Data
+	Importance                *params.Importance
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	trailing []byte
+	opts     parseOptions
+}
+
+// NewRLSD creates a new RLSD.
+//
+// The optional parameters given as opts should be the optional ones, e.g.,
+// created with params.NewDataOptional.
+func NewRLSD(dlr, slr uint32, cause params.ReleaseCauseValue, opts ...params.Parameter) *RLSD {
+	r := &RLSD{
+		Type:                      MsgTypeRLSD,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+		ReleaseCause:              params.NewCause(cause),
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeData:
+			r.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			r.Importance = opt.(*params.Importance)
+		case params.PCodeEndOfOptionalParameters:
+			// always appended when there is any optional parameter.
+		default:
+			if takeUnknownParameter(&r.UnknownParameters, opt) {
+				continue
+			}
+			logf("unexpected parameter: %s in NewRLSD", opt.Code())
+		}
+	}
+
+	return r
+}
+
+// MarshalBinary returns the byte sequence generated from a RLSD instance.
+func (r *RLSD) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RLSD) MarshalTo(b []byte) error {
+	fixed, optional := r.sections()
+	return marshalSections(b, r.Type, fixed, nil, optional, true)
+}
+
+// AppendTo appends the byte sequence generated from the RLSD to dst, and
+// returns the extended slice.
+func (r *RLSD) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, r)
+}
+
+// WriteTo writes the byte sequence generated from the RLSD to w. It implements
+// io.WriterTo.
+func (r *RLSD) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, r)
+}
+
+// sections returns the parameters in each section of the RLSD.
+func (r *RLSD) sections() (fixed, optional []params.Parameter) {
+	fixed = []params.Parameter{r.DestinationLocalReference, r.SourceLocalReference, r.ReleaseCause}
+
+	if param := r.Data; param != nil {
+		optional = append(optional, param)
+	}
+	if param := r.Importance; param != nil {
+		optional = append(optional, param)
+	}
+	for _, param := range r.UnknownParameters {
+		optional = append(optional, param)
+	}
+
+	return fixed, optional
+}
+
+// ParseRLSD decodes given byte sequence as a SCCP RLSD.
+func ParseRLSD(b []byte, opts ...ParseOption) (*RLSD, error) {
+	r := &RLSD{opts: *newParseOptions(opts)}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RLSD.
+func (r *RLSD) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+	r.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	r.SourceLocalReference = params.NewSourceLocalReference(0)
+	r.ReleaseCause = &params.ReleaseCause{}
+	r.Data, r.Importance = nil, nil
+	r.UnknownParameters = nil
+
+	opts, n, err := r.opts.unmarshalSections(
+		b[1:],
+		[]params.Parameter{r.DestinationLocalReference, r.SourceLocalReference, r.ReleaseCause},
+		nil,
+		true,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeData:
+			r.Data = opt.(*params.Data)
+		case params.PCodeImportance:
+			r.Importance = opt.(*params.Importance)
+		default:
+			takeUnknownParameter(&r.UnknownParameters, opt)
+		}
+	}
+
+	r.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the RLSD that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (r *RLSD) Clone() *RLSD {
+	c := *r
+	c.DestinationLocalReference = r.DestinationLocalReference.Clone()
+	c.SourceLocalReference = r.SourceLocalReference.Clone()
+	c.ReleaseCause = clonePtr(r.ReleaseCause)
+	c.Data = r.Data.Clone()
+	c.Importance = clonePtr(r.Importance)
+	c.UnknownParameters = cloneUnknownParameters(r.UnknownParameters)
+	c.trailing = bytes.Clone(r.trailing)
+
+	return &c
+}
+
+// Reset clears the RLSD to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (r *RLSD) Reset() {
+	*r = RLSD{opts: r.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the RLSD computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (r *RLSD) TrailingBytes() []byte {
+	return r.trailing
+}
+
+// MarshalLen returns the serial length.
+func (r *RLSD) MarshalLen() int {
+	fixed, optional := r.sections()
+	return 1 + params.SectionsLen(fixed, nil, optional, true)
+}
+
+// String returns the RLSD values in human readable format.
+func (r *RLSD) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s, ReleaseCause: %s, Data: %v, Importance: %v}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+		r.ReleaseCause,
+		r.Data,
+		r.Importance,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RLSD) MessageType() MsgType {
+	return MsgTypeRLSD
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RLSD) MessageTypeName() string {
+	return r.MessageType().String()
+}
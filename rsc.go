@@ -0,0 +1,141 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RSC represents a SCCP Message Reset Confirm (RSC).
+type RSC struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+
+	trailing []byte
+}
+
+// NewRSC creates a new RSC.
+func NewRSC(dlr, slr uint32) *RSC {
+	return &RSC{
+		Type:                      MsgTypeRSC,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an RSC instance.
+func (r *RSC) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RSC) MarshalTo(b []byte) error {
+	return marshalSections(b, r.Type, r.fixed(), nil, nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the RSC to dst, and
+// returns the extended slice.
+func (r *RSC) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, r)
+}
+
+// WriteTo writes the byte sequence generated from the RSC to w. It implements
+// io.WriterTo.
+func (r *RSC) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, r)
+}
+
+// fixed returns the mandatory fixed parameters of the RSC.
+func (r *RSC) fixed() []params.Parameter {
+	return []params.Parameter{r.DestinationLocalReference, r.SourceLocalReference}
+}
+
+// ParseRSC decodes given byte sequence as a SCCP RSC.
+func ParseRSC(b []byte) (*RSC, error) {
+	r := &RSC{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RSC.
+func (r *RSC) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+	r.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	r.SourceLocalReference = params.NewSourceLocalReference(0)
+
+	_, n, err := params.UnmarshalSections(b[1:], r.fixed(), nil, false)
+	if err != nil {
+		return err
+	}
+
+	r.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the RSC that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (r *RSC) Clone() *RSC {
+	c := *r
+	c.DestinationLocalReference = r.DestinationLocalReference.Clone()
+	c.SourceLocalReference = r.SourceLocalReference.Clone()
+	c.trailing = bytes.Clone(r.trailing)
+
+	return &c
+}
+
+// Reset clears the RSC to be reused with UnmarshalBinary.
+func (r *RSC) Reset() {
+	*r = RSC{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the RSC when
+// it is parsed, or nil if there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (r *RSC) TrailingBytes() []byte {
+	return r.trailing
+}
+
+// MarshalLen returns the serial length.
+func (r *RSC) MarshalLen() int {
+	return 1 + params.SectionsLen(r.fixed(), nil, nil, false)
+}
+
+// String returns the RSC values in human readable format.
+func (r *RSC) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RSC) MessageType() MsgType {
+	return MsgTypeRSC
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RSC) MessageTypeName() string {
+	return r.MessageType().String()
+}
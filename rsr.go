@@ -0,0 +1,146 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RSR represents a SCCP Message Reset Request (RSR).
+type RSR struct {
+	Type                      MsgType
+	DestinationLocalReference *params.LocalReference
+	SourceLocalReference      *params.LocalReference
+	ResetCause                *params.ResetCause
+
+	trailing []byte
+}
+
+// NewRSR creates a new RSR.
+func NewRSR(dlr, slr uint32, cause params.ResetCauseValue) *RSR {
+	return &RSR{
+		Type:                      MsgTypeRSR,
+		DestinationLocalReference: params.NewDestinationLocalReference(dlr),
+		SourceLocalReference:      params.NewSourceLocalReference(slr),
+		ResetCause:                params.NewCause(cause),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from an RSR instance.
+func (r *RSR) MarshalBinary() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (r *RSR) MarshalTo(b []byte) error {
+	return marshalSections(b, r.Type, r.fixed(), nil, nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the RSR to dst, and
+// returns the extended slice.
+func (r *RSR) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, r)
+}
+
+// WriteTo writes the byte sequence generated from the RSR to w. It implements
+// io.WriterTo.
+func (r *RSR) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, r)
+}
+
+// fixed returns the mandatory fixed parameters of the RSR.
+func (r *RSR) fixed() []params.Parameter {
+	return []params.Parameter{r.DestinationLocalReference, r.SourceLocalReference, r.ResetCause}
+}
+
+// ParseRSR decodes given byte sequence as a SCCP RSR.
+func ParseRSR(b []byte) (*RSR, error) {
+	r := &RSR{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP RSR.
+func (r *RSR) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.Type = MsgType(b[0])
+	r.DestinationLocalReference = params.NewDestinationLocalReference(0)
+	r.SourceLocalReference = params.NewSourceLocalReference(0)
+	r.ResetCause = &params.ResetCause{}
+
+	_, n, err := params.UnmarshalSections(b[1:], r.fixed(), nil, false)
+	if err != nil {
+		return err
+	}
+
+	r.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the RSR that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (r *RSR) Clone() *RSR {
+	c := *r
+	c.DestinationLocalReference = r.DestinationLocalReference.Clone()
+	c.SourceLocalReference = r.SourceLocalReference.Clone()
+	c.ResetCause = clonePtr(r.ResetCause)
+	c.trailing = bytes.Clone(r.trailing)
+
+	return &c
+}
+
+// Reset clears the RSR to be reused with UnmarshalBinary.
+func (r *RSR) Reset() {
+	*r = RSR{}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the RSR when
+// it is parsed, or nil if there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (r *RSR) TrailingBytes() []byte {
+	return r.trailing
+}
+
+// MarshalLen returns the serial length.
+func (r *RSR) MarshalLen() int {
+	return 1 + params.SectionsLen(r.fixed(), nil, nil, false)
+}
+
+// String returns the RSR values in human readable format.
+func (r *RSR) String() string {
+	return fmt.Sprintf("%s: {DestinationLocalReference: %s, SourceLocalReference: %s, ResetCause: %s}",
+		r.Type,
+		r.DestinationLocalReference,
+		r.SourceLocalReference,
+		r.ResetCause,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (r *RSR) MessageType() MsgType {
+	return MsgTypeRSR
+}
+
+// MessageTypeName returns the Message Type in string.
+func (r *RSR) MessageTypeName() string {
+	return r.MessageType().String()
+}
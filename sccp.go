@@ -12,8 +12,13 @@ package sccp
 
 import (
 	"encoding"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"slices"
+	"sync"
+
+	"github.com/wmnsk/go-sccp/params"
 )
 
 // MsgType is type of SCCP message.
@@ -49,6 +54,8 @@ type Message interface {
 	encoding.BinaryMarshaler
 	encoding.BinaryUnmarshaler
 	MarshalTo([]byte) error
+	AppendTo(dst []byte) ([]byte, error)
+	io.WriterTo
 	MarshalLen() int
 	MessageType() MsgType
 	MessageTypeName() string
@@ -56,47 +63,221 @@ type Message interface {
 }
 
 // ParseMessage decodes the byte sequence into Message by Message Type.
-func ParseMessage(b []byte) (Message, error) {
-	if len(b) < 1 {
-		return nil, fmt.Errorf("invalid SCCP message %v: %w", b, io.ErrUnexpectedEOF)
-	}
-
-	var m Message
-	switch MsgType(b[0]) {
-	/* TODO: implement!
-	case MsgTypeCR:
-	case MsgTypeCC:
-	case MsgTypeCREF:
-	case MsgTypeRLSD:
-	case MsgTypeRLC:
-	case MsgTypeDT1:
-	case MsgTypeDT2:
-	case MsgTypeAK:
-	*/
-	case MsgTypeUDT:
-		m = &UDT{}
-	/* TODO: implement!
-	case MsgTypeUDTS:
-	case MsgTypeED:
-	case MsgTypeEA:
-	case MsgTypeRSR:
-	case MsgTypeRSC:
-	case MsgTypeERR:
-	case MsgTypeIT:
-	*/
-	case MsgTypeXUDT:
-		m = &XUDT{}
-	/* TODO: implement!
-	case MsgTypeXUDTS:
-	case MsgTypeLUDT:
-	case MsgTypeLUDTS:
-	*/
+//
+// It is the same as DefaultCodec.ParseMessage if no ParseOption is given.
+func ParseMessage(b []byte, opts ...ParseOption) (Message, error) {
+	return codecOf(opts).ParseMessage(b)
+}
+
+// validateProtocolClass checks if the class in p is allowed in the message type t.
+func validateProtocolClass(t MsgType, p *params.ProtocolClass) error {
+	var ok bool
+	switch t {
+	case MsgTypeUDT, MsgTypeXUDT, MsgTypeLUDT:
+		ok = p.IsConnectionless()
+	case MsgTypeCR, MsgTypeCC, MsgTypeIT:
+		ok = p.IsConnectionOriented()
 	default:
-		return nil, UnsupportedTypeError(b[0])
+		ok = true
+	}
+
+	if !ok {
+		return fmt.Errorf("class %d in %s: %w", p.Class(), t, ErrInvalidProtocolClass)
+	}
+	return nil
+}
+
+// marshalSections puts the Message Type t and the parameter sections in b.
+// See params.MarshalSections for the meaning of the other arguments.
+func marshalSections(b []byte, t MsgType, fixed, variable, optional []params.Parameter, hasOptionalPart bool) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	b[0] = uint8(t)
+	if _, err := params.MarshalSections(b[1:], fixed, variable, optional, hasOptionalPart); err != nil {
+		return err
+	}
+
+	if mt := currentMetrics(); mt != nil {
+		mt.MessageMarshalled(t)
+	}
+	return nil
+}
+
+// marshalLongSections puts the Message Type t and the parameter sections of
+// the LUDT or LUDTS in b. Unlike marshalSections, the pointers are two octets
+// long with the least significant octet first (Q.713 2.3), and the pointer to
+// the optional part is always present.
+func marshalLongSections(b []byte, t MsgType, fixed, variable, optional []params.Parameter) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+	b[0] = uint8(t)
+
+	n := 1
+	for _, p := range fixed {
+		m, err := p.Write(b[n:])
+		if err != nil {
+			return err
+		}
+		n += m
+	}
+
+	nptr := len(variable) + 1
+	if len(b) < n+2*nptr {
+		return io.ErrUnexpectedEOF
+	}
+
+	offset := n + 2*nptr
+	for i, p := range variable {
+		if err := putLongPointer(b, n+2*i, offset, p.Code()); err != nil {
+			return err
+		}
+
+		m, err := p.Write(b[offset:])
+		if err != nil {
+			return err
+		}
+		offset += m
+	}
+
+	at := n + 2*len(variable)
+	if len(optional) == 0 {
+		binary.LittleEndian.PutUint16(b[at:], 0)
+	} else {
+		if err := putLongPointer(b, at, offset, params.PCodeEndOfOptionalParameters); err != nil {
+			return err
+		}
+		if _, err := params.MarshalOptionalParameters(b[offset:], optional); err != nil {
+			return err
+		}
+	}
+
+	if mt := currentMetrics(); mt != nil {
+		mt.MessageMarshalled(t)
+	}
+	return nil
+}
+
+// putLongPointer puts the two-octet pointer at b[at:] to the parameter at b[to:].
+func putLongPointer(b []byte, at, to int, code params.ParameterNameCode) error {
+	ptr := to - at
+	if ptr > 0xffff {
+		return fmt.Errorf("pointer to %s is too far (%d): %w", code, ptr, params.ErrValueTooLong)
+	}
+
+	binary.LittleEndian.PutUint16(b[at:], uint16(ptr))
+	return nil
+}
+
+// longSectionsLen returns the serial length of the LUDT or LUDTS with the
+// given parameters, including the Message Type.
+func longSectionsLen(fixed, variable, optional []params.Parameter) int {
+	l := 1 + 2*(len(variable)+1)
+	for _, p := range fixed {
+		l += p.MarshalLen()
+	}
+	for _, p := range variable {
+		l += p.MarshalLen()
+	}
+	if len(optional) > 0 {
+		l += params.OptionalParametersLen(optional)
+	}
+
+	return l
+}
+
+// appendMessage appends the byte sequence generated from m to dst, growing it
+// only if it does not have enough capacity.
+func appendMessage(dst []byte, m interface {
+	MarshalTo([]byte) error
+	MarshalLen() int
+}) ([]byte, error) {
+	n := m.MarshalLen()
+	dst = slices.Grow(dst, n)
+	b := dst[len(dst) : len(dst)+n]
+	clear(b) // MarshalTo expects the zeroed buffer.
+	if err := m.MarshalTo(b); err != nil {
+		return dst, err
+	}
+
+	return dst[:len(dst)+n], nil
+}
+
+// writeBuffers are the buffers reused by writeMessage.
+var writeBuffers = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// maxPooledWriteBuffer is the capacity of the buffer not to be put back to
+// writeBuffers, so that a large message does not keep the memory forever.
+const maxPooledWriteBuffer = 64 * 1024
+
+// writeMessage writes the byte sequence generated from m to w, using the
+// buffer from writeBuffers instead of allocating one every time.
+func writeMessage(w io.Writer, m interface {
+	MarshalTo([]byte) error
+	MarshalLen() int
+}) (int64, error) {
+	bp := writeBuffers.Get().(*[]byte)
+	defer func() {
+		if cap(*bp) <= maxPooledWriteBuffer {
+			writeBuffers.Put(bp)
+		}
+	}()
+
+	b, err := appendMessage((*bp)[:0], m)
+	*bp = b[:0]
+	if err != nil {
+		return 0, err
 	}
 
-	if err := m.UnmarshalBinary(b); err != nil {
-		return nil, err
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// trailingBytes returns the bytes in b after end, or nil if there is no such bytes.
+func trailingBytes(b []byte, end int) []byte {
+	if len(b) > end {
+		return b[end:]
+	}
+	return nil
+}
+
+// clonePtr returns a shallow copy of the value p points to, which is enough
+// for the parameters that have no reference types in them.
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	c := *p
+	return &c
+}
+
+// cloneUnknownParameters returns a deep copy of ps.
+func cloneUnknownParameters(ps []*params.UnknownParameter) []*params.UnknownParameter {
+	if ps == nil {
+		return nil
+	}
+
+	c := make([]*params.UnknownParameter, len(ps))
+	for i, p := range ps {
+		c[i] = p.Clone()
+	}
+	return c
+}
+
+// takeUnknownParameter appends opt to ps if it is an UnknownParameter, and
+// reports whether it is.
+func takeUnknownParameter(ps *[]*params.UnknownParameter, opt params.Parameter) bool {
+	u, ok := opt.(*params.UnknownParameter)
+	if ok {
+		*ps = append(*ps, u)
 	}
-	return m, nil
+	return ok
 }
@@ -0,0 +1,20 @@
+module github.com/wmnsk/go-sccp/sccpotel
+
+go 1.23
+
+require (
+	github.com/pascaldekloe/goe v0.1.1
+	github.com/wmnsk/go-sccp v0.0.0-20261015134319-4e5edd259f0d
+	go.opentelemetry.io/otel v1.32.0
+	go.opentelemetry.io/otel/sdk v1.32.0
+	go.opentelemetry.io/otel/trace v1.32.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.32.0 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
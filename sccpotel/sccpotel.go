@@ -0,0 +1,284 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package sccpotel provides the OpenTelemetry tracing of the messages sent and
+received by sccp.Endpoint, the GTT lookups and the lifecycle of the signalling
+connections. It is a separate module so that the go-sccp module does not
+depend on OpenTelemetry, released after the root module in the same way as
+the sccpprom module.
+
+	sccpotel.Instrument(ep)
+
+	router := scrc.New(scrc.Config{Translator: sccpotel.NewTranslator(table)})
+
+	svc := scoc.NewService(&scoc.Config{Observer: sccpotel.Observer()}, send)
+
+The spans are created by the TracerProvider given by WithTracerProvider, or
+the global one by default, and have the following attributes if present:
+
+	sccp.message.type
+	sccp.cdpa.gt, sccp.cdpa.ssn, sccp.cdpa.pc
+	sccp.cgpa.gt, sccp.cgpa.ssn, sccp.cgpa.pc
+	sccp.return_cause, sccp.sls
+	sccp.gtt.rule, sccp.gtt.pc, sccp.gtt.gt
+	sccp.connection.local_reference, sccp.connection.remote_reference
+	sccp.connection.refusal_cause, sccp.connection.release_cause
+	sccp.connection.reset_cause
+*/
+package sccpotel
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/scoc"
+	"github.com/wmnsk/go-sccp/scrc"
+)
+
+// ScopeName is the instrumentation scope name of the Tracer.
+const ScopeName = "github.com/wmnsk/go-sccp/sccpotel"
+
+// The keys of the attributes of the spans.
+const (
+	MessageTypeKey     = attribute.Key("sccp.message.type")
+	CalledGTKey        = attribute.Key("sccp.cdpa.gt")
+	CalledSSNKey       = attribute.Key("sccp.cdpa.ssn")
+	CalledPCKey        = attribute.Key("sccp.cdpa.pc")
+	CallingGTKey       = attribute.Key("sccp.cgpa.gt")
+	CallingSSNKey      = attribute.Key("sccp.cgpa.ssn")
+	CallingPCKey       = attribute.Key("sccp.cgpa.pc")
+	ReturnCauseKey     = attribute.Key("sccp.return_cause")
+	SLSKey             = attribute.Key("sccp.sls")
+	GTTRuleKey         = attribute.Key("sccp.gtt.rule")
+	GTTPointCodeKey    = attribute.Key("sccp.gtt.pc")
+	GTTGlobalTitleKey  = attribute.Key("sccp.gtt.gt")
+	LocalReferenceKey  = attribute.Key("sccp.connection.local_reference")
+	RemoteReferenceKey = attribute.Key("sccp.connection.remote_reference")
+	RefusalCauseKey    = attribute.Key("sccp.connection.refusal_cause")
+	ReleaseCauseKey    = attribute.Key("sccp.connection.release_cause")
+	ResetCauseKey      = attribute.Key("sccp.connection.reset_cause")
+)
+
+// Option configures the tracing.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the TracerProvider the spans are created by, which
+// is the global one by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+func newTracer(opts []Option) trace.Tracer {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.provider == nil {
+		c.provider = otel.GetTracerProvider()
+	}
+
+	return c.provider.Tracer(ScopeName)
+}
+
+// Instrument adds InboundMiddleware and OutboundMiddleware to ep.
+func Instrument(ep *sccp.Endpoint, opts ...Option) {
+	ep.UseInbound(InboundMiddleware(opts...))
+	ep.UseOutbound(OutboundMiddleware(opts...))
+}
+
+// InboundMiddleware returns the sccp.Middleware that creates a span named
+// "sccp.receive <type>" for each message received, which is the parent of
+// the spans created while handling it, e.g., for the messages returned.
+func InboundMiddleware(opts ...Option) sccp.Middleware {
+	return middleware(newTracer(opts), "sccp.receive ", trace.SpanKindConsumer)
+}
+
+// OutboundMiddleware returns the sccp.Middleware that creates a span named
+// "sccp.send <type>" for each message sent.
+func OutboundMiddleware(opts ...Option) sccp.Middleware {
+	return middleware(newTracer(opts), "sccp.send ", trace.SpanKindProducer)
+}
+
+func middleware(tracer trace.Tracer, prefix string, kind trace.SpanKind) sccp.Middleware {
+	return func(next sccp.MessageHandler) sccp.MessageHandler {
+		return func(ctx context.Context, m sccp.Message) error {
+			ctx, span := tracer.Start(ctx, prefix+m.MessageTypeName(),
+				trace.WithSpanKind(kind),
+				trace.WithAttributes(MessageAttributes(m)...),
+			)
+			defer span.End()
+
+			err := next(ctx, m)
+			setError(span, err)
+			return err
+		}
+	}
+}
+
+// MessageAttributes returns the attributes of m: the message type, the Called
+// and Calling Party Addresses and the Return Cause that are present.
+func MessageAttributes(m sccp.Message) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{MessageTypeKey.String(m.MessageTypeName())}
+
+	var cdpa, cgpa *params.PartyAddress
+	var cause *params.ReturnCause
+	switch m := m.(type) {
+	case *sccp.UDT:
+		cdpa, cgpa = m.CalledPartyAddress, m.CallingPartyAddress
+	case *sccp.XUDT:
+		cdpa, cgpa = m.CalledPartyAddress, m.CallingPartyAddress
+	case *sccp.UDTS:
+		cdpa, cgpa, cause = m.CalledPartyAddress, m.CallingPartyAddress, m.ReturnCause
+	case *sccp.XUDTS:
+		cdpa, cgpa, cause = m.CalledPartyAddress, m.CallingPartyAddress, m.ReturnCause
+	case *sccp.CR:
+		cdpa, cgpa = m.CalledPartyAddress, m.CallingPartyAddress
+	case *sccp.CC:
+		cdpa = m.CalledPartyAddress
+	}
+
+	attrs = appendAddress(attrs, cdpa, CalledGTKey, CalledSSNKey, CalledPCKey)
+	attrs = appendAddress(attrs, cgpa, CallingGTKey, CallingSSNKey, CallingPCKey)
+	if cause != nil {
+		attrs = append(attrs, ReturnCauseKey.String(cause.Value().String()))
+	}
+	return attrs
+}
+
+// appendAddress appends the GT digits, the SSN and the PC present in p with
+// the keys.
+func appendAddress(attrs []attribute.KeyValue, p *params.PartyAddress, gt, ssn, pc attribute.Key) []attribute.KeyValue {
+	if p == nil {
+		return attrs
+	}
+
+	if p.GlobalTitle != nil {
+		attrs = append(attrs, gt.String(p.GlobalTitle.Address()))
+	}
+	if p.HasSSN() {
+		attrs = append(attrs, ssn.Int(int(p.SubsystemNumber)))
+	}
+	if p.HasPC() {
+		attrs = append(attrs, pc.String(p.Variant().FormatPointCode(p.SignalingPointCode)))
+	}
+	return attrs
+}
+
+// Translator is the scrc.Translator that creates a span named "sccp.gtt" for
+// each translation by the underlying one.
+type Translator struct {
+	translator scrc.Translator
+	tracer     trace.Tracer
+}
+
+// NewTranslator creates a new Translator that traces the translations by t,
+// e.g., a *gtt.Table.
+func NewTranslator(t scrc.Translator, opts ...Option) *Translator {
+	return &Translator{translator: t, tracer: newTracer(opts)}
+}
+
+// TranslateSLS translates the GT in addr with a span that has no parent, as
+// scrc.Translator does not take the context. Use TranslateContext where the
+// context is available.
+func (t *Translator) TranslateSLS(addr *params.PartyAddress, sls uint8) (*gtt.Result, error) {
+	return t.TranslateContext(context.Background(), addr, sls)
+}
+
+// TranslateContext translates the GT in addr with the span created in ctx,
+// which has the Rule matched and the translated address as the attributes.
+func (t *Translator) TranslateContext(ctx context.Context, addr *params.PartyAddress, sls uint8) (*gtt.Result, error) {
+	attrs := appendAddress(nil, addr, CalledGTKey, CalledSSNKey, CalledPCKey)
+	_, span := t.tracer.Start(ctx, "sccp.gtt",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(append(attrs, SLSKey.Int(int(sls)))...),
+	)
+	defer span.End()
+
+	res, err := t.translator.TranslateSLS(addr, sls)
+	if err != nil {
+		setError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(
+		GTTRuleKey.String(res.Rule.Name),
+		GTTPointCodeKey.String(res.Address.Variant().FormatPointCode(res.PointCode)),
+	)
+	if res.Address.GlobalTitle != nil {
+		span.SetAttributes(GTTGlobalTitleKey.String(res.Address.GlobalTitle.Address()))
+	}
+	return res, nil
+}
+
+// Observer returns the function to be set to scoc.Config.Observer, which
+// creates a span named "sccp.connection" for each connection that lasts until
+// it is released.
+//
+// The span has the events "connect_request", "connected" and "reset" on the
+// corresponding Events, and ends with the error status if the connection is
+// refused or released for other reasons than the RLSD or RLC from the peer.
+func Observer(opts ...Option) func(localRef uint32) scoc.Events {
+	tracer := newTracer(opts)
+	return func(localRef uint32) scoc.Events {
+		_, span := tracer.Start(context.Background(), "sccp.connection",
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(LocalReferenceKey.Int64(int64(localRef))),
+		)
+
+		return scoc.Events{
+			ConnectRequest: func(cr *sccp.CR) {
+				span.SetAttributes(RemoteReferenceKey.Int64(int64(cr.SourceLocalReference.Uint32())))
+				span.AddEvent("connect_request", trace.WithAttributes(MessageAttributes(cr)...))
+			},
+			Connected: func(cc *sccp.CC) {
+				span.SetAttributes(RemoteReferenceKey.Int64(int64(cc.SourceLocalReference.Uint32())))
+				span.AddEvent("connected")
+			},
+			Reset: func(cause params.ResetCauseValue) {
+				span.AddEvent("reset", trace.WithAttributes(ResetCauseKey.String(cause.String())))
+			},
+			Released: func(err error) {
+				defer span.End()
+
+				var refused *scoc.RefusedError
+				var released *scoc.ReleasedError
+				switch {
+				case err == nil:
+				case errors.As(err, &released):
+					span.SetAttributes(ReleaseCauseKey.String(released.Cause.String()))
+				case errors.As(err, &refused):
+					span.SetAttributes(RefusalCauseKey.String(refused.Cause.String()))
+					setError(span, err)
+				default:
+					setError(span, err)
+				}
+			},
+		}
+	}
+}
+
+// setError records err in span and sets the error status if err is not nil.
+func setError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
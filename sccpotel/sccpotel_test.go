@@ -0,0 +1,212 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccpotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pascaldekloe/goe/verify"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/sccpotel"
+	"github.com/wmnsk/go-sccp/scoc"
+)
+
+// writeTransport records the messages written to it.
+type writeTransport struct {
+	written []sccp.Message
+}
+
+func (w *writeTransport) ReadMessage() (sccp.Message, error) {
+	select {}
+}
+
+func (w *writeTransport) WriteMessage(m sccp.Message) error {
+	w.written = append(w.written, m)
+	return nil
+}
+
+func newRecorder() (*tracetest.SpanRecorder, sccpotel.Option) {
+	sr := tracetest.NewSpanRecorder()
+	return sr, sccpotel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+}
+
+func attributes(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestInstrument(t *testing.T) {
+	sr, opt := newRecorder()
+
+	tr := &writeTransport{}
+	ep := sccp.NewEndpoint(tr, nil)
+	sccpotel.Instrument(ep, opt)
+
+	cdpa, err := params.NewE164Address(6, "819012345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cgpa := params.NewSSNAddress(7)
+
+	// no user for SSN 6, which is returned in UDTS.
+	if err := ep.Handle(context.Background(), sccp.NewUDT(0, true, cdpa, cgpa, []byte{0xde, 0xad})); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.written) != 1 {
+		t.Fatalf("got %d messages written, want 1", len(tr.written))
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	send, recv := spans[0], spans[1]
+
+	if !verify.Values(t, "names", []string{recv.Name(), send.Name()}, []string{"sccp.receive UDT", "sccp.send UDTS"}) {
+		t.Fail()
+	}
+	if send.Parent().SpanID() != recv.SpanContext().SpanID() {
+		t.Error("send span is not the child of the receive span")
+	}
+
+	attrs := attributes(recv)
+	if !verify.Values(t, "receive attributes", []any{
+		attrs[sccpotel.MessageTypeKey].AsString(),
+		attrs[sccpotel.CalledGTKey].AsString(),
+		attrs[sccpotel.CalledSSNKey].AsInt64(),
+		attrs[sccpotel.CallingSSNKey].AsInt64(),
+	}, []any{"UDT", "819012345678", int64(6), int64(7)}) {
+		t.Fail()
+	}
+
+	if got := attributes(send)[sccpotel.ReturnCauseKey].AsString(); got != params.ReturnCauseUnequippedUser.String() {
+		t.Errorf("got return cause %q", got)
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	table, err := gtt.NewTable(gtt.Rule{Name: "jp", Prefix: "81", PointCode: 0x100, SSN: 6, RouteOnSSN: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		digits      string
+		rule        string
+		err         bool
+	}{
+		{description: "match", digits: "819012345678", rule: "jp"},
+		{description: "no match", digits: "447912345678", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			sr, opt := newRecorder()
+			tr := sccpotel.NewTranslator(table, opt)
+
+			addr, err := params.NewE164Address(6, c.digits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tr.TranslateSLS(addr, 3); (err != nil) != c.err {
+				t.Fatalf("got error %v", err)
+			}
+
+			spans := sr.Ended()
+			if len(spans) != 1 || spans[0].Name() != "sccp.gtt" {
+				t.Fatalf("got %d spans", len(spans))
+			}
+			attrs := attributes(spans[0])
+			if !verify.Values(t, "attributes", []any{
+				attrs[sccpotel.CalledGTKey].AsString(),
+				attrs[sccpotel.SLSKey].AsInt64(),
+				attrs[sccpotel.GTTRuleKey].AsString(),
+			}, []any{c.digits, int64(3), c.rule}) {
+				t.Fail()
+			}
+			if got := spans[0].Status().Code == codes.Error; got != c.err {
+				t.Errorf("got error status %v", got)
+			}
+		})
+	}
+}
+
+func TestObserver(t *testing.T) {
+	sr, opt := newRecorder()
+
+	var b *scoc.Service
+	a := scoc.NewService(&scoc.Config{Observer: sccpotel.Observer(opt)}, func(m sccp.Message) error {
+		return b.Handle(m)
+	})
+	b = scoc.NewService(nil, a.Handle)
+
+	l, err := b.ListenSSN(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := a.Connect(context.Background(), params.NewSSNAddress(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := peer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	<-c.Done()
+
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "sccp.connection" {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	s := spans[0]
+
+	var events []string
+	for _, e := range s.Events() {
+		events = append(events, e.Name)
+	}
+	attrs := attributes(s)
+	if !verify.Values(t, "span", []any{
+		events,
+		attrs[sccpotel.LocalReferenceKey].AsInt64(),
+		attrs[sccpotel.RemoteReferenceKey].AsInt64(),
+		attrs[sccpotel.ReleaseCauseKey].AsString(),
+		s.Status().Code,
+	}, []any{
+		[]string{"connected"},
+		int64(c.LocalReference()),
+		int64(peer.LocalReference()),
+		params.ReleaseCauseEndUserOriginated.String(),
+		codes.Unset,
+	}) {
+		t.Fail()
+	}
+
+	_, err = a.Connect(context.Background(), params.NewSSNAddress(9))
+	var rerr *scoc.RefusedError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("got %v, want RefusedError", err)
+	}
+	spans = sr.Ended()
+	if got := spans[len(spans)-1]; got.Status().Code != codes.Error || attributes(got)[sccpotel.RefusalCauseKey].AsString() != params.RefusalCauseUnequippedUser.String() {
+		t.Errorf("got status %v, attributes %v", got.Status(), got.Attributes())
+	}
+}
@@ -0,0 +1,568 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package sccppb provides the Protocol Buffers representation of the SCCP
+messages, which is defined in sccp.proto, and the conversion from and to the
+messages in go-sccp.
+
+It is meant for shipping the SCCP messages to the monitoring systems over
+gRPC, Kafka or anything that carries the protobuf messages.
+*/
+package sccppb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative sccp.proto
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// FromMessage converts the SCCP message into Message.
+//
+// The message types that are not implemented in go-sccp are supported only
+// as a *sccp.RawMessage.
+func FromMessage(m sccp.Message) (*Message, error) {
+	pb := &Message{Type: MessageType(m.MessageType())}
+	switch m := m.(type) {
+	case *sccp.CR:
+		pb.SourceLocalReference = localReference(m.SourceLocalReference)
+		pb.ProtocolClass = protocolClass(m.ProtocolClass)
+		pb.CalledPartyAddress = FromPartyAddress(m.CalledPartyAddress)
+		pb.Credit = uint8Value(m.Credit)
+		pb.CallingPartyAddress = FromPartyAddress(m.CallingPartyAddress)
+		pb.Data = data(m.Data)
+		pb.HopCounter = uint8Value(m.HopCounter)
+		pb.Importance = uint8Value(m.Importance)
+		pb.UnknownParameters = unknownParameters(m.UnknownParameters)
+	case *sccp.CC:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.SourceLocalReference = localReference(m.SourceLocalReference)
+		pb.ProtocolClass = protocolClass(m.ProtocolClass)
+		pb.Credit = uint8Value(m.Credit)
+		pb.CalledPartyAddress = FromPartyAddress(m.CalledPartyAddress)
+		pb.Data = data(m.Data)
+		pb.Importance = uint8Value(m.Importance)
+		pb.UnknownParameters = unknownParameters(m.UnknownParameters)
+	case *sccp.CREF:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.RefusalCause = cause(m.RefusalCause)
+		pb.CalledPartyAddress = FromPartyAddress(m.CalledPartyAddress)
+		pb.Data = data(m.Data)
+		pb.Importance = uint8Value(m.Importance)
+		pb.UnknownParameters = unknownParameters(m.UnknownParameters)
+	case *sccp.RLSD:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.SourceLocalReference = localReference(m.SourceLocalReference)
+		pb.ReleaseCause = cause(m.ReleaseCause)
+		pb.Data = data(m.Data)
+		pb.Importance = uint8Value(m.Importance)
+		pb.UnknownParameters = unknownParameters(m.UnknownParameters)
+	case *sccp.RLC:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.SourceLocalReference = localReference(m.SourceLocalReference)
+	case *sccp.DT1:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		if m.SegmentingReassembling != nil {
+			more := m.SegmentingReassembling.MoreData()
+			pb.SegmentingReassembling = &more
+		}
+		pb.Data = data(m.Data)
+	case *sccp.DT2:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.SequencingSegmenting = sequencingSegmenting(m.SequencingSegmenting)
+		pb.Data = data(m.Data)
+	case *sccp.AK:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		if m.ReceiveSequenceNumber != nil {
+			pr := uint32(m.ReceiveSequenceNumber.PR())
+			pb.ReceiveSequenceNumber = &pr
+		}
+		pb.Credit = uint8Value(m.Credit)
+	case *sccp.UDT:
+		pb.ProtocolClass = protocolClass(m.ProtocolClass)
+		pb.CalledPartyAddress = FromPartyAddress(m.CalledPartyAddress)
+		pb.CallingPartyAddress = FromPartyAddress(m.CallingPartyAddress)
+		pb.Data = data(m.LoadData())
+	case *sccp.UDTS:
+		pb.ReturnCause = cause(m.ReturnCause)
+		pb.CalledPartyAddress = FromPartyAddress(m.CalledPartyAddress)
+		pb.CallingPartyAddress = FromPartyAddress(m.CallingPartyAddress)
+		pb.Data = data(m.Data)
+	case *sccp.ED:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.Data = data(m.Data)
+	case *sccp.EA:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+	case *sccp.RSR:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.SourceLocalReference = localReference(m.SourceLocalReference)
+		pb.ResetCause = cause(m.ResetCause)
+	case *sccp.RSC:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.SourceLocalReference = localReference(m.SourceLocalReference)
+	case *sccp.IT:
+		pb.DestinationLocalReference = localReference(m.DestinationLocalReference)
+		pb.SourceLocalReference = localReference(m.SourceLocalReference)
+		pb.ProtocolClass = protocolClass(m.ProtocolClass)
+		pb.SequencingSegmenting = sequencingSegmenting(m.SequencingSegmenting)
+		pb.Credit = uint8Value(m.Credit)
+	case *sccp.XUDT:
+		pb.ProtocolClass = protocolClass(m.ProtocolClass)
+		pb.HopCounter = uint8Value(m.HopCounter)
+		pb.CalledPartyAddress = FromPartyAddress(m.CalledPartyAddress)
+		pb.CallingPartyAddress = FromPartyAddress(m.CallingPartyAddress)
+		pb.Data = data(m.LoadData())
+		pb.Segmentation = segmentation(m.Segmentation)
+		pb.Importance = uint8Value(m.Importance)
+		pb.Isni = isni(m.ISNI)
+		pb.UnknownParameters = unknownParameters(m.UnknownParameters)
+	case *sccp.XUDTS:
+		pb.ReturnCause = cause(m.ReturnCause)
+		pb.HopCounter = uint8Value(m.HopCounter)
+		pb.CalledPartyAddress = FromPartyAddress(m.CalledPartyAddress)
+		pb.CallingPartyAddress = FromPartyAddress(m.CallingPartyAddress)
+		pb.Data = data(m.Data)
+		pb.Segmentation = segmentation(m.Segmentation)
+		pb.Importance = uint8Value(m.Importance)
+		pb.Isni = isni(m.ISNI)
+		pb.UnknownParameters = unknownParameters(m.UnknownParameters)
+	case *sccp.RawMessage:
+		pb.Payload = m.Payload
+	default:
+		return nil, sccp.UnsupportedTypeError(m.MessageType())
+	}
+
+	return pb, nil
+}
+
+// ToMessage converts the Message into the SCCP message.
+//
+// The message types that are not implemented in go-sccp are converted into
+// *sccp.RawMessage. It returns sccp.ErrMissingParameter if any mandatory
+// parameter is not set.
+func ToMessage(pb *Message) (sccp.Message, error) {
+	t := sccp.MsgType(pb.GetType())
+	if err := checkMandatory(t, pb); err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case sccp.MsgTypeCR:
+		cdpa, err := ToPartyAddress(pb.CalledPartyAddress, params.PCodeCalledPartyAddress, false)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := optionals(pb, pb.CallingPartyAddress, params.PCodeCallingPartyAddress)
+		if err != nil {
+			return nil, err
+		}
+		if pb.HopCounter != nil {
+			opts = append(opts, params.NewHopCounterOptional(uint8(*pb.HopCounter)))
+		}
+		return sccp.NewCR(pb.GetSourceLocalReference(), int(pb.ProtocolClass.GetClass()), cdpa, opts...), nil
+	case sccp.MsgTypeCC:
+		opts, err := optionals(pb, pb.CalledPartyAddress, params.PCodeCalledPartyAddress)
+		if err != nil {
+			return nil, err
+		}
+		return sccp.NewCC(
+			pb.GetDestinationLocalReference(), pb.GetSourceLocalReference(), int(pb.ProtocolClass.GetClass()), opts...,
+		), nil
+	case sccp.MsgTypeCREF:
+		opts, err := optionals(pb, pb.CalledPartyAddress, params.PCodeCalledPartyAddress)
+		if err != nil {
+			return nil, err
+		}
+		return sccp.NewCREF(
+			pb.GetDestinationLocalReference(), params.RefusalCauseValue(pb.GetRefusalCause()), opts...,
+		), nil
+	case sccp.MsgTypeRLSD:
+		opts, err := optionals(pb, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		return sccp.NewRLSD(
+			pb.GetDestinationLocalReference(), pb.GetSourceLocalReference(),
+			params.ReleaseCauseValue(pb.GetReleaseCause()), opts...,
+		), nil
+	case sccp.MsgTypeRLC:
+		return sccp.NewRLC(pb.GetDestinationLocalReference(), pb.GetSourceLocalReference()), nil
+	case sccp.MsgTypeDT1:
+		return sccp.NewDT1(pb.GetDestinationLocalReference(), pb.GetSegmentingReassembling(), pb.GetData()), nil
+	case sccp.MsgTypeDT2:
+		s := pb.SequencingSegmenting
+		return sccp.NewDT2(
+			pb.GetDestinationLocalReference(), uint8(s.GetPs()), uint8(s.GetPr()), s.GetMoreData(), pb.GetData(),
+		), nil
+	case sccp.MsgTypeAK:
+		return sccp.NewAK(
+			pb.GetDestinationLocalReference(), uint8(pb.GetReceiveSequenceNumber()), uint8(pb.GetCredit()),
+		), nil
+	case sccp.MsgTypeUDT, sccp.MsgTypeUDTS, sccp.MsgTypeXUDT, sccp.MsgTypeXUDTS:
+		return toUnitdata(t, pb)
+	case sccp.MsgTypeED:
+		return sccp.NewED(pb.GetDestinationLocalReference(), pb.GetData()), nil
+	case sccp.MsgTypeEA:
+		return sccp.NewEA(pb.GetDestinationLocalReference()), nil
+	case sccp.MsgTypeRSR:
+		return sccp.NewRSR(
+			pb.GetDestinationLocalReference(), pb.GetSourceLocalReference(),
+			params.ResetCauseValue(pb.GetResetCause()),
+		), nil
+	case sccp.MsgTypeRSC:
+		return sccp.NewRSC(pb.GetDestinationLocalReference(), pb.GetSourceLocalReference()), nil
+	case sccp.MsgTypeIT:
+		s := pb.SequencingSegmenting
+		it := sccp.NewIT(
+			pb.GetDestinationLocalReference(), pb.GetSourceLocalReference(), int(pb.ProtocolClass.GetClass()),
+			uint8(s.GetPs()), uint8(s.GetPr()), uint8(pb.GetCredit()),
+		)
+		it.SequencingSegmenting.SetMore(s.GetMoreData())
+		return it, nil
+	default:
+		return sccp.NewRawMessage(t, pb.GetPayload()), nil
+	}
+}
+
+// toUnitdata converts the Message into UDT, UDTS, XUDT or XUDTS.
+func toUnitdata(t sccp.MsgType, pb *Message) (sccp.Message, error) {
+	cdpa, err := ToPartyAddress(pb.CalledPartyAddress, params.PCodeCalledPartyAddress, false)
+	if err != nil {
+		return nil, err
+	}
+	cgpa, err := ToPartyAddress(pb.CallingPartyAddress, params.PCodeCallingPartyAddress, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == sccp.MsgTypeUDT {
+		pcls := pb.ProtocolClass
+		return sccp.NewUDT(int(pcls.GetClass()), pcls.GetReturnOnError(), cdpa, cgpa, pb.GetData()), nil
+	}
+	if t == sccp.MsgTypeUDTS {
+		return sccp.NewUDTS(params.ReturnCauseValue(pb.GetReturnCause()), cdpa, cgpa, pb.GetData()), nil
+	}
+
+	// the Data is mandatory in XUDT and XUDTS, which is not in opts.
+	opts, err := optionals(pb, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	opts = slices.DeleteFunc(opts, func(p params.Parameter) bool {
+		return p.Code() == params.PCodeData
+	})
+
+	hc := uint8(pb.GetHopCounter())
+	if t == sccp.MsgTypeXUDT {
+		pcls := pb.ProtocolClass
+		return sccp.NewXUDT(int(pcls.GetClass()), pcls.GetReturnOnError(), hc, cdpa, cgpa, pb.GetData(), opts...), nil
+	}
+	return sccp.NewXUDTS(params.ReturnCauseValue(pb.GetReturnCause()), hc, cdpa, cgpa, pb.GetData(), opts...), nil
+}
+
+// checkMandatory checks if the mandatory parameters of the message type t are
+// set in pb.
+func checkMandatory(t sccp.MsgType, pb *Message) error {
+	var (
+		dlr      = pb.DestinationLocalReference != nil
+		slr      = pb.SourceLocalReference != nil
+		pcls     = pb.ProtocolClass != nil
+		cdpa     = pb.CalledPartyAddress != nil
+		cgpa     = pb.CallingPartyAddress != nil
+		data     = pb.Data != nil
+		hc       = pb.HopCounter != nil
+		retCause = pb.ReturnCause != nil
+		seg      = pb.SequencingSegmenting != nil
+		credit   = pb.Credit != nil
+	)
+
+	var ok bool
+	switch t {
+	case sccp.MsgTypeCR:
+		ok = slr && pcls && cdpa
+	case sccp.MsgTypeCC:
+		ok = dlr && slr && pcls
+	case sccp.MsgTypeCREF:
+		ok = dlr && pb.RefusalCause != nil
+	case sccp.MsgTypeRLSD:
+		ok = dlr && slr && pb.ReleaseCause != nil
+	case sccp.MsgTypeRLC, sccp.MsgTypeRSC:
+		ok = dlr && slr
+	case sccp.MsgTypeDT1:
+		ok = dlr && pb.SegmentingReassembling != nil && data
+	case sccp.MsgTypeDT2:
+		ok = dlr && seg && data
+	case sccp.MsgTypeAK:
+		ok = dlr && pb.ReceiveSequenceNumber != nil && credit
+	case sccp.MsgTypeUDT:
+		ok = pcls && cdpa && cgpa && data
+	case sccp.MsgTypeUDTS:
+		ok = retCause && cdpa && cgpa && data
+	case sccp.MsgTypeED:
+		ok = dlr && data
+	case sccp.MsgTypeEA:
+		ok = dlr
+	case sccp.MsgTypeRSR:
+		ok = dlr && slr && pb.ResetCause != nil
+	case sccp.MsgTypeIT:
+		ok = dlr && slr && pcls && seg && credit
+	case sccp.MsgTypeXUDT:
+		ok = pcls && hc && cdpa && cgpa && data
+	case sccp.MsgTypeXUDTS:
+		ok = retCause && hc && cdpa && cgpa && data
+	default:
+		ok = true
+	}
+
+	if !ok {
+		return fmt.Errorf("%s: %w", t, sccp.ErrMissingParameter)
+	}
+	return nil
+}
+
+// optionals returns the optional parameters in pb, which are passed to the
+// constructors of the messages, with the optional PartyAddress of the code
+// if addr is not nil.
+//
+// The Hop Counter is not included, as it is mandatory in XUDT and XUDTS.
+func optionals(pb *Message, addr *PartyAddress, code params.ParameterNameCode) ([]params.Parameter, error) {
+	var opts []params.Parameter
+	if pb.Credit != nil {
+		opts = append(opts, params.NewCreditOptional(uint8(*pb.Credit)))
+	}
+	if addr != nil {
+		p, err := ToPartyAddress(addr, code, true)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, p)
+	}
+	if pb.Data != nil {
+		opts = append(opts, params.NewDataOptional(pb.Data))
+	}
+	if s := pb.Segmentation; s != nil {
+		opts = append(opts, params.NewSegmentationOptional(
+			s.FirstSegment, uint8(s.Class), uint8(s.RemainingSegments), s.LocalReference,
+		))
+	}
+	if pb.Importance != nil {
+		opts = append(opts, params.NewImportanceOptional(uint8(*pb.Importance)))
+	}
+	if i := pb.Isni; i != nil {
+		var networks []params.ISNINetwork
+		for _, n := range i.Networks {
+			networks = append(networks, params.ISNINetwork{Network: uint8(n.Network), Cluster: uint8(n.Cluster)})
+		}
+
+		iri := params.ISNIRoutingIndicator(i.RoutingIndicator)
+		if i.NetworkSpecific != nil {
+			opts = append(opts, params.NewISNIType1(
+				i.MarkIdentification, iri, uint8(i.Counter), uint8(*i.NetworkSpecific), networks...,
+			))
+		} else {
+			opts = append(opts, params.NewISNI(i.MarkIdentification, iri, uint8(i.Counter), networks...))
+		}
+	}
+	for _, p := range pb.UnknownParameters {
+		opts = append(opts, params.NewUnknownParameter(params.ParameterNameCode(p.Code), p.Value))
+	}
+
+	return opts, nil
+}
+
+// FromPartyAddress converts the PartyAddress into the protobuf message, or
+// returns nil if p is nil.
+func FromPartyAddress(p *params.PartyAddress) *PartyAddress {
+	if p == nil {
+		return nil
+	}
+
+	pb := &PartyAddress{Indicator: uint32(p.Indicator)}
+	if v := p.Variant(); v != params.VariantITU {
+		pb.Variant = v.String()
+	}
+	if p.HasPC() {
+		pc := uint32(p.SignalingPointCode)
+		pb.PointCode = &pc
+	}
+	if p.HasSSN() {
+		ssn := uint32(p.SubsystemNumber)
+		pb.Ssn = &ssn
+	}
+	if p.GlobalTitle != nil {
+		pb.GlobalTitle = fromGlobalTitle(p.GlobalTitle)
+	}
+
+	return pb
+}
+
+// ToPartyAddress converts the protobuf message into the PartyAddress with the
+// code, which is either PCodeCalledPartyAddress or PCodeCallingPartyAddress.
+//
+// The PartyAddress is in the format of the optional parameter if optional
+// is true, which depends on the message type that carries it.
+func ToPartyAddress(pb *PartyAddress, code params.ParameterNameCode, optional bool) (*params.PartyAddress, error) {
+	variant := params.VariantITU
+	if pb.Variant != "" {
+		var err error
+		if variant, err = params.ParseVariant(pb.Variant); err != nil {
+			return nil, err
+		}
+	}
+
+	var gt params.GlobalTitle
+	if g := pb.GlobalTitle; g != nil {
+		nai := params.NatureOfAddressIndicator(g.NatureOfAddressIndicator)
+		if g.OddDigits && params.GlobalTitleIndicator(g.Gti) == params.GTINAIOnly {
+			nai = nai.Odd()
+		}
+		gt = params.NewGlobalTitle(
+			params.GlobalTitleIndicator(g.Gti),
+			params.TranslationType(g.TranslationType),
+			params.NumberingPlan(g.NumberingPlan),
+			params.EncodingScheme(g.EncodingScheme),
+			nai,
+			g.AddressInformation,
+		)
+	}
+
+	ai := uint8(pb.Indicator)
+	pc, ssn := params.PointCode(pb.GetPointCode()), uint8(pb.GetSsn())
+	if !optional {
+		return params.NewPartyAddressVariant(variant, code, ai, pc, ssn, gt), nil
+	}
+
+	p := params.NewPartyAddressOptional(code, ai, pc, ssn, gt)
+	p.SetVariant(variant)
+	return p, nil
+}
+
+func fromGlobalTitle(g params.GlobalTitle) *GlobalTitle {
+	pb := &GlobalTitle{
+		Gti:                uint32(g.GTI()),
+		OddDigits:          g.IsOddDigits(),
+		AddressInformation: g.AddressInfo(),
+		Digits:             g.Address(),
+	}
+	switch g := g.(type) {
+	case *params.GTNAIOnly:
+		pb.NatureOfAddressIndicator = uint32(g.NatureOfAddressIndicator.Even())
+	case *params.GTTTOnly:
+		pb.TranslationType = uint32(g.TranslationType)
+	case *params.GTTTNPES:
+		pb.TranslationType = uint32(g.TranslationType)
+		pb.NumberingPlan = uint32(g.NumberingPlan)
+		pb.EncodingScheme = uint32(g.EncodingScheme)
+	case *params.GTTTNPESNAI:
+		pb.TranslationType = uint32(g.TranslationType)
+		pb.NumberingPlan = uint32(g.NumberingPlan)
+		pb.EncodingScheme = uint32(g.EncodingScheme)
+		pb.NatureOfAddressIndicator = uint32(g.NatureOfAddressIndicator)
+	}
+
+	return pb
+}
+
+func localReference(l *params.LocalReference) *uint32 {
+	if l == nil {
+		return nil
+	}
+
+	v := l.Uint32()
+	return &v
+}
+
+func protocolClass(p *params.ProtocolClass) *ProtocolClass {
+	if p == nil {
+		return nil
+	}
+
+	return &ProtocolClass{Class: uint32(p.Class()), ReturnOnError: p.ReturnOnError()}
+}
+
+func cause[T ~uint8](c *params.Cause[T]) *uint32 {
+	if c == nil {
+		return nil
+	}
+
+	v := uint32(c.Value())
+	return &v
+}
+
+func uint8Value[T any, P interface {
+	*T
+	Value() uint8
+}](p P) *uint32 {
+	if p == nil {
+		return nil
+	}
+
+	v := uint32(p.Value())
+	return &v
+}
+
+func data(d *params.Data) []byte {
+	if d == nil {
+		return nil
+	}
+
+	// the Data that is present is distinguished from the absent one by
+	// being non-nil.
+	return append([]byte{}, d.Value()...)
+}
+
+func sequencingSegmenting(s *params.SequencingSegmenting) *SequencingSegmenting {
+	if s == nil {
+		return nil
+	}
+
+	return &SequencingSegmenting{Ps: uint32(s.PS()), Pr: uint32(s.PR()), MoreData: s.More()}
+}
+
+func segmentation(s *params.Segmentation) *Segmentation {
+	if s == nil {
+		return nil
+	}
+
+	return &Segmentation{
+		FirstSegment:      s.FirstSegment,
+		Class:             uint32(s.Class),
+		RemainingSegments: uint32(s.RemainingSegments),
+		LocalReference:    s.LocalReference,
+	}
+}
+
+func isni(i *params.ISNI) *ISNI {
+	if i == nil {
+		return nil
+	}
+
+	pb := &ISNI{
+		MarkIdentification: i.MarkIdentification,
+		RoutingIndicator:   uint32(i.RoutingIndicator),
+		Counter:            uint32(i.Counter),
+	}
+	if i.TypeIndicator {
+		ns := uint32(i.NetworkSpecific)
+		pb.NetworkSpecific = &ns
+	}
+	for _, n := range i.Networks {
+		pb.Networks = append(pb.Networks, &ISNI_Network{Network: uint32(n.Network), Cluster: uint32(n.Cluster)})
+	}
+
+	return pb
+}
+
+func unknownParameters(ps []*params.UnknownParameter) []*UnknownParameter {
+	var pb []*UnknownParameter
+	for _, p := range ps {
+		pb = append(pb, &UnknownParameter{Code: uint32(p.Code()), Value: p.Value()})
+	}
+
+	return pb
+}
@@ -0,0 +1,1170 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: sccp.proto
+
+package sccppb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MessageType is the Message Type defined in Q.713. The values that are not
+// defined here are used as they are for the message types not implemented.
+type MessageType int32
+
+const (
+	MessageType_MESSAGE_TYPE_UNSPECIFIED MessageType = 0
+	MessageType_MESSAGE_TYPE_CR          MessageType = 1
+	MessageType_MESSAGE_TYPE_CC          MessageType = 2
+	MessageType_MESSAGE_TYPE_CREF        MessageType = 3
+	MessageType_MESSAGE_TYPE_RLSD        MessageType = 4
+	MessageType_MESSAGE_TYPE_RLC         MessageType = 5
+	MessageType_MESSAGE_TYPE_DT1         MessageType = 6
+	MessageType_MESSAGE_TYPE_DT2         MessageType = 7
+	MessageType_MESSAGE_TYPE_AK          MessageType = 8
+	MessageType_MESSAGE_TYPE_UDT         MessageType = 9
+	MessageType_MESSAGE_TYPE_UDTS        MessageType = 10
+	MessageType_MESSAGE_TYPE_ED          MessageType = 11
+	MessageType_MESSAGE_TYPE_EA          MessageType = 12
+	MessageType_MESSAGE_TYPE_RSR         MessageType = 13
+	MessageType_MESSAGE_TYPE_RSC         MessageType = 14
+	MessageType_MESSAGE_TYPE_ERR         MessageType = 15
+	MessageType_MESSAGE_TYPE_IT          MessageType = 16
+	MessageType_MESSAGE_TYPE_XUDT        MessageType = 17
+	MessageType_MESSAGE_TYPE_XUDTS       MessageType = 18
+	MessageType_MESSAGE_TYPE_LUDT        MessageType = 19
+	MessageType_MESSAGE_TYPE_LUDTS       MessageType = 20
+)
+
+// Enum value maps for MessageType.
+var (
+	MessageType_name = map[int32]string{
+		0:  "MESSAGE_TYPE_UNSPECIFIED",
+		1:  "MESSAGE_TYPE_CR",
+		2:  "MESSAGE_TYPE_CC",
+		3:  "MESSAGE_TYPE_CREF",
+		4:  "MESSAGE_TYPE_RLSD",
+		5:  "MESSAGE_TYPE_RLC",
+		6:  "MESSAGE_TYPE_DT1",
+		7:  "MESSAGE_TYPE_DT2",
+		8:  "MESSAGE_TYPE_AK",
+		9:  "MESSAGE_TYPE_UDT",
+		10: "MESSAGE_TYPE_UDTS",
+		11: "MESSAGE_TYPE_ED",
+		12: "MESSAGE_TYPE_EA",
+		13: "MESSAGE_TYPE_RSR",
+		14: "MESSAGE_TYPE_RSC",
+		15: "MESSAGE_TYPE_ERR",
+		16: "MESSAGE_TYPE_IT",
+		17: "MESSAGE_TYPE_XUDT",
+		18: "MESSAGE_TYPE_XUDTS",
+		19: "MESSAGE_TYPE_LUDT",
+		20: "MESSAGE_TYPE_LUDTS",
+	}
+	MessageType_value = map[string]int32{
+		"MESSAGE_TYPE_UNSPECIFIED": 0,
+		"MESSAGE_TYPE_CR":          1,
+		"MESSAGE_TYPE_CC":          2,
+		"MESSAGE_TYPE_CREF":        3,
+		"MESSAGE_TYPE_RLSD":        4,
+		"MESSAGE_TYPE_RLC":         5,
+		"MESSAGE_TYPE_DT1":         6,
+		"MESSAGE_TYPE_DT2":         7,
+		"MESSAGE_TYPE_AK":          8,
+		"MESSAGE_TYPE_UDT":         9,
+		"MESSAGE_TYPE_UDTS":        10,
+		"MESSAGE_TYPE_ED":          11,
+		"MESSAGE_TYPE_EA":          12,
+		"MESSAGE_TYPE_RSR":         13,
+		"MESSAGE_TYPE_RSC":         14,
+		"MESSAGE_TYPE_ERR":         15,
+		"MESSAGE_TYPE_IT":          16,
+		"MESSAGE_TYPE_XUDT":        17,
+		"MESSAGE_TYPE_XUDTS":       18,
+		"MESSAGE_TYPE_LUDT":        19,
+		"MESSAGE_TYPE_LUDTS":       20,
+	}
+)
+
+func (x MessageType) Enum() *MessageType {
+	p := new(MessageType)
+	*p = x
+	return p
+}
+
+func (x MessageType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageType) Descriptor() protoreflect.EnumDescriptor {
+	return file_sccp_proto_enumTypes[0].Descriptor()
+}
+
+func (MessageType) Type() protoreflect.EnumType {
+	return &file_sccp_proto_enumTypes[0]
+}
+
+func (x MessageType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageType.Descriptor instead.
+func (MessageType) EnumDescriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{0}
+}
+
+// Message is a SCCP message of any type.
+//
+// The fields of the parameters that are not in the message, or are optional
+// and not present, are not set.
+type Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type                      MessageType    `protobuf:"varint,1,opt,name=type,proto3,enum=sccp.v1.MessageType" json:"type,omitempty"`
+	DestinationLocalReference *uint32        `protobuf:"varint,2,opt,name=destination_local_reference,json=destinationLocalReference,proto3,oneof" json:"destination_local_reference,omitempty"`
+	SourceLocalReference      *uint32        `protobuf:"varint,3,opt,name=source_local_reference,json=sourceLocalReference,proto3,oneof" json:"source_local_reference,omitempty"`
+	ProtocolClass             *ProtocolClass `protobuf:"bytes,4,opt,name=protocol_class,json=protocolClass,proto3" json:"protocol_class,omitempty"`
+	RefusalCause              *uint32        `protobuf:"varint,5,opt,name=refusal_cause,json=refusalCause,proto3,oneof" json:"refusal_cause,omitempty"`
+	ReleaseCause              *uint32        `protobuf:"varint,6,opt,name=release_cause,json=releaseCause,proto3,oneof" json:"release_cause,omitempty"`
+	ResetCause                *uint32        `protobuf:"varint,7,opt,name=reset_cause,json=resetCause,proto3,oneof" json:"reset_cause,omitempty"`
+	ReturnCause               *uint32        `protobuf:"varint,8,opt,name=return_cause,json=returnCause,proto3,oneof" json:"return_cause,omitempty"`
+	// more data indication in the Segmenting/Reassembling.
+	SegmentingReassembling *bool `protobuf:"varint,9,opt,name=segmenting_reassembling,json=segmentingReassembling,proto3,oneof" json:"segmenting_reassembling,omitempty"`
+	// P(R) in the Receive Sequence Number.
+	ReceiveSequenceNumber *uint32               `protobuf:"varint,10,opt,name=receive_sequence_number,json=receiveSequenceNumber,proto3,oneof" json:"receive_sequence_number,omitempty"`
+	SequencingSegmenting  *SequencingSegmenting `protobuf:"bytes,11,opt,name=sequencing_segmenting,json=sequencingSegmenting,proto3" json:"sequencing_segmenting,omitempty"`
+	Credit                *uint32               `protobuf:"varint,12,opt,name=credit,proto3,oneof" json:"credit,omitempty"`
+	HopCounter            *uint32               `protobuf:"varint,13,opt,name=hop_counter,json=hopCounter,proto3,oneof" json:"hop_counter,omitempty"`
+	CalledPartyAddress    *PartyAddress         `protobuf:"bytes,14,opt,name=called_party_address,json=calledPartyAddress,proto3" json:"called_party_address,omitempty"`
+	CallingPartyAddress   *PartyAddress         `protobuf:"bytes,15,opt,name=calling_party_address,json=callingPartyAddress,proto3" json:"calling_party_address,omitempty"`
+	Data                  []byte                `protobuf:"bytes,16,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	Segmentation          *Segmentation         `protobuf:"bytes,17,opt,name=segmentation,proto3" json:"segmentation,omitempty"`
+	Importance            *uint32               `protobuf:"varint,18,opt,name=importance,proto3,oneof" json:"importance,omitempty"`
+	Isni                  *ISNI                 `protobuf:"bytes,19,opt,name=isni,proto3" json:"isni,omitempty"`
+	UnknownParameters     []*UnknownParameter   `protobuf:"bytes,20,rep,name=unknown_parameters,json=unknownParameters,proto3" json:"unknown_parameters,omitempty"`
+	// octets that follow the Message Type of the types not implemented.
+	Payload []byte `protobuf:"bytes,21,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_sccp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message) GetType() MessageType {
+	if x != nil {
+		return x.Type
+	}
+	return MessageType_MESSAGE_TYPE_UNSPECIFIED
+}
+
+func (x *Message) GetDestinationLocalReference() uint32 {
+	if x != nil && x.DestinationLocalReference != nil {
+		return *x.DestinationLocalReference
+	}
+	return 0
+}
+
+func (x *Message) GetSourceLocalReference() uint32 {
+	if x != nil && x.SourceLocalReference != nil {
+		return *x.SourceLocalReference
+	}
+	return 0
+}
+
+func (x *Message) GetProtocolClass() *ProtocolClass {
+	if x != nil {
+		return x.ProtocolClass
+	}
+	return nil
+}
+
+func (x *Message) GetRefusalCause() uint32 {
+	if x != nil && x.RefusalCause != nil {
+		return *x.RefusalCause
+	}
+	return 0
+}
+
+func (x *Message) GetReleaseCause() uint32 {
+	if x != nil && x.ReleaseCause != nil {
+		return *x.ReleaseCause
+	}
+	return 0
+}
+
+func (x *Message) GetResetCause() uint32 {
+	if x != nil && x.ResetCause != nil {
+		return *x.ResetCause
+	}
+	return 0
+}
+
+func (x *Message) GetReturnCause() uint32 {
+	if x != nil && x.ReturnCause != nil {
+		return *x.ReturnCause
+	}
+	return 0
+}
+
+func (x *Message) GetSegmentingReassembling() bool {
+	if x != nil && x.SegmentingReassembling != nil {
+		return *x.SegmentingReassembling
+	}
+	return false
+}
+
+func (x *Message) GetReceiveSequenceNumber() uint32 {
+	if x != nil && x.ReceiveSequenceNumber != nil {
+		return *x.ReceiveSequenceNumber
+	}
+	return 0
+}
+
+func (x *Message) GetSequencingSegmenting() *SequencingSegmenting {
+	if x != nil {
+		return x.SequencingSegmenting
+	}
+	return nil
+}
+
+func (x *Message) GetCredit() uint32 {
+	if x != nil && x.Credit != nil {
+		return *x.Credit
+	}
+	return 0
+}
+
+func (x *Message) GetHopCounter() uint32 {
+	if x != nil && x.HopCounter != nil {
+		return *x.HopCounter
+	}
+	return 0
+}
+
+func (x *Message) GetCalledPartyAddress() *PartyAddress {
+	if x != nil {
+		return x.CalledPartyAddress
+	}
+	return nil
+}
+
+func (x *Message) GetCallingPartyAddress() *PartyAddress {
+	if x != nil {
+		return x.CallingPartyAddress
+	}
+	return nil
+}
+
+func (x *Message) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Message) GetSegmentation() *Segmentation {
+	if x != nil {
+		return x.Segmentation
+	}
+	return nil
+}
+
+func (x *Message) GetImportance() uint32 {
+	if x != nil && x.Importance != nil {
+		return *x.Importance
+	}
+	return 0
+}
+
+func (x *Message) GetIsni() *ISNI {
+	if x != nil {
+		return x.Isni
+	}
+	return nil
+}
+
+func (x *Message) GetUnknownParameters() []*UnknownParameter {
+	if x != nil {
+		return x.UnknownParameters
+	}
+	return nil
+}
+
+func (x *Message) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type ProtocolClass struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Class         uint32 `protobuf:"varint,1,opt,name=class,proto3" json:"class,omitempty"`
+	ReturnOnError bool   `protobuf:"varint,2,opt,name=return_on_error,json=returnOnError,proto3" json:"return_on_error,omitempty"`
+}
+
+func (x *ProtocolClass) Reset() {
+	*x = ProtocolClass{}
+	mi := &file_sccp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtocolClass) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtocolClass) ProtoMessage() {}
+
+func (x *ProtocolClass) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtocolClass.ProtoReflect.Descriptor instead.
+func (*ProtocolClass) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProtocolClass) GetClass() uint32 {
+	if x != nil {
+		return x.Class
+	}
+	return 0
+}
+
+func (x *ProtocolClass) GetReturnOnError() bool {
+	if x != nil {
+		return x.ReturnOnError
+	}
+	return false
+}
+
+// SequencingSegmenting has P(S) and P(R), the sequence numbers without the
+// spare bit.
+type SequencingSegmenting struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ps       uint32 `protobuf:"varint,1,opt,name=ps,proto3" json:"ps,omitempty"`
+	Pr       uint32 `protobuf:"varint,2,opt,name=pr,proto3" json:"pr,omitempty"`
+	MoreData bool   `protobuf:"varint,3,opt,name=more_data,json=moreData,proto3" json:"more_data,omitempty"`
+}
+
+func (x *SequencingSegmenting) Reset() {
+	*x = SequencingSegmenting{}
+	mi := &file_sccp_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SequencingSegmenting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SequencingSegmenting) ProtoMessage() {}
+
+func (x *SequencingSegmenting) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SequencingSegmenting.ProtoReflect.Descriptor instead.
+func (*SequencingSegmenting) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SequencingSegmenting) GetPs() uint32 {
+	if x != nil {
+		return x.Ps
+	}
+	return 0
+}
+
+func (x *SequencingSegmenting) GetPr() uint32 {
+	if x != nil {
+		return x.Pr
+	}
+	return 0
+}
+
+func (x *SequencingSegmenting) GetMoreData() bool {
+	if x != nil {
+		return x.MoreData
+	}
+	return false
+}
+
+// PartyAddress is the Called or Calling Party Address.
+type PartyAddress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// "ITU", "ANSI", "China" or "TTC". ITU if empty.
+	Variant string `protobuf:"bytes,1,opt,name=variant,proto3" json:"variant,omitempty"`
+	// Address Indicator in the format of the variant.
+	Indicator   uint32       `protobuf:"varint,2,opt,name=indicator,proto3" json:"indicator,omitempty"`
+	PointCode   *uint32      `protobuf:"varint,3,opt,name=point_code,json=pointCode,proto3,oneof" json:"point_code,omitempty"`
+	Ssn         *uint32      `protobuf:"varint,4,opt,name=ssn,proto3,oneof" json:"ssn,omitempty"`
+	GlobalTitle *GlobalTitle `protobuf:"bytes,5,opt,name=global_title,json=globalTitle,proto3" json:"global_title,omitempty"`
+}
+
+func (x *PartyAddress) Reset() {
+	*x = PartyAddress{}
+	mi := &file_sccp_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyAddress) ProtoMessage() {}
+
+func (x *PartyAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyAddress.ProtoReflect.Descriptor instead.
+func (*PartyAddress) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PartyAddress) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *PartyAddress) GetIndicator() uint32 {
+	if x != nil {
+		return x.Indicator
+	}
+	return 0
+}
+
+func (x *PartyAddress) GetPointCode() uint32 {
+	if x != nil && x.PointCode != nil {
+		return *x.PointCode
+	}
+	return 0
+}
+
+func (x *PartyAddress) GetSsn() uint32 {
+	if x != nil && x.Ssn != nil {
+		return *x.Ssn
+	}
+	return 0
+}
+
+func (x *PartyAddress) GetGlobalTitle() *GlobalTitle {
+	if x != nil {
+		return x.GlobalTitle
+	}
+	return nil
+}
+
+type GlobalTitle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Gti                      uint32 `protobuf:"varint,1,opt,name=gti,proto3" json:"gti,omitempty"`
+	TranslationType          uint32 `protobuf:"varint,2,opt,name=translation_type,json=translationType,proto3" json:"translation_type,omitempty"`
+	NumberingPlan            uint32 `protobuf:"varint,3,opt,name=numbering_plan,json=numberingPlan,proto3" json:"numbering_plan,omitempty"`
+	EncodingScheme           uint32 `protobuf:"varint,4,opt,name=encoding_scheme,json=encodingScheme,proto3" json:"encoding_scheme,omitempty"`
+	NatureOfAddressIndicator uint32 `protobuf:"varint,5,opt,name=nature_of_address_indicator,json=natureOfAddressIndicator,proto3" json:"nature_of_address_indicator,omitempty"`
+	OddDigits                bool   `protobuf:"varint,6,opt,name=odd_digits,json=oddDigits,proto3" json:"odd_digits,omitempty"`
+	// address information as encoded.
+	AddressInformation []byte `protobuf:"bytes,7,opt,name=address_information,json=addressInformation,proto3" json:"address_information,omitempty"`
+	// address information in readable form, which is ignored on conversion
+	// from the protobuf message.
+	Digits string `protobuf:"bytes,8,opt,name=digits,proto3" json:"digits,omitempty"`
+}
+
+func (x *GlobalTitle) Reset() {
+	*x = GlobalTitle{}
+	mi := &file_sccp_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GlobalTitle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GlobalTitle) ProtoMessage() {}
+
+func (x *GlobalTitle) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GlobalTitle.ProtoReflect.Descriptor instead.
+func (*GlobalTitle) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GlobalTitle) GetGti() uint32 {
+	if x != nil {
+		return x.Gti
+	}
+	return 0
+}
+
+func (x *GlobalTitle) GetTranslationType() uint32 {
+	if x != nil {
+		return x.TranslationType
+	}
+	return 0
+}
+
+func (x *GlobalTitle) GetNumberingPlan() uint32 {
+	if x != nil {
+		return x.NumberingPlan
+	}
+	return 0
+}
+
+func (x *GlobalTitle) GetEncodingScheme() uint32 {
+	if x != nil {
+		return x.EncodingScheme
+	}
+	return 0
+}
+
+func (x *GlobalTitle) GetNatureOfAddressIndicator() uint32 {
+	if x != nil {
+		return x.NatureOfAddressIndicator
+	}
+	return 0
+}
+
+func (x *GlobalTitle) GetOddDigits() bool {
+	if x != nil {
+		return x.OddDigits
+	}
+	return false
+}
+
+func (x *GlobalTitle) GetAddressInformation() []byte {
+	if x != nil {
+		return x.AddressInformation
+	}
+	return nil
+}
+
+func (x *GlobalTitle) GetDigits() string {
+	if x != nil {
+		return x.Digits
+	}
+	return ""
+}
+
+type Segmentation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FirstSegment      bool   `protobuf:"varint,1,opt,name=first_segment,json=firstSegment,proto3" json:"first_segment,omitempty"`
+	Class             uint32 `protobuf:"varint,2,opt,name=class,proto3" json:"class,omitempty"`
+	RemainingSegments uint32 `protobuf:"varint,3,opt,name=remaining_segments,json=remainingSegments,proto3" json:"remaining_segments,omitempty"`
+	LocalReference    uint32 `protobuf:"varint,4,opt,name=local_reference,json=localReference,proto3" json:"local_reference,omitempty"`
+}
+
+func (x *Segmentation) Reset() {
+	*x = Segmentation{}
+	mi := &file_sccp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Segmentation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Segmentation) ProtoMessage() {}
+
+func (x *Segmentation) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Segmentation.ProtoReflect.Descriptor instead.
+func (*Segmentation) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Segmentation) GetFirstSegment() bool {
+	if x != nil {
+		return x.FirstSegment
+	}
+	return false
+}
+
+func (x *Segmentation) GetClass() uint32 {
+	if x != nil {
+		return x.Class
+	}
+	return 0
+}
+
+func (x *Segmentation) GetRemainingSegments() uint32 {
+	if x != nil {
+		return x.RemainingSegments
+	}
+	return 0
+}
+
+func (x *Segmentation) GetLocalReference() uint32 {
+	if x != nil {
+		return x.LocalReference
+	}
+	return 0
+}
+
+// ISNI is the Intermediate Signaling Network Identification in ANSI.
+type ISNI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MarkIdentification bool   `protobuf:"varint,1,opt,name=mark_identification,json=markIdentification,proto3" json:"mark_identification,omitempty"`
+	RoutingIndicator   uint32 `protobuf:"varint,2,opt,name=routing_indicator,json=routingIndicator,proto3" json:"routing_indicator,omitempty"`
+	Counter            uint32 `protobuf:"varint,3,opt,name=counter,proto3" json:"counter,omitempty"`
+	// present only in type 1.
+	NetworkSpecific *uint32         `protobuf:"varint,4,opt,name=network_specific,json=networkSpecific,proto3,oneof" json:"network_specific,omitempty"`
+	Networks        []*ISNI_Network `protobuf:"bytes,5,rep,name=networks,proto3" json:"networks,omitempty"`
+}
+
+func (x *ISNI) Reset() {
+	*x = ISNI{}
+	mi := &file_sccp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ISNI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ISNI) ProtoMessage() {}
+
+func (x *ISNI) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ISNI.ProtoReflect.Descriptor instead.
+func (*ISNI) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ISNI) GetMarkIdentification() bool {
+	if x != nil {
+		return x.MarkIdentification
+	}
+	return false
+}
+
+func (x *ISNI) GetRoutingIndicator() uint32 {
+	if x != nil {
+		return x.RoutingIndicator
+	}
+	return 0
+}
+
+func (x *ISNI) GetCounter() uint32 {
+	if x != nil {
+		return x.Counter
+	}
+	return 0
+}
+
+func (x *ISNI) GetNetworkSpecific() uint32 {
+	if x != nil && x.NetworkSpecific != nil {
+		return *x.NetworkSpecific
+	}
+	return 0
+}
+
+func (x *ISNI) GetNetworks() []*ISNI_Network {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+// UnknownParameter is the optional parameter unknown to go-sccp.
+type UnknownParameter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code  uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *UnknownParameter) Reset() {
+	*x = UnknownParameter{}
+	mi := &file_sccp_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnknownParameter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnknownParameter) ProtoMessage() {}
+
+func (x *UnknownParameter) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnknownParameter.ProtoReflect.Descriptor instead.
+func (*UnknownParameter) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UnknownParameter) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *UnknownParameter) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type ISNI_Network struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Network uint32 `protobuf:"varint,1,opt,name=network,proto3" json:"network,omitempty"`
+	Cluster uint32 `protobuf:"varint,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+}
+
+func (x *ISNI_Network) Reset() {
+	*x = ISNI_Network{}
+	mi := &file_sccp_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ISNI_Network) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ISNI_Network) ProtoMessage() {}
+
+func (x *ISNI_Network) ProtoReflect() protoreflect.Message {
+	mi := &file_sccp_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ISNI_Network.ProtoReflect.Descriptor instead.
+func (*ISNI_Network) Descriptor() ([]byte, []int) {
+	return file_sccp_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (x *ISNI_Network) GetNetwork() uint32 {
+	if x != nil {
+		return x.Network
+	}
+	return 0
+}
+
+func (x *ISNI_Network) GetCluster() uint32 {
+	if x != nil {
+		return x.Cluster
+	}
+	return 0
+}
+
+var File_sccp_proto protoreflect.FileDescriptor
+
+var file_sccp_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x73, 0x63, 0x63, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x73, 0x63,
+	0x63, 0x70, 0x2e, 0x76, 0x31, 0x22, 0xa5, 0x0a, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x28, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x14, 0x2e, 0x73, 0x63, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x43, 0x0a, 0x1b, 0x64,
+	0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x00, 0x52, 0x19, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c,
+	0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x39, 0x0a, 0x16, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x01, 0x52, 0x14, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x0e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x63, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x52, 0x0d, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x28, 0x0a, 0x0d, 0x72, 0x65,
+	0x66, 0x75, 0x73, 0x61, 0x6c, 0x5f, 0x63, 0x61, 0x75, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x02, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x73, 0x61, 0x6c, 0x43, 0x61, 0x75, 0x73,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x5f,
+	0x63, 0x61, 0x75, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x0c, 0x72,
+	0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x43, 0x61, 0x75, 0x73, 0x65, 0x88, 0x01, 0x01, 0x12, 0x24,
+	0x0a, 0x0b, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x63, 0x61, 0x75, 0x73, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x65, 0x74, 0x43, 0x61, 0x75, 0x73,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x63,
+	0x61, 0x75, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x05, 0x52, 0x0b, 0x72, 0x65,
+	0x74, 0x75, 0x72, 0x6e, 0x43, 0x61, 0x75, 0x73, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3c, 0x0a, 0x17,
+	0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x73,
+	0x65, 0x6d, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x06, 0x52,
+	0x16, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x61, 0x73, 0x73,
+	0x65, 0x6d, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x17, 0x72, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x07, 0x52, 0x15, 0x72,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x52, 0x0a, 0x15, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x63, 0x63, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x14, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x69, 0x6e,
+	0x67, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1b, 0x0a, 0x06, 0x63,
+	0x72, 0x65, 0x64, 0x69, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x08, 0x52, 0x06, 0x63,
+	0x72, 0x65, 0x64, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x68, 0x6f, 0x70, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x09, 0x52,
+	0x0a, 0x68, 0x6f, 0x70, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x47,
+	0x0a, 0x14, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x5f, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73,
+	0x63, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x79, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x52, 0x12, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x72, 0x74, 0x79,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x49, 0x0a, 0x15, 0x63, 0x61, 0x6c, 0x6c, 0x69,
+	0x6e, 0x67, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x63, 0x63, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x72, 0x74, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x13, 0x63,
+	0x61, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x72, 0x74, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x17, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0c,
+	0x48, 0x0a, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x12, 0x39, 0x0a, 0x0c, 0x73,
+	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x73, 0x63, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0a, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x0b, 0x52, 0x0a, 0x69, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x04, 0x69,
+	0x73, 0x6e, 0x69, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x63, 0x63, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x53, 0x4e, 0x49, 0x52, 0x04, 0x69, 0x73, 0x6e, 0x69, 0x12, 0x48,
+	0x0a, 0x12, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65,
+	0x74, 0x65, 0x72, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x63, 0x63,
+	0x70, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x52, 0x11, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x42, 0x1e, 0x0a, 0x1c, 0x5f, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x72, 0x65, 0x66, 0x75, 0x73, 0x61, 0x6c, 0x5f, 0x63, 0x61, 0x75, 0x73, 0x65, 0x42,
+	0x10, 0x0a, 0x0e, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x5f, 0x63, 0x61, 0x75, 0x73,
+	0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x63, 0x61, 0x75, 0x73,
+	0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x63, 0x61, 0x75,
+	0x73, 0x65, 0x42, 0x1a, 0x0a, 0x18, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6e,
+	0x67, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x73, 0x65, 0x6d, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x42, 0x1a,
+	0x0a, 0x18, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x63,
+	0x72, 0x65, 0x64, 0x69, 0x74, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x65, 0x72, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x42, 0x0d,
+	0x0a, 0x0b, 0x5f, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x4d, 0x0a,
+	0x0d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x6f,
+	0x6e, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x72,
+	0x65, 0x74, 0x75, 0x72, 0x6e, 0x4f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x53, 0x0a, 0x14,
+	0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x69, 0x6e, 0x67, 0x12, 0x0e, 0x0a, 0x02, 0x70, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x02, 0x70, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x70, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x02, 0x70, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x6f, 0x72, 0x65, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6d, 0x6f, 0x72, 0x65, 0x44, 0x61, 0x74,
+	0x61, 0x22, 0xd1, 0x01, 0x0a, 0x0c, 0x50, 0x61, 0x72, 0x74, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x09, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0a, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00,
+	0x52, 0x09, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15,
+	0x0a, 0x03, 0x73, 0x73, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x03, 0x73,
+	0x73, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x0c, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x5f,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x63,
+	0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x54, 0x69, 0x74, 0x6c,
+	0x65, 0x52, 0x0b, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x42, 0x0d,
+	0x0a, 0x0b, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x42, 0x06, 0x0a,
+	0x04, 0x5f, 0x73, 0x73, 0x6e, 0x22, 0xc1, 0x02, 0x0a, 0x0b, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c,
+	0x54, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x74, 0x69, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x03, 0x67, 0x74, 0x69, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x5f,
+	0x70, 0x6c, 0x61, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x6e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x69, 0x6e, 0x67, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x63,
+	0x6f, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x63, 0x68, 0x65,
+	0x6d, 0x65, 0x12, 0x3d, 0x0a, 0x1b, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6f, 0x66, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x18, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4f,
+	0x66, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f,
+	0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x64, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6f, 0x64, 0x64, 0x44, 0x69, 0x67, 0x69, 0x74, 0x73,
+	0x12, 0x2f, 0x0a, 0x13, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x22, 0xa1, 0x01, 0x0a, 0x0c, 0x53, 0x65,
+	0x67, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69,
+	0x6e, 0x67, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x11, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x72, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x22, 0xb5, 0x02,
+	0x0a, 0x04, 0x49, 0x53, 0x4e, 0x49, 0x12, 0x2f, 0x0a, 0x13, 0x6d, 0x61, 0x72, 0x6b, 0x5f, 0x69,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x12, 0x6d, 0x61, 0x72, 0x6b, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x6f, 0x75, 0x74, 0x69,
+	0x6e, 0x67, 0x5f, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x10, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x64, 0x69, 0x63,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x2e,
+	0x0a, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x69, 0x66,
+	0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0f, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69, 0x63, 0x88, 0x01, 0x01, 0x12, 0x31,
+	0x0a, 0x08, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x73, 0x63, 0x63, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x53, 0x4e, 0x49, 0x2e,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x08, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x73, 0x1a, 0x3d, 0x0a, 0x07, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x18, 0x0a, 0x07,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x42, 0x13, 0x0a, 0x11, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x70, 0x65,
+	0x63, 0x69, 0x66, 0x69, 0x63, 0x22, 0x3c, 0x0a, 0x10, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x2a, 0xe6, 0x03, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x43, 0x52, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47,
+	0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x43, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x4d,
+	0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x52, 0x45, 0x46,
+	0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x52, 0x4c, 0x53, 0x44, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x53,
+	0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x4c, 0x43, 0x10, 0x05, 0x12,
+	0x14, 0x0a, 0x10, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x44, 0x54, 0x31, 0x10, 0x06, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x54, 0x32, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x4d,
+	0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x4b, 0x10, 0x08,
+	0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x55, 0x44, 0x54, 0x10, 0x09, 0x12, 0x15, 0x0a, 0x11, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47,
+	0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x44, 0x54, 0x53, 0x10, 0x0a, 0x12, 0x13, 0x0a,
+	0x0f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x45, 0x44,
+	0x10, 0x0b, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x45, 0x41, 0x10, 0x0c, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x53, 0x53, 0x41,
+	0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x53, 0x52, 0x10, 0x0d, 0x12, 0x14, 0x0a,
+	0x10, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x53,
+	0x43, 0x10, 0x0e, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x45, 0x52, 0x52, 0x10, 0x0f, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x45, 0x53,
+	0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x49, 0x54, 0x10, 0x10, 0x12, 0x15,
+	0x0a, 0x11, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x58,
+	0x55, 0x44, 0x54, 0x10, 0x11, 0x12, 0x16, 0x0a, 0x12, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x58, 0x55, 0x44, 0x54, 0x53, 0x10, 0x12, 0x12, 0x15, 0x0a,
+	0x11, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4c, 0x55,
+	0x44, 0x54, 0x10, 0x13, 0x12, 0x16, 0x0a, 0x12, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x4c, 0x55, 0x44, 0x54, 0x53, 0x10, 0x14, 0x42, 0x21, 0x5a, 0x1f,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x6d, 0x6e, 0x73, 0x6b,
+	0x2f, 0x67, 0x6f, 0x2d, 0x73, 0x63, 0x63, 0x70, 0x2f, 0x73, 0x63, 0x63, 0x70, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_sccp_proto_rawDescOnce sync.Once
+	file_sccp_proto_rawDescData = file_sccp_proto_rawDesc
+)
+
+func file_sccp_proto_rawDescGZIP() []byte {
+	file_sccp_proto_rawDescOnce.Do(func() {
+		file_sccp_proto_rawDescData = protoimpl.X.CompressGZIP(file_sccp_proto_rawDescData)
+	})
+	return file_sccp_proto_rawDescData
+}
+
+var file_sccp_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_sccp_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_sccp_proto_goTypes = []any{
+	(MessageType)(0),             // 0: sccp.v1.MessageType
+	(*Message)(nil),              // 1: sccp.v1.Message
+	(*ProtocolClass)(nil),        // 2: sccp.v1.ProtocolClass
+	(*SequencingSegmenting)(nil), // 3: sccp.v1.SequencingSegmenting
+	(*PartyAddress)(nil),         // 4: sccp.v1.PartyAddress
+	(*GlobalTitle)(nil),          // 5: sccp.v1.GlobalTitle
+	(*Segmentation)(nil),         // 6: sccp.v1.Segmentation
+	(*ISNI)(nil),                 // 7: sccp.v1.ISNI
+	(*UnknownParameter)(nil),     // 8: sccp.v1.UnknownParameter
+	(*ISNI_Network)(nil),         // 9: sccp.v1.ISNI.Network
+}
+var file_sccp_proto_depIdxs = []int32{
+	0,  // 0: sccp.v1.Message.type:type_name -> sccp.v1.MessageType
+	2,  // 1: sccp.v1.Message.protocol_class:type_name -> sccp.v1.ProtocolClass
+	3,  // 2: sccp.v1.Message.sequencing_segmenting:type_name -> sccp.v1.SequencingSegmenting
+	4,  // 3: sccp.v1.Message.called_party_address:type_name -> sccp.v1.PartyAddress
+	4,  // 4: sccp.v1.Message.calling_party_address:type_name -> sccp.v1.PartyAddress
+	6,  // 5: sccp.v1.Message.segmentation:type_name -> sccp.v1.Segmentation
+	7,  // 6: sccp.v1.Message.isni:type_name -> sccp.v1.ISNI
+	8,  // 7: sccp.v1.Message.unknown_parameters:type_name -> sccp.v1.UnknownParameter
+	5,  // 8: sccp.v1.PartyAddress.global_title:type_name -> sccp.v1.GlobalTitle
+	9,  // 9: sccp.v1.ISNI.networks:type_name -> sccp.v1.ISNI.Network
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_sccp_proto_init() }
+func file_sccp_proto_init() {
+	if File_sccp_proto != nil {
+		return
+	}
+	file_sccp_proto_msgTypes[0].OneofWrappers = []any{}
+	file_sccp_proto_msgTypes[3].OneofWrappers = []any{}
+	file_sccp_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_sccp_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_sccp_proto_goTypes,
+		DependencyIndexes: file_sccp_proto_depIdxs,
+		EnumInfos:         file_sccp_proto_enumTypes,
+		MessageInfos:      file_sccp_proto_msgTypes,
+	}.Build()
+	File_sccp_proto = out.File
+	file_sccp_proto_rawDesc = nil
+	file_sccp_proto_goTypes = nil
+	file_sccp_proto_depIdxs = nil
+}
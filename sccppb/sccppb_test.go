@@ -0,0 +1,122 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccppb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/sccppb"
+)
+
+func TestConvert(t *testing.T) {
+	cdpa := params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, 6,
+		params.NewGlobalTitle(
+			params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.ESBCDOdd, params.NAIInternationalNumber,
+			[]byte{0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x65},
+		),
+	)
+	cgpa := params.NewCallingPartyAddress(params.NewAddressIndicator(true, true, true, params.GTINoGT), 0x1234, 7, nil)
+	ansi := params.NewPartyAddressVariant(
+		params.VariantANSI, params.PCodeCalledPartyAddress,
+		params.VariantANSI.NewAddressIndicator(true, true, true, params.GTINoGT), 0x010203, 8, nil,
+	)
+	optCgpa := params.NewCallingPartyAddressOptional(params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 7, nil)
+	optCdpa := params.NewCalledPartyAddressOptional(params.NewAddressIndicator(false, true, true, params.GTINoGT), 0, 6, nil)
+	data := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	cases := []struct {
+		description string
+		msg         sccp.Message
+	}{
+		{"UDT", sccp.NewUDT(1, true, cdpa, cgpa, data)},
+		{"UDTS", sccp.NewUDTS(params.ReturnCauseUnequippedUser, cdpa, cgpa, data)},
+		{"XUDT", sccp.NewXUDT(1, false, 15, ansi, cgpa, data,
+			params.NewSegmentationOptional(true, 1, 2, 0xabcdef),
+			params.NewImportanceOptional(3),
+			params.NewISNIType1(true, params.IRIConstrained, 1, 0x55, params.ISNINetwork{Network: 1, Cluster: 2}),
+			params.NewUnknownParameter(0xf0, []byte{0x01, 0x02}),
+		)},
+		{"XUDTS", sccp.NewXUDTS(params.ReturnCauseSubsystemCongestion, 15, cdpa, cgpa, data)},
+		{"CR", sccp.NewCR(0x010203, 2, cdpa,
+			params.NewCreditOptional(1), optCgpa, params.NewDataOptional(data), params.NewHopCounterOptional(10),
+		)},
+		{"CC", sccp.NewCC(1, 2, 3, optCdpa, params.NewImportanceOptional(1))},
+		{"CREF", sccp.NewCREF(1, params.RefusalCauseValue(2), params.NewDataOptional(data))},
+		{"RLSD", sccp.NewRLSD(1, 2, params.ReleaseCauseValue(3))},
+		{"RLC", sccp.NewRLC(1, 2)},
+		{"DT1", sccp.NewDT1(1, true, data)},
+		{"DT2", sccp.NewDT2(1, 2, 3, true, data)},
+		{"AK", sccp.NewAK(1, 2, 3)},
+		{"ED", sccp.NewED(1, data)},
+		{"EA", sccp.NewEA(1)},
+		{"RSR", sccp.NewRSR(1, 2, params.ResetCauseValue(3))},
+		{"RSC", sccp.NewRSC(1, 2)},
+		{"IT", sccp.NewIT(1, 2, 3, 4, 5, 6)},
+		{"Raw", sccp.NewRawMessage(200, []byte{0x01, 0x02})},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			pb, err := sccppb.FromMessage(c.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := proto.Marshal(pb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := &sccppb.Message{}
+			if err := proto.Unmarshal(b, decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := sccppb.ToMessage(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := m.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := c.msg.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDigits(t *testing.T) {
+	gt := params.NewGlobalTitle(
+		params.GTITTNPESNAI, 0, params.NPISDNTelephony, params.ESBCDOdd, params.NAIInternationalNumber,
+		[]byte{0x21, 0x43, 0x05},
+	)
+	pb := sccppb.FromPartyAddress(params.NewCalledPartyAddress(
+		params.NewAddressIndicator(false, true, false, params.GTITTNPESNAI), 0, 6, gt,
+	))
+
+	if diff := cmp.Diff(pb.GetGlobalTitle().GetDigits(), "12345"); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMissingParameter(t *testing.T) {
+	_, err := sccppb.ToMessage(&sccppb.Message{Type: sccppb.MessageType_MESSAGE_TYPE_UDT})
+	if !errors.Is(err, sccp.ErrMissingParameter) {
+		t.Errorf("got %v, want ErrMissingParameter", err)
+	}
+}
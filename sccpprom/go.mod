@@ -0,0 +1,19 @@
+module github.com/wmnsk/go-sccp/sccpprom
+
+go 1.23
+
+require (
+	github.com/prometheus/client_golang v1.20.5
+	github.com/wmnsk/go-sccp v0.0.0-20261015134319-4e5edd259f0d
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+	google.golang.org/protobuf v1.35.2 // indirect
+)
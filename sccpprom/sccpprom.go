@@ -0,0 +1,113 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package sccpprom provides the Prometheus implementation of sccp.Metrics.
+
+It is a separate module so that the go-sccp module does not depend on the
+Prometheus client. It requires the version of go-sccp that has sccp.Metrics,
+which go.work in the repository root replaces with the local one during the
+development; the root module is released first, and the requirement is
+updated to it before this module is tagged.
+
+	m := sccpprom.New("")
+	prometheus.MustRegister(m)
+	sccp.SetMetrics(m)
+
+The following counters are exported, with the namespace given to New if any:
+
+	sccp_messages_parsed_total{type}
+	sccp_messages_marshalled_total{type}
+	sccp_parse_errors_total{cause}
+	sccp_messages_returned_total{cause}
+	sccp_reassembly_timeouts_total
+*/
+package sccpprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Metrics is the sccp.Metrics that counts the events in the Prometheus
+// counters. It implements prometheus.Collector, and should be registered to
+// a prometheus.Registerer to be exported.
+type Metrics struct {
+	parsed             *prometheus.CounterVec
+	marshalled         *prometheus.CounterVec
+	parseErrors        *prometheus.CounterVec
+	returned           *prometheus.CounterVec
+	reassemblyTimeouts prometheus.Counter
+}
+
+// New creates a new Metrics with the counters in the namespace, which can be
+// empty.
+func New(namespace string) *Metrics {
+	opts := func(name, help string) prometheus.CounterOpts {
+		return prometheus.CounterOpts{Namespace: namespace, Subsystem: "sccp", Name: name, Help: help}
+	}
+
+	return &Metrics{
+		parsed: prometheus.NewCounterVec(
+			opts("messages_parsed_total", "Number of SCCP messages parsed by message type."), []string{"type"},
+		),
+		marshalled: prometheus.NewCounterVec(
+			opts("messages_marshalled_total", "Number of SCCP messages marshalled by message type."), []string{"type"},
+		),
+		parseErrors: prometheus.NewCounterVec(
+			opts("parse_errors_total", "Number of SCCP messages failed to parse by cause."), []string{"cause"},
+		),
+		returned: prometheus.NewCounterVec(
+			opts("messages_returned_total", "Number of SCCP messages returned in UDTS or XUDTS by return cause."), []string{"cause"},
+		),
+		reassemblyTimeouts: prometheus.NewCounter(
+			opts("reassembly_timeouts_total", "Number of SCCP reassemblies timed out."),
+		),
+	}
+}
+
+// MessageParsed counts the message parsed by type.
+func (m *Metrics) MessageParsed(t sccp.MsgType) {
+	m.parsed.WithLabelValues(t.String()).Inc()
+}
+
+// MessageMarshalled counts the message marshalled by type.
+func (m *Metrics) MessageMarshalled(t sccp.MsgType) {
+	m.marshalled.WithLabelValues(t.String()).Inc()
+}
+
+// ParseError counts the parse error by cause.
+func (m *Metrics) ParseError(cause string) {
+	m.parseErrors.WithLabelValues(cause).Inc()
+}
+
+// MessageReturned counts the message returned by cause.
+func (m *Metrics) MessageReturned(cause params.ReturnCauseValue) {
+	m.returned.WithLabelValues(cause.String()).Inc()
+}
+
+// ReassemblyTimeout counts the reassembly timed out.
+func (m *Metrics) ReassemblyTimeout() {
+	m.reassemblyTimeouts.Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.parsed.Describe(ch)
+	m.marshalled.Describe(ch)
+	m.parseErrors.Describe(ch)
+	m.returned.Describe(ch)
+	m.reassemblyTimeouts.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.parsed.Collect(ch)
+	m.marshalled.Collect(ch)
+	m.parseErrors.Collect(ch)
+	m.returned.Collect(ch)
+	m.reassemblyTimeouts.Collect(ch)
+}
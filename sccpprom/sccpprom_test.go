@@ -0,0 +1,114 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccpprom_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/sccpprom"
+)
+
+func TestMetrics(t *testing.T) {
+	m := sccpprom.New("test")
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(m); err != nil {
+		t.Fatal(err)
+	}
+
+	sccp.SetMetrics(m)
+	defer sccp.SetMetrics(nil)
+
+	ai := params.NewAddressIndicator(false, true, true, params.GTINoGT)
+	cdpa := params.NewCalledPartyAddress(ai, 0, 6, nil)
+	cgpa := params.NewCallingPartyAddress(ai, 0, 7, nil)
+
+	udt := sccp.NewUDT(0, true, cdpa, cgpa, []byte{0xde, 0xad})
+	b, err := udt.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := sccp.ParseMessage(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := sccp.ParseMessage(b[:3]); err == nil {
+		t.Fatal("parsed truncated message")
+	}
+	if _, err := sccp.ParseMessage([]byte{0xff}); err == nil {
+		t.Fatal("parsed unsupported type")
+	}
+	if _, err := sccp.NewServiceMessage(udt, params.ReturnCauseUnequippedUser); err != nil {
+		t.Fatal(err)
+	}
+
+	timedOut := make(chan struct{})
+	r := sccp.NewReassembler(&sccp.ReassemblerConfig{
+		Timeout: 10 * time.Millisecond,
+		Dropped: func(*sccp.ReassemblyError) { close(timedOut) },
+	})
+	segs, err := sccp.Segment(make([]byte, 20), 10, sccp.SegmentOptions{CalledPartyAddress: cdpa, CallingPartyAddress: cgpa})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Add(segs[0]); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-timedOut:
+	case <-time.After(time.Second):
+		t.Fatal("reassembly not timed out")
+	}
+
+	for _, c := range []struct {
+		name, label, value string
+		want               float64
+	}{
+		{"test_sccp_messages_parsed_total", "type", "UDT", 2},
+		{"test_sccp_messages_marshalled_total", "type", "UDT", 1},
+		{"test_sccp_parse_errors_total", "cause", sccp.ParseErrorTruncated, 1},
+		{"test_sccp_parse_errors_total", "cause", sccp.ParseErrorUnsupportedType, 1},
+		{"test_sccp_messages_returned_total", "cause", params.ReturnCauseUnequippedUser.String(), 1},
+		{"test_sccp_reassembly_timeouts_total", "", "", 1},
+	} {
+		if got := counterValue(t, reg, c.name, c.label, c.value); got != c.want {
+			t.Errorf("%s{%s=%q}: got %v, want %v", c.name, c.label, c.value, got, c.want)
+		}
+	}
+}
+
+// counterValue returns the value of the counter gathered from reg, which has
+// the label with the value if label is not empty.
+func counterValue(t *testing.T, reg prometheus.Gatherer, name, label, value string) float64 {
+	t.Helper()
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, metric := range mf.GetMetric() {
+			if label == "" {
+				return metric.GetCounter().GetValue()
+			}
+			for _, lp := range metric.GetLabel() {
+				if lp.GetName() == label && lp.GetValue() == value {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	t.Fatalf("%s{%s=%q} not found", name, label, value)
+	return 0
+}
@@ -5,9 +5,11 @@
 package sccp
 
 import (
-	"encoding/binary"
+	"bytes"
 	"fmt"
 	"io"
+
+	"github.com/wmnsk/go-sccp/params"
 )
 
 // SCMGType is type of SCMG message.
@@ -24,18 +26,47 @@ const (
 	SCMGTypeSSC          // SSC
 )
 
+// SCMG types defined only in T1.112 (Table 23A/T1.112.3), which have the same
+// format as SSA.
+const (
+	SCMGTypeSBR SCMGType = 0xfd // SBR
+	SCMGTypeSNR SCMGType = 0xfe // SNR
+	SCMGTypeSRT SCMGType = 0xff // SRT
+)
+
+// Subsystem Multiplicity Indicator values.
+//
+// Only SMIUnknown is defined in Q.713; the others are defined in T1.112.
+const (
+	SMIUnknown    uint8 = 0b00
+	SMISolitary   uint8 = 0b01
+	SMIDuplicated uint8 = 0b10
+)
+
 // SCMG represents a SCCP Management message (SCMG).
 // Chapter 5.3/Q.713
+//
+// The SCMG format identifier is Type. The spare bits of AffectedPC,
+// SubsystemMultiplicityIndicator (bits 3-8) and SCCPCongestionLevel (bits 5-8)
+// are ignored on parsing and set to 0 on serializing.
+//
+// In VariantANSI, the AffectedPC is 24 bits long, and SBR, SNR and SRT are
+// available, which are not defined in the other Variants. SSC has the same
+// format identifier in all Variants, and its SCCPCongestionLevel follows the
+// AffectedPC of the length in the Variant.
 type SCMG struct {
 	Type                           SCMGType
 	AffectedSSN                    uint8
-	AffectedPC                     uint16
+	AffectedPC                     params.PointCode
 	SubsystemMultiplicityIndicator uint8
 	SCCPCongestionLevel            uint8
+
+	trailing []byte
+	variant  params.Variant
 }
 
 // NewSCMG creates a new SCMG.
-func NewSCMG(typ SCMGType, assn uint8, apc uint16, smi uint8, scl uint8) *SCMG {
+func NewSCMG(typ SCMGType, assn uint8, apc params.PointCode, smi uint8, scl uint8) *SCMG {
 	return &SCMG{
 		Type:                           typ,
 		AffectedSSN:                    assn,
@@ -57,59 +88,173 @@ func (s *SCMG) MarshalBinary() ([]byte, error) {
 
 // MarshalTo puts the byte sequence in the byte array given as b.
 func (s *SCMG) MarshalTo(b []byte) error {
-	l := len(b)
+	if err := s.checkType(); err != nil {
+		return err
+	}
 
+	l := len(b)
 	if l < s.MarshalLen() {
 		return io.ErrUnexpectedEOF
 	}
 
+	codec := s.pointCodeCodec()
 	b[0] = uint8(s.Type)
 	b[1] = s.AffectedSSN
-	binary.LittleEndian.PutUint16(b[2:4], s.AffectedPC)
-	b[4] = s.SubsystemMultiplicityIndicator
+	if err := codec.Encode(b[2:], s.AffectedPC); err != nil {
+		return err
+	}
+	n := 2 + codec.Len()
+	b[n] = s.SubsystemMultiplicityIndicator & 0b11
 	if s.Type == SCMGTypeSSC {
-		b[5] = s.SCCPCongestionLevel
+		b[n+1] = s.SCCPCongestionLevel & 0x0f
 	}
 
 	return nil
 }
 
-// ParseSCMG decodes given byte sequence as a SCMG.
-func ParseSCMG(b []byte) (*SCMG, error) {
-	s := &SCMG{}
-	if err := s.UnmarshalBinary(b); err != nil {
-		return nil, err
-	}
+// AppendTo appends the byte sequence generated from the SCMG to dst, and
+// returns the extended slice.
+func (s *SCMG) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, s)
+}
 
-	return s, nil
+// WriteTo writes the byte sequence generated from the SCMG to w. It implements
+// io.WriterTo.
+func (s *SCMG) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, s)
+}
+
+// ParseSCMG decodes given byte sequence as a SCMG.
+//
+// WithVariant can be given to decode the SCMG in the format of ANSI. It is
+// the same as DefaultCodec.ParseSCMG if no ParseOption is given.
+func ParseSCMG(b []byte, opts ...ParseOption) (*SCMG, error) {
+	return codecOf(opts).ParseSCMG(b)
 }
 
 // UnmarshalBinary sets the values retrieved from byte sequence in a SCMG.
 func (s *SCMG) UnmarshalBinary(b []byte) error {
+	codec := s.pointCodeCodec()
+	n := 2 + codec.Len()
+
 	l := len(b)
-	if l < 5 {
+	if l < n+1 {
 		return io.ErrUnexpectedEOF
 	}
 
 	s.Type = SCMGType(b[0])
+	if err := s.checkType(); err != nil {
+		return err
+	}
 	s.AffectedSSN = b[1]
-	s.AffectedPC = binary.LittleEndian.Uint16(b[2:4])
-	s.SubsystemMultiplicityIndicator = b[4]
+	s.AffectedPC = codec.Decode(b[2:n])
+	s.SubsystemMultiplicityIndicator = b[n] & 0b11
 
 	if s.Type == SCMGTypeSSC {
-		if l < 6 {
+		if l < n+2 {
 			return io.ErrUnexpectedEOF
 		}
-		s.SCCPCongestionLevel = b[5]
+		s.SCCPCongestionLevel = b[n+1] & 0x0f
+	}
+
+	s.trailing = nil
+	if end := s.MarshalLen(); l > end {
+		s.trailing = b[end:]
 	}
 
 	return nil
 }
 
+// Variant returns the Variant of the SCMG.
+func (s *SCMG) Variant() params.Variant {
+	return s.variant
+}
+
+// SetVariant sets the Variant of the SCMG, which determines the length of the
+// AffectedPC and the message types available.
+func (s *SCMG) SetVariant(v params.Variant) {
+	s.variant = v
+}
+
+func (s *SCMG) pointCodeCodec() params.PointCodeCodec {
+	return s.variant.PointCodeCodec()
+}
+
+// checkType checks if the Type is available in the Variant.
+func (s *SCMG) checkType() error {
+	var ok bool
+	switch s.Type {
+	case SCMGTypeSBR, SCMGTypeSNR, SCMGTypeSRT:
+		ok = s.variant == params.VariantANSI
+	default:
+		ok = true
+	}
+
+	if !ok {
+		return fmt.Errorf("%s is not defined in %s: %w", s.Type, s.variant, UnsupportedTypeError(s.Type))
+	}
+	return nil
+}
+
+// ExtractSCMG parses the Data of the UDT or XUDT as a SCMG if it is addressed
+// to SSNManagement, in the Variant of the message.
+//
+// It returns nil without error if m is not a UDT or XUDT, or if it is not
+// addressed to SSNManagement, so that it can be called on any incoming message.
+func ExtractSCMG(m Message) (*SCMG, error) {
+	var (
+		cdpa *params.PartyAddress
+		data *params.Data
+		opts parseOptions
+	)
+	switch m := m.(type) {
+	case *UDT:
+		cdpa, data, opts = m.CalledPartyAddress, m.LoadData(), m.opts
+	case *XUDT:
+		cdpa, data, opts = m.CalledPartyAddress, m.LoadData(), m.opts
+	default:
+		return nil, nil
+	}
+
+	if cdpa == nil || !cdpa.HasSSN() || cdpa.SubsystemNumber != SSNManagement || data == nil {
+		return nil, nil
+	}
+
+	s := &SCMG{variant: opts.variant}
+	if err := s.UnmarshalBinary(data.Value()); err != nil {
+		return nil, fmt.Errorf("failed to parse SCMG in %s: %w", m.MessageType(), err)
+	}
+
+	return s, nil
+}
+
+// Clone returns a copy of the SCMG that does not share any memory with the
+// original, including the byte sequence it was parsed from.
+func (s *SCMG) Clone() *SCMG {
+	c := *s
+	c.trailing = bytes.Clone(s.trailing)
+	return &c
+}
+
+// Reset clears the SCMG to be reused with UnmarshalBinary, keeping the
+// Variant it was parsed with.
+func (s *SCMG) Reset() {
+	*s = SCMG{variant: s.variant}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the SCMG when
+// it is parsed, or nil if there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (s *SCMG) TrailingBytes() []byte {
+	return s.trailing
+}
+
 // MarshalLen returns the serial length.
 func (s *SCMG) MarshalLen() int {
 	// Table 24/Q.713 – SCMG messages
-	l := 5
+	l := 3 + s.pointCodeCodec().Len()
 
 	// Table 25/Q.713 – SSC
 	if s.Type == SCMGTypeSSC {
@@ -121,10 +266,12 @@ func (s *SCMG) MarshalLen() int {
 
 // String returns the SCMG values in human readable format.
 func (s *SCMG) String() string {
-	return fmt.Sprintf("%s: {AffectedSSN: %v, AffectedPC: %v, SubsystemMultiplicityIndicator: %d, SCCPCongestionLevel: %d}",
+	apc := s.variant.FormatPointCode(s.AffectedPC)
+
+	return fmt.Sprintf("%s: {AffectedSSN: %v, AffectedPC: %s, SubsystemMultiplicityIndicator: %d, SCCPCongestionLevel: %d}",
 		s.Type,
 		s.AffectedSSN,
-		s.AffectedPC,
+		apc,
 		s.SubsystemMultiplicityIndicator,
 		s.SCCPCongestionLevel,
 	)
@@ -0,0 +1,250 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Conn is a signalling connection that can be used like net.Conn, which is
+// created by Service.Connect or Listener.Accept.
+//
+// Read returns the data received in DT1s (or DT2s in the protocol class 3) in
+// order, and Write sends the data in the same way. After the connection is released, Read returns io.EOF once all
+// the received data is read, or the error that caused the release, e.g.,
+// ErrReleaseTimeout.
+type Conn struct {
+	svc  *Service
+	conn *Connection
+
+	established chan struct{}
+	notify      chan struct{}
+	exNotify    chan struct{}
+	done        chan struct{}
+
+	mu      sync.Mutex
+	rx      [][]byte
+	ex      [][]byte
+	err     error
+	closing bool
+}
+
+func newConn(svc *Service, ref uint32) *Conn {
+	c := &Conn{
+		svc:         svc,
+		established: make(chan struct{}),
+		notify:      make(chan struct{}, 1),
+		exNotify:    make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+
+	c.conn = New(ref, svc.cfg, svc.send, Events{
+		ConnectRequest: func(cr *sccp.CR) { svc.incoming(c, cr) },
+		Connected:      func(*sccp.CC) { c.connected() },
+		Data:           c.received,
+		ExpeditedData:  c.receivedExpedited,
+		Released:       c.released,
+	})
+	return c
+}
+
+// Read reads the data received on the connection into b. If b is shorter
+// than the data reassembled from the DT1s or DT2s, the rest is returned by the
+// subsequent Read.
+func (c *Conn) Read(b []byte) (int, error) {
+	for {
+		c.mu.Lock()
+		if len(c.rx) > 0 {
+			n := copy(b, c.rx[0])
+			if n < len(c.rx[0]) {
+				c.rx[0] = c.rx[0][n:]
+			} else {
+				c.rx = c.rx[1:]
+			}
+			c.mu.Unlock()
+			return n, nil
+		}
+		select {
+		case <-c.done:
+			err := c.err
+			c.mu.Unlock()
+			return 0, err
+		default:
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-c.notify:
+		case <-c.done:
+		}
+	}
+}
+
+// Write sends b in DT1s or DT2s, which are segmented if b is longer than
+// Config.SegmentSize. In the protocol class 3, it blocks while the credit
+// window is full.
+func (c *Conn) Write(b []byte) (int, error) {
+	if c.isClosing() {
+		return 0, ErrConnClosed
+	}
+
+	if err := c.conn.Send(b); err != nil {
+		if errors.Is(err, ErrInvalidState) {
+			return 0, ErrConnClosed
+		}
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// SendExpedited sends b in an ED, which is available in the protocol class 3.
+// It blocks until the EA for the previous ED is received.
+func (c *Conn) SendExpedited(b []byte) error {
+	if c.isClosing() {
+		return ErrConnClosed
+	}
+
+	if err := c.conn.SendExpedited(b); err != nil {
+		if errors.Is(err, ErrInvalidState) && c.conn.State() != StateActive {
+			return ErrConnClosed
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ReceiveExpedited returns the data received in the next ED, waiting for it
+// if there is none. After the connection is released, it returns the same
+// error as Read.
+func (c *Conn) ReceiveExpedited() ([]byte, error) {
+	for {
+		c.mu.Lock()
+		if len(c.ex) > 0 {
+			data := c.ex[0]
+			c.ex = c.ex[1:]
+			c.mu.Unlock()
+			return data, nil
+		}
+		select {
+		case <-c.done:
+			err := c.err
+			c.mu.Unlock()
+			return nil, err
+		default:
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-c.exNotify:
+		case <-c.done:
+		}
+	}
+}
+
+// Close releases the connection by sending RLSD. It does not wait for the
+// RLC; the resources are freed when it is received or T(int) expires.
+//
+// If it is called while the connection is being established, the connection
+// is released as soon as the CC is received.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	if c.closing {
+		c.mu.Unlock()
+		return ErrConnClosed
+	}
+	c.closing = true
+	c.mu.Unlock()
+
+	switch c.conn.State() {
+	case StateActive, StateResetPending:
+		return c.release()
+	}
+	return nil
+}
+
+// LocalReference returns the local reference of the connection.
+func (c *Conn) LocalReference() uint32 {
+	return c.conn.LocalReference()
+}
+
+// RemoteReference returns the local reference of the peer.
+func (c *Conn) RemoteReference() uint32 {
+	return c.conn.RemoteReference()
+}
+
+// Done returns a channel that is closed when the connection is released.
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Conn) release() error {
+	err := c.conn.Release(params.ReleaseCauseEndUserOriginated)
+	if errors.Is(err, ErrInvalidState) {
+		// already released by the peer.
+		return nil
+	}
+	return err
+}
+
+func (c *Conn) isClosing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closing
+}
+
+func (c *Conn) connected() {
+	close(c.established)
+
+	if c.isClosing() {
+		if err := c.release(); err != nil {
+			logf("failed to release connection %d: %v", c.LocalReference(), err)
+		}
+	}
+}
+
+func (c *Conn) received(data []byte) {
+	c.mu.Lock()
+	c.rx = append(c.rx, bytes.Clone(data))
+	c.mu.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Conn) receivedExpedited(data []byte) {
+	c.mu.Lock()
+	c.ex = append(c.ex, bytes.Clone(data))
+	c.mu.Unlock()
+
+	select {
+	case c.exNotify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Conn) released(err error) {
+	var rerr *ReleasedError
+	if err == nil || errors.As(err, &rerr) {
+		err = io.EOF
+	}
+
+	c.mu.Lock()
+	c.err = err
+	close(c.done)
+	c.mu.Unlock()
+
+	c.svc.remove(c.LocalReference())
+}
@@ -0,0 +1,64 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Error definitions.
+var (
+	ErrInvalidState      = errors.New("scoc: operation not allowed in the current state")
+	ErrConnectionTimeout = errors.New("scoc: connection establishment timer expired")
+	ErrReleaseTimeout    = errors.New("scoc: release timer expired")
+	ErrResetTimeout      = errors.New("scoc: reset timer expired")
+	ErrInactivityTimeout = errors.New("scoc: receive inactivity timer expired")
+	ErrInconsistentData  = errors.New("scoc: inconsistent connection data")
+	ErrReferenceMismatch = errors.New("scoc: destination local reference mismatch")
+	ErrNoReference       = errors.New("scoc: no local reference available")
+	ErrUnknownReference  = errors.New("scoc: no connection for the local reference")
+	ErrUnexpectedMessage = errors.New("scoc: unexpected message")
+	ErrConnClosed        = errors.New("scoc: use of closed connection")
+	ErrListenerExists    = errors.New("scoc: listener already exists")
+	ErrListenerClosed    = errors.New("scoc: listener closed")
+
+	ErrReassemblyTooLarge = errors.New("scoc: reassembled data too large")
+	ErrExpeditedDataSize  = errors.New("scoc: expedited data must be 1 to 32 octets")
+)
+
+// RefusedError indicates that the connection is refused by the peer with CREF.
+type RefusedError struct {
+	Cause params.RefusalCauseValue
+}
+
+// Error returns the type of receiver and some additional message.
+func (e *RefusedError) Error() string {
+	return fmt.Sprintf("scoc: connection refused with cause %d", e.Cause)
+}
+
+// ReleasedError indicates that the connection is released by the peer with RLSD.
+type ReleasedError struct {
+	Cause params.ReleaseCauseValue
+}
+
+// Error returns the type of receiver and some additional message.
+func (e *ReleasedError) Error() string {
+	return fmt.Sprintf("scoc: connection released with cause %d", e.Cause)
+}
+
+// SequenceError indicates that the sequence number of the DT2 or AK received
+// in the protocol class 3 is not the expected one, which resets the
+// connection with the Cause.
+type SequenceError struct {
+	Cause params.ResetCauseValue
+}
+
+// Error returns the type of receiver and some additional message.
+func (e *SequenceError) Error() string {
+	return fmt.Sprintf("scoc: sequence error with cause %d", e.Cause)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// MaxExpeditedDataSize is the maximum size of the data in an ED.
+const MaxExpeditedDataSize = 32
+
+// SendExpedited sends data in an ED, which is available only in the protocol
+// class 3 (see Q.714 3.7).
+//
+// Only one ED can be outstanding at a time; it blocks until the EA for the
+// previous one is received.
+func (c *Connection) SendExpedited(data []byte) error {
+	if l := len(data); l < 1 || l > MaxExpeditedDataSize {
+		return fmt.Errorf("%d bytes of expedited data: %w", l, ErrExpeditedDataSize)
+	}
+
+	c.mu.Lock()
+	for c.state == StateResetPending || c.state == StateActive && c.edPending {
+		c.cond.Wait()
+	}
+	if c.state != StateActive {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to send expedited data in %s state: %w", c.state, ErrInvalidState)
+	}
+	if c.class != params.ClassFlowControlConnectionOriented {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to send expedited data in class %d: %w", c.class, ErrInvalidState)
+	}
+
+	c.edPending = true
+	ed := sccp.NewED(c.remoteRef, data)
+	c.mu.Unlock()
+
+	if err := c.send(ed); err != nil {
+		c.mu.Lock()
+		c.edPending = false
+		c.cond.Broadcast()
+		c.mu.Unlock()
+		return err
+	}
+	c.sent()
+
+	return nil
+}
+
+// handleED acknowledges the ED received in the active state with EA, and
+// returns the function to notify the user of the data.
+func (c *Connection) handleED(ed *sccp.ED) (sccp.Message, func()) {
+	if c.class != params.ClassFlowControlConnectionOriented {
+		logf("discarding ED on connection %d in class %d", c.localRef, c.class)
+		return nil, nil
+	}
+
+	ea := sccp.NewEA(c.remoteRef)
+	fn := c.events.ExpeditedData
+	if fn == nil {
+		return ea, nil
+	}
+	data := ed.Data.Value()
+	return ea, func() { fn(data) }
+}
+
+// handleEA allows the next ED to be sent.
+func (c *Connection) handleEA(*sccp.EA) (sccp.Message, func()) {
+	if !c.edPending {
+		logf("discarding unexpected EA on connection %d", c.localRef)
+	}
+	c.edPending = false
+	return nil, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// seqMod is the modulo of the 7-bit sequence numbers P(S) and P(R).
+const seqMod = 0x80
+
+// seqDiff returns a-b in the modulo of the sequence numbers.
+func seqDiff(a, b uint8) uint8 {
+	return (a - b) % seqMod
+}
+
+// window is the state of the flow control in the protocol class 3
+// (see Q.714 3.6.2).
+type window struct {
+	credit uint8
+
+	// sendNext is P(S) of the next DT2 to send, and sendLow is the lowest
+	// P(S) not acknowledged by the peer, i.e., the last P(R) received.
+	sendNext, sendLow uint8
+	// recvNext is P(S) of the next DT2 expected, and ackedPR is the last
+	// P(R) sent to the peer.
+	recvNext, ackedPR uint8
+}
+
+// reset initializes the sequence numbers with the credit agreed.
+func (w *window) reset(credit uint8) {
+	*w = window{credit: credit}
+}
+
+// canSend reports whether the send window is open.
+func (w *window) canSend() bool {
+	return seqDiff(w.sendNext, w.sendLow) < w.credit
+}
+
+// next returns P(S) and P(R) of the next DT2 to send.
+func (w *window) next() (ps, pr uint8) {
+	ps, pr = w.sendNext, w.recvNext
+	w.sendNext = (w.sendNext + 1) % seqMod
+	w.ackedPR = w.recvNext
+	return ps, pr
+}
+
+// acknowledge updates the send window with P(R) received from the peer.
+// It returns false if pr is not within the DT2s sent.
+func (w *window) acknowledge(pr uint8) bool {
+	if seqDiff(pr, w.sendLow) > seqDiff(w.sendNext, w.sendLow) {
+		return false
+	}
+	w.sendLow = pr
+	return true
+}
+
+// receive checks P(S) and P(R) of the DT2 received and moves the windows.
+func (w *window) receive(ps, pr uint8) *SequenceError {
+	if seqDiff(ps, w.ackedPR) >= w.credit {
+		return &SequenceError{Cause: params.ResetCauseRemoteProcedureErrorMessageOutOfWindow}
+	}
+	if ps != w.recvNext {
+		return &SequenceError{Cause: params.ResetCauseMessageOutOfOrderIncorrectSendSequenceNumber}
+	}
+	if !w.acknowledge(pr) {
+		return &SequenceError{Cause: params.ResetCauseMessageOutOfOrderIncorrectReceiveSequenceNumber}
+	}
+
+	w.recvNext = (w.recvNext + 1) % seqMod
+	return nil
+}
+
+// shouldAcknowledge reports whether an AK should be sent, which is when half
+// of the receive window is used without sending P(R) to the peer.
+func (w *window) shouldAcknowledge() bool {
+	return seqDiff(w.recvNext, w.ackedPR) >= max(1, w.credit/2)
+}
+
+// nextDT2 returns the DT2 that carries data, waiting for the send window to
+// open.
+func (c *Connection) nextDT2(more bool, data []byte) (*sccp.DT2, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.state == StateResetPending || c.state == StateActive && !c.window.canSend() {
+		c.cond.Wait()
+	}
+	if c.state != StateActive {
+		return nil, fmt.Errorf("failed to send data in %s state: %w", c.state, ErrInvalidState)
+	}
+
+	ps, pr := c.window.next()
+	return sccp.NewDT2(c.remoteRef, ps, pr, more, data), nil
+}
+
+// handleDT2 handles the DT2 received in the active state.
+func (c *Connection) handleDT2(dt2 *sccp.DT2) (sccp.Message, func()) {
+	if c.class != params.ClassFlowControlConnectionOriented {
+		return c.startRelease(params.ReleaseCauseRemoteProcedureError, ErrUnexpectedMessage), nil
+	}
+
+	seq := dt2.SequencingSegmenting
+	if err := c.window.receive(seq.PS(), seq.PR()); err != nil {
+		return c.resetOnError(err)
+	}
+
+	reply, notify := c.reassemble(dt2.Data.Value(), seq.More())
+	if reply == nil && c.window.shouldAcknowledge() {
+		c.window.ackedPR = c.window.recvNext
+		reply = sccp.NewAK(c.remoteRef, c.window.recvNext, c.window.credit)
+	}
+	return reply, notify
+}
+
+// handleAK handles the AK received in the active state.
+func (c *Connection) handleAK(ak *sccp.AK) (sccp.Message, func()) {
+	if c.class != params.ClassFlowControlConnectionOriented {
+		return c.startRelease(params.ReleaseCauseRemoteProcedureError, ErrUnexpectedMessage), nil
+	}
+
+	if !c.window.acknowledge(ak.ReceiveSequenceNumber.PR()) {
+		return c.resetOnError(&SequenceError{Cause: params.ResetCauseMessageOutOfOrderIncorrectReceiveSequenceNumber})
+	}
+	c.window.credit = ak.Credit.Value()
+	return nil, nil
+}
+
+// hasCredit reports whether the Credit is given in opts.
+func hasCredit(opts []params.Parameter) bool {
+	for _, opt := range opts {
+		if opt.Code() == params.PCodeCredit {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// MaxLocalReference is the maximum value of the local reference, which is
+// three octets long.
+const MaxLocalReference uint32 = 0xffffff
+
+// DefaultFreezeTime is the default time the local reference is frozen after
+// it is released, so that the messages for the old connection are not taken
+// as the ones for the new connection (see Q.714 3.3.4.2).
+const DefaultFreezeTime = 1 * time.Minute
+
+// RefAllocator allocates the local references for the connections.
+//
+// A released reference is not allocated again until the freeze time passes.
+// The references are picked at random in the range, which is 1 to
+// MaxLocalReference by default; use Seed to get the same sequence every time
+// for testing.
+//
+// RefAllocator is safe for concurrent use.
+type RefAllocator struct {
+	mu       sync.Mutex
+	freeze   time.Duration
+	min, max uint32
+	rand     *rand.Rand
+	inUse    map[uint32]struct{}
+	frozen   map[uint32]time.Time
+	queue    []frozenRef
+}
+
+// frozenRef is a released reference and the time it is unfrozen at.
+type frozenRef struct {
+	ref   uint32
+	until time.Time
+}
+
+// NewRefAllocator creates a new RefAllocator that freezes the released
+// references for freeze. If freeze is 0, DefaultFreezeTime is used.
+func NewRefAllocator(freeze time.Duration) *RefAllocator {
+	if freeze == 0 {
+		freeze = DefaultFreezeTime
+	}
+
+	return &RefAllocator{
+		freeze: freeze,
+		min:    1,
+		max:    MaxLocalReference,
+		rand:   rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		inUse:  map[uint32]struct{}{},
+		frozen: map[uint32]time.Time{},
+	}
+}
+
+// Seed makes the RefAllocator pick the references in the deterministic order
+// derived from seed.
+func (a *RefAllocator) Seed(seed uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.rand = rand.New(rand.NewPCG(seed, seed))
+}
+
+// SetRange limits the references to allocate to the range from min to max,
+// e.g., to share the reference space among the instances with the same point
+// code. The values exceeding MaxLocalReference are cut off.
+func (a *RefAllocator) SetRange(min, max uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.min, a.max = min&MaxLocalReference, max&MaxLocalReference
+	if a.min > a.max {
+		a.min, a.max = a.max, a.min
+	}
+}
+
+// Allocate allocates a reference that is neither in use nor frozen.
+// It returns ErrNoReference if there is no such reference in the range.
+func (a *RefAllocator) Allocate() (uint32, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.unfreeze(time.Now())
+
+	size := uint64(a.max-a.min) + 1
+	offset := a.rand.Uint64N(size)
+	for i := uint64(0); i < size; i++ {
+		ref := a.min + uint32((offset+i)%size)
+		if _, ok := a.inUse[ref]; ok {
+			continue
+		}
+		if _, ok := a.frozen[ref]; ok {
+			continue
+		}
+
+		a.inUse[ref] = struct{}{}
+		return ref, nil
+	}
+
+	return 0, ErrNoReference
+}
+
+// Release releases the reference allocated by Allocate, which is frozen for
+// the freeze time. The references that are not in use are ignored.
+func (a *RefAllocator) Release(ref uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.inUse[ref]; !ok {
+		return
+	}
+	delete(a.inUse, ref)
+
+	now := time.Now()
+	a.unfreeze(now)
+
+	until := now.Add(a.freeze)
+	a.frozen[ref] = until
+	a.queue = append(a.queue, frozenRef{ref: ref, until: until})
+}
+
+// InUse reports whether the reference is allocated and not released yet.
+func (a *RefAllocator) InUse(ref uint32) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, ok := a.inUse[ref]
+	return ok
+}
+
+// unfreeze removes the references whose freeze time has passed at now.
+// As the freeze time is the same for all, the queue is in the order of it.
+func (a *RefAllocator) unfreeze(now time.Time) {
+	i := 0
+	for ; i < len(a.queue) && !now.Before(a.queue[i].until); i++ {
+		delete(a.frozen, a.queue[i].ref)
+	}
+	a.queue = a.queue[i:]
+}
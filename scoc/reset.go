@@ -0,0 +1,112 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Reset sends RSR with the cause to reinitialize the sequence numbers of the
+// connection in the protocol class 3, and starts the timer T(reset) (see
+// Q.714 3.5). The data not acknowledged yet may be lost.
+//
+// Send blocks until the RSC is received. If T(reset) expires, the connection
+// is released with ErrResetTimeout.
+func (c *Connection) Reset(cause params.ResetCauseValue) error {
+	c.mu.Lock()
+	if c.state != StateActive {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to reset in %s state: %w", c.state, ErrInvalidState)
+	}
+	if c.class != params.ClassFlowControlConnectionOriented {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to reset in class %d: %w", c.class, ErrInvalidState)
+	}
+
+	rsr := c.startReset(cause)
+	c.mu.Unlock()
+
+	return c.send(rsr)
+}
+
+// startReset moves to the reset pending state and returns the RSR to send
+// with the lock held.
+func (c *Connection) startReset(cause params.ResetCauseValue) *sccp.RSR {
+	c.state = StateResetPending
+	c.reinitialize()
+	c.startTimer(&c.resetTimer, c.cfg.ResetTimeout, func() func() {
+		rlsd := c.startRelease(params.ReleaseCauseExpirationOfResetTimer, ErrResetTimeout)
+		return func() {
+			if err := c.send(rlsd); err != nil {
+				logf("failed to send RLSD on connection %d: %v", c.localRef, err)
+			}
+		}
+	})
+
+	return sccp.NewRSR(c.remoteRef, c.localRef, cause)
+}
+
+// reinitialize discards the data in transit and resets the sequence numbers
+// with the credit kept.
+func (c *Connection) reinitialize() {
+	c.window.reset(c.window.credit)
+	c.reassembly = nil
+	c.edPending = false
+	c.cond.Broadcast()
+}
+
+// resetOnError starts the reset on the sequence error detected in the DT2 or
+// AK received, and returns the function to notify the user of it.
+func (c *Connection) resetOnError(err *SequenceError) (sccp.Message, func()) {
+	logf("sequence error on connection %d: %v", c.localRef, err)
+	return c.startReset(err.Cause), c.notifyReset(err.Cause)
+}
+
+// handleRSR responds to the RSR received in the active state with RSC.
+func (c *Connection) handleRSR(rsr *sccp.RSR) (sccp.Message, func()) {
+	if c.class != params.ClassFlowControlConnectionOriented {
+		logf("discarding RSR on connection %d in class %d", c.localRef, c.class)
+		return nil, nil
+	}
+
+	c.reinitialize()
+	return sccp.NewRSC(c.remoteRef, c.localRef), c.notifyReset(rsr.ResetCause.Value())
+}
+
+// handleResetPending handles the messages received in the reset pending state.
+func (c *Connection) handleResetPending(m sccp.Message) (sccp.Message, func()) {
+	switch m := m.(type) {
+	case *sccp.RSC:
+		stopTimer(&c.resetTimer)
+		c.state = StateActive
+	case *sccp.RSR:
+		// collision of the reset from both sides, which completes the reset
+		// without RSC (see Q.714 3.5.3).
+		stopTimer(&c.resetTimer)
+		c.state = StateActive
+	case *sccp.RLSD:
+		stopTimer(&c.resetTimer)
+		c.state = StateIdle
+		c.stopInactivity()
+		rlc := sccp.NewRLC(c.remoteRef, c.localRef)
+		return rlc, c.released(&ReleasedError{Cause: m.ReleaseCause.Value()})
+	default:
+		// the data in transit before the reset is discarded.
+	}
+
+	return nil, nil
+}
+
+// notifyReset returns the function that calls Events.Reset with the cause.
+func (c *Connection) notifyReset(cause params.ResetCauseValue) func() {
+	fn := c.events.Reset
+	if fn == nil {
+		return nil
+	}
+	return func() { fn(cause) }
+}
@@ -0,0 +1,805 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package scoc provides the SCCP connection-oriented control (SCOC) defined in
+section 3 of Q.714, which establishes, maintains and releases the signalling
+connections of the protocol classes 2 and 3.
+
+It does not implement any transport. A Connection hands the messages to send
+to the function given to New, and the received messages are given to Handle
+by the caller, which is responsible for routing them by the Destination Local
+Reference. Service does the routing and provides the connections that can be
+used like net.Conn with Connect and Listener.
+*/
+package scoc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Default values of the timers defined in Q.714 Table 5.
+const (
+	DefaultConnEstTimeout        = 1 * time.Minute  // T(conn est)
+	DefaultReleaseTimeout        = 10 * time.Second // T(rel)
+	DefaultRepeatReleaseTimeout  = 10 * time.Second // T(repeat rel)
+	DefaultIntervalTimeout       = 1 * time.Minute  // T(int)
+	DefaultResetTimeout          = 20 * time.Second // T(reset)
+	DefaultSendInactivityTimeout = 5 * time.Minute  // T(ias)
+	DefaultRecvInactivityTimeout = 11 * time.Minute // T(iar)
+)
+
+// Default values of the segmenting and reassembly.
+const (
+	// DefaultSegmentSize is the maximum size of the data in a DT1, which is
+	// limited by the length octet of the Data.
+	DefaultSegmentSize = 255
+	// DefaultMaxReassembledSize is the maximum size of the data reassembled
+	// from the DT1s with the M-bit set.
+	DefaultMaxReassembledSize = 64 * 1024
+)
+
+// DefaultCredit is the default window size of the protocol class 3.
+const DefaultCredit uint8 = 8
+
+// State is the state of a Connection.
+type State uint8
+
+// State definitions.
+const (
+	StateIdle              State = iota // no connection
+	StateConnectionPending              // CR sent, waiting for CC or CREF
+	StateIncomingPending                // CR received, waiting for Accept or Refuse
+	StateActive                         // data transfer
+	StateDisconnectPending              // RLSD sent, waiting for RLC
+	StateResetPending                   // RSR sent, waiting for RSC
+)
+
+// String returns the State in string.
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateConnectionPending:
+		return "connection pending"
+	case StateIncomingPending:
+		return "incoming connection pending"
+	case StateActive:
+		return "active"
+	case StateDisconnectPending:
+		return "disconnect pending"
+	case StateResetPending:
+		return "reset pending"
+	default:
+		return fmt.Sprintf("unknown state %d", s)
+	}
+}
+
+// Config is the configuration of a Connection.
+//
+// The zero values are replaced with the default ones.
+type Config struct {
+	ConnEstTimeout time.Duration // T(conn est)
+	ReleaseTimeout time.Duration // T(rel)
+
+	// RepeatReleaseTimeout is T(repeat rel), the interval the RLSD is sent
+	// again at after T(rel) expires.
+	RepeatReleaseTimeout time.Duration
+	// IntervalTimeout is T(int), which is started when T(rel) expires. The
+	// connection is released locally with ErrReleaseTimeout on expiry of it.
+	IntervalTimeout time.Duration
+	// ResetTimeout is T(reset), on expiry of which the connection is released
+	// as the RSC is not received.
+	ResetTimeout time.Duration
+
+	// SendInactivityTimeout is T(ias), on expiry of which an IT is sent as
+	// nothing has been sent on the active connection. A negative value
+	// disables it.
+	SendInactivityTimeout time.Duration
+	// RecvInactivityTimeout is T(iar), on expiry of which the connection is
+	// released as nothing has been received from the peer. It should be
+	// longer than T(ias) of the peer. A negative value disables it.
+	RecvInactivityTimeout time.Duration
+
+	// SegmentSize is the maximum size of the data in a DT1. The data given to
+	// Send is split into the DT1s of this size with the M-bit set. The values
+	// exceeding DefaultSegmentSize are cut off.
+	SegmentSize int
+	// MaxReassembledSize is the maximum size of the data reassembled from the
+	// received DT1s. The connection is released if it is exceeded.
+	MaxReassembledSize int
+
+	// ProtocolClass is the protocol class Service.Connect requests, which is
+	// params.ClassBasicConnectionOriented if 0.
+	ProtocolClass int
+	// Credit is the window size proposed in the CR or CC of the protocol
+	// class 3. The smaller one of the both sides is used.
+	Credit uint8
+
+	// Observer, if not nil, is called when a Connection is created with the
+	// local reference, and the Events it returns are called along with the
+	// ones of the Connection, e.g., to trace the lifecycle of the connections
+	// as the sccpotel package does.
+	Observer func(localRef uint32) Events
+}
+
+func (c *Config) withDefaults() Config {
+	var cfg Config
+	if c != nil {
+		cfg = *c
+	}
+
+	if cfg.ConnEstTimeout == 0 {
+		cfg.ConnEstTimeout = DefaultConnEstTimeout
+	}
+	if cfg.ReleaseTimeout == 0 {
+		cfg.ReleaseTimeout = DefaultReleaseTimeout
+	}
+	if cfg.RepeatReleaseTimeout == 0 {
+		cfg.RepeatReleaseTimeout = DefaultRepeatReleaseTimeout
+	}
+	if cfg.IntervalTimeout == 0 {
+		cfg.IntervalTimeout = DefaultIntervalTimeout
+	}
+	if cfg.ResetTimeout == 0 {
+		cfg.ResetTimeout = DefaultResetTimeout
+	}
+	if cfg.SendInactivityTimeout == 0 {
+		cfg.SendInactivityTimeout = DefaultSendInactivityTimeout
+	}
+	if cfg.RecvInactivityTimeout == 0 {
+		cfg.RecvInactivityTimeout = DefaultRecvInactivityTimeout
+	}
+	if cfg.SegmentSize <= 0 || cfg.SegmentSize > DefaultSegmentSize {
+		cfg.SegmentSize = DefaultSegmentSize
+	}
+	if cfg.MaxReassembledSize <= 0 {
+		cfg.MaxReassembledSize = DefaultMaxReassembledSize
+	}
+	if cfg.ProtocolClass == 0 {
+		cfg.ProtocolClass = params.ClassBasicConnectionOriented
+	}
+	if cfg.Credit == 0 {
+		cfg.Credit = DefaultCredit
+	}
+
+	return cfg
+}
+
+// Events is the set of functions called on the events of a Connection.
+// Any of them may be nil.
+//
+// They are called without holding the lock of the Connection, so it is safe
+// to call the methods of the Connection in them.
+type Events struct {
+	// ConnectRequest is called when a CR is received in the idle state.
+	// Accept or Refuse should be called afterwards.
+	ConnectRequest func(cr *sccp.CR)
+	// Connected is called when a CC is received for the CR sent by Connect.
+	Connected func(cc *sccp.CC)
+	// Data is called when the data is received in the active state. If the
+	// data is segmented, it is called once all the DT1s or DT2s are received.
+	Data func(data []byte)
+	// ExpeditedData is called when an ED is received in the active state of
+	// the protocol class 3. The EA is sent before it is called.
+	ExpeditedData func(data []byte)
+	// Reset is called when the connection of the protocol class 3 is reset by
+	// the peer with RSR, or by the Connection on the sequence error. The data
+	// in transit is discarded.
+	Reset func(cause params.ResetCauseValue)
+	// Released is called when the Connection gets back to the idle state.
+	// err is nil if it is released by Release and the RLC is received,
+	// or the reason otherwise, e.g., RefusedError or ReleasedError.
+	Released func(err error)
+}
+
+// observed returns the Events that call the ones of obs before the ones of
+// events.
+func observed(events, obs Events) Events {
+	return Events{
+		ConnectRequest: both(obs.ConnectRequest, events.ConnectRequest),
+		Connected:      both(obs.Connected, events.Connected),
+		Data:           both(obs.Data, events.Data),
+		ExpeditedData:  both(obs.ExpeditedData, events.ExpeditedData),
+		Reset:          both(obs.Reset, events.Reset),
+		Released:       both(obs.Released, events.Released),
+	}
+}
+
+// both returns the function that calls first and then second, either of which
+// may be nil.
+func both[T any](first, second func(T)) func(T) {
+	switch {
+	case first == nil:
+		return second
+	case second == nil:
+		return first
+	}
+	return func(v T) {
+		first(v)
+		second(v)
+	}
+}
+
+// Connection is a signalling connection identified by the local reference,
+// which runs the state machine of Q.714 driven by the messages given to
+// Handle and by the calls from the user.
+//
+// Connection is safe for concurrent use.
+type Connection struct {
+	localRef uint32
+	cfg      Config
+	send     func(sccp.Message) error
+	events   Events
+
+	// sendMu serializes Send, so that the segments are not interleaved.
+	sendMu sync.Mutex
+
+	mu         sync.Mutex
+	state      State
+	remoteRef  uint32
+	class      int
+	connEst    *time.Timer
+	release    *time.Timer // T(rel)
+	repeatRel  *time.Timer // T(repeat rel)
+	interval   *time.Timer // T(int)
+	resetTimer *time.Timer // T(reset)
+	rlsd       *sccp.RLSD
+	sendIdle   *time.Timer // T(ias)
+	recvIdle   *time.Timer // T(iar)
+	reassembly []byte
+	reason     error
+	window     window
+	edPending  bool
+
+	// cond is signaled when the send window moves, the EA is received or the
+	// state changes.
+	cond *sync.Cond
+}
+
+// New creates a new Connection in the idle state with the local reference
+// localRef. cfg may be nil to use the default values.
+func New(localRef uint32, cfg *Config, send func(sccp.Message) error, events Events) *Connection {
+	c := &Connection{
+		localRef: localRef,
+		cfg:      cfg.withDefaults(),
+		send:     send,
+		events:   events,
+	}
+	if c.cfg.Observer != nil {
+		c.events = observed(events, c.cfg.Observer(localRef))
+	}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// State returns the current state of the Connection.
+func (c *Connection) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// LocalReference returns the local reference of the Connection.
+func (c *Connection) LocalReference() uint32 {
+	return c.localRef
+}
+
+// RemoteReference returns the local reference of the peer, which is known
+// after the CR or CC is received.
+func (c *Connection) RemoteReference() uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.remoteRef
+}
+
+// ProtocolClass returns the protocol class of the Connection.
+func (c *Connection) ProtocolClass() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.class
+}
+
+// Connect sends a CR to cdpa to establish the connection in the protocol
+// class pcls, and starts the timer T(conn est). The optional parameters of
+// the CR can be given as opts.
+//
+// The connection is active when Events.Connected is called.
+func (c *Connection) Connect(cdpa *params.PartyAddress, pcls int, opts ...params.Parameter) error {
+	c.mu.Lock()
+	if c.state != StateIdle {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to connect in %s state: %w", c.state, ErrInvalidState)
+	}
+
+	c.state = StateConnectionPending
+	c.class = pcls
+	if pcls == params.ClassFlowControlConnectionOriented && !hasCredit(opts) {
+		opts = append(opts, params.NewCreditOptional(c.cfg.Credit))
+	}
+	c.startTimer(&c.connEst, c.cfg.ConnEstTimeout, func() func() {
+		c.state = StateIdle
+		return c.released(ErrConnectionTimeout)
+	})
+	c.mu.Unlock()
+
+	if err := c.send(sccp.NewCR(c.localRef, pcls, cdpa, opts...)); err != nil {
+		c.reset()
+		return err
+	}
+
+	return nil
+}
+
+// Accept accepts the connection requested by the CR given to
+// Events.ConnectRequest by sending CC. The optional parameters of the CC
+// can be given as opts.
+func (c *Connection) Accept(opts ...params.Parameter) error {
+	c.mu.Lock()
+	if c.state != StateIncomingPending {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to accept in %s state: %w", c.state, ErrInvalidState)
+	}
+
+	c.state = StateActive
+	if c.class == params.ClassFlowControlConnectionOriented {
+		c.window.reset(min(c.window.credit, c.cfg.Credit))
+		if !hasCredit(opts) {
+			opts = append(opts, params.NewCreditOptional(c.window.credit))
+		}
+	}
+	c.edPending = false
+	cc := sccp.NewCC(c.remoteRef, c.localRef, c.class, opts...)
+	c.startInactivity()
+	c.mu.Unlock()
+
+	if err := c.send(cc); err != nil {
+		c.reset()
+		return err
+	}
+
+	return nil
+}
+
+// Refuse refuses the connection requested by the CR given to
+// Events.ConnectRequest by sending CREF with the cause.
+func (c *Connection) Refuse(cause params.RefusalCauseValue, opts ...params.Parameter) error {
+	c.mu.Lock()
+	if c.state != StateIncomingPending {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to refuse in %s state: %w", c.state, ErrInvalidState)
+	}
+
+	c.state = StateIdle
+	cref := sccp.NewCREF(c.remoteRef, cause, opts...)
+	c.mu.Unlock()
+
+	return c.send(cref)
+}
+
+// Send sends data in DT1s, or in DT2s in the protocol class 3. If data is
+// longer than Config.SegmentSize, it is split into multiple messages with the
+// M-bit set in all but the last one.
+//
+// In the protocol class 3, it blocks while the send window is closed until
+// the peer acknowledges the data, or while the connection is being reset.
+func (c *Connection) Send(data []byte) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	c.mu.Lock()
+	if c.state != StateActive && c.state != StateResetPending {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to send data in %s state: %w", c.state, ErrInvalidState)
+	}
+	dlr, class := c.remoteRef, c.class
+	c.mu.Unlock()
+
+	for {
+		n := min(len(data), c.cfg.SegmentSize)
+		more := n < len(data)
+
+		var m sccp.Message = sccp.NewDT1(dlr, more, data[:n])
+		if class == params.ClassFlowControlConnectionOriented {
+			dt2, err := c.nextDT2(more, data[:n])
+			if err != nil {
+				return err
+			}
+			m = dt2
+		}
+		if err := c.send(m); err != nil {
+			return err
+		}
+		c.sent()
+
+		if !more {
+			return nil
+		}
+		data = data[n:]
+	}
+}
+
+// Release sends RLSD with the cause, and starts the timer T(rel).
+//
+// Events.Released is called with nil when the RLC is received. If it is not
+// received before T(rel) expires, the RLSD is sent again every T(repeat rel)
+// until T(int) expires, and then Events.Released is called with
+// ErrReleaseTimeout (see Q.714 3.3.4.2).
+func (c *Connection) Release(cause params.ReleaseCauseValue, opts ...params.Parameter) error {
+	c.mu.Lock()
+	if c.state != StateActive && c.state != StateResetPending {
+		defer c.mu.Unlock()
+		return fmt.Errorf("failed to release in %s state: %w", c.state, ErrInvalidState)
+	}
+
+	rlsd := c.startRelease(cause, nil, opts...)
+	c.mu.Unlock()
+
+	return c.send(rlsd)
+}
+
+// startRelease moves to the disconnect pending state and returns the RLSD to
+// send with the lock held. reason is given to Events.Released when the RLC
+// is received.
+func (c *Connection) startRelease(cause params.ReleaseCauseValue, reason error, opts ...params.Parameter) *sccp.RLSD {
+	c.state = StateDisconnectPending
+	c.reason = reason
+	c.reassembly = nil
+	stopTimer(&c.resetTimer)
+	c.stopInactivity()
+	c.cond.Broadcast()
+	c.startTimer(&c.release, c.cfg.ReleaseTimeout, c.releaseExpired)
+
+	c.rlsd = sccp.NewRLSD(c.remoteRef, c.localRef, cause, opts...)
+	return c.rlsd
+}
+
+// releaseExpired sends the RLSD again on expiry of T(rel), and starts
+// T(repeat rel) and T(int).
+func (c *Connection) releaseExpired() func() {
+	c.startTimer(&c.interval, c.cfg.IntervalTimeout, func() func() {
+		logf("release of connection %d not completed, freeing it", c.localRef)
+		stopTimer(&c.repeatRel)
+		c.state = StateIdle
+		c.rlsd = nil
+		return c.released(ErrReleaseTimeout)
+	})
+	return c.repeatRelease()
+}
+
+// repeatRelease sends the RLSD again, and restarts T(repeat rel).
+func (c *Connection) repeatRelease() func() {
+	c.startTimer(&c.repeatRel, c.cfg.RepeatReleaseTimeout, c.repeatRelease)
+
+	rlsd := c.rlsd
+	return func() {
+		if err := c.send(rlsd); err != nil {
+			logf("failed to send RLSD on connection %d: %v", c.localRef, err)
+		}
+	}
+}
+
+// stopRelease stops the timers of the release procedure.
+func (c *Connection) stopRelease() {
+	stopTimer(&c.release)
+	stopTimer(&c.repeatRel)
+	stopTimer(&c.interval)
+	c.rlsd = nil
+}
+
+// Handle handles the message received for the Connection.
+//
+// The messages that are not expected in the current state are discarded, and
+// the ones with the Destination Local Reference that does not match the local
+// reference result in error.
+func (c *Connection) Handle(m sccp.Message) error {
+	if dlr, ok := destinationLocalReference(m); ok && dlr != c.localRef {
+		return fmt.Errorf("%s for local reference %d: %w", m.MessageTypeName(), dlr, ErrReferenceMismatch)
+	}
+
+	c.mu.Lock()
+	if c.state == StateActive {
+		c.startTimer(&c.recvIdle, c.cfg.RecvInactivityTimeout, c.recvInactive)
+	}
+	reply, notify := c.handle(m)
+	if reply != nil && c.state == StateActive {
+		c.startTimer(&c.sendIdle, c.cfg.SendInactivityTimeout, c.sendInactive)
+	}
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if reply != nil {
+		if err := c.send(reply); err != nil {
+			return err
+		}
+	}
+	if notify != nil {
+		notify()
+	}
+
+	return nil
+}
+
+// handle runs the state transition on m with the lock held, and returns the
+// message to send and the function to notify the user, if any.
+func (c *Connection) handle(m sccp.Message) (sccp.Message, func()) {
+	switch c.state {
+	case StateIdle:
+		if cr, ok := m.(*sccp.CR); ok {
+			c.state = StateIncomingPending
+			c.remoteRef = cr.SourceLocalReference.Uint32()
+			c.class = cr.ProtocolClass.Class()
+			c.window.credit = c.cfg.Credit
+			if cr.Credit != nil {
+				c.window.credit = cr.Credit.Value()
+			}
+			if fn := c.events.ConnectRequest; fn != nil {
+				return nil, func() { fn(cr) }
+			}
+		}
+	case StateConnectionPending:
+		switch m := m.(type) {
+		case *sccp.CC:
+			stopTimer(&c.connEst)
+			c.state = StateActive
+			c.remoteRef = m.SourceLocalReference.Uint32()
+			c.class = m.ProtocolClass.Class()
+			credit := c.cfg.Credit
+			if m.Credit != nil {
+				credit = m.Credit.Value()
+			}
+			c.window.reset(credit)
+			c.edPending = false
+			c.startInactivity()
+			if fn := c.events.Connected; fn != nil {
+				return nil, func() { fn(m) }
+			}
+		case *sccp.CREF:
+			stopTimer(&c.connEst)
+			c.state = StateIdle
+			return nil, c.released(&RefusedError{Cause: m.RefusalCause.Value()})
+		case *sccp.RLSD:
+			stopTimer(&c.connEst)
+			c.state = StateIdle
+			rlc := sccp.NewRLC(m.SourceLocalReference.Uint32(), c.localRef)
+			return rlc, c.released(&ReleasedError{Cause: m.ReleaseCause.Value()})
+		}
+	case StateActive:
+		switch m := m.(type) {
+		case *sccp.DT1:
+			return c.reassemble(m.Data.Value(), m.SegmentingReassembling.More())
+		case *sccp.DT2:
+			return c.handleDT2(m)
+		case *sccp.AK:
+			return c.handleAK(m)
+		case *sccp.ED:
+			return c.handleED(m)
+		case *sccp.EA:
+			return c.handleEA(m)
+		case *sccp.RSR:
+			return c.handleRSR(m)
+		case *sccp.RLSD:
+			c.state = StateIdle
+			c.reassembly = nil
+			c.stopInactivity()
+			rlc := sccp.NewRLC(c.remoteRef, c.localRef)
+			return rlc, c.released(&ReleasedError{Cause: m.ReleaseCause.Value()})
+		case *sccp.IT:
+			return c.handleIT(m)
+		}
+	case StateResetPending:
+		return c.handleResetPending(m)
+	case StateDisconnectPending:
+		switch m.(type) {
+		case *sccp.RLC:
+			c.stopRelease()
+			c.state = StateIdle
+			return nil, c.released(c.reason)
+		case *sccp.RLSD:
+			// collision of the release from both sides.
+			c.stopRelease()
+			c.state = StateIdle
+			return sccp.NewRLC(c.remoteRef, c.localRef), c.released(c.reason)
+		}
+	}
+
+	return nil, nil
+}
+
+// reassemble appends data to the ones received before, and returns the
+// function to notify the user of the whole data when more (the M-bit) is not
+// set. The connection is released if the data exceeds
+// Config.MaxReassembledSize.
+func (c *Connection) reassemble(data []byte, more bool) (sccp.Message, func()) {
+	if len(c.reassembly)+len(data) > c.cfg.MaxReassembledSize {
+		err := fmt.Errorf("%d bytes received: %w", len(c.reassembly)+len(data), ErrReassemblyTooLarge)
+		return c.startRelease(params.ReleaseCauseRemoteProcedureError, err), nil
+	}
+
+	if more {
+		c.reassembly = append(c.reassembly, data...)
+		return nil, nil
+	}
+
+	if c.reassembly != nil {
+		data = append(c.reassembly, data...)
+		c.reassembly = nil
+	}
+
+	fn := c.events.Data
+	if fn == nil {
+		return nil, nil
+	}
+	return nil, func() { fn(data) }
+}
+
+// released returns the function that calls Events.Released with err.
+func (c *Connection) released(err error) func() {
+	fn := c.events.Released
+	if fn == nil {
+		return nil
+	}
+	return func() { fn(err) }
+}
+
+// reset stops the timers and puts the Connection back to the idle state
+// without notifying the user, e.g., when the message could not be sent.
+func (c *Connection) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stopTimer(&c.connEst)
+	stopTimer(&c.resetTimer)
+	c.stopRelease()
+	c.stopInactivity()
+	c.state = StateIdle
+	c.cond.Broadcast()
+}
+
+// startTimer starts the timer stored in slot, which calls expired with the
+// lock held if it is not stopped, and calls the returned function without it.
+//
+// The timer is not started if d is negative.
+func (c *Connection) startTimer(slot **time.Timer, d time.Duration, expired func() func()) {
+	stopTimer(slot)
+	if d < 0 {
+		return
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(d, func() {
+		c.mu.Lock()
+		if *slot != t {
+			c.mu.Unlock()
+			return
+		}
+		*slot = nil
+		notify := expired()
+		c.cond.Broadcast()
+		c.mu.Unlock()
+
+		if notify != nil {
+			notify()
+		}
+	})
+	*slot = t
+}
+
+// startInactivity starts T(ias) and T(iar) when the connection gets active.
+func (c *Connection) startInactivity() {
+	c.startTimer(&c.sendIdle, c.cfg.SendInactivityTimeout, c.sendInactive)
+	c.startTimer(&c.recvIdle, c.cfg.RecvInactivityTimeout, c.recvInactive)
+}
+
+func (c *Connection) stopInactivity() {
+	stopTimer(&c.sendIdle)
+	stopTimer(&c.recvIdle)
+}
+
+// sent restarts T(ias) after the message is sent on the active connection.
+func (c *Connection) sent() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateActive {
+		c.startTimer(&c.sendIdle, c.cfg.SendInactivityTimeout, c.sendInactive)
+	}
+}
+
+// sendInactive sends an IT on expiry of T(ias), and restarts it.
+func (c *Connection) sendInactive() func() {
+	if c.state != StateActive {
+		return nil
+	}
+
+	var ps, pr, credit uint8
+	if c.class == params.ClassFlowControlConnectionOriented {
+		ps, pr, credit = c.window.sendNext, c.window.recvNext, c.window.credit
+	}
+	it := sccp.NewIT(c.remoteRef, c.localRef, c.class, ps, pr, credit)
+	c.startTimer(&c.sendIdle, c.cfg.SendInactivityTimeout, c.sendInactive)
+
+	return func() {
+		if err := c.send(it); err != nil {
+			logf("failed to send IT on connection %d: %v", c.localRef, err)
+		}
+	}
+}
+
+// recvInactive releases the connection on expiry of T(iar).
+func (c *Connection) recvInactive() func() {
+	if c.state != StateActive {
+		return nil
+	}
+
+	rlsd := c.startRelease(params.ReleaseCauseExpirationOfReceiveInactivityTimer, ErrInactivityTimeout)
+	return func() {
+		if err := c.send(rlsd); err != nil {
+			logf("failed to send RLSD on connection %d: %v", c.localRef, err)
+		}
+	}
+}
+
+// handleIT checks the connection data in the IT received in the active state,
+// and releases the connection if it is inconsistent (see Q.714 3.4.3).
+func (c *Connection) handleIT(it *sccp.IT) (sccp.Message, func()) {
+	if it.SourceLocalReference.Uint32() != c.remoteRef || it.ProtocolClass.Class() != c.class {
+		err := fmt.Errorf("IT from %d in class %d: %w", it.SourceLocalReference.Uint32(), it.ProtocolClass.Class(), ErrInconsistentData)
+		return c.startRelease(params.ReleaseCauseInconsistentConnectionData, err), nil
+	}
+	return nil, nil
+}
+
+func stopTimer(slot **time.Timer) {
+	if *slot != nil {
+		(*slot).Stop()
+		*slot = nil
+	}
+}
+
+// destinationLocalReference returns the Destination Local Reference in m,
+// and reports whether m has it.
+func destinationLocalReference(m sccp.Message) (uint32, bool) {
+	var l *params.LocalReference
+	switch m := m.(type) {
+	case *sccp.CC:
+		l = m.DestinationLocalReference
+	case *sccp.CREF:
+		l = m.DestinationLocalReference
+	case *sccp.RLSD:
+		l = m.DestinationLocalReference
+	case *sccp.RLC:
+		l = m.DestinationLocalReference
+	case *sccp.DT1:
+		l = m.DestinationLocalReference
+	case *sccp.DT2:
+		l = m.DestinationLocalReference
+	case *sccp.AK:
+		l = m.DestinationLocalReference
+	case *sccp.ED:
+		l = m.DestinationLocalReference
+	case *sccp.EA:
+		l = m.DestinationLocalReference
+	case *sccp.RSR:
+		l = m.DestinationLocalReference
+	case *sccp.RSC:
+		l = m.DestinationLocalReference
+	case *sccp.IT:
+		l = m.DestinationLocalReference
+	}
+
+	if l == nil {
+		return 0, false
+	}
+	return l.Uint32(), true
+}
@@ -0,0 +1,815 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/scoc"
+)
+
+// recorder records the events of a Connection.
+type recorder struct {
+	requested bool
+	connected bool
+	data      [][]byte
+	resets    []params.ResetCauseValue
+	released  []error
+}
+
+func (r *recorder) events() scoc.Events {
+	return scoc.Events{
+		ConnectRequest: func(*sccp.CR) { r.requested = true },
+		Connected:      func(*sccp.CC) { r.connected = true },
+		Data:           func(data []byte) { r.data = append(r.data, data) },
+		Reset:          func(cause params.ResetCauseValue) { r.resets = append(r.resets, cause) },
+		Released:       func(err error) { r.released = append(r.released, err) },
+	}
+}
+
+// deliver returns the send function that delivers the messages to peer
+// through the encoding and decoding.
+func deliver[T interface{ Handle(sccp.Message) error }](t *testing.T, peer *T) func(sccp.Message) error {
+	t.Helper()
+
+	return func(m sccp.Message) error {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		decoded, err := sccp.ParseMessage(b)
+		if err != nil {
+			return err
+		}
+		return (*peer).Handle(decoded)
+	}
+}
+
+// pair creates two Connections a and b that send the messages to each other.
+func pair(t *testing.T) (a, b *scoc.Connection, ra, rb *recorder) {
+	t.Helper()
+
+	ra, rb = &recorder{}, &recorder{}
+	a = scoc.New(1, nil, deliver(t, &b), ra.events())
+	b = scoc.New(2, nil, deliver(t, &a), rb.events())
+	return a, b, ra, rb
+}
+
+func TestConnection(t *testing.T) {
+	a, b, ra, rb := pair(t)
+
+	if err := a.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if !rb.requested || b.State() != scoc.StateIncomingPending {
+		t.Fatalf("CR not handled: requested=%v, state=%s", rb.requested, b.State())
+	}
+
+	if err := b.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if !ra.connected || a.State() != scoc.StateActive || b.State() != scoc.StateActive {
+		t.Fatalf("not connected: a=%s, b=%s", a.State(), b.State())
+	}
+	if got, want := a.RemoteReference(), b.LocalReference(); got != want {
+		t.Errorf("got remote reference %d, want %d", got, want)
+	}
+
+	if err := a.Send([]byte{0xde, 0xad}); err != nil {
+		t.Fatal(err)
+	}
+	if !verify.Values(t, "data", rb.data, [][]byte{{0xde, 0xad}}) {
+		t.Fail()
+	}
+
+	if err := a.Release(params.ReleaseCauseEndUserOriginated); err != nil {
+		t.Fatal(err)
+	}
+	if a.State() != scoc.StateIdle || b.State() != scoc.StateIdle {
+		t.Fatalf("not released: a=%s, b=%s", a.State(), b.State())
+	}
+
+	var rerr *scoc.ReleasedError
+	if len(rb.released) != 1 || !errors.As(rb.released[0], &rerr) || rerr.Cause != params.ReleaseCauseEndUserOriginated {
+		t.Errorf("got %v on the released side", rb.released)
+	}
+	if !verify.Values(t, "released", ra.released, []error{nil}) {
+		t.Fail()
+	}
+}
+
+func TestConnectionRefused(t *testing.T) {
+	a, b, ra, _ := pair(t)
+
+	if err := a.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Refuse(params.RefusalCauseUnequippedUser); err != nil {
+		t.Fatal(err)
+	}
+
+	var rerr *scoc.RefusedError
+	if len(ra.released) != 1 || !errors.As(ra.released[0], &rerr) || rerr.Cause != params.RefusalCauseUnequippedUser {
+		t.Errorf("got %v", ra.released)
+	}
+	if a.State() != scoc.StateIdle || b.State() != scoc.StateIdle {
+		t.Errorf("not idle: a=%s, b=%s", a.State(), b.State())
+	}
+}
+
+func TestConnectionObserver(t *testing.T) {
+	var refs []uint32
+	obs := &recorder{}
+	cfg := &scoc.Config{Observer: func(localRef uint32) scoc.Events {
+		refs = append(refs, localRef)
+		return obs.events()
+	}}
+
+	var a, b *scoc.Connection
+	ra, rb := &recorder{}, &recorder{}
+	a = scoc.New(1, cfg, deliver(t, &b), ra.events())
+	b = scoc.New(2, nil, deliver(t, &a), rb.events())
+
+	if err := a.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Release(params.ReleaseCauseEndUserOriginated); err != nil {
+		t.Fatal(err)
+	}
+
+	if !verify.Values(t, "local references", refs, []uint32{1}) {
+		t.Fail()
+	}
+	if !obs.connected || !ra.connected {
+		t.Errorf("Connected not called on both: observer=%v, events=%v", obs.connected, ra.connected)
+	}
+	if !verify.Values(t, "released", [][]error{obs.released, ra.released}, [][]error{{nil}, {nil}}) {
+		t.Fail()
+	}
+}
+
+func TestConnectionTimeout(t *testing.T) {
+	released := make(chan error, 1)
+	c := scoc.New(
+		1,
+		&scoc.Config{ConnEstTimeout: 10 * time.Millisecond},
+		func(sccp.Message) error { return nil },
+		scoc.Events{Released: func(err error) { released <- err }},
+	)
+
+	if err := c.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-released:
+		if !errors.Is(err, scoc.ErrConnectionTimeout) {
+			t.Errorf("got %v, want %v", err, scoc.ErrConnectionTimeout)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("T(conn est) not expired")
+	}
+
+	if got := c.State(); got != scoc.StateIdle {
+		t.Errorf("got %s, want idle", got)
+	}
+}
+
+func TestConnectionInvalid(t *testing.T) {
+	c := scoc.New(1, nil, func(sccp.Message) error { return nil }, scoc.Events{})
+
+	if err := c.Send([]byte{0}); !errors.Is(err, scoc.ErrInvalidState) {
+		t.Errorf("Send in idle: got %v", err)
+	}
+	if err := c.Handle(sccp.NewRLC(2, 3)); !errors.Is(err, scoc.ErrReferenceMismatch) {
+		t.Errorf("RLC to another reference: got %v", err)
+	}
+}
+
+func TestRefAllocator(t *testing.T) {
+	a := scoc.NewRefAllocator(20 * time.Millisecond)
+	a.SetRange(10, 11)
+
+	r1, err := a.Allocate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := a.Allocate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1 == r2 || r1 < 10 || r1 > 11 || r2 < 10 || r2 > 11 {
+		t.Fatalf("got %d and %d, want 10 and 11", r1, r2)
+	}
+	if _, err := a.Allocate(); !errors.Is(err, scoc.ErrNoReference) {
+		t.Fatalf("got %v, want %v", err, scoc.ErrNoReference)
+	}
+
+	a.Release(r1)
+	if a.InUse(r1) {
+		t.Errorf("%d still in use", r1)
+	}
+	if _, err := a.Allocate(); !errors.Is(err, scoc.ErrNoReference) {
+		t.Fatalf("frozen reference allocated: got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	got, err := a.Allocate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != r1 {
+		t.Errorf("got %d, want %d", got, r1)
+	}
+}
+
+func TestRefAllocatorSeed(t *testing.T) {
+	allocate := func() []uint32 {
+		a := scoc.NewRefAllocator(0)
+		a.Seed(42)
+
+		var refs []uint32
+		for range 5 {
+			ref, err := a.Allocate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			refs = append(refs, ref)
+		}
+		return refs
+	}
+
+	if !verify.Values(t, "refs", allocate(), allocate()) {
+		t.Fail()
+	}
+}
+
+func TestService(t *testing.T) {
+	var a, b *scoc.Service
+	a = scoc.NewService(nil, deliver(t, &b))
+	b = scoc.NewService(nil, deliver(t, &a))
+
+	ctx := context.Background()
+	if _, err := a.Connect(ctx, params.NewSSNAddress(8)); !errors.As(err, new(*scoc.RefusedError)) {
+		t.Fatalf("connect without listener: got %v", err)
+	}
+
+	l, err := b.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ca, err := a.Connect(ctx, params.NewSSNAddress(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ca.RemoteReference(), cb.LocalReference(); got != want {
+		t.Errorf("got remote reference %d, want %d", got, want)
+	}
+
+	if _, err := ca.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 3)
+	for _, want := range []string{"hel", "lo"} {
+		n, err := cb.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+
+	if err := ca.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cb.Read(buf); err != io.EOF {
+		t.Errorf("Read after release: got %v, want EOF", err)
+	}
+	if _, err := ca.Write([]byte{0}); !errors.Is(err, scoc.ErrConnClosed) {
+		t.Errorf("Write after Close: got %v", err)
+	}
+	if a.RefAllocator().InUse(ca.LocalReference()) || b.RefAllocator().InUse(cb.LocalReference()) {
+		t.Error("local reference not released")
+	}
+
+	l.Close()
+	if _, err := l.Accept(); !errors.Is(err, scoc.ErrListenerClosed) {
+		t.Errorf("Accept after Close: got %v", err)
+	}
+}
+
+func TestSegmenting(t *testing.T) {
+	var (
+		a, b   *scoc.Connection
+		ra, rb = &recorder{}, &recorder{}
+		dt1s   int
+	)
+	toB := deliver(t, &b)
+	a = scoc.New(1, &scoc.Config{SegmentSize: 4}, func(m sccp.Message) error {
+		if _, ok := m.(*sccp.DT1); ok {
+			dt1s++
+		}
+		return toB(m)
+	}, ra.events())
+	b = scoc.New(2, &scoc.Config{MaxReassembledSize: 12}, deliver(t, &a), rb.events())
+
+	if err := a.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("0123456789")
+	if err := a.Send(data); err != nil {
+		t.Fatal(err)
+	}
+	if dt1s != 3 {
+		t.Errorf("got %d DT1s, want 3", dt1s)
+	}
+	if !verify.Values(t, "data", rb.data, [][]byte{data}) {
+		t.Fail()
+	}
+
+	// exceeds MaxReassembledSize in b.
+	if err := a.Send(append(data, data...)); err != nil {
+		t.Fatal(err)
+	}
+	if len(rb.released) != 1 || !errors.Is(rb.released[0], scoc.ErrReassemblyTooLarge) {
+		t.Errorf("got %v, want %v", rb.released, scoc.ErrReassemblyTooLarge)
+	}
+	if a.State() != scoc.StateIdle || b.State() != scoc.StateIdle {
+		t.Errorf("not released: a=%s, b=%s", a.State(), b.State())
+	}
+}
+
+func TestFlowControl(t *testing.T) {
+	var (
+		a, b   *scoc.Connection
+		ra, rb = &recorder{}, &recorder{}
+		aks    int
+	)
+	toA := deliver(t, &a)
+	a = scoc.New(1, &scoc.Config{Credit: 4}, deliver(t, &b), ra.events())
+	b = scoc.New(2, &scoc.Config{Credit: 2}, func(m sccp.Message) error {
+		if _, ok := m.(*sccp.AK); ok {
+			aks++
+		}
+		return toA(m)
+	}, rb.events())
+
+	if err := a.Connect(params.NewSSNAddress(8), 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	var want [][]byte
+	for i := range 5 {
+		data := []byte{byte(i)}
+		if err := a.Send(data); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, data)
+	}
+	if !verify.Values(t, "data", rb.data, want) {
+		t.Fail()
+	}
+	if aks != 5 {
+		t.Errorf("got %d AKs, want 5", aks)
+	}
+
+	// P(S) out of window resets the connection.
+	if err := b.Handle(sccp.NewDT2(b.LocalReference(), 9, 0, false, []byte{0})); err != nil {
+		t.Fatal(err)
+	}
+	cause := params.ResetCauseRemoteProcedureErrorMessageOutOfWindow
+	if !verify.Values(t, "resets", [][]params.ResetCauseValue{ra.resets, rb.resets}, [][]params.ResetCauseValue{{cause}, {cause}}) {
+		t.Fail()
+	}
+	if a.State() != scoc.StateActive || b.State() != scoc.StateActive {
+		t.Fatalf("not active after reset: a=%s, b=%s", a.State(), b.State())
+	}
+
+	// the sequence numbers start from 0 again.
+	if err := a.Send([]byte{5}); err != nil {
+		t.Fatal(err)
+	}
+	if !verify.Values(t, "data", rb.data, append(want, []byte{5})) {
+		t.Fail()
+	}
+}
+
+func TestReset(t *testing.T) {
+	var (
+		a, b   *scoc.Connection
+		ra, rb = &recorder{}, &recorder{}
+		toB    = deliver(t, &b)
+		held   []sccp.Message
+	)
+	// holds the messages to b to make the resets collide.
+	hold := false
+	a = scoc.New(1, nil, func(m sccp.Message) error {
+		if hold {
+			held = append(held, m)
+			return nil
+		}
+		return toB(m)
+	}, ra.events())
+	b = scoc.New(2, nil, deliver(t, &a), rb.events())
+
+	if err := a.Connect(params.NewSSNAddress(8), 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Reset(params.ResetCauseEndUserOriginated); err != nil {
+		t.Fatal(err)
+	}
+	if a.State() != scoc.StateActive || !verify.Values(t, "resets", rb.resets, []params.ResetCauseValue{params.ResetCauseEndUserOriginated}) {
+		t.Fatalf("reset not completed: a=%s", a.State())
+	}
+
+	hold = true
+	if err := a.Reset(params.ResetCauseEndUserOriginated); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Reset(params.ResetCauseSCCPUserOriginated); err != nil {
+		t.Fatal(err)
+	}
+	hold = false
+	for _, m := range held {
+		if err := toB(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.State() != scoc.StateActive || b.State() != scoc.StateActive {
+		t.Fatalf("collision not resolved: a=%s, b=%s", a.State(), b.State())
+	}
+}
+
+func TestInactivity(t *testing.T) {
+	var (
+		a, b     *scoc.Connection
+		its      atomic.Int32
+		released = make(chan error, 2)
+	)
+	cfg := &scoc.Config{
+		SendInactivityTimeout: 10 * time.Millisecond,
+		RecvInactivityTimeout: 50 * time.Millisecond,
+	}
+	events := scoc.Events{Released: func(err error) { released <- err }}
+
+	toB := deliver(t, &b)
+	a = scoc.New(1, cfg, func(m sccp.Message) error {
+		if _, ok := m.(*sccp.IT); ok {
+			its.Add(1)
+		}
+		return toB(m)
+	}, events)
+	b = scoc.New(2, cfg, deliver(t, &a), events)
+
+	if err := a.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// kept alive by the ITs.
+	time.Sleep(150 * time.Millisecond)
+	if a.State() != scoc.StateActive || b.State() != scoc.StateActive {
+		t.Fatalf("released while IT sent: a=%s, b=%s", a.State(), b.State())
+	}
+	if its.Load() == 0 {
+		t.Error("no IT sent")
+	}
+
+	// the peer that goes silent, except for the RLC.
+	var c *scoc.Connection
+	c = scoc.New(3, cfg, func(m sccp.Message) error {
+		if _, ok := m.(*sccp.RLSD); ok {
+			return c.Handle(sccp.NewRLC(3, 4))
+		}
+		return nil
+	}, events)
+	if err := c.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Handle(sccp.NewCC(3, 4, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-released:
+		if !errors.Is(err, scoc.ErrInactivityTimeout) {
+			t.Errorf("got %v, want %v", err, scoc.ErrInactivityTimeout)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("T(iar) not expired")
+	}
+}
+
+func TestReleaseTimeout(t *testing.T) {
+	var (
+		rlsds    atomic.Int32
+		released = make(chan error, 1)
+	)
+	c := scoc.New(
+		1,
+		&scoc.Config{
+			ReleaseTimeout:       10 * time.Millisecond,
+			RepeatReleaseTimeout: 10 * time.Millisecond,
+			IntervalTimeout:      100 * time.Millisecond,
+		},
+		func(m sccp.Message) error {
+			if _, ok := m.(*sccp.RLSD); ok {
+				rlsds.Add(1)
+			}
+			return nil
+		},
+		scoc.Events{Released: func(err error) { released <- err }},
+	)
+
+	if err := c.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Handle(sccp.NewCC(1, 2, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Release(params.ReleaseCauseEndUserOriginated); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-released:
+		if !errors.Is(err, scoc.ErrReleaseTimeout) {
+			t.Errorf("got %v, want %v", err, scoc.ErrReleaseTimeout)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("T(int) not expired")
+	}
+
+	// the first one, the one on T(rel) and the repeated ones.
+	if n := rlsds.Load(); n < 3 {
+		t.Errorf("got %d RLSDs, want 3 or more", n)
+	}
+	if got := c.State(); got != scoc.StateIdle {
+		t.Errorf("got %s, want idle", got)
+	}
+}
+
+func TestExpedited(t *testing.T) {
+	var a, b *scoc.Service
+	cfg := &scoc.Config{ProtocolClass: 3}
+	a = scoc.NewService(cfg, deliver(t, &b))
+	b = scoc.NewService(cfg, deliver(t, &a))
+
+	l, err := b.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ca, err := a.Connect(context.Background(), params.NewSSNAddress(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second one is sent after the EA for the first one.
+	for _, data := range []string{"urgent", "again"} {
+		if err := ca.SendExpedited([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := cb.ReceiveExpedited()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != data {
+			t.Errorf("got %q, want %q", got, data)
+		}
+	}
+
+	if err := ca.SendExpedited(make([]byte, 33)); !errors.Is(err, scoc.ErrExpeditedDataSize) {
+		t.Errorf("33 octets: got %v", err)
+	}
+
+	if err := ca.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cb.ReceiveExpedited(); err != io.EOF {
+		t.Errorf("ReceiveExpedited after release: got %v, want EOF", err)
+	}
+}
+
+func TestExpeditedClass2(t *testing.T) {
+	a, b, _, _ := pair(t)
+
+	if err := a.Connect(params.NewSSNAddress(8), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SendExpedited([]byte{0}); !errors.Is(err, scoc.ErrInvalidState) {
+		t.Errorf("got %v, want %v", err, scoc.ErrInvalidState)
+	}
+}
+
+func TestExpeditedOutstanding(t *testing.T) {
+	var eds atomic.Int32
+	c := scoc.New(1, nil, func(m sccp.Message) error {
+		if _, ok := m.(*sccp.ED); ok {
+			eds.Add(1)
+		}
+		return nil
+	}, scoc.Events{})
+
+	if err := c.Connect(params.NewSSNAddress(8), 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Handle(sccp.NewCC(1, 2, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendExpedited([]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make(chan error, 1)
+	go func() { sent <- c.SendExpedited([]byte{2}) }()
+
+	time.Sleep(20 * time.Millisecond)
+	if n := eds.Load(); n != 1 {
+		t.Fatalf("got %d EDs before EA, want 1", n)
+	}
+
+	if err := c.Handle(sccp.NewEA(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+	if n := eds.Load(); n != 2 {
+		t.Errorf("got %d EDs after EA, want 2", n)
+	}
+}
+
+func TestListenSSN(t *testing.T) {
+	var a, b *scoc.Service
+	a = scoc.NewService(nil, deliver(t, &b))
+	b = scoc.NewService(nil, deliver(t, &a))
+
+	fallback, err := b.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fallback.Close()
+	l8, err := b.ListenSSN(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ListenSSN(8); !errors.Is(err, scoc.ErrListenerExists) {
+		t.Errorf("second listener for SSN 8: got %v", err)
+	}
+
+	accept := func(l *scoc.Listener, ssn uint8) {
+		t.Helper()
+
+		ca, err := a.Connect(context.Background(), params.NewSSNAddress(ssn))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cb, err := l.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := cb.RemoteReference(), ca.LocalReference(); got != want {
+			t.Errorf("SSN %d: got connection from %d, want %d", ssn, got, want)
+		}
+	}
+
+	accept(l8, 8)
+	accept(fallback, 9)
+
+	// falls back to the one for any SSN.
+	l8.Close()
+	accept(fallback, 8)
+}
+
+func TestServiceRefusal(t *testing.T) {
+	var a, b *scoc.Service
+	a = scoc.NewService(nil, deliver(t, &b))
+	b = scoc.NewService(nil, deliver(t, &a))
+
+	ctx := context.Background()
+	refused := func(ssn uint8, want params.RefusalCauseValue, opts ...params.Parameter) {
+		t.Helper()
+
+		_, err := a.Connect(ctx, params.NewSSNAddress(ssn), opts...)
+		var rerr *scoc.RefusedError
+		if !errors.As(err, &rerr) || rerr.Cause != want {
+			t.Errorf("SSN %d: got %v, want refusal cause %d", ssn, err, want)
+		}
+	}
+
+	// no listener.
+	refused(8, params.RefusalCauseUnequippedUser)
+
+	l, err := b.ListenSSN(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// application refusal.
+	l.SetFilter(func(cr *sccp.CR) error {
+		if cr.Data != nil {
+			return &scoc.RefusedError{Cause: params.RefusalCauseIncompatibleUserData}
+		}
+		return errors.New("not now")
+	})
+	refused(8, params.RefusalCauseIncompatibleUserData, params.NewDataOptional([]byte{0}))
+	refused(8, params.RefusalCauseEndUserOriginated)
+	l.SetFilter(nil)
+
+	// backlog full.
+	for range scoc.DefaultBacklog {
+		if _, err := a.Connect(ctx, params.NewSSNAddress(8)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refused(8, params.RefusalCauseEndUserCongestion)
+
+	// no local reference.
+	b.RefAllocator().SetRange(1, 1)
+	if _, err := b.RefAllocator().Allocate(); err != nil {
+		t.Fatal(err)
+	}
+	refused(8, params.RefusalCauseNetworkResourceQoSNotAvailableTransient)
+}
+
+func TestListenerCloseRace(t *testing.T) {
+	var a, b *scoc.Service
+	a = scoc.NewService(nil, deliver(t, &b))
+	b = scoc.NewService(nil, deliver(t, &a))
+
+	l, err := b.ListenSSN(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the filter holds the CR until the Listener is closed.
+	entered, release := make(chan struct{}), make(chan struct{})
+	l.SetFilter(func(*sccp.CR) error {
+		close(entered)
+		<-release
+		return nil
+	})
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := a.Connect(context.Background(), params.NewSSNAddress(8))
+		errc <- err
+	}()
+
+	<-entered
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	var rerr *scoc.RefusedError
+	if err := <-errc; !errors.As(err, &rerr) || rerr.Cause != params.RefusalCauseUnequippedUser {
+		t.Errorf("got %v, want refusal cause %d", err, params.RefusalCauseUnequippedUser)
+	}
+	if _, err := l.Accept(); !errors.Is(err, scoc.ErrListenerClosed) {
+		t.Errorf("got %v, want ErrListenerClosed", err)
+	}
+}
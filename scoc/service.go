@@ -0,0 +1,336 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DefaultBacklog is the number of the incoming connections a Listener holds
+// until they are accepted.
+const DefaultBacklog = 16
+
+// Service manages the connections of a signalling point, so that the
+// applications can use them with Connect and Listen without driving the
+// state machine of each Connection.
+//
+// Like Connection, it does not implement any transport; the messages are
+// handed to the send function, and the received connection-oriented messages
+// should be given to Handle.
+//
+// Service is safe for concurrent use.
+type Service struct {
+	send func(sccp.Message) error
+	cfg  *Config
+	refs *RefAllocator
+
+	mu        sync.Mutex
+	conns     map[uint32]*Conn
+	listener  *Listener
+	listeners map[uint8]*Listener
+}
+
+// NewService creates a new Service. cfg may be nil to use the default values.
+func NewService(cfg *Config, send func(sccp.Message) error) *Service {
+	return &Service{
+		send:      send,
+		cfg:       cfg,
+		refs:      NewRefAllocator(0),
+		conns:     map[uint32]*Conn{},
+		listeners: map[uint8]*Listener{},
+	}
+}
+
+// RefAllocator returns the RefAllocator the Service allocates the local
+// references with, e.g., to seed it or to set the range.
+func (s *Service) RefAllocator() *RefAllocator {
+	return s.refs
+}
+
+// Connect establishes a connection of the protocol class in Config, which is 2
+// by default, to cdpa, and returns it when the CC is received. The optional parameters of the CR can
+// be given as opts.
+//
+// If ctx is done before the CC is received, the connection is released as
+// soon as it is received, and the error of ctx is returned.
+func (s *Service) Connect(ctx context.Context, cdpa *params.PartyAddress, opts ...params.Parameter) (*Conn, error) {
+	c, err := s.newConn()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.conn.Connect(cdpa, s.cfg.withDefaults().ProtocolClass, opts...); err != nil {
+		s.remove(c.LocalReference())
+		return nil, err
+	}
+
+	select {
+	case <-c.established:
+		return c, nil
+	case <-c.done:
+		return nil, c.err
+	case <-ctx.Done():
+		_ = c.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// Listen returns the Listener that accepts the incoming connections to any
+// subsystem that has no Listener by ListenSSN. Only one such Listener can be
+// active at a time.
+func (s *Service) Listen() (*Listener, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return nil, ErrListenerExists
+	}
+
+	s.listener = newListener(s, 0, false)
+	return s.listener, nil
+}
+
+// ListenSSN returns the Listener that accepts the incoming connections whose
+// Called Party Address has the Subsystem Number ssn. Only one Listener can be
+// active for each ssn at a time.
+func (s *Service) ListenSSN(ssn uint8) (*Listener, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.listeners[ssn]; ok {
+		return nil, fmt.Errorf("SSN %d: %w", ssn, ErrListenerExists)
+	}
+
+	l := newListener(s, ssn, true)
+	s.listeners[ssn] = l
+	return l, nil
+}
+
+// listenerFor returns the Listener for the connection requested by cr, or
+// nil if there is none.
+func (s *Service) listenerFor(cr *sccp.CR) *Listener {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cdpa := cr.CalledPartyAddress; cdpa != nil && cdpa.HasSSN() {
+		if l, ok := s.listeners[cdpa.SubsystemNumber]; ok {
+			return l
+		}
+	}
+	return s.listener
+}
+
+// Handle handles the connection-oriented message received by the signalling
+// point. A CR creates a new connection for the Listener of the Called Party
+// SSN, and the other messages are given to the connection identified by the
+// Destination Local Reference.
+//
+// The CR is refused with CREF when no local reference is available, there is
+// no Listener for it, the backlog of the Listener is full or the filter of the
+// Listener rejects it.
+func (s *Service) Handle(m sccp.Message) error {
+	if cr, ok := m.(*sccp.CR); ok {
+		c, err := s.newConn()
+		if err != nil {
+			logf("refusing CR from %d: %v", cr.SourceLocalReference.Uint32(), err)
+			cref := sccp.NewCREF(cr.SourceLocalReference.Uint32(), params.RefusalCauseNetworkResourceQoSNotAvailableTransient)
+			return s.send(cref)
+		}
+		return c.conn.Handle(m)
+	}
+
+	dlr, ok := destinationLocalReference(m)
+	if !ok {
+		return fmt.Errorf("%s is not a connection-oriented message: %w", m.MessageTypeName(), ErrUnexpectedMessage)
+	}
+
+	s.mu.Lock()
+	c, ok := s.conns[dlr]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%s for local reference %d: %w", m.MessageTypeName(), dlr, ErrUnknownReference)
+	}
+
+	return c.conn.Handle(m)
+}
+
+// newConn allocates a local reference and registers a new Conn with it.
+func (s *Service) newConn() (*Conn, error) {
+	ref, err := s.refs.Allocate()
+	if err != nil {
+		return nil, err
+	}
+
+	c := newConn(s, ref)
+
+	s.mu.Lock()
+	s.conns[ref] = c
+	s.mu.Unlock()
+
+	return c, nil
+}
+
+// remove unregisters the Conn and releases the local reference.
+func (s *Service) remove(ref uint32) {
+	s.mu.Lock()
+	delete(s.conns, ref)
+	s.mu.Unlock()
+
+	s.refs.Release(ref)
+}
+
+// incoming accepts the connection requested by cr and queues it to the
+// Listener, or refuses it with the cause for the reason.
+func (s *Service) incoming(c *Conn, cr *sccp.CR) {
+	l := s.listenerFor(cr)
+	if l == nil {
+		s.refuse(c, params.RefusalCauseUnequippedUser)
+		return
+	}
+
+	if filter := l.filterFunc(); filter != nil {
+		if err := filter(cr); err != nil {
+			cause := params.RefusalCauseEndUserOriginated
+			var rerr *RefusedError
+			if errors.As(err, &rerr) {
+				cause = rerr.Cause
+			}
+			s.refuse(c, cause)
+			return
+		}
+	}
+
+	// the lock keeps the backlog from filling up between the check and the
+	// queueing, and the Listener from being closed in between, so that the
+	// CC is not sent for the connection not queued or never drained.
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		s.refuse(c, params.RefusalCauseUnequippedUser)
+		return
+	default:
+	}
+
+	if len(l.conns) == cap(l.conns) {
+		logf("backlog full, refusing connection %d", c.LocalReference())
+		s.refuse(c, params.RefusalCauseEndUserCongestion)
+		return
+	}
+
+	if err := c.conn.Accept(); err != nil {
+		logf("failed to accept connection %d: %v", c.LocalReference(), err)
+		s.remove(c.LocalReference())
+		return
+	}
+	l.conns <- c
+}
+
+// refuse refuses the connection requested to c with the cause.
+func (s *Service) refuse(c *Conn, cause params.RefusalCauseValue) {
+	if err := c.conn.Refuse(cause); err != nil {
+		logf("failed to refuse connection %d: %v", c.LocalReference(), err)
+	}
+	s.remove(c.LocalReference())
+}
+
+// Listener accepts the incoming connections of a Service.
+type Listener struct {
+	svc   *Service
+	conns chan *Conn
+	ssn   uint8
+	bySSN bool
+
+	// mu guards filter and serializes the queueing to conns with Close.
+	mu     sync.Mutex
+	filter func(cr *sccp.CR) error
+
+	once sync.Once
+	done chan struct{}
+}
+
+func newListener(svc *Service, ssn uint8, bySSN bool) *Listener {
+	return &Listener{
+		svc:   svc,
+		conns: make(chan *Conn, DefaultBacklog),
+		ssn:   ssn,
+		bySSN: bySSN,
+		done:  make(chan struct{}),
+	}
+}
+
+// SetFilter sets the function that decides whether to accept the connection
+// requested by cr before it is queued. If it returns an error, the connection
+// is refused with CREF; the cause is the one in RefusedError if the error is
+// (or wraps) it, or RefusalCauseEndUserOriginated otherwise.
+func (l *Listener) SetFilter(filter func(cr *sccp.CR) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.filter = filter
+}
+
+func (l *Listener) filterFunc() func(cr *sccp.CR) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.filter
+}
+
+// Accept waits for and returns the next incoming connection. It returns
+// ErrListenerClosed after the Listener is closed.
+func (l *Listener) Accept() (*Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, ErrListenerClosed
+	}
+}
+
+// Close stops accepting the connections. The connections that are not
+// accepted yet are released.
+func (l *Listener) Close() error {
+	l.once.Do(func() {
+		l.svc.mu.Lock()
+		if l.bySSN {
+			if l.svc.listeners[l.ssn] == l {
+				delete(l.svc.listeners, l.ssn)
+			}
+		} else if l.svc.listener == l {
+			l.svc.listener = nil
+		}
+		l.svc.mu.Unlock()
+
+		// the connections are released after unlocking, as the RLSD may
+		// be handled synchronously by the peer.
+		var pending []*Conn
+		l.mu.Lock()
+		close(l.done)
+	drain:
+		for {
+			select {
+			case c := <-l.conns:
+				pending = append(pending, c)
+			default:
+				break drain
+			}
+		}
+		l.mu.Unlock()
+
+		for _, c := range pending {
+			_ = c.Close()
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,326 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package scrc provides the SCCP routing control (SCRC) defined in section 2 of
+Q.714, which decides where the connectionless messages are routed.
+
+It does not implement any transport. Router.Route returns a Decision, which
+is delivering the message to the local subsystem, forwarding it to another
+signalling point, or returning or discarding it with the Return Cause, and
+the caller is responsible for carrying it out. RoutingTable keeps the route
+to each DPC and SSN with their availability, which can be used by both.
+*/
+package scrc
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// Action is what to do with the message routed.
+type Action uint8
+
+// Action definitions.
+const (
+	// ActionDeliver delivers the message to the local subsystem.
+	ActionDeliver Action = iota
+	// ActionForward forwards the message to the DPC.
+	ActionForward
+	// ActionReturn returns the message to the originator in UDTS or XUDTS
+	// with the Return Cause, as the return option is set.
+	ActionReturn
+	// ActionDiscard discards the message, as the return option is not set.
+	ActionDiscard
+)
+
+var actionNames = [...]string{
+	ActionDeliver: "deliver",
+	ActionForward: "forward",
+	ActionReturn:  "return",
+	ActionDiscard: "discard",
+}
+
+// String returns the name of the Action.
+func (a Action) String() string {
+	if int(a) < len(actionNames) {
+		return actionNames[a]
+	}
+	return fmt.Sprintf("Action(%d)", uint8(a))
+}
+
+// Translator translates the GT in the Called Party Address, which is
+// implemented by *gtt.Table.
+type Translator interface {
+	TranslateSLS(addr *params.PartyAddress, sls uint8) (*gtt.Result, error)
+}
+
+// Config is the local configuration of the signalling point used by Router.
+type Config struct {
+	// PointCodes are the PCs of the signalling point. The first one is used
+	// as the DPC of the messages delivered locally.
+	PointCodes []params.PointCode
+	// SSNs are the local subsystems. The messages for the other SSNs are
+	// returned with "unequipped user".
+	SSNs []uint8
+	// Translator is used for the messages routed on GT. The messages are
+	// returned with "no translation for an address of such nature" if nil.
+	Translator Translator
+	// Available reports whether the subsystem ssn at pc is available, where
+	// ssn is 0 for the signalling point itself. All of them are considered
+	// available if nil.
+	Available func(pc params.PointCode, ssn uint8) bool
+}
+
+// Router decides the routing of the connectionless messages.
+//
+// Router is immutable after it is created, and is safe for concurrent use as
+// long as the Translator and Available in the Config are.
+type Router struct {
+	cfg   Config
+	local map[params.PointCode]bool
+	ssns  map[uint8]bool
+}
+
+// New creates a new Router with cfg.
+func New(cfg Config) *Router {
+	r := &Router{cfg: cfg, local: map[params.PointCode]bool{}, ssns: map[uint8]bool{}}
+	for _, pc := range cfg.PointCodes {
+		r.local[pc] = true
+	}
+	for _, ssn := range cfg.SSNs {
+		r.ssns[ssn] = true
+	}
+
+	return r
+}
+
+// Decision is the result of the routing.
+type Decision struct {
+	Action Action
+	// DPC is the PC to forward the message to, or the local PC the message
+	// is delivered at.
+	DPC params.PointCode
+	// SSN is the local subsystem to deliver the message to.
+	SSN uint8
+	// CalledPartyAddress is the Called Party Address to put in the message
+	// forwarded, which is translated if the message is routed on GT.
+	CalledPartyAddress *params.PartyAddress
+	// HopCounter is the Hop Counter to put in the XUDT forwarded, which is
+	// decremented by the GTT.
+	HopCounter uint8
+	// Cause is the reason why the message is returned or discarded.
+	Cause params.ReturnCauseValue
+	// Translation is the result of the GTT if the message is routed on GT.
+	Translation *gtt.Result
+}
+
+// String returns the Decision in a human-readable format.
+func (d *Decision) String() string {
+	switch d.Action {
+	case ActionDeliver:
+		return fmt.Sprintf("{Action: %s, DPC: %s, SSN: %d}", d.Action, d.DPC, d.SSN)
+	case ActionForward:
+		return fmt.Sprintf("{Action: %s, DPC: %s, CalledPartyAddress: %v}", d.Action, d.DPC, d.CalledPartyAddress)
+	default:
+		return fmt.Sprintf("{Action: %s, Cause: %s}", d.Action, d.Cause)
+	}
+}
+
+// Message returns the message to send for m, which must be the message given
+// to Route. For ActionForward, it is a copy of m with the CalledPartyAddress
+// and the HopCounter of the Decision, keeping the optional parameters
+// including the ones unknown to this package. For ActionReturn, it is the UDTS or
+// XUDTS with the Cause created by sccp.NewServiceMessage.
+func (d *Decision) Message(m sccp.Message) (sccp.Message, error) {
+	switch d.Action {
+	case ActionForward:
+	case ActionReturn:
+		return sccp.NewServiceMessage(m, d.Cause)
+	default:
+		return nil, fmt.Errorf("scrc: no message to send to %s", d.Action)
+	}
+
+	switch m := m.(type) {
+	case *sccp.UDT:
+		pc := m.ProtocolClass
+		return sccp.NewUDT(pc.Class(), pc.ReturnOnError(), d.CalledPartyAddress, m.CallingPartyAddress.Clone(), m.LoadData().Clone().Value()), nil
+	case *sccp.XUDT:
+		var opts []params.Parameter
+		if m.Segmentation != nil {
+			opts = append(opts, m.Segmentation)
+		}
+		if m.Importance != nil {
+			opts = append(opts, m.Importance)
+		}
+		if m.ISNI != nil {
+			opts = append(opts, m.ISNI)
+		}
+		for _, u := range m.UnknownParameters {
+			opts = append(opts, u.Clone())
+		}
+		pc := m.ProtocolClass
+		return sccp.NewXUDT(pc.Class(), pc.ReturnOnError(), d.HopCounter, d.CalledPartyAddress, m.CallingPartyAddress.Clone(), m.LoadData().Clone().Value(), opts...), nil
+	default:
+		return nil, sccp.UnsupportedTypeError(m.MessageType())
+	}
+}
+
+// Route decides the routing of m, which is UDT or XUDT, following Q.714 2.2
+// and 2.3. sls is given to the Translator for the load sharing.
+//
+// It returns the error only if m is not supported or malformed; the failure
+// of the routing is returned as the Decision with ActionReturn or
+// ActionDiscard.
+func (r *Router) Route(m sccp.Message, sls uint8) (*Decision, error) {
+	var (
+		cdpa  *params.PartyAddress
+		class *params.ProtocolClass
+		hc    *params.HopCounter
+	)
+	switch m := m.(type) {
+	case *sccp.UDT:
+		cdpa, class = m.CalledPartyAddress, m.ProtocolClass
+	case *sccp.XUDT:
+		cdpa, class, hc = m.CalledPartyAddress, m.ProtocolClass, m.HopCounter
+	default:
+		return nil, sccp.UnsupportedTypeError(m.MessageType())
+	}
+	if cdpa == nil || class == nil {
+		return nil, fmt.Errorf("scrc: %s without Called Party Address or Protocol Class", m.MessageTypeName())
+	}
+
+	rt := &routing{Router: r, returnOnError: class.ReturnOnError()}
+	if hc != nil {
+		rt.hasHopCounter, rt.hopCounter = true, hc.Value()
+	}
+
+	if cdpa.RouteOnGT() {
+		return rt.routeOnGT(cdpa, sls)
+	}
+	return rt.routeOnSSN(cdpa), nil
+}
+
+// routing is the state of the routing of a message.
+type routing struct {
+	*Router
+
+	returnOnError bool
+	hasHopCounter bool
+	hopCounter    uint8
+}
+
+func (rt *routing) routeOnGT(cdpa *params.PartyAddress, sls uint8) (*Decision, error) {
+	if rt.cfg.Translator == nil {
+		return rt.fail(params.ReturnCauseNoTranslationForAnAddressOfSuchNature), nil
+	}
+
+	res, err := rt.cfg.Translator.TranslateSLS(cdpa, sls)
+	if err != nil {
+		if cause, ok := gtt.ReturnCause(err); ok {
+			return rt.fail(cause), nil
+		}
+		return nil, fmt.Errorf("scrc: failed to translate: %w", err)
+	}
+
+	if rt.isLocal(res.PointCode) {
+		// the translation ends at this signalling point.
+		if !res.Address.HasSSN() {
+			return rt.fail(params.ReturnCauseNoTranslationForThisSpecificAddress), nil
+		}
+		d := rt.deliver(res.Address.SubsystemNumber)
+		d.Translation = res
+		return d, nil
+	}
+
+	if rt.hasHopCounter {
+		if rt.hopCounter <= 1 {
+			return rt.fail(params.ReturnCauseHopCounterViolation), nil
+		}
+		rt.hopCounter--
+	}
+
+	// try the alternates in order if the selected destination is down.
+	selected := res
+	for i := 0; ; i++ {
+		cause, down := rt.unavailable(res.Address)
+		if !down {
+			d := rt.forward(res.PointCode, res.Address)
+			d.Translation = res
+			return d, nil
+		}
+		if i == len(selected.Alternates) {
+			d := rt.fail(cause)
+			d.Translation = selected
+			return d, nil
+		}
+		res = selected.Failover(selected.Alternates[i])
+	}
+}
+
+func (rt *routing) routeOnSSN(cdpa *params.PartyAddress) *Decision {
+	if cdpa.HasPC() && !rt.isLocal(cdpa.SignalingPointCode) {
+		if cause, down := rt.unavailable(cdpa); down {
+			return rt.fail(cause)
+		}
+		return rt.forward(cdpa.SignalingPointCode, cdpa.Clone())
+	}
+
+	if !cdpa.HasSSN() {
+		return rt.fail(params.ReturnCauseUnequippedUser)
+	}
+	return rt.deliver(cdpa.SubsystemNumber)
+}
+
+// unavailable returns the Return Cause and true if the destination in addr,
+// which is routed on SSN if the SSN is given, is not available.
+func (rt *routing) unavailable(addr *params.PartyAddress) (params.ReturnCauseValue, bool) {
+	pc := addr.SignalingPointCode
+	if !rt.available(pc, 0) {
+		return params.ReturnCauseMTPFailure, true
+	}
+	if addr.RouteOnSSN() && addr.HasSSN() && !rt.available(pc, addr.SubsystemNumber) {
+		return params.ReturnCauseSubsystemFailure, true
+	}
+	return 0, false
+}
+
+func (rt *routing) deliver(ssn uint8) *Decision {
+	if !rt.ssns[ssn] {
+		return rt.fail(params.ReturnCauseUnequippedUser)
+	}
+
+	var pc params.PointCode
+	if len(rt.cfg.PointCodes) > 0 {
+		pc = rt.cfg.PointCodes[0]
+	}
+	if !rt.available(pc, ssn) {
+		return rt.fail(params.ReturnCauseSubsystemFailure)
+	}
+	return &Decision{Action: ActionDeliver, DPC: pc, SSN: ssn}
+}
+
+func (rt *routing) forward(dpc params.PointCode, cdpa *params.PartyAddress) *Decision {
+	return &Decision{Action: ActionForward, DPC: dpc, CalledPartyAddress: cdpa, HopCounter: rt.hopCounter}
+}
+
+func (rt *routing) fail(cause params.ReturnCauseValue) *Decision {
+	if rt.returnOnError {
+		return &Decision{Action: ActionReturn, Cause: cause}
+	}
+	return &Decision{Action: ActionDiscard, Cause: cause}
+}
+
+// isLocal reports whether pc is the one of the signalling point. 0 means the
+// PC is not specified, which is considered local.
+func (r *Router) isLocal(pc params.PointCode) bool {
+	return pc == 0 || r.local[pc]
+}
+
+func (r *Router) available(pc params.PointCode, ssn uint8) bool {
+	return r.cfg.Available == nil || r.cfg.Available(pc, ssn)
+}
@@ -0,0 +1,351 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scrc_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/gtt"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/scrc"
+)
+
+const (
+	localPC  params.PointCode = 0x100
+	remotePC params.PointCode = 0x200
+	backupPC params.PointCode = 0x300
+	downPC   params.PointCode = 0x400
+)
+
+func address(t *testing.T, b *params.AddressBuilder) *params.PartyAddress {
+	t.Helper()
+
+	addr, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func gt(t *testing.T, digits string) *params.PartyAddress {
+	t.Helper()
+
+	addr, err := params.NewE164Address(6, digits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func TestRoute(t *testing.T) {
+	table, err := gtt.NewTable(
+		gtt.Rule{Name: "remote", Prefix: "44", PointCode: remotePC},
+		gtt.Rule{Name: "local", Prefix: "81", PointCode: localPC, SSN: 8, RouteOnSSN: true},
+		gtt.Rule{Name: "down", Prefix: "33", PointCode: downPC},
+		gtt.Rule{Name: "failover", Prefix: "49", Mode: gtt.ModePrimaryBackup, RouteOnSSN: true, Destinations: []gtt.Destination{
+			{PointCode: remotePC, SSN: 7},
+			{PointCode: backupPC, SSN: 7, Cost: 1},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := scrc.New(scrc.Config{
+		PointCodes: []params.PointCode{localPC},
+		SSNs:       []uint8{6, 8, 9},
+		Translator: table,
+		Available: func(pc params.PointCode, ssn uint8) bool {
+			return pc != downPC && !(pc == localPC && ssn == 9) && !(pc == remotePC && ssn == 7)
+		},
+	})
+	cgpa := params.NewSSNAddress(6)
+
+	cases := []struct {
+		description string
+		msg         sccp.Message
+		action      scrc.Action
+		dpc         params.PointCode
+		ssn         uint8
+		cause       params.ReturnCauseValue
+		hopCounter  uint8
+	}{
+		{
+			description: "SSN local",
+			msg:         sccp.NewUDT(0, true, params.NewSSNAddress(6), cgpa, nil),
+			action:      scrc.ActionDeliver,
+			dpc:         localPC,
+			ssn:         6,
+		}, {
+			description: "SSN local with PC",
+			msg:         sccp.NewUDT(0, true, address(t, params.NewAddressBuilder().PC(localPC).SSN(8)), cgpa, nil),
+			action:      scrc.ActionDeliver,
+			dpc:         localPC,
+			ssn:         8,
+		}, {
+			description: "SSN unequipped",
+			msg:         sccp.NewUDT(0, true, params.NewSSNAddress(7), cgpa, nil),
+			action:      scrc.ActionReturn,
+			cause:       params.ReturnCauseUnequippedUser,
+		}, {
+			description: "SSN unequipped without return option",
+			msg:         sccp.NewUDT(0, false, params.NewSSNAddress(7), cgpa, nil),
+			action:      scrc.ActionDiscard,
+			cause:       params.ReturnCauseUnequippedUser,
+		}, {
+			description: "SSN local failure",
+			msg:         sccp.NewUDT(0, true, params.NewSSNAddress(9), cgpa, nil),
+			action:      scrc.ActionReturn,
+			cause:       params.ReturnCauseSubsystemFailure,
+		}, {
+			description: "SSN remote",
+			msg:         sccp.NewUDT(0, true, address(t, params.NewAddressBuilder().PC(remotePC).SSN(6)), cgpa, nil),
+			action:      scrc.ActionForward,
+			dpc:         remotePC,
+		}, {
+			description: "SSN remote failure",
+			msg:         sccp.NewUDT(0, true, address(t, params.NewAddressBuilder().PC(remotePC).SSN(7)), cgpa, nil),
+			action:      scrc.ActionReturn,
+			cause:       params.ReturnCauseSubsystemFailure,
+		}, {
+			description: "SSN remote MTP failure",
+			msg:         sccp.NewUDT(0, true, address(t, params.NewAddressBuilder().PC(downPC).SSN(6)), cgpa, nil),
+			action:      scrc.ActionReturn,
+			cause:       params.ReturnCauseMTPFailure,
+		}, {
+			description: "GT remote",
+			msg:         sccp.NewXUDT(1, true, 10, gt(t, "441234"), cgpa, nil),
+			action:      scrc.ActionForward,
+			dpc:         remotePC,
+			hopCounter:  9,
+		}, {
+			description: "GT hop counter violation",
+			msg:         sccp.NewXUDT(1, true, 1, gt(t, "441234"), cgpa, nil),
+			action:      scrc.ActionReturn,
+			cause:       params.ReturnCauseHopCounterViolation,
+		}, {
+			description: "GT local",
+			msg:         sccp.NewXUDT(1, true, 1, gt(t, "811234"), cgpa, nil),
+			action:      scrc.ActionDeliver,
+			dpc:         localPC,
+			ssn:         8,
+		}, {
+			description: "GT no translation",
+			msg:         sccp.NewUDT(0, true, gt(t, "991234"), cgpa, nil),
+			action:      scrc.ActionReturn,
+			cause:       params.ReturnCauseNoTranslationForThisSpecificAddress,
+		}, {
+			description: "GT MTP failure",
+			msg:         sccp.NewUDT(0, true, gt(t, "331234"), cgpa, nil),
+			action:      scrc.ActionReturn,
+			cause:       params.ReturnCauseMTPFailure,
+		}, {
+			description: "GT failover",
+			msg:         sccp.NewUDT(0, true, gt(t, "491234"), cgpa, nil),
+			action:      scrc.ActionForward,
+			dpc:         backupPC,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			d, err := router.Route(c.msg, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d.Action != c.action {
+				t.Fatalf("got %v, want action %s", d, c.action)
+			}
+			switch d.Action {
+			case scrc.ActionDeliver:
+				if d.DPC != c.dpc || d.SSN != c.ssn {
+					t.Errorf("got %v, want DPC %s, SSN %d", d, c.dpc, c.ssn)
+				}
+			case scrc.ActionForward:
+				if d.DPC != c.dpc || d.HopCounter != c.hopCounter {
+					t.Errorf("got %v, want DPC %s, hop counter %d", d, c.dpc, c.hopCounter)
+				}
+			default:
+				if d.Cause != c.cause {
+					t.Errorf("got %v, want cause %s", d, c.cause)
+				}
+			}
+		})
+	}
+}
+
+func TestDecisionMessage(t *testing.T) {
+	table, err := gtt.NewTable(gtt.Rule{Prefix: "44", PointCode: remotePC, SSN: 7, RouteOnSSN: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := scrc.New(scrc.Config{PointCodes: []params.PointCode{localPC}, Translator: table})
+
+	xudt := sccp.NewXUDT(1, true, 15, gt(t, "441234"), params.NewSSNAddress(6), []byte{0xde, 0xad}, params.NewImportance(3))
+	d, err := router.Route(xudt, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := d.Message(xudt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := sccp.ParseXUDT(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hc := got.HopCounter.Value(); hc != 14 {
+		t.Errorf("got hop counter %d, want 14", hc)
+	}
+	if cdpa := got.CalledPartyAddress; !cdpa.RouteOnSSN() || cdpa.SignalingPointCode != remotePC || cdpa.SubsystemNumber != 7 {
+		t.Errorf("got Called Party Address %v", cdpa)
+	}
+	if got.Importance == nil || got.Importance.Value() != 3 {
+		t.Errorf("got importance %v, want 3", got.Importance)
+	}
+	if xudt.HopCounter.Value() != 15 || xudt.CalledPartyAddress.RouteOnSSN() {
+		t.Errorf("original message is modified: %v", xudt)
+	}
+
+	udt := sccp.NewUDT(0, true, gt(t, "331234"), params.NewSSNAddress(6), nil)
+	d, err = router.Route(udt, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err = d.Message(udt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if udts, ok := m.(*sccp.UDTS); !ok || udts.ReturnCause.Value() != params.ReturnCauseNoTranslationForThisSpecificAddress {
+		t.Errorf("got %v, want UDTS", m)
+	}
+}
+
+func TestDecisionMessageRelay(t *testing.T) {
+	table, err := gtt.NewTable(gtt.Rule{Prefix: "44", PointCode: remotePC, SSN: 7, RouteOnSSN: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := scrc.New(scrc.Config{PointCodes: []params.PointCode{localPC}, Translator: table})
+
+	// the optional parameter unknown to this package is relayed as is.
+	unknown := params.NewUnknownParameter(0xf0, []byte{0x01, 0x02})
+	b, err := sccp.NewXUDT(1, true, 15, gt(t, "441234"), params.NewSSNAddress(6), []byte{0xde, 0xad}, unknown).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	received, err := sccp.ParseXUDT(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := router.Route(received, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := d.Message(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err = m.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	relayed, err := sccp.ParseXUDT(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(relayed.UnknownParameters) != 1 {
+		t.Fatalf("got unknown parameters %v, want %v", relayed.UnknownParameters, unknown)
+	}
+	if got := relayed.UnknownParameters[0]; got.Code() != unknown.Code() || !bytes.Equal(got.Value(), unknown.Value()) {
+		t.Errorf("got unknown parameter %v, want %v", got, unknown)
+	}
+	if m.(*sccp.XUDT).UnknownParameters[0] == received.UnknownParameters[0] {
+		t.Error("unknown parameter is shared with the original message")
+	}
+}
+
+func TestRoutingTable(t *testing.T) {
+	table := scrc.NewRoutingTable[string]()
+	table.Add(localPC, 6, "hlr")
+	table.Add(remotePC, 0, "stp")
+	table.Add(remotePC, 8, "msc")
+
+	lookup := func(pc params.PointCode, ssn uint8, want string) {
+		t.Helper()
+		got, ok := table.Lookup(pc, ssn)
+		if want == "" {
+			if ok {
+				t.Errorf("(%s, %d): got %q, want unavailable", pc, ssn, got)
+			}
+			return
+		}
+		if !ok || got != want {
+			t.Errorf("(%s, %d): got %q, %v, want %q", pc, ssn, got, ok, want)
+		}
+	}
+
+	lookup(localPC, 6, "hlr")
+	lookup(localPC, 7, "")
+	lookup(remotePC, 7, "stp")
+	lookup(remotePC, 8, "msc")
+	lookup(downPC, 0, "")
+	if !table.Available(localPC, 0) {
+		t.Error("local PC is not available")
+	}
+
+	table.HandleSCMG(sccp.NewSCMG(sccp.SCMGTypeSSP, 8, remotePC, 0, 0))
+	lookup(remotePC, 8, "")
+	lookup(remotePC, 7, "stp")
+	table.HandleSCMG(sccp.NewSCMG(sccp.SCMGTypeSSA, 8, remotePC, 0, 0))
+	lookup(remotePC, 8, "msc")
+
+	table.MTPUserPartUnavailable(remotePC)
+	lookup(remotePC, 8, "")
+	lookup(remotePC, 0, "stp")
+	table.HandleSCMG(sccp.NewSCMG(sccp.SCMGTypeSSA, sccp.SSNManagement, remotePC, 0, 0))
+	lookup(remotePC, 8, "msc")
+
+	table.SetSubsystemAvailable(remotePC, 8, false)
+	table.MTPPause(remotePC)
+	lookup(remotePC, 0, "")
+	lookup(remotePC, 7, "")
+	if table.Available(remotePC, 0) {
+		t.Error("paused PC is available")
+	}
+	table.MTPResume(remotePC)
+	lookup(remotePC, 8, "msc")
+
+	table.Remove(remotePC, 0)
+	lookup(remotePC, 7, "")
+	lookup(remotePC, 8, "msc")
+
+	// the Router routes with the availability in the table.
+	router := scrc.New(scrc.Config{PointCodes: []params.PointCode{localPC}, SSNs: []uint8{6}, Available: table.Available})
+	table.MTPPause(remotePC)
+	d, err := router.Route(sccp.NewUDT(0, true, address(t, params.NewAddressBuilder().PC(remotePC).SSN(8)), params.NewSSNAddress(6), nil), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Action != scrc.ActionReturn || d.Cause != params.ReturnCauseMTPFailure {
+		t.Errorf("got %v, want return with MTP failure", d)
+	}
+	d, err = router.Route(sccp.NewUDT(0, true, params.NewSSNAddress(6), params.NewSSNAddress(6), nil), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Action != scrc.ActionDeliver {
+		t.Errorf("got %v, want deliver", d)
+	}
+}
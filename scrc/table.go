@@ -0,0 +1,174 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package scrc
+
+import (
+	"sync"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// RoutingTable maps the DPC and SSN to the handle of the transport or the
+// destination, e.g., an M3UA association or a local subsystem, with the
+// availability of them given by MTP and SCCP management.
+//
+// Its Available can be given to Config to let Router check the availability,
+// and Lookup returns the handle to carry out the Decision.
+//
+// RoutingTable is safe for concurrent use.
+type RoutingTable[H any] struct {
+	mu     sync.RWMutex
+	points map[params.PointCode]*pointState[H]
+}
+
+// pointState is the routes and the status of a signalling point.
+type pointState[H any] struct {
+	// routes has the handle for each SSN, and the one for SSN 0 is used for
+	// any SSN that has no specific route.
+	routes map[uint8]H
+
+	// paused is set by MTP-PAUSE, and sccpDown by MTP-STATUS that tells the
+	// remote SCCP is unavailable.
+	paused, sccpDown bool
+	prohibited       map[uint8]bool
+}
+
+// NewRoutingTable creates a new empty RoutingTable.
+func NewRoutingTable[H any]() *RoutingTable[H] {
+	return &RoutingTable[H]{points: map[params.PointCode]*pointState[H]{}}
+}
+
+// Add adds the route to the subsystem ssn at pc via h. If ssn is 0, h is used
+// for all the subsystems at pc that are not added explicitly.
+func (t *RoutingTable[H]) Add(pc params.PointCode, ssn uint8, h H) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.points[pc]
+	if !ok {
+		st = &pointState[H]{routes: map[uint8]H{}, prohibited: map[uint8]bool{}}
+		t.points[pc] = st
+	}
+	st.routes[ssn] = h
+}
+
+// Remove removes the route added with pc and ssn. The status of pc is also
+// removed when it has no route.
+func (t *RoutingTable[H]) Remove(pc params.PointCode, ssn uint8) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.points[pc]
+	if !ok {
+		return
+	}
+	delete(st.routes, ssn)
+	if len(st.routes) == 0 {
+		delete(t.points, pc)
+	}
+}
+
+// Lookup returns the handle for the subsystem ssn at pc, or for pc itself if
+// ssn is 0. It reports false if there is no route or it is not available.
+func (t *RoutingTable[H]) Lookup(pc params.PointCode, ssn uint8) (H, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var zero H
+	st, ok := t.points[pc]
+	if !ok || !st.available(ssn) {
+		return zero, false
+	}
+	if h, ok := st.routes[ssn]; ok {
+		return h, true
+	}
+	if h, ok := st.routes[0]; ok {
+		return h, true
+	}
+	return zero, false
+}
+
+// Available reports whether the subsystem ssn at pc has the route and is
+// available, which can be used as Config.Available. If ssn is 0, it reports
+// whether pc has any route and is accessible.
+func (t *RoutingTable[H]) Available(pc params.PointCode, ssn uint8) bool {
+	if ssn != 0 {
+		_, ok := t.Lookup(pc, ssn)
+		return ok
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	st, ok := t.points[pc]
+	return ok && !st.paused
+}
+
+func (st *pointState[H]) available(ssn uint8) bool {
+	if st.paused || st.sccpDown && ssn != 0 {
+		return false
+	}
+	return !st.prohibited[ssn]
+}
+
+// MTPPause handles the MTP-PAUSE indication, which makes pc and all the
+// subsystems at it unavailable.
+func (t *RoutingTable[H]) MTPPause(pc params.PointCode) {
+	t.update(pc, func(st *pointState[H]) { st.paused = true })
+}
+
+// MTPResume handles the MTP-RESUME indication, which makes pc and all the
+// subsystems at it available again (see Q.714 5.2.3).
+func (t *RoutingTable[H]) MTPResume(pc params.PointCode) {
+	t.update(pc, func(st *pointState[H]) {
+		st.paused, st.sccpDown = false, false
+		clear(st.prohibited)
+	})
+}
+
+// MTPUserPartUnavailable handles the MTP-STATUS indication that the SCCP at
+// pc is unavailable, which makes all the subsystems at pc unavailable until
+// MTPResume or SSA for SCMG is received.
+func (t *RoutingTable[H]) MTPUserPartUnavailable(pc params.PointCode) {
+	t.update(pc, func(st *pointState[H]) { st.sccpDown = true })
+}
+
+// SetSubsystemAvailable sets the availability of the subsystem ssn at pc.
+func (t *RoutingTable[H]) SetSubsystemAvailable(pc params.PointCode, ssn uint8, available bool) {
+	t.update(pc, func(st *pointState[H]) {
+		if available {
+			delete(st.prohibited, ssn)
+		} else {
+			st.prohibited[ssn] = true
+		}
+	})
+}
+
+// HandleSCMG updates the availability of the subsystems with SSA and SSP. The
+// SSA for SCMG itself makes the SCCP at the AffectedPC available. The other
+// SCMG messages are ignored.
+func (t *RoutingTable[H]) HandleSCMG(s *sccp.SCMG) {
+	switch s.Type {
+	case sccp.SCMGTypeSSA:
+		if s.AffectedSSN == sccp.SSNManagement {
+			t.update(s.AffectedPC, func(st *pointState[H]) { st.sccpDown = false })
+			return
+		}
+		t.SetSubsystemAvailable(s.AffectedPC, s.AffectedSSN, true)
+	case sccp.SCMGTypeSSP:
+		t.SetSubsystemAvailable(s.AffectedPC, s.AffectedSSN, false)
+	}
+}
+
+// update calls fn with the state of pc if it has any route.
+func (t *RoutingTable[H]) update(pc params.PointCode, fn func(*pointState[H])) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if st, ok := t.points[pc]; ok {
+		fn(st)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+const (
+	// MaxSegments is the maximum number of the segments a payload can be
+	// split into, which is limited by the 4-bit Remaining Segments.
+	MaxSegments = 16
+
+	// MaxSegmentSize is the maximum size of the data in a segment, which is
+	// limited by the length of the Data parameter.
+	MaxSegmentSize = 255
+)
+
+// SegmentOptions is the values set in the XUDTs created by Segment.
+type SegmentOptions struct {
+	// ProtocolClass is the protocol class of the XUDTs, 0 or 1. In the
+	// class 1, the Class bit of the Segmentation is also set to request the
+	// in-sequence delivery.
+	ProtocolClass int
+	// ReturnOnError is the return option of the XUDTs.
+	ReturnOnError bool
+	// HopCounter is the Hop Counter of the XUDTs. DefaultHopCounter is used
+	// if it is 0.
+	HopCounter uint8
+
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+
+	// LocalReference is the Segmentation Local Reference shared by all the
+	// segments, which should be unique for the CallingPartyAddress while the
+	// segments may be reassembled at the receiver, e.g., generated by
+	// SegmentationRefGenerator.
+	LocalReference uint32
+
+	// Optionals are the other optional parameters put in all the XUDTs, e.g.,
+	// created with params.NewImportanceOptional.
+	Optionals []params.Parameter
+}
+
+// Segment splits data into the XUDTs with the data of maxSegmentSize octets
+// at most, each of which has the Segmentation with the First Segment bit set
+// only in the first one and the number of the segments that follow it (see
+// Q.714 4.1.1.2).
+//
+// It returns ErrTooManySegments if data needs more than MaxSegments segments,
+// and ErrInvalidSegmentSize if maxSegmentSize is out of the range from 1 to
+// MaxSegmentSize. The data that fits in one segment is still returned in an
+// XUDT with the Segmentation.
+func Segment(data []byte, maxSegmentSize int, opts SegmentOptions) ([]*XUDT, error) {
+	if maxSegmentSize < 1 || maxSegmentSize > MaxSegmentSize {
+		return nil, fmt.Errorf("%d: %w", maxSegmentSize, ErrInvalidSegmentSize)
+	}
+
+	n := max(1, (len(data)+maxSegmentSize-1)/maxSegmentSize)
+	if n > MaxSegments {
+		return nil, fmt.Errorf("%d octets in %d segments: %w", len(data), n, ErrTooManySegments)
+	}
+
+	hc := opts.HopCounter
+	if hc == 0 {
+		hc = DefaultHopCounter
+	}
+
+	var cls uint8
+	if opts.ProtocolClass == 1 {
+		cls = 1
+	}
+
+	xudts := make([]*XUDT, n)
+	for i := range xudts {
+		seg := data[i*maxSegmentSize : min((i+1)*maxSegmentSize, len(data))]
+		optionals := append(
+			[]params.Parameter{params.NewSegmentationOptional(i == 0, cls, uint8(n-1-i), opts.LocalReference)},
+			opts.Optionals...,
+		)
+		xudts[i] = NewXUDT(
+			opts.ProtocolClass, opts.ReturnOnError, hc,
+			opts.CalledPartyAddress, opts.CallingPartyAddress, seg, optionals...,
+		)
+	}
+
+	return xudts, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// MaxSegmentationLocalReference is the maximum value of the Segmentation Local
+// Reference, which is three octets long.
+const MaxSegmentationLocalReference uint32 = 0xffffff
+
+// ErrNoSegmentationRef is returned by SegmentationRefGenerator.Next when all
+// the references for the Calling Party Address are held.
+var ErrNoSegmentationRef = errors.New("sccp: no segmentation local reference available")
+
+// SegmentationRefGenerator generates the Segmentation Local References for
+// the segmented messages, so that the segments of the different messages from
+// the same Calling Party Address are not mixed up at the receiver, which
+// reassembles them keyed by the address and the reference.
+//
+// The references are generated for each Calling Party Address in sequence from
+// a random one, and a reference is not generated again for the same address
+// until the hold time passes, which should be longer than T(reass) at the
+// receiver.
+//
+// SegmentationRefGenerator is safe for concurrent use.
+type SegmentationRefGenerator struct {
+	mu    sync.Mutex
+	hold  time.Duration
+	rand  *rand.Rand
+	addrs map[string]*segRefSpace
+	queue []heldSegRef
+}
+
+// segRefSpace is the references held for a Calling Party Address.
+type segRefSpace struct {
+	next uint32
+	held map[uint32]struct{}
+}
+
+// heldSegRef is a reference generated and the time it is released at.
+type heldSegRef struct {
+	addr  string
+	ref   uint32
+	until time.Time
+}
+
+// NewSegmentationRefGenerator creates a new SegmentationRefGenerator that
+// holds the references generated for hold. If hold is 0,
+// DefaultReassemblyTimeout is used.
+func NewSegmentationRefGenerator(hold time.Duration) *SegmentationRefGenerator {
+	if hold == 0 {
+		hold = DefaultReassemblyTimeout
+	}
+
+	return &SegmentationRefGenerator{
+		hold:  hold,
+		rand:  rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		addrs: map[string]*segRefSpace{},
+	}
+}
+
+// Seed makes the SegmentationRefGenerator start the sequences from the
+// references derived from seed.
+func (g *SegmentationRefGenerator) Seed(seed uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.rand = rand.New(rand.NewPCG(seed, seed))
+}
+
+// Next returns the reference to segment the message from cgpa with. It returns
+// ErrNoSegmentationRef if all the references for cgpa are held.
+func (g *SegmentationRefGenerator) Next(cgpa *params.PartyAddress) (uint32, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	g.release(now)
+
+	key := addressKey(cgpa)
+	space, ok := g.addrs[key]
+	if !ok {
+		space = &segRefSpace{
+			next: g.rand.Uint32N(MaxSegmentationLocalReference + 1),
+			held: map[uint32]struct{}{},
+		}
+		g.addrs[key] = space
+	}
+
+	for i := uint32(0); i <= MaxSegmentationLocalReference; i++ {
+		ref := space.next
+		space.next = (space.next + 1) & MaxSegmentationLocalReference
+		if _, ok := space.held[ref]; ok {
+			continue
+		}
+
+		space.held[ref] = struct{}{}
+		g.queue = append(g.queue, heldSegRef{addr: key, ref: ref, until: now.Add(g.hold)})
+		return ref, nil
+	}
+
+	return 0, ErrNoSegmentationRef
+}
+
+// release releases the references whose hold time has passed at now, and
+// removes the addresses that have no reference held. As the hold time is the
+// same for all, the queue is in the order of it.
+func (g *SegmentationRefGenerator) release(now time.Time) {
+	i := 0
+	for ; i < len(g.queue) && !now.Before(g.queue[i].until); i++ {
+		h := g.queue[i]
+		space := g.addrs[h.addr]
+		delete(space.held, h.ref)
+		if len(space.held) == 0 {
+			delete(g.addrs, h.addr)
+		}
+	}
+	g.queue = g.queue[i:]
+}
@@ -0,0 +1,143 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// DefaultSequencerQueueSize is the default number of the tasks queued for
+// each worker of a Sequencer.
+const DefaultSequencerQueueSize = 64
+
+// ErrSequencerStopped is returned by Sequencer.Submit when the Sequencer is
+// not running.
+var ErrSequencerStopped = errors.New("sccp: sequencer is not running")
+
+// SequencerConfig is the configuration of a Sequencer. The zero values are
+// replaced with the defaults.
+type SequencerConfig struct {
+	// Workers is the number of the workers, which is runtime.GOMAXPROCS(0)
+	// if 0.
+	Workers int
+	// QueueSize is the number of the tasks queued for each worker, which is
+	// DefaultSequencerQueueSize if 0. Submit blocks while the queue is full.
+	QueueSize int
+}
+
+// Sequencer runs the tasks on a fixed set of workers, where the tasks with the
+// same key are run in the order they are submitted on the same worker.
+//
+// It provides the in-sequence delivery of the protocol class 1 to the
+// application that handles the messages concurrently: the messages received
+// with the same SLS, which are delivered in sequence by MTP, are submitted
+// with the SLS as the key. Endpoint uses it if EndpointConfig.Workers is set.
+//
+// Sequencer implements Component, and Stop waits for the tasks queued to
+// finish. It is safe for concurrent use.
+type Sequencer struct {
+	workers   int
+	queueSize int
+
+	mu     sync.RWMutex
+	queues []chan func()
+	wg     sync.WaitGroup
+}
+
+// NewSequencer creates a new Sequencer with cfg, which can be nil to use the
+// default values. The workers are started by Start.
+func NewSequencer(cfg *SequencerConfig) *Sequencer {
+	s := &Sequencer{}
+	if cfg != nil {
+		s.workers, s.queueSize = cfg.Workers, cfg.QueueSize
+	}
+	if s.workers <= 0 {
+		s.workers = runtime.GOMAXPROCS(0)
+	}
+	if s.queueSize <= 0 {
+		s.queueSize = DefaultSequencerQueueSize
+	}
+
+	return s
+}
+
+// Workers returns the number of the workers.
+func (s *Sequencer) Workers() int {
+	return s.workers
+}
+
+// Start starts the workers.
+func (s *Sequencer) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.queues != nil {
+		return nil
+	}
+
+	s.queues = make([]chan func(), s.workers)
+	for i := range s.queues {
+		q := make(chan func(), s.queueSize)
+		s.queues[i] = q
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for fn := range q {
+				fn()
+			}
+		}()
+	}
+
+	return nil
+}
+
+// Stop stops accepting the tasks and waits for the ones queued to finish, or
+// returns the error of ctx if it is done before that.
+func (s *Sequencer) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	for _, q := range s.queues {
+		close(q)
+	}
+	s.queues = nil
+	s.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Submit queues fn to the worker for key. It blocks while the queue is full,
+// and returns the error of ctx if it is done before fn is queued, or
+// ErrSequencerStopped if the Sequencer is not running.
+func (s *Sequencer) Submit(ctx context.Context, key uint32, fn func()) error {
+	// the read lock is held while blocking so that Stop does not close the
+	// queue being sent to.
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.queues == nil {
+		return ErrSequencerStopped
+	}
+
+	select {
+	case s.queues[key%uint32(len(s.queues))] <- fn:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
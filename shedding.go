@@ -0,0 +1,205 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DefaultMaxDeferred is the default number of the messages deferred by an
+// Endpoint for each congested signalling point.
+const DefaultMaxDeferred = 256
+
+// ShedStats is the number of the messages shed by an Endpoint due to the
+// congestion.
+type ShedStats struct {
+	// Dropped is the number of the messages discarded instead of being sent,
+	// including the deferred ones that overflowed.
+	Dropped uint64
+	// Deferred is the number of the messages deferred until the congestion
+	// is abated.
+	Deferred uint64
+	// InboundDropped is the number of the messages received and discarded
+	// due to the local congestion.
+	InboundDropped uint64
+}
+
+// shedder sheds the traffic of an Endpoint following the traffic limitation
+// of Q.714 5.2.8, with the restriction levels of the destinations tracked by
+// the CongestionTracker and the local one set by SetLocalCongestion.
+type shedder struct {
+	tracker  *CongestionTracker
+	dpc      func(m Message) (params.PointCode, bool)
+	maxQueue int
+
+	localRL atomic.Uint32
+
+	mu       sync.Mutex
+	deferred map[params.PointCode][]Message
+
+	dropped, delayed, inbound atomic.Uint64
+}
+
+func newShedder(cfg *EndpointConfig) *shedder {
+	s := &shedder{
+		tracker:  cfg.Congestion,
+		dpc:      cfg.DestinationPC,
+		maxQueue: cfg.MaxDeferred,
+		deferred: map[params.PointCode][]Message{},
+	}
+	if s.dpc == nil {
+		s.dpc = destinationPC
+	}
+	if s.maxQueue == 0 {
+		s.maxQueue = DefaultMaxDeferred
+	}
+
+	return s
+}
+
+// destinationPC returns the PC in the Called Party Address of m.
+func destinationPC(m Message) (params.PointCode, bool) {
+	cdpa, _, _ := unitdataAddresses(m)
+	if cdpa == nil || !cdpa.HasPC() {
+		return 0, false
+	}
+	return cdpa.SignalingPointCode, true
+}
+
+// SetLocalCongestion sets the restriction level of the local node. The UDT
+// and XUDT received whose importance is lower than rl are discarded, and 0
+// stops discarding them.
+func (e *Endpoint) SetLocalCongestion(rl uint8) {
+	e.shed.localRL.Store(uint32(min(rl, MaxRestrictionLevel)))
+}
+
+// MTPCongestionAbated lowers the restriction of pc in the CongestionTracker
+// given as EndpointConfig.Congestion by one sublevel, and sends the messages
+// deferred to pc that are allowed at the new level.
+func (e *Endpoint) MTPCongestionAbated(pc params.PointCode) {
+	if e.shed.tracker == nil {
+		return
+	}
+
+	e.shed.tracker.MTPCongestionAbated(pc)
+	e.flushDeferred(pc)
+}
+
+// ShedStats returns the number of the messages shed due to the congestion.
+func (e *Endpoint) ShedStats() ShedStats {
+	return ShedStats{
+		Dropped:        e.shed.dropped.Load(),
+		Deferred:       e.shed.delayed.Load(),
+		InboundDropped: e.shed.inbound.Load(),
+	}
+}
+
+// shedInbound reports whether m received should be discarded due to the
+// local congestion.
+func (e *Endpoint) shedInbound(m Message) bool {
+	rl := e.shed.localRL.Load()
+	if rl == 0 || uint32(MessageImportance(m)) >= rl {
+		return false
+	}
+
+	e.shed.inbound.Add(1)
+	return true
+}
+
+// handleSCMG updates the CongestionTracker with the SSC in m, and sends the
+// messages deferred to the affected PC that are allowed at the new level.
+func (e *Endpoint) handleSCMG(m Message) {
+	if e.shed.tracker == nil {
+		return
+	}
+
+	s, err := ExtractSCMG(m)
+	if err != nil || s == nil || s.Type != SCMGTypeSSC {
+		return
+	}
+
+	e.shed.tracker.HandleSCMG(s)
+	e.flushDeferred(s.AffectedPC)
+}
+
+// writeShedding writes m to the Transport unless the destination is
+// congested, in which case m is discarded with ErrCongestion or deferred by
+// its importance. The backpressure from the Transport is given to the
+// CongestionTracker as the MTP congestion, and m is deferred.
+func (e *Endpoint) writeShedding(m Message) error {
+	pc, ok := e.shed.dpc(m)
+	if e.shed.tracker == nil || !ok {
+		return e.writeTransport(m)
+	}
+
+	switch e.shed.tracker.Action(pc, MessageImportance(m)) {
+	case CongestionDrop:
+		e.shed.dropped.Add(1)
+		return fmt.Errorf("%s to PC %d: %w", m.MessageTypeName(), pc, ErrCongestion)
+	case CongestionDelay:
+		return e.deferMessage(pc, m)
+	}
+
+	err := e.writeTransport(m)
+	if errors.Is(err, ErrCongestion) {
+		e.shed.tracker.MTPCongestion(pc)
+		return e.deferMessage(pc, m)
+	}
+	return err
+}
+
+// deferMessage queues m until the congestion of pc is abated, or discards it
+// with ErrCongestion if the queue is full.
+func (e *Endpoint) deferMessage(pc params.PointCode, m Message) error {
+	e.shed.mu.Lock()
+	defer e.shed.mu.Unlock()
+
+	if len(e.shed.deferred[pc]) >= e.shed.maxQueue {
+		e.shed.dropped.Add(1)
+		return fmt.Errorf("%s to PC %d: deferred queue is full: %w", m.MessageTypeName(), pc, ErrCongestion)
+	}
+
+	e.shed.deferred[pc] = append(e.shed.deferred[pc], m)
+	e.shed.delayed.Add(1)
+	return nil
+}
+
+// flushDeferred sends the messages deferred to pc that are allowed at the
+// current restriction level, and discards the ones that are not allowed any
+// more. The others are kept in the queue.
+func (e *Endpoint) flushDeferred(pc params.PointCode) {
+	e.shed.mu.Lock()
+	queue := e.shed.deferred[pc]
+	delete(e.shed.deferred, pc)
+	e.shed.mu.Unlock()
+
+	var kept []Message
+	for _, m := range queue {
+		switch e.shed.tracker.Action(pc, MessageImportance(m)) {
+		case CongestionDrop:
+			e.shed.dropped.Add(1)
+			continue
+		case CongestionDelay:
+			kept = append(kept, m)
+			continue
+		}
+
+		if err := e.writeTransport(m); err != nil {
+			logf("failed to send deferred %s to PC %d: %v", m.MessageTypeName(), pc, err)
+		}
+	}
+	if len(kept) == 0 {
+		return
+	}
+
+	e.shed.mu.Lock()
+	e.shed.deferred[pc] = append(kept, e.shed.deferred[pc]...)
+	e.shed.mu.Unlock()
+}
@@ -0,0 +1,193 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package sigtran binds the SCCP messages to M3UA provided by go-m3ua
+(https://github.com/wmnsk/go-m3ua).
+
+A Transfer is a SCCP message with the MTP3 routing information, which is
+carried in the Protocol Data of a M3UA DATA. Conn reads and writes the
+Transfers on a *m3ua.Conn, or on anything that has ReadPD and WritePD.
+*/
+package sigtran
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wmnsk/go-m3ua/messages"
+	m3params "github.com/wmnsk/go-m3ua/messages/params"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// ErrNotSCCP indicates that the Service Indicator in the Protocol Data is not
+// SCCP, or that the M3UA message is not a DATA.
+var ErrNotSCCP = errors.New("sigtran: not a SCCP message")
+
+// Transfer is a SCCP message with the MTP3 routing information.
+type Transfer struct {
+	OPC, DPC params.PointCode
+	NI       uint8 // Network Indicator
+	MP       uint8 // Message Priority
+	SLS      uint8 // Signalling Link Selection
+
+	Message sccp.Message
+}
+
+// ProtocolData returns the M3UA Protocol Data that carries the Transfer, with
+// the Service Indicator of SCCP.
+func (t *Transfer) ProtocolData() (*m3params.Param, error) {
+	b, err := t.Message.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return m3params.NewProtocolData(
+		uint32(t.OPC), uint32(t.DPC), m3params.ServiceIndSCCP, t.NI, t.MP, t.SLS, b,
+	), nil
+}
+
+// Data returns the M3UA DATA that carries the Transfer. Network Appearance,
+// Routing Context and Correlation ID are not set, which can be set to the
+// returned Data if needed.
+func (t *Transfer) Data() (*messages.Data, error) {
+	pd, err := t.ProtocolData()
+	if err != nil {
+		return nil, err
+	}
+
+	return messages.NewData(nil, nil, pd, nil), nil
+}
+
+// MarshalBinary returns the byte sequence of the M3UA DATA that carries the
+// Transfer.
+func (t *Transfer) MarshalBinary() ([]byte, error) {
+	d, err := t.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.MarshalBinary()
+}
+
+// String returns the Transfer in human readable format.
+func (t *Transfer) String() string {
+	return fmt.Sprintf("{OPC: %d, DPC: %d, NI: %d, MP: %d, SLS: %d, Message: %v}",
+		t.OPC, t.DPC, t.NI, t.MP, t.SLS, t.Message,
+	)
+}
+
+// DecodeProtocolData decodes the SCCP message in the M3UA Protocol Data.
+//
+// It returns ErrNotSCCP if the Service Indicator is not SCCP. The Message
+// refers to the Data in pd.
+func DecodeProtocolData(pd *m3params.ProtocolDataPayload, opts ...sccp.ParseOption) (*Transfer, error) {
+	return decodeProtocolData(sccp.NewCodec(opts...), pd)
+}
+
+// ParseData decodes the byte sequence of a M3UA DATA as a Transfer.
+//
+// It returns ErrNotSCCP if b is not a M3UA DATA, or if the Service Indicator
+// is not SCCP.
+func ParseData(b []byte, opts ...sccp.ParseOption) (*Transfer, error) {
+	m, err := messages.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	d, ok := m.(*messages.Data)
+	if !ok || d.ProtocolData == nil {
+		return nil, ErrNotSCCP
+	}
+
+	pd, err := d.ProtocolData.ProtocolData()
+	if err != nil {
+		return nil, err
+	}
+	return DecodeProtocolData(pd, opts...)
+}
+
+func decodeProtocolData(codec *sccp.Codec, pd *m3params.ProtocolDataPayload) (*Transfer, error) {
+	if pd.ServiceIndicator != m3params.ServiceIndSCCP {
+		return nil, fmt.Errorf("service indicator %d: %w", pd.ServiceIndicator, ErrNotSCCP)
+	}
+
+	m, err := codec.ParseMessage(pd.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transfer{
+		OPC:     params.PointCode(pd.OriginatingPointCode),
+		DPC:     params.PointCode(pd.DestinationPointCode),
+		NI:      pd.NetworkIndicator,
+		MP:      pd.MessagePriority,
+		SLS:     pd.SignalingLinkSelection,
+		Message: m,
+	}, nil
+}
+
+// PDConn is the M3UA connection that reads and writes the Protocol Data,
+// which is implemented by *m3ua.Conn.
+type PDConn interface {
+	ReadPD() (*m3params.ProtocolDataPayload, error)
+	WritePD(pd *m3params.Param) (int, error)
+}
+
+// Conn reads and writes the Transfers on a M3UA connection.
+type Conn struct {
+	conn  PDConn
+	codec *sccp.Codec
+}
+
+// NewConn creates a new Conn on the M3UA connection, which is typically a
+// *m3ua.Conn established with m3ua.Dial or accepted by m3ua.Listener.
+//
+// The received messages are decoded with opts.
+func NewConn(conn PDConn, opts ...sccp.ParseOption) *Conn {
+	return &Conn{conn: conn, codec: sccp.NewCodec(opts...)}
+}
+
+// ReadTransfer reads the next Transfer from the connection.
+//
+// The Protocol Data whose Service Indicator is not SCCP is discarded.
+func (c *Conn) ReadTransfer() (*Transfer, error) {
+	for {
+		pd, err := c.conn.ReadPD()
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := decodeProtocolData(c.codec, pd)
+		if errors.Is(err, ErrNotSCCP) {
+			continue
+		}
+		return t, err
+	}
+}
+
+// WriteTransfer writes the Transfer to the connection with the Network
+// Appearance and Routing Context configured in the connection.
+func (c *Conn) WriteTransfer(t *Transfer) error {
+	pd, err := t.ProtocolData()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.conn.WritePD(pd)
+	return err
+}
+
+// ReadMessage reads the next SCCP message from the connection, discarding
+// the routing information.
+func (c *Conn) ReadMessage() (sccp.Message, error) {
+	t, err := c.ReadTransfer()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Message, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sigtran_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wmnsk/go-m3ua"
+	m3params "github.com/wmnsk/go-m3ua/messages/params"
+
+	"github.com/wmnsk/go-sccp"
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/sigtran"
+)
+
+var _ sigtran.PDConn = (*m3ua.Conn)(nil)
+
+// pipe is a PDConn that returns what is written to it.
+type pipe struct {
+	pds []*m3params.ProtocolDataPayload
+}
+
+func (p *pipe) ReadPD() (*m3params.ProtocolDataPayload, error) {
+	if len(p.pds) == 0 {
+		return nil, io.EOF
+	}
+
+	pd := p.pds[0]
+	p.pds = p.pds[1:]
+	return pd, nil
+}
+
+func (p *pipe) WritePD(param *m3params.Param) (int, error) {
+	pd, err := param.ProtocolData()
+	if err != nil {
+		return 0, err
+	}
+
+	p.pds = append(p.pds, pd)
+	return param.MarshalLen(), nil
+}
+
+func newTransfer(t *testing.T) *sigtran.Transfer {
+	t.Helper()
+
+	cdpa := params.NewCalledPartyAddress(0x42, 0, 6, nil)
+	cgpa := params.NewCallingPartyAddress(0x42, 0, 7, nil)
+	return &sigtran.Transfer{
+		OPC:     100,
+		DPC:     200,
+		NI:      2,
+		SLS:     5,
+		Message: sccp.NewUDT(1, true, cdpa, cgpa, []byte{0xde, 0xad, 0xbe, 0xef}),
+	}
+}
+
+func TestParseData(t *testing.T) {
+	tr := newTransfer(t)
+	b, err := tr.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sigtran.ParseData(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := tr.Message.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := got.Message.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(msg, want); diff != "" {
+		t.Error(diff)
+	}
+
+	got.Message, tr.Message = nil, nil
+	if diff := cmp.Diff(got, tr); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestConn(t *testing.T) {
+	p := &pipe{}
+	// ISUP, which should be discarded by ReadTransfer.
+	p.pds = append(p.pds, m3params.NewProtocolDataPayload(1, 2, m3params.ServiceIndISUP, 0, 0, 0, []byte{0x01}))
+
+	c := sigtran.NewConn(p)
+	tr := newTransfer(t)
+	if err := c.WriteTransfer(tr); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ReadTransfer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got.Message.MessageType(), sccp.MsgTypeUDT); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]params.PointCode{got.OPC, got.DPC}, []params.PointCode{tr.OPC, tr.DPC}); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := c.ReadMessage(); !errors.Is(err, io.EOF) {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+}
+
+func TestDecodeProtocolData(t *testing.T) {
+	pd := m3params.NewProtocolDataPayload(1, 2, m3params.ServiceIndISUP, 0, 0, 0, []byte{0x01})
+	if _, err := sigtran.DecodeProtocolData(pd); !errors.Is(err, sigtran.ErrNotSCCP) {
+		t.Errorf("got %v, want ErrNotSCCP", err)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// logKeys are the keys of the parameters in LogValue that are abbreviated.
+var logKeys = map[string]string{
+	"CalledPartyAddress":        "cdpa",
+	"CallingPartyAddress":       "cgpa",
+	"DestinationLocalReference": "dlr",
+	"SourceLocalReference":      "slr",
+}
+
+// logValue returns m as a group of the type, the length and the parameters
+// present in m.
+//
+// The user data is never included but its length, and the Global Title digits
+// in the addresses are redacted if enabled by params.SetLogRedaction.
+func logValue(m Message) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("type", m.MessageTypeName()),
+		slog.Int("length", m.MarshalLen()),
+	}
+
+	v := reflect.ValueOf(m).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		key, ok := logKeys[f.Name]
+		if !ok {
+			key = strings.ToLower(f.Name[:1]) + f.Name[1:]
+		}
+
+		switch p := v.Field(i).Interface().(type) {
+		case *params.Data:
+			// UDT and XUDT may have the Data not decoded yet.
+			if l, ok := m.(interface{ peekData() *params.Data }); ok {
+				p = l.peekData()
+			}
+			if p != nil {
+				attrs = append(attrs, slog.Int("dataLength", len(p.Value())))
+			}
+		case *params.LongData:
+			if p != nil {
+				attrs = append(attrs, slog.Int("dataLength", len(p.Value())))
+			}
+		case []*params.UnknownParameter:
+			if len(p) > 0 {
+				attrs = append(attrs, slog.Int("unknownParameters", len(p)))
+			}
+		case params.Parameter:
+			if v.Field(i).IsNil() {
+				continue
+			}
+			if a, ok := parameterAttr(key, p); ok {
+				attrs = append(attrs, a)
+			}
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// parameterAttr returns the parameter p as an attribute with the key.
+func parameterAttr(key string, p params.Parameter) (slog.Attr, bool) {
+	switch p := p.(type) {
+	case *params.PartyAddress:
+		return slog.Any(key, p), true
+	case *params.LocalReference:
+		return slog.Uint64(key, uint64(p.Uint32())), true
+	case *params.ProtocolClass:
+		return slog.Group(key, slog.Int("class", p.Class()), slog.Bool("returnOnError", p.ReturnOnError())), true
+	case *params.ReceiveSequenceNumber:
+		return slog.Int(key, int(p.PR())), true
+	case *params.Segmentation:
+		return slog.Group(key,
+			slog.Bool("first", p.FirstSegment),
+			slog.Int("remaining", int(p.RemainingSegments)),
+			slog.Uint64("localReference", uint64(p.LocalReference)),
+		), true
+	case *params.EndOfOptionalParameters:
+		return slog.Attr{}, false
+	}
+
+	// the parameters that have a scalar value, such as the causes.
+	if m := reflect.ValueOf(p).MethodByName("Value"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		switch v := m.Call(nil)[0]; v.Kind() {
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			return slog.Any(key, v.Interface()), true
+		}
+	}
+	return slog.String(key, p.String()), true
+}
+
+// LogValue implements slog.LogValuer, which returns the CR as a group of the
+// type, the length and the parameters.
+func (c *CR) LogValue() slog.Value {
+	return logValue(c)
+}
+
+// LogValue implements slog.LogValuer, which returns the CC as a group of the
+// type, the length and the parameters.
+func (c *CC) LogValue() slog.Value {
+	return logValue(c)
+}
+
+// LogValue implements slog.LogValuer, which returns the CREF as a group of the
+// type, the length and the parameters.
+func (c *CREF) LogValue() slog.Value {
+	return logValue(c)
+}
+
+// LogValue implements slog.LogValuer, which returns the RLSD as a group of the
+// type, the length and the parameters.
+func (r *RLSD) LogValue() slog.Value {
+	return logValue(r)
+}
+
+// LogValue implements slog.LogValuer, which returns the RLC as a group of the
+// type, the length and the parameters.
+func (r *RLC) LogValue() slog.Value {
+	return logValue(r)
+}
+
+// LogValue implements slog.LogValuer, which returns the DT1 as a group of the
+// type, the length and the parameters.
+func (d *DT1) LogValue() slog.Value {
+	return logValue(d)
+}
+
+// LogValue implements slog.LogValuer, which returns the DT2 as a group of the
+// type, the length and the parameters.
+func (d *DT2) LogValue() slog.Value {
+	return logValue(d)
+}
+
+// LogValue implements slog.LogValuer, which returns the AK as a group of the
+// type, the length and the parameters.
+func (a *AK) LogValue() slog.Value {
+	return logValue(a)
+}
+
+// LogValue implements slog.LogValuer, which returns the UDT as a group of the
+// type, the length and the parameters.
+func (u *UDT) LogValue() slog.Value {
+	return logValue(u)
+}
+
+// LogValue implements slog.LogValuer, which returns the UDTS as a group of the
+// type, the length and the parameters.
+func (u *UDTS) LogValue() slog.Value {
+	return logValue(u)
+}
+
+// LogValue implements slog.LogValuer, which returns the ED as a group of the
+// type, the length and the parameters.
+func (e *ED) LogValue() slog.Value {
+	return logValue(e)
+}
+
+// LogValue implements slog.LogValuer, which returns the EA as a group of the
+// type, the length and the parameters.
+func (e *EA) LogValue() slog.Value {
+	return logValue(e)
+}
+
+// LogValue implements slog.LogValuer, which returns the RSR as a group of the
+// type, the length and the parameters.
+func (r *RSR) LogValue() slog.Value {
+	return logValue(r)
+}
+
+// LogValue implements slog.LogValuer, which returns the RSC as a group of the
+// type, the length and the parameters.
+func (r *RSC) LogValue() slog.Value {
+	return logValue(r)
+}
+
+// LogValue implements slog.LogValuer, which returns the IT as a group of the
+// type, the length and the parameters.
+func (i *IT) LogValue() slog.Value {
+	return logValue(i)
+}
+
+// LogValue implements slog.LogValuer, which returns the XUDT as a group of the
+// type, the length and the parameters.
+func (x *XUDT) LogValue() slog.Value {
+	return logValue(x)
+}
+
+// LogValue implements slog.LogValuer, which returns the XUDTS as a group of
+// the type, the length and the parameters.
+func (x *XUDTS) LogValue() slog.Value {
+	return logValue(x)
+}
+
+// LogValue implements slog.LogValuer, which returns the LUDT as a group of the
+// type, the length and the parameters.
+func (l *LUDT) LogValue() slog.Value {
+	return logValue(l)
+}
+
+// LogValue implements slog.LogValuer, which returns the LUDTS as a group of
+// the type, the length and the parameters.
+func (l *LUDTS) LogValue() slog.Value {
+	return logValue(l)
+}
+
+// LogValue implements slog.LogValuer, which returns the RawMessage as a group
+// of the type and the length. The Payload is not included.
+func (r *RawMessage) LogValue() slog.Value {
+	return logValue(r)
+}
@@ -0,0 +1,192 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// The masks of the Signalling Link Selection (SLS) in the MTP3 routing label.
+const (
+	SLSMaskITU  uint8 = 0x0f // 4-bit SLS in ITU, China and TTC
+	SLSMaskANSI uint8 = 0xff // 8-bit SLS in ANSI
+)
+
+// SLSSelector selects the SLS of the messages handed to the Transport.
+//
+// MTP delivers the messages with the same SLS in sequence, so the messages
+// that require the in-sequence delivery, i.e., the ones in the protocol class
+// 1 and the segments of a message, must be given the same SLS for the same
+// Called and Calling Party Addresses (see Q.714 1.1.2.2 and 4.1.1.2).
+type SLSSelector interface {
+	SelectSLS(m Message) uint8
+}
+
+// SLSSelectorFunc is a function that implements SLSSelector.
+type SLSSelectorFunc func(m Message) uint8
+
+// SelectSLS calls f.
+func (f SLSSelectorFunc) SelectSLS(m Message) uint8 {
+	return f(m)
+}
+
+// SLSTransport is a Transport that can send the messages with the SLS, such
+// as the one over MTP3 or M3UA. Endpoint gives the SLS selected by
+// EndpointConfig.SLS to WriteMessageSLS instead of calling WriteMessage.
+type SLSTransport interface {
+	Transport
+	WriteMessageSLS(m Message, sls uint8) error
+}
+
+// SLSReader is a Transport that can tell the SLS the messages are received
+// with, such as the one over MTP3 or M3UA. Endpoint uses it to keep the
+// sequence of the messages in the protocol class 1 with EndpointConfig.Workers.
+type SLSReader interface {
+	Transport
+	ReadMessageSLS() (Message, uint8, error)
+}
+
+// HashSLS returns the SLSSelector that selects the SLS from the hash of the
+// Called and Calling Party Addresses, masked with mask.
+//
+// All the messages between the same pair of addresses are given the same SLS,
+// which preserves the sequence of them regardless of the protocol class.
+func HashSLS(mask uint8) SLSSelector {
+	return SLSSelectorFunc(func(m Message) uint8 {
+		return hashSLS(m) & mask
+	})
+}
+
+// RoundRobinSLS returns the SLSSelector that selects the SLS in turn for each
+// message of the protocol class 0 to share the load over the links, masked
+// with mask.
+//
+// The messages that require the in-sequence delivery, i.e., the ones in the
+// protocol class 1 and the segmented XUDTs, are given the SLS selected by
+// HashSLS instead.
+func RoundRobinSLS(mask uint8) SLSSelector {
+	var next atomic.Uint32
+	return SLSSelectorFunc(func(m Message) uint8 {
+		if inSequence(m) {
+			return hashSLS(m) & mask
+		}
+		return uint8(next.Add(1)-1) & mask
+	})
+}
+
+// StickySLS is the SLSSelector that assigns the SLS in turn to each key, e.g.,
+// the dialogue of the application, and selects the same one for the messages
+// with the key until it is released.
+//
+// StickySLS is safe for concurrent use.
+type StickySLS struct {
+	mask uint8
+	key  func(m Message) string
+
+	mu   sync.Mutex
+	next uint8
+	sls  map[string]uint8
+}
+
+// NewStickySLS creates a new StickySLS with the SLS masked with mask, which
+// keys the messages with key. If key is nil, the pair of the Called and
+// Calling Party Addresses is used as the key.
+func NewStickySLS(mask uint8, key func(m Message) string) *StickySLS {
+	if key == nil {
+		key = addressPairKey
+	}
+
+	return &StickySLS{mask: mask, key: key, sls: map[string]uint8{}}
+}
+
+// SelectSLS returns the SLS assigned to the key of m, or assigns the next one
+// if the key is new.
+func (s *StickySLS) SelectSLS(m Message) uint8 {
+	k := s.key(m)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sls, ok := s.sls[k]; ok {
+		return sls
+	}
+
+	sls := s.next & s.mask
+	s.next++
+	s.sls[k] = sls
+	return sls
+}
+
+// Release releases the SLS assigned to the key, which should be called when
+// the messages with the key are no longer sent, e.g., the dialogue ends.
+func (s *StickySLS) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sls, key)
+}
+
+// Len returns the number of the keys that have the SLS assigned.
+func (s *StickySLS) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sls)
+}
+
+// unitdataAddresses returns the Called and Calling Party Addresses and the
+// Protocol Class of the connectionless message m.
+func unitdataAddresses(m Message) (cdpa, cgpa *params.PartyAddress, pcls *params.ProtocolClass) {
+	switch m := m.(type) {
+	case *UDT:
+		return m.CalledPartyAddress, m.CallingPartyAddress, m.ProtocolClass
+	case *XUDT:
+		return m.CalledPartyAddress, m.CallingPartyAddress, m.ProtocolClass
+	case *LUDT:
+		return m.CalledPartyAddress, m.CallingPartyAddress, m.ProtocolClass
+	case *UDTS:
+		return m.CalledPartyAddress, m.CallingPartyAddress, nil
+	case *XUDTS:
+		return m.CalledPartyAddress, m.CallingPartyAddress, nil
+	case *LUDTS:
+		return m.CalledPartyAddress, m.CallingPartyAddress, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// inSequence reports whether m requires the in-sequence delivery.
+func inSequence(m Message) bool {
+	if x, ok := m.(*XUDT); ok && x.Segmentation != nil {
+		return true
+	}
+	if l, ok := m.(*LUDT); ok && l.Segmentation != nil {
+		return true
+	}
+
+	_, _, pcls := unitdataAddresses(m)
+	return pcls != nil && pcls.Class() == 1
+}
+
+// addressPairKey returns the key of the pair of the Called and Calling Party
+// Addresses of m.
+func addressPairKey(m Message) string {
+	cdpa, cgpa, _ := unitdataAddresses(m)
+	return addressKey(cdpa) + "\x00" + addressKey(cgpa)
+}
+
+// hashSLS returns the hash of the Called and Calling Party Addresses of m
+// folded into an octet.
+func hashSLS(m Message) uint8 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(addressPairKey(m)))
+
+	v := h.Sum32()
+	return uint8(v ^ v>>8 ^ v>>16 ^ v>>24)
+}
@@ -0,0 +1,148 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package ssn provides the well-known Subsystem Numbers (SSN) and a registry
+of their names, so that the addresses can be built and printed with symbolic
+names.
+
+The constants are of type uint8, so that they can be given directly to the
+constructors of params.PartyAddress.
+*/
+package ssn
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Subsystem Numbers defined in Q.713 3.4.2.2.
+const (
+	Unknown   uint8 = 0x00 // SSN not known/not used
+	SCMG      uint8 = 0x01 // SCCP management
+	ISUP      uint8 = 0x03 // ISDN user part
+	OMAP      uint8 = 0x04 // operation, maintenance and administration part
+	MAP       uint8 = 0x05 // mobile application part
+	HLR       uint8 = 0x06 // home location register
+	VLR       uint8 = 0x07 // visitor location register
+	MSC       uint8 = 0x08 // mobile switching centre
+	EIR       uint8 = 0x09 // equipment identifier register
+	AUC       uint8 = 0x0a // authentication centre
+	ISDNSS    uint8 = 0x0b // ISDN supplementary services
+	INAP      uint8 = 0x0c // reserved for international use, commonly used for INAP
+	BISDN     uint8 = 0x0d // broadband ISDN edge-to-edge applications
+	TCTest    uint8 = 0x0e // TC test responder
+	Expansion uint8 = 0xff // reserved for expansion
+)
+
+// Subsystem Numbers allocated for the national use in 3GPP TS 23.003 8.2.
+const (
+	RANAP  uint8 = 0x8e // RANAP
+	RNSAP  uint8 = 0x8f // RNSAP
+	GMLC   uint8 = 0x91 // GMLC (MAP)
+	CAP    uint8 = 0x92 // CAP
+	GSMSCF uint8 = 0x93 // gsmSCF (MAP) or IM-SSF (MAP)
+	SIWF   uint8 = 0x94 // SIWF (MAP)
+	SGSN   uint8 = 0x95 // SGSN (MAP)
+	GGSN   uint8 = 0x96 // GGSN (MAP)
+	PCAP   uint8 = 0xf9 // PCAP
+	BSCLE  uint8 = 0xfa // BSC (BSSAP-LE)
+	MSCLE  uint8 = 0xfb // MSC (BSSAP-LE)
+	SMLC   uint8 = 0xfc // SMLC (BSSAP-LE)
+	BSSOM  uint8 = 0xfd // BSS O&M (A interface)
+	BSSAP  uint8 = 0xfe // BSSAP (A interface)
+)
+
+var (
+	registry = map[uint8]string{
+		Unknown:   "unknown",
+		SCMG:      "SCMG",
+		ISUP:      "ISUP",
+		OMAP:      "OMAP",
+		MAP:       "MAP",
+		HLR:       "HLR",
+		VLR:       "VLR",
+		MSC:       "MSC",
+		EIR:       "EIR",
+		AUC:       "AUC",
+		ISDNSS:    "ISDN-SS",
+		INAP:      "INAP",
+		BISDN:     "B-ISDN",
+		TCTest:    "TC-test",
+		Expansion: "expansion",
+		RANAP:     "RANAP",
+		RNSAP:     "RNSAP",
+		GMLC:      "GMLC",
+		CAP:       "CAP",
+		GSMSCF:    "gsmSCF",
+		SIWF:      "SIWF",
+		SGSN:      "SGSN",
+		GGSN:      "GGSN",
+		PCAP:      "PCAP",
+		BSCLE:     "BSC-LE",
+		MSCLE:     "MSC-LE",
+		SMLC:      "SMLC",
+		BSSOM:     "BSS-O&M",
+		BSSAP:     "BSSAP",
+	}
+	registryMu sync.RWMutex
+)
+
+// Register registers the name of the Subsystem Number, which is used in Name,
+// Format and Lookup.
+//
+// This is useful to give names to the values used in a specific network.
+// Registering the same value again overwrites the name, including the
+// predefined ones.
+func Register(v uint8, name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[v] = name
+}
+
+// Unregister removes the name of the Subsystem Number.
+func Unregister(v uint8) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, v)
+}
+
+// Name returns the name of the Subsystem Number, or the value in decimal
+// if no name is registered.
+func Name(v uint8) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if name, ok := registry[v]; ok {
+		return name
+	}
+	return strconv.Itoa(int(v))
+}
+
+// Format returns the Subsystem Number with its name, e.g., "HLR (6)", or the
+// value in decimal if no name is registered.
+func Format(v uint8) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if name, ok := registry[v]; ok {
+		return name + " (" + strconv.Itoa(int(v)) + ")"
+	}
+	return strconv.Itoa(int(v))
+}
+
+// Lookup returns the Subsystem Number registered with the name.
+func Lookup(name string) (uint8, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for v, n := range registry {
+		if n == name {
+			return v, true
+		}
+	}
+	return 0, false
+}
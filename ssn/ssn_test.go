@@ -0,0 +1,30 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ssn_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-sccp/ssn"
+)
+
+func TestRegistry(t *testing.T) {
+	if got, want := ssn.Format(ssn.HLR), "HLR (6)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := ssn.Name(0x80), "128"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	ssn.Register(0x80, "USSD-GW")
+	defer ssn.Unregister(0x80)
+
+	if got, want := ssn.Format(0x80), "USSD-GW (128)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if v, ok := ssn.Lookup("USSD-GW"); !ok || v != 0x80 {
+		t.Errorf("got %d, %v, want %d, true", v, ok, 0x80)
+	}
+}
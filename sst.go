@@ -0,0 +1,159 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// DefaultStatInfoInterval is the default value of the timer T(stat.info),
+// the interval of the SST messages in the subsystem status test.
+const DefaultStatInfoInterval = 30 * time.Second
+
+// StatusTester runs the subsystem status test procedure defined in Q.714 5.3.4.
+//
+// When a remote subsystem is marked as prohibited, an SST is sent to the SCMG
+// of the node hosting it every time the timer T(stat.info) expires, until the
+// subsystem is marked as allowed, e.g., by an SSA given to HandleSCMG.
+//
+// Like SubsystemSimulator, it does not implement any transport; the messages
+// are handed to the send function. The errors on sending are logged, and the
+// test continues.
+//
+// StatusTester implements Component, and Stop stops all the running tests.
+// It is safe for concurrent use.
+type StatusTester struct {
+	localPC  params.PointCode
+	interval time.Duration
+	send     func(Message) error
+
+	mu    sync.Mutex
+	tests map[remoteSubsystem]chan struct{}
+	wg    sync.WaitGroup
+}
+
+// remoteSubsystem identifies a subsystem at a remote signalling point.
+type remoteSubsystem struct {
+	pc  params.PointCode
+	ssn uint8
+}
+
+// NewStatusTester creates a new StatusTester for the signalling point
+// identified by localPC. If interval is 0, DefaultStatInfoInterval is used.
+func NewStatusTester(localPC params.PointCode, interval time.Duration, send func(Message) error) *StatusTester {
+	if interval == 0 {
+		interval = DefaultStatInfoInterval
+	}
+
+	return &StatusTester{
+		localPC:  localPC,
+		interval: interval,
+		send:     send,
+		tests:    map[remoteSubsystem]chan struct{}{},
+	}
+}
+
+// Prohibited marks the subsystem ssn at pc as prohibited, and starts the
+// status test for it if it is not running yet.
+func (t *StatusTester) Prohibited(pc params.PointCode, ssn uint8) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := remoteSubsystem{pc, ssn}
+	if _, ok := t.tests[key]; ok {
+		return
+	}
+
+	done := make(chan struct{})
+	t.tests[key] = done
+
+	t.wg.Add(1)
+	go t.run(key, done)
+}
+
+// Allowed marks the subsystem ssn at pc as allowed, and stops the status test
+// for it if it is running.
+func (t *StatusTester) Allowed(pc params.PointCode, ssn uint8) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := remoteSubsystem{pc, ssn}
+	if done, ok := t.tests[key]; ok {
+		close(done)
+		delete(t.tests, key)
+	}
+}
+
+// IsTesting reports whether the status test for the subsystem ssn at pc is running.
+func (t *StatusTester) IsTesting(pc params.PointCode, ssn uint8) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.tests[remoteSubsystem{pc, ssn}]
+	return ok
+}
+
+// HandleSCMG starts the status test on SSP and stops it on SSA for the
+// affected subsystem. The other SCMG messages are ignored.
+func (t *StatusTester) HandleSCMG(s *SCMG) {
+	switch s.Type {
+	case SCMGTypeSSP:
+		t.Prohibited(s.AffectedPC, s.AffectedSSN)
+	case SCMGTypeSSA:
+		t.Allowed(s.AffectedPC, s.AffectedSSN)
+	}
+}
+
+// Start does nothing, as the tests are started by Prohibited or HandleSCMG.
+func (t *StatusTester) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop stops all the running tests and waits for them to finish, or returns
+// the error of ctx if it is done before that.
+func (t *StatusTester) Stop(ctx context.Context) error {
+	t.mu.Lock()
+	for key, done := range t.tests {
+		close(done)
+		delete(t.tests, key)
+	}
+	t.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *StatusTester) run(key remoteSubsystem, done chan struct{}) {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	sst := NewSCMG(SCMGTypeSST, key.ssn, key.pc, 0, 0)
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := sendSCMG(t.send, t.localPC, key.pc, sst); err != nil {
+				logf("subsystem status test for SSN %d at PC %d: %v", key.ssn, key.pc, err)
+			}
+		}
+	}
+}
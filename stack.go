@@ -0,0 +1,127 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Component is a part of the Stack that has its own lifecycle, such as a
+// dispatcher, a transport or a connection-oriented control.
+//
+// Stop should drain the in-flight work (e.g., release the connections and
+// flush the queues) before returning, or give up when ctx is done.
+type Component interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// ErrStackRunning is returned when Start is called on the running Stack.
+var ErrStackRunning = errors.New("sccp: stack is already running")
+
+// Stack owns the Components and starts and stops them in order, so that the
+// embedding applications get the correct shutdown semantics with one call.
+//
+// The Components are started in the order they are added, and stopped in the
+// reverse order. When stopping, the local subsystems are announced as
+// prohibited first, so that the peers stop sending the new traffic before
+// the Components are drained.
+type Stack struct {
+	mu         sync.Mutex
+	components []Component
+	mgmt       *SubsystemSimulator
+	localSSNs  []uint8
+	running    bool
+}
+
+// NewStack creates a new Stack with the given Components.
+func NewStack(components ...Component) *Stack {
+	return &Stack{components: components}
+}
+
+// Add adds the Components to the Stack. It should be called before Start.
+func (s *Stack) Add(components ...Component) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.components = append(s.components, components...)
+}
+
+// SetManagement sets the SubsystemSimulator used to send SSP for the local
+// subsystems given as localSSNs on Stop, and SSA on Start.
+func (s *Stack) SetManagement(mgmt *SubsystemSimulator, localSSNs ...uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mgmt = mgmt
+	s.localSSNs = localSSNs
+}
+
+// Start starts the Components in order, then announces the local subsystems
+// as allowed.
+//
+// If any Component fails to start, the ones already started are stopped in
+// the reverse order and the error is returned.
+func (s *Stack) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return ErrStackRunning
+	}
+
+	for i, c := range s.components {
+		if err := c.Start(ctx); err != nil {
+			return errors.Join(err, stopAll(ctx, s.components[:i]))
+		}
+	}
+
+	if s.mgmt != nil {
+		for _, ssn := range s.localSSNs {
+			if err := s.mgmt.Recover(ssn); err != nil {
+				return errors.Join(err, stopAll(ctx, s.components))
+			}
+		}
+	}
+
+	s.running = true
+	return nil
+}
+
+// Stop announces the local subsystems as prohibited, then stops the
+// Components in the reverse order.
+//
+// All the Components are stopped even if some of them fail, and the errors
+// are returned together. Stop does nothing if the Stack is not running.
+func (s *Stack) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return nil
+	}
+	s.running = false
+
+	var errs []error
+	if s.mgmt != nil {
+		for _, ssn := range s.localSSNs {
+			errs = append(errs, s.mgmt.Fail(ssn))
+		}
+	}
+
+	errs = append(errs, stopAll(ctx, s.components))
+	return errors.Join(errs...)
+}
+
+func stopAll(ctx context.Context, components []Component) error {
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		errs = append(errs, components[i].Stop(ctx))
+	}
+
+	return errors.Join(errs...)
+}
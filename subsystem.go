@@ -0,0 +1,274 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-sccp/params"
+	"github.com/wmnsk/go-sccp/ssn"
+)
+
+// SSNManagement is the Subsystem Number of SCCP management.
+const SSNManagement = ssn.SCMG
+
+// DefaultCoordChgInterval is the default value of the timer T(coord.chg), the
+// time to wait for the SOG after sending SOR in the coordinated state change.
+const DefaultCoordChgInterval = 30 * time.Second
+
+// ErrNoReplica is returned when the coordinated state change is requested for
+// the subsystem that has no replicate.
+var ErrNoReplica = errors.New("sccp: subsystem has no replicate")
+
+// SubsystemSimulator simulates the failure and recovery of the local
+// subsystems by sending the SCMG messages to the concerned signalling points.
+//
+// It does not implement any transport; the messages are handed to the send
+// function given to NewSubsystemSimulator, which is responsible for delivering
+// them to the signalling point in the Called Party Address. It is intended to
+// rehearse the failover behavior of the peers, e.g., against a test STP.
+//
+// SubsystemSimulator is safe for concurrent use.
+type SubsystemSimulator struct {
+	localPC uint16
+	send    func(Message) error
+
+	mu        sync.Mutex
+	concerned []uint16
+	replicas  map[uint8]uint16
+	down      map[uint8]bool
+
+	coordChg  time.Duration
+	pending   map[uint8]*coordChange
+	grantFunc func(ssn uint8, pc uint16) bool
+}
+
+// coordChange is a coordinated state change waiting for the SOG.
+type coordChange struct {
+	timer *time.Timer
+	done  func(granted bool)
+}
+
+// NewSubsystemSimulator creates a new SubsystemSimulator for the signalling
+// point identified by localPC.
+func NewSubsystemSimulator(localPC uint16, send func(Message) error) *SubsystemSimulator {
+	return &SubsystemSimulator{
+		localPC:  localPC,
+		send:     send,
+		replicas: map[uint8]uint16{},
+		down:     map[uint8]bool{},
+		coordChg: DefaultCoordChgInterval,
+		pending:  map[uint8]*coordChange{},
+	}
+}
+
+// SetCoordChgInterval sets the value of the timer T(coord.chg).
+func (s *SubsystemSimulator) SetCoordChgInterval(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.coordChg = d
+}
+
+// SetGrantFunc sets the function that decides whether the SOR from the
+// subsystem ssn at pc is granted. By default, it is granted if the local
+// replicate is available.
+func (s *SubsystemSimulator) SetGrantFunc(fn func(ssn uint8, pc uint16) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.grantFunc = fn
+}
+
+// AddConcerned adds the point codes that SSP and SSA are broadcast to.
+func (s *SubsystemSimulator) AddConcerned(pcs ...uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.concerned = append(s.concerned, pcs...)
+}
+
+// SetReplica sets the point code of the node that hosts the replicate of
+// the local subsystem ssn.
+func (s *SubsystemSimulator) SetReplica(ssn uint8, pc uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replicas[ssn] = pc
+}
+
+// IsAvailable reports whether the local subsystem ssn is available.
+func (s *SubsystemSimulator) IsAvailable(ssn uint8) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !s.down[ssn]
+}
+
+// Fail marks the local subsystem ssn as prohibited.
+//
+// If the subsystem is replicated, SOR is sent to the node hosting the
+// replicate first, then SSP is broadcast to all the concerned points.
+func (s *SubsystemSimulator) Fail(ssn uint8) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down[ssn] = true
+
+	if pc, ok := s.replicas[ssn]; ok {
+		if err := s.sendSCMG(pc, NewSCMG(SCMGTypeSOR, ssn, params.PointCode(s.localPC), 0, 0)); err != nil {
+			return err
+		}
+	}
+
+	return s.broadcast(NewSCMG(SCMGTypeSSP, ssn, params.PointCode(s.localPC), 0, 0))
+}
+
+// Recover marks the local subsystem ssn as allowed, and broadcasts SSA to
+// all the concerned points.
+func (s *SubsystemSimulator) Recover(ssn uint8) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.down, ssn)
+
+	return s.broadcast(NewSCMG(SCMGTypeSSA, ssn, params.PointCode(s.localPC), 0, 0))
+}
+
+// RequestOutOfService starts the coordinated state change of the local
+// subsystem ssn defined in Q.714 5.3.5.
+//
+// SOR is sent to the node hosting the replicate, and done is called with true
+// when the SOG is given to HandleSCMG, after the subsystem is marked as
+// prohibited and SSP is broadcast. If the SOG is not received before the timer
+// T(coord.chg) expires, done is called with false and the subsystem stays
+// allowed. done may be nil.
+func (s *SubsystemSimulator) RequestOutOfService(ssn uint8, done func(granted bool)) error {
+	s.mu.Lock()
+	pc, ok := s.replicas[ssn]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to request out of service of SSN %d: %w", ssn, ErrNoReplica)
+	}
+	if _, ok := s.pending[ssn]; ok {
+		s.mu.Unlock()
+		return nil
+	}
+
+	c := &coordChange{done: done}
+	c.timer = time.AfterFunc(s.coordChg, func() {
+		if s.finishCoordChange(ssn, c) && c.done != nil {
+			c.done(false)
+		}
+	})
+	s.pending[ssn] = c
+	s.mu.Unlock()
+
+	// the lock is not held while sending, as the SOG may be given to
+	// HandleSCMG before send returns.
+	if err := s.sendSCMG(pc, NewSCMG(SCMGTypeSOR, ssn, params.PointCode(s.localPC), 0, 0)); err != nil {
+		c.timer.Stop()
+		s.finishCoordChange(ssn, c)
+		return err
+	}
+
+	return nil
+}
+
+// finishCoordChange removes c from the pending changes, and reports whether
+// it was still pending.
+func (s *SubsystemSimulator) finishCoordChange(ssn uint8, c *coordChange) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending[ssn] != c {
+		return false
+	}
+	delete(s.pending, ssn)
+	return true
+}
+
+// HandleSCMG handles the SCMG messages for the coordinated state change.
+//
+// On SOG for a local subsystem waiting for it, the subsystem is marked as
+// prohibited and SSP is broadcast. On SOR from the remote subsystem whose
+// replicate is local, SOG is sent back if it is granted (see SetGrantFunc).
+// The other SCMG messages are ignored.
+func (s *SubsystemSimulator) HandleSCMG(scmg *SCMG) error {
+	switch scmg.Type {
+	case SCMGTypeSOG:
+		s.mu.Lock()
+		c, ok := s.pending[scmg.AffectedSSN]
+		if !ok {
+			s.mu.Unlock()
+			return nil
+		}
+		c.timer.Stop()
+		delete(s.pending, scmg.AffectedSSN)
+		s.down[scmg.AffectedSSN] = true
+		err := s.broadcast(NewSCMG(SCMGTypeSSP, scmg.AffectedSSN, params.PointCode(s.localPC), 0, 0))
+		s.mu.Unlock()
+
+		if c.done != nil {
+			c.done(true)
+		}
+		return err
+	case SCMGTypeSOR:
+		pc, ssn := uint16(scmg.AffectedPC), scmg.AffectedSSN
+
+		s.mu.Lock()
+		granted, grantFunc := !s.down[ssn], s.grantFunc
+		s.mu.Unlock()
+
+		if grantFunc != nil {
+			granted = grantFunc(ssn, pc)
+		}
+		if !granted {
+			return nil
+		}
+		return s.sendSCMG(pc, NewSCMG(SCMGTypeSOG, ssn, scmg.AffectedPC, 0, 0))
+	default:
+		return nil
+	}
+}
+
+func (s *SubsystemSimulator) broadcast(scmg *SCMG) error {
+	for _, pc := range s.concerned {
+		if err := s.sendSCMG(pc, scmg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SubsystemSimulator) sendSCMG(pc uint16, scmg *SCMG) error {
+	return sendSCMG(s.send, params.PointCode(s.localPC), params.PointCode(pc), scmg)
+}
+
+// sendSCMG sends the SCMG in a UDT from the SCMG of localPC to the SCMG of pc.
+func sendSCMG(send func(Message) error, localPC, pc params.PointCode, scmg *SCMG) error {
+	data, err := scmg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	ai := params.NewAddressIndicator(true, true, true, params.GTINoGT)
+	u := NewUDT(
+		0, false,
+		params.NewCalledPartyAddress(ai, pc, SSNManagement, nil),
+		params.NewCallingPartyAddress(ai, localPC, SSNManagement, nil),
+		data,
+	)
+
+	if err := send(u); err != nil {
+		return fmt.Errorf("failed to send %s to PC %d: %w", scmg.MessageTypeName(), pc, err)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@
 package sccp
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 
@@ -19,24 +20,22 @@ type UDT struct {
 	CallingPartyAddress *params.PartyAddress
 	Data                *params.Data
 
-	ptr1, ptr2, ptr3 uint8
+	ptr1, ptr2, ptr3 uint8  // as parsed, see RawPointers.
+	lazyData         []byte // the Data not decoded yet, see LoadData.
+	trailing         []byte
+	opts             parseOptions
+	reuse            *reusable
 }
 
 // NewUDT creates a new UDT.
 func NewUDT(pcls int, retOnErr bool, cdpa, cgpa *params.PartyAddress, data []byte) *UDT {
-	u := &UDT{
+	return &UDT{
 		Type:                MsgTypeUDT,
 		ProtocolClass:       params.NewProtocolClass(pcls, retOnErr),
 		CalledPartyAddress:  cdpa,
 		CallingPartyAddress: cgpa,
 		Data:                params.NewData(data),
 	}
-
-	u.ptr1 = 3
-	u.ptr2 = u.ptr1 + uint8(cdpa.MarshalLen()) - 1
-	u.ptr3 = u.ptr2 + uint8(cgpa.MarshalLen()) - 1
-
-	return u
 }
 
 // MarshalBinary returns the byte sequence generated from a UDT instance.
@@ -50,56 +49,85 @@ func (u *UDT) MarshalBinary() ([]byte, error) {
 }
 
 // MarshalTo puts the byte sequence in the byte array given as b.
-// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+// The pointers are computed from the parameters set at the time of calling it.
 func (u *UDT) MarshalTo(b []byte) error {
-	l := len(b)
-	if l < 5 {
-		return io.ErrUnexpectedEOF
+	if err := validateProtocolClass(u.Type, u.ProtocolClass); err != nil {
+		return err
 	}
 
-	b[0] = uint8(u.Type)
+	return marshalSections(b, u.Type, u.fixed(), u.variable(), nil, false)
+}
 
-	n := 1
-	m, err := u.ProtocolClass.Write(b[1:])
-	if err != nil {
-		return err
-	}
-	n += m
+// AppendTo appends the byte sequence generated from the UDT to dst, and
+// returns the extended slice.
+func (u *UDT) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, u)
+}
 
-	b[n] = u.ptr1
-	if p := int(u.ptr1); l < p {
-		return io.ErrUnexpectedEOF
-	}
-	b[n+1] = u.ptr2
-	if p := int(u.ptr2 + 3); l < p {
-		return io.ErrUnexpectedEOF
-	}
-	b[n+2] = u.ptr3
-	if p := int(u.ptr3 + 5); l < p {
-		return io.ErrUnexpectedEOF
-	}
-	n += 3
+// WriteTo writes the byte sequence generated from the UDT to w. It implements
+// io.WriterTo.
+func (u *UDT) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, u)
+}
 
-	cdpaEnd := int(u.ptr2 + 3)
-	cgpaEnd := int(u.ptr3 + 4)
-	if _, err := u.CalledPartyAddress.Write(b[n:cdpaEnd]); err != nil {
-		return err
+// fixed returns the mandatory fixed parameters of the UDT.
+func (u *UDT) fixed() []params.Parameter {
+	return []params.Parameter{u.ProtocolClass}
+}
+
+// variable returns the mandatory variable parameters of the UDT.
+func (u *UDT) variable() []params.Parameter {
+	return []params.Parameter{u.CalledPartyAddress, u.CallingPartyAddress, u.dataParameter()}
+}
+
+// RawPointers returns the pointers to the Called Party Address, the Calling
+// Party Address and the Data as they were in the byte sequence the UDT is
+// parsed from, which may be useful to diagnose malformed messages. They are
+// zero if the UDT is not parsed, and not used by MarshalTo.
+func (u *UDT) RawPointers() [3]uint8 {
+	return [3]uint8{u.ptr1, u.ptr2, u.ptr3}
+}
+
+// LoadData returns the Data, decoding it first if the UDT is parsed with
+// WithLazyData and it is not decoded yet. The decoded Data is set to the Data
+// field, so LoadData must not be called concurrently with the other uses of
+// the UDT. String, LogValue and MarshalJSON do not set it.
+func (u *UDT) LoadData() *params.Data {
+	if u.Data == nil && u.lazyData != nil {
+		// the length is already checked on parsing.
+		if d, _, err := params.ParseData(u.lazyData); err == nil {
+			u.Data, u.lazyData = d, nil
+		}
 	}
 
-	if _, err := u.CallingPartyAddress.Write(b[cdpaEnd:cgpaEnd]); err != nil {
-		return err
+	return u.Data
+}
+
+// peekData returns the Data as LoadData does, but without setting the Data
+// field, so that formatting the UDT does not modify it.
+func (u *UDT) peekData() *params.Data {
+	if u.Data == nil && u.lazyData != nil {
+		if d, _, err := params.ParseData(u.lazyData); err == nil {
+			return d
+		}
 	}
 
-	if _, err := u.Data.Write(b[cgpaEnd:]); err != nil {
-		return err
+	return u.Data
+}
+
+// dataParameter returns the Data to be marshaled, which is the one not
+// decoded yet as it is if the Data field is not set.
+func (u *UDT) dataParameter() params.Parameter {
+	if u.Data == nil && u.lazyData != nil {
+		return &rawParameter{code: params.PCodeData, b: u.lazyData}
 	}
 
-	return nil
+	return u.Data
 }
 
 // ParseUDT decodes given byte sequence as a SCCP UDT.
-func ParseUDT(b []byte) (*UDT, error) {
-	u := &UDT{}
+func ParseUDT(b []byte, opts ...ParseOption) (*UDT, error) {
+	u := &UDT{opts: *newParseOptions(opts)}
 	if err := u.UnmarshalBinary(b); err != nil {
 		return nil, err
 	}
@@ -115,13 +143,17 @@ func (u *UDT) UnmarshalBinary(b []byte) error {
 	}
 
 	u.Type = MsgType(b[0])
+	spare := u.reuse.take()
 
 	offset := 1
-	u.ProtocolClass = &params.ProtocolClass{}
+	u.ProtocolClass = spare.protocolClass()
 	n, err := u.ProtocolClass.Read(b[offset:])
 	if err != nil {
 		return err
 	}
+	if err := validateProtocolClass(u.Type, u.ProtocolClass); err != nil {
+		return err
+	}
 	offset += n
 
 	u.ptr1 = b[offset]
@@ -153,34 +185,74 @@ func (u *UDT) UnmarshalBinary(b []byte) error {
 		return io.ErrUnexpectedEOF
 	}
 
-	u.CalledPartyAddress, _, err = params.ParseCalledPartyAddress(b[offsetPtr1:cdpaEnd])
+	u.CalledPartyAddress, err = u.opts.readPartyAddress(spare.cdpa, params.PCodeCalledPartyAddress, b[offsetPtr1:cdpaEnd])
 	if err != nil {
 		return err
 	}
 
-	u.CallingPartyAddress, _, err = params.ParseCallingPartyAddress(b[offsetPtr2:cgpaEnd])
+	u.CallingPartyAddress, err = u.opts.readPartyAddress(spare.cgpa, params.PCodeCallingPartyAddress, b[offsetPtr2:cgpaEnd])
 	if err != nil {
 		return err
 	}
 
-	u.Data = &params.Data{}
-	if _, err := u.Data.Read(b[offsetPtr3:dataEnd]); err != nil {
-		return err
+	u.Data, u.lazyData = nil, nil
+	if u.opts.lazyData {
+		u.lazyData = b[offsetPtr3:dataEnd]
+	} else {
+		u.Data = spare.dataParam()
+		if _, err := u.Data.Read(b[offsetPtr3:dataEnd]); err != nil {
+			return err
+		}
+	}
+	u.reuse.set(reusable{pcls: u.ProtocolClass, cdpa: u.CalledPartyAddress, cgpa: u.CallingPartyAddress, data: u.Data})
+
+	u.trailing = nil
+	if end := max(cdpaEnd, cgpaEnd, dataEnd); l > end {
+		u.trailing = b[end:]
 	}
 
 	return nil
 }
 
-// MarshalLen returns the serial length.
-func (u *UDT) MarshalLen() int {
-	l := 5 // MsgType, ProtocolClass, pointers
+// Clone returns a deep copy of the UDT that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (u *UDT) Clone() *UDT {
+	c := *u
+	c.ProtocolClass = clonePtr(u.ProtocolClass)
+	c.CalledPartyAddress = u.CalledPartyAddress.Clone()
+	c.CallingPartyAddress = u.CallingPartyAddress.Clone()
+	c.Data = u.Data.Clone()
+	c.trailing = bytes.Clone(u.trailing)
+	c.lazyData = bytes.Clone(u.lazyData)
+	c.reuse = nil
+
+	return &c
+}
 
-	l += int(u.ptr3) - 1 // length without Data
-	if param := u.Data; param != nil {
-		l += param.MarshalLen()
-	}
+// Reset clears the UDT to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+//
+// If the UDT is taken from Pool, the parameters allocated by UnmarshalBinary
+// are kept and overwritten by the next UnmarshalBinary, so they must not be
+// used after Reset.
+func (u *UDT) Reset() {
+	u.reuse.keep(u.ProtocolClass, nil, u.CalledPartyAddress, u.CallingPartyAddress, u.Data)
+	*u = UDT{opts: u.opts, reuse: u.reuse}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the UDT computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (u *UDT) TrailingBytes() []byte {
+	return u.trailing
+}
 
-	return l
+// MarshalLen returns the serial length.
+func (u *UDT) MarshalLen() int {
+	return 1 + params.SectionsLen(u.fixed(), u.variable(), nil, false)
 }
 
 // String returns the UDT values in human readable format.
@@ -190,7 +262,7 @@ func (u *UDT) String() string {
 		u.ProtocolClass,
 		u.CalledPartyAddress,
 		u.CallingPartyAddress,
-		u.Data,
+		u.peekData(),
 	)
 }
 
@@ -0,0 +1,159 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// UDTS represents a SCCP Message Unitdata Service (UDTS).
+type UDTS struct {
+	Type                MsgType
+	ReturnCause         *params.ReturnCause
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	Data                *params.Data
+
+	trailing []byte
+	opts     parseOptions
+}
+
+// NewUDTS creates a new UDTS.
+func NewUDTS(cause params.ReturnCauseValue, cdpa, cgpa *params.PartyAddress, data []byte) *UDTS {
+	return &UDTS{
+		Type:                MsgTypeUDTS,
+		ReturnCause:         params.NewCause(cause),
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		Data:                params.NewData(data),
+	}
+}
+
+// MarshalBinary returns the byte sequence generated from a UDTS instance.
+func (u *UDTS) MarshalBinary() ([]byte, error) {
+	b := make([]byte, u.MarshalLen())
+	if err := u.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (u *UDTS) MarshalTo(b []byte) error {
+	return marshalSections(b, u.Type, u.fixed(), u.variable(), nil, false)
+}
+
+// AppendTo appends the byte sequence generated from the UDTS to dst, and
+// returns the extended slice.
+func (u *UDTS) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, u)
+}
+
+// WriteTo writes the byte sequence generated from the UDTS to w. It implements
+// io.WriterTo.
+func (u *UDTS) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, u)
+}
+
+// fixed returns the mandatory fixed parameters of the UDTS.
+func (u *UDTS) fixed() []params.Parameter {
+	return []params.Parameter{u.ReturnCause}
+}
+
+// variable returns the mandatory variable parameters of the UDTS.
+func (u *UDTS) variable() []params.Parameter {
+	return []params.Parameter{u.CalledPartyAddress, u.CallingPartyAddress, u.Data}
+}
+
+// ParseUDTS decodes given byte sequence as a SCCP UDTS.
+func ParseUDTS(b []byte, opts ...ParseOption) (*UDTS, error) {
+	u := &UDTS{opts: *newParseOptions(opts)}
+	if err := u.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP UDTS.
+func (u *UDTS) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	u.Type = MsgType(b[0])
+	u.ReturnCause = &params.ReturnCause{}
+	u.CalledPartyAddress = params.NewCalledPartyAddress(0, 0, 0, nil)
+	u.CallingPartyAddress = params.NewCallingPartyAddress(0, 0, 0, nil)
+	u.Data = &params.Data{}
+
+	_, n, err := u.opts.unmarshalSections(b[1:], u.fixed(), u.variable(), false)
+	if err != nil {
+		return err
+	}
+
+	u.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the UDTS that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (u *UDTS) Clone() *UDTS {
+	c := *u
+	c.ReturnCause = clonePtr(u.ReturnCause)
+	c.CalledPartyAddress = u.CalledPartyAddress.Clone()
+	c.CallingPartyAddress = u.CallingPartyAddress.Clone()
+	c.Data = u.Data.Clone()
+	c.trailing = bytes.Clone(u.trailing)
+
+	return &c
+}
+
+// Reset clears the UDTS to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (u *UDTS) Reset() {
+	*u = UDTS{opts: u.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the UDTS computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (u *UDTS) TrailingBytes() []byte {
+	return u.trailing
+}
+
+// MarshalLen returns the serial length.
+func (u *UDTS) MarshalLen() int {
+	return 1 + params.SectionsLen(u.fixed(), u.variable(), nil, false)
+}
+
+// String returns the UDTS values in human readable format.
+func (u *UDTS) String() string {
+	return fmt.Sprintf("%s: {ReturnCause: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, Data: %s}",
+		u.Type,
+		u.ReturnCause,
+		u.CalledPartyAddress,
+		u.CallingPartyAddress,
+		u.Data,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (u *UDTS) MessageType() MsgType {
+	return MsgTypeUDTS
+}
+
+// MessageTypeName returns the Message Type in string.
+func (u *UDTS) MessageTypeName() string {
+	return u.MessageType().String()
+}
@@ -0,0 +1,135 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// The maximum sizes of a SCCP message carried in a MTP3 MSU, which are the
+// SIF of 272 octets without the routing label of each variant.
+const (
+	MaxMessageSizeITU   = 272 - 4
+	MaxMessageSizeANSI  = 272 - 7
+	MaxMessageSizeChina = 272 - 7
+	MaxMessageSizeTTC   = 272 - 5
+)
+
+// maxMessageSize returns the maximum size of a SCCP message in v.
+func maxMessageSize(v params.Variant) int {
+	switch v {
+	case params.VariantANSI:
+		return MaxMessageSizeANSI
+	case params.VariantChina:
+		return MaxMessageSizeChina
+	case params.VariantTTC:
+		return MaxMessageSizeTTC
+	default:
+		return MaxMessageSizeITU
+	}
+}
+
+// UnitdataOptions is the values set in the messages created by BuildUnitdata.
+type UnitdataOptions struct {
+	// ProtocolClass is the protocol class of the messages, 0 or 1.
+	ProtocolClass int
+	// ReturnOnError is the return option of the messages.
+	ReturnOnError bool
+	// HopCounter is the Hop Counter of the XUDTs and LUDTs. DefaultHopCounter
+	// is used if it is 0.
+	HopCounter uint8
+
+	// Variant selects the default of MaxMessageSize.
+	Variant params.Variant
+	// MaxMessageSize is the maximum size of each message in octets, which is
+	// the one of Variant, e.g., MaxMessageSizeITU, if 0.
+	MaxMessageSize int
+
+	// LocalReference is the Segmentation Local Reference used if the data is
+	// segmented.
+	LocalReference uint32
+
+	// Optionals are the optional parameters, e.g., created with
+	// params.NewImportanceOptional. XUDT is used if any is given, as UDT
+	// cannot carry them.
+	Optionals []params.Parameter
+
+	// ForceXUDT makes XUDT used instead of UDT even if there is no optional
+	// parameter, and instead of LUDT even if the data fits in one, with the
+	// size checked and the data segmented in the XUDT encoding.
+	ForceXUDT bool
+}
+
+// BuildUnitdata creates the connectionless messages to send data from cgpa to
+// cdpa, choosing the smallest format that fits in opts.MaxMessageSize:
+//
+//   - a UDT, if there is no optional parameter and ForceXUDT is not set,
+//   - an XUDT without the Segmentation,
+//   - a LUDT, if ForceXUDT is not set and the data is up to MaxLongDataSize,
+//   - XUDTs with the data segmented by Segment.
+//
+// As the maximum sizes of the variants are the ones of the MTP3 MSU, LUDT is
+// chosen only if MaxMessageSize is set to the one of the MTP that carries the
+// long messages, such as M3UA. ErrTooManySegments is returned if the data
+// does not fit in MaxSegments XUDTs.
+func BuildUnitdata(cdpa, cgpa *params.PartyAddress, data []byte, opts UnitdataOptions) ([]Message, error) {
+	maxSize := opts.MaxMessageSize
+	if maxSize == 0 {
+		maxSize = maxMessageSize(opts.Variant)
+	}
+
+	hc := opts.HopCounter
+	if hc == 0 {
+		hc = DefaultHopCounter
+	}
+
+	if len(data) <= MaxSegmentSize {
+		if len(opts.Optionals) == 0 && !opts.ForceXUDT {
+			if udt := NewUDT(opts.ProtocolClass, opts.ReturnOnError, cdpa, cgpa, data); udt.MarshalLen() <= maxSize {
+				return []Message{udt}, nil
+			}
+		}
+		if xudt := NewXUDT(opts.ProtocolClass, opts.ReturnOnError, hc, cdpa, cgpa, data, opts.Optionals...); xudt.MarshalLen() <= maxSize {
+			return []Message{xudt}, nil
+		}
+	}
+
+	if len(data) <= MaxLongDataSize && !opts.ForceXUDT {
+		if ludt := NewLUDT(opts.ProtocolClass, opts.ReturnOnError, hc, cdpa, cgpa, data, opts.Optionals...); ludt.MarshalLen() <= maxSize {
+			return []Message{ludt}, nil
+		}
+	}
+
+	// the size of the XUDT with the Segmentation and no data.
+	overhead := NewXUDT(
+		opts.ProtocolClass, opts.ReturnOnError, hc, cdpa, cgpa, nil,
+		append([]params.Parameter{params.NewSegmentationOptional(true, 0, 0, 0)}, opts.Optionals...)...,
+	).MarshalLen()
+	size := min(maxSize-overhead, MaxSegmentSize)
+	if size < 1 {
+		return nil, fmt.Errorf("no room for data in %d octets: %w", maxSize, ErrInvalidSegmentSize)
+	}
+
+	xudts, err := Segment(data, size, SegmentOptions{
+		ProtocolClass:       opts.ProtocolClass,
+		ReturnOnError:       opts.ReturnOnError,
+		HopCounter:          hc,
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		LocalReference:      opts.LocalReference,
+		Optionals:           opts.Optionals,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]Message, len(xudts))
+	for i, x := range xudts {
+		msgs[i] = x
+	}
+	return msgs, nil
+}
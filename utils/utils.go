@@ -7,6 +7,7 @@ package utils
 
 import (
 	"encoding/hex"
+	"strings"
 )
 
 // BCDEncode encodes a string into BCD-encoded bytes.
@@ -35,18 +36,42 @@ func BCDDecode(isOdd bool, b []byte) string {
 // The second parameter is the hex character(0-f) to fill the last digit when
 // handling a odd number. "f" is used In most cases.
 func StrToSwappedBytes(s, filler string) ([]byte, error) {
-	var raw []byte
-	var err error
-	if len(s)%2 == 0 {
-		raw, err = hex.DecodeString(s)
-	} else {
-		raw, err = hex.DecodeString(s + filler)
+	n := len(s)
+	if n%2 != 0 {
+		n += len(filler)
 	}
-	if err != nil {
-		return nil, err
+	if n%2 != 0 {
+		return nil, hex.ErrLength
+	}
+
+	// the digits are read from s followed by filler, without concatenating them.
+	digit := func(i int) (byte, error) {
+		var c byte
+		if i < len(s) {
+			c = s[i]
+		} else {
+			c = filler[i-len(s)]
+		}
+		if v, ok := fromHexChar(c); ok {
+			return v, nil
+		}
+		return 0, hex.InvalidByteError(c)
+	}
+
+	b := make([]byte, n/2)
+	for i := range b {
+		lo, err := digit(2 * i)
+		if err != nil {
+			return nil, err
+		}
+		hi, err := digit(2*i + 1)
+		if err != nil {
+			return nil, err
+		}
+		b[i] = hi<<4 | lo
 	}
 
-	return swap(raw), nil
+	return b, nil
 }
 
 // SwappedBytesToStr decodes raw swapped bytes into string.
@@ -54,21 +79,35 @@ func StrToSwappedBytes(s, filler string) ([]byte, error) {
 //
 // The second parameter is to decide whether to cut the last digit or not.
 func SwappedBytesToStr(raw []byte, cutLastDigit bool) string {
-	s := hex.EncodeToString(swap(raw))
-	if cutLastDigit {
-		s = s[:len(s)-1]
+	n := 2 * len(raw)
+	if cutLastDigit && n > 0 {
+		n--
+	}
+
+	var sb strings.Builder
+	sb.Grow(n)
+	for i := 0; i < n; i++ {
+		nibble := raw[i/2] >> (4 * (i % 2)) & 0xf
+		sb.WriteByte(hexDigits[nibble])
 	}
 
-	return s
+	return sb.String()
 }
 
-func swap(raw []byte) []byte {
-	swapped := make([]byte, len(raw))
-	for n := range raw {
-		t := ((raw[n] >> 4) & 0xf) + ((raw[n] << 4) & 0xf0)
-		swapped[n] = t
+const hexDigits = "0123456789abcdef"
+
+// fromHexChar converts a hex character into its value, in the same way as
+// encoding/hex.
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
 	}
-	return swapped
+	return 0, false
 }
 
 // Uint24To32 converts 24bits-length []byte value into the uint32 with 8bits of zeros as prefix.
@@ -138,3 +177,163 @@ func DecodePLMN(b []byte) (mcc, mnc string, err error) {
 
 	return
 }
+
+// DecodeHexDump decodes the hex dump in s into bytes, ignoring the whitespace.
+//
+// It accepts the plain hex string with or without the separators such as
+// "0a0b0c", "0a 0b 0c", "0a:0b:0c" and "0x0a, 0x0b, 0x0c", the escaped string
+// such as "\x0a\x0b\x0c", and the C array copied from Wireshark. The lines that
+// start with the offset, which are copied from Wireshark, tshark -x, xxd or
+// hexdump -C, are also accepted and the offset and the ASCII columns are
+// discarded.
+func DecodeHexDump(s string) ([]byte, error) {
+	// the C array has the bytes in the braces.
+	if i := strings.IndexByte(s, '{'); i >= 0 {
+		s = s[i+1:]
+		if j := strings.IndexByte(s, '}'); j >= 0 {
+			s = s[:j]
+		}
+	}
+
+	type dumpLine struct {
+		offset    uint64
+		width     int
+		hasOffset bool
+		rest      string
+	}
+
+	var lines []dumpLine
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+
+		// hexdump -C ends with the line that has the offset only.
+		if n := len(lines); n > 0 && lines[n-1].hasOffset && len(l) == lines[n-1].width {
+			if offset, _, ok := splitOffset(l + ":"); ok {
+				lines = append(lines, dumpLine{offset: offset, width: len(l), hasOffset: true})
+				continue
+			}
+		}
+
+		offset, rest, ok := splitOffset(l)
+		lines = append(lines, dumpLine{offset: offset, width: len(l) - len(rest), hasOffset: ok, rest: rest})
+	}
+
+	var digits strings.Builder
+	for i, l := range lines {
+		if !l.hasOffset {
+			if err := appendHexDigits(&digits, l.rest); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// the number of octets in the line is known from the offset of the
+		// next line, which is needed to tell the ASCII column that looks
+		// like hex.
+		n := 0
+		if i+1 < len(lines) && lines[i+1].hasOffset && lines[i+1].offset > l.offset {
+			n = int(lines[i+1].offset - l.offset)
+		}
+		digits.WriteString(dumpDigits(l.rest, n))
+	}
+
+	return hex.DecodeString(digits.String())
+}
+
+// splitOffset returns the offset at the head of a line in the hex dump and
+// the rest of the line. The offset should be 4 digits or more followed by a
+// colon or two spaces or more.
+func splitOffset(l string) (uint64, string, bool) {
+	i := 0
+	for i < len(l) {
+		if _, ok := fromHexChar(l[i]); !ok {
+			break
+		}
+		i++
+	}
+	if i < 4 || i > 16 {
+		return 0, l, false
+	}
+
+	rest := l[i:]
+	switch {
+	case strings.HasPrefix(rest, ":"):
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "  "), strings.HasPrefix(rest, "\t"):
+	default:
+		return 0, l, false
+	}
+
+	var offset uint64
+	for _, c := range []byte(l[:i]) {
+		v, _ := fromHexChar(c)
+		offset = offset<<4 | uint64(v)
+	}
+	return offset, rest, true
+}
+
+// dumpDigits returns the hex digits in a line of the hex dump after the
+// offset, up to n octets if n is not zero. The ASCII column is detected by
+// the gap of three spaces or more, or by the leading "|".
+func dumpDigits(rest string, n int) string {
+	var b strings.Builder
+	for len(rest) > 0 {
+		gap := 0
+		for len(rest) > 0 && (rest[0] == ' ' || rest[0] == '\t') {
+			if rest[0] == '\t' {
+				gap += 3
+			} else {
+				gap++
+			}
+			rest = rest[1:]
+		}
+		if rest == "" || gap >= 3 && b.Len() > 0 {
+			break
+		}
+
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		token := rest[:end]
+		rest = rest[end:]
+
+		if len(token)%2 != 0 || strings.TrimFunc(token, func(r rune) bool {
+			_, ok := fromHexChar(byte(r))
+			return r < 0x80 && ok
+		}) != "" {
+			break
+		}
+
+		b.WriteString(token)
+		if n > 0 && b.Len() >= n*2 {
+			return b.String()[:n*2]
+		}
+	}
+
+	return b.String()
+}
+
+// appendHexDigits appends the hex digits in l to b, discarding the prefixes
+// and separators.
+func appendHexDigits(b *strings.Builder, l string) error {
+	l = strings.NewReplacer("0x", "", "0X", "", `\x`, "").Replace(l)
+	for i := 0; i < len(l); i++ {
+		c := l[i]
+		if _, ok := fromHexChar(c); ok {
+			b.WriteByte(c)
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\r', ',', ':', ';', '-', '"', '\'':
+		default:
+			return hex.InvalidByteError(c)
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,9 @@
 package utils_test
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -21,6 +24,18 @@ func TestBCDEncoding(t *testing.T) {
 			"imsi",
 			"123451234567890",
 			[]byte{0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0},
+		}, {
+			"even",
+			"81901234",
+			[]byte{0x18, 0x09, 0x21, 0x43},
+		}, {
+			"hex digits",
+			"0abcdef",
+			[]byte{0xa0, 0xcb, 0xed, 0xff},
+		}, {
+			"empty",
+			"",
+			[]byte{},
 		},
 	}
 
@@ -37,7 +52,7 @@ func TestBCDEncoding(t *testing.T) {
 		})
 
 		t.Run("Bytes2Str/"+c.description, func(t *testing.T) {
-			str := utils.SwappedBytesToStr(c.bytes, true)
+			str := utils.SwappedBytesToStr(c.bytes, len(c.str)%2 == 1)
 
 			if diff := cmp.Diff(str, c.str); diff != "" {
 				t.Error(diff)
@@ -46,6 +61,38 @@ func TestBCDEncoding(t *testing.T) {
 	}
 }
 
+func TestStrToSwappedBytesErrors(t *testing.T) {
+	if b, err := utils.StrToSwappedBytes("12AB", "f"); err != nil || !bytes.Equal(b, []byte{0x21, 0xba}) {
+		t.Errorf("upper case: got %x, %v", b, err)
+	}
+	if _, err := utils.StrToSwappedBytes("12x4", "f"); !errors.Is(err, hex.InvalidByteError('x')) {
+		t.Errorf("invalid digit: got %v", err)
+	}
+	if _, err := utils.StrToSwappedBytes("123", "x"); !errors.Is(err, hex.InvalidByteError('x')) {
+		t.Errorf("invalid filler: got %v", err)
+	}
+	if _, err := utils.StrToSwappedBytes("123", ""); !errors.Is(err, hex.ErrLength) {
+		t.Errorf("odd without filler: got %v", err)
+	}
+}
+
+func BenchmarkBCDEncode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := utils.BCDEncode("123451234567890"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBCDDecode(b *testing.B) {
+	raw := []byte{0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = utils.BCDDecode(true, raw)
+	}
+}
+
 func TestUint32And24(t *testing.T) {
 	cases := []struct {
 		description string
@@ -154,3 +201,64 @@ func TestPLMN(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeHexDump(t *testing.T) {
+	want := []byte{
+		0x09, 0x81, 0x03, 0x0e, 0x19, 0x0b, 0x12, 0x06, 0x00, 0x12, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09,
+		0x41, 0x42,
+	}
+
+	cases := []struct {
+		description string
+		dump        string
+	}{
+		{"stream", "098103 0e190b120600120421436587094142"},
+		{"spaced", "09 81 03 0e 19 0b 12 06\n00 12 04 21 43 65 87 09 41 42\n"},
+		{"colon", "09:81:03:0E:19:0B:12:06:00:12:04:21:43:65:87:09:41:42"},
+		{"escaped", `"\x09\x81\x03\x0e\x19\x0b\x12\x06\x00\x12\x04\x21\x43\x65\x87\x09\x41\x42"`},
+		{
+			"C array",
+			"static const unsigned char pkt1[18] = {\n" +
+				"0x09, 0x81, 0x03, 0x0e, 0x19, 0x0b, 0x12, 0x06,\n" +
+				"0x00, 0x12, 0x04, 0x21, 0x43, 0x65, 0x87, 0x09,\n" +
+				"0x41, 0x42 };\n",
+		},
+		{
+			"Wireshark",
+			"0000   09 81 03 0e 19 0b 12 06 00 12 04 21 43 65 87 09   ...........!Ce..\n" +
+				"0010   41 42                                             AB\n",
+		},
+		{
+			"hexdump -C",
+			"00000000  09 81 03 0e 19 0b 12 06  00 12 04 21 43 65 87 09  |...........!Ce..|\n" +
+				"00000010  41 42                                             |AB|\n" +
+				"00000012\n",
+		},
+		{
+			"xxd",
+			"00000000: 0981 030e 190b 1206 0012 0421 4365 8709  ...........!Ce..\n" +
+				"00000010: 4142                                     AB\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := utils.DecodeHexDump(c.dump)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := utils.DecodeHexDump("09 8z"); err == nil {
+			t.Error("got no error with invalid character")
+		}
+		if _, err := utils.DecodeHexDump("09 8"); !errors.Is(err, hex.ErrLength) {
+			t.Errorf("got %v, want hex.ErrLength", err)
+		}
+	})
+}
@@ -0,0 +1,245 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// View is a read-only view of a connectionless message, i.e., UDT, UDTS, XUDT
+// or XUDTS, that refers to the byte sequence it is parsed from instead of
+// decoding it into a Message. Only the pointers are checked on parsing, and
+// the parameters are decoded when they are accessed.
+//
+// It is meant for the applications that only look at a few parameters of
+// each message, such as an STP routing on the Called Party Address. Parsing
+// into a reused View does not allocate.
+//
+// The View and the slices returned by it alias the byte sequence given to
+// UnmarshalBinary or ParseView, which must not be modified or reused while
+// they are in use. The PartyAddresses and the other parameters returned by
+// the View are decoded into new values, but may still refer to it in the
+// same way as the ones in a Message. Use Message to get the Message that can
+// be kept after the byte sequence is reused, with Clone.
+type View struct {
+	b    []byte
+	opts parseOptions
+
+	// the positions of the parameters in b.
+	cdpa, cgpa, data [2]int
+	optional         int
+}
+
+// ParseView parses b as a View. See View for the aliasing rules.
+func ParseView(b []byte, opts ...ParseOption) (*View, error) {
+	return codecOf(opts).ParseView(b)
+}
+
+// UnmarshalBinary sets b to the View, after checking the pointers in it.
+//
+// The View keeps the ParseOptions it is created with by ParseView, or the
+// default ones if it is the zero value.
+func (v *View) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	// the number of the fixed parameters, which are one octet each.
+	nfixed := 0
+	switch MsgType(b[0]) {
+	case MsgTypeUDT, MsgTypeUDTS:
+		nfixed = 1
+	case MsgTypeXUDT, MsgTypeXUDTS:
+		nfixed = 2
+	default:
+		return UnsupportedTypeError(b[0])
+	}
+	hasOptionalPart := nfixed == 2
+
+	at := 1 + nfixed
+	nptr := 3
+	if hasOptionalPart {
+		nptr++
+	}
+	if len(b) < at+nptr {
+		return io.ErrUnexpectedEOF
+	}
+
+	v.b = b
+	for i, pos := range []*[2]int{&v.cdpa, &v.cgpa, &v.data} {
+		start := at + i + int(b[at+i])
+		if b[at+i] == 0 || len(b) < start+1 {
+			return io.ErrUnexpectedEOF
+		}
+		end := start + 1 + int(b[start])
+		if len(b) < end {
+			return io.ErrUnexpectedEOF
+		}
+		*pos = [2]int{start + 1, end}
+	}
+
+	v.optional = 0
+	if hasOptionalPart && b[at+3] != 0 {
+		v.optional = at + 3 + int(b[at+3])
+		if len(b) < v.optional+1 {
+			return io.ErrUnexpectedEOF
+		}
+	}
+
+	return nil
+}
+
+// Bytes returns the byte sequence the View is parsed from.
+func (v *View) Bytes() []byte {
+	return v.b
+}
+
+// MessageType returns the Message Type of the View.
+func (v *View) MessageType() MsgType {
+	return MsgType(v.b[0])
+}
+
+// ProtocolClass returns the class and the return option in the Protocol Class
+// of UDT and XUDT. It returns 0 and false for UDTS and XUDTS.
+func (v *View) ProtocolClass() (int, bool) {
+	switch v.MessageType() {
+	case MsgTypeUDT, MsgTypeXUDT:
+		return int(v.b[1] & 0xf), v.b[1]>>7 == 1
+	default:
+		return 0, false
+	}
+}
+
+// ReturnCause returns the Return Cause of UDTS and XUDTS, and reports whether
+// the View has it.
+func (v *View) ReturnCause() (params.ReturnCauseValue, bool) {
+	switch v.MessageType() {
+	case MsgTypeUDTS, MsgTypeXUDTS:
+		return params.ReturnCauseValue(v.b[1]), true
+	default:
+		return 0, false
+	}
+}
+
+// HopCounter returns the Hop Counter of XUDT and XUDTS, and reports whether
+// the View has it.
+func (v *View) HopCounter() (uint8, bool) {
+	switch v.MessageType() {
+	case MsgTypeXUDT, MsgTypeXUDTS:
+		return v.b[2], true
+	default:
+		return 0, false
+	}
+}
+
+// CalledPartyAddressBytes returns the value of the Called Party Address,
+// without the length octet.
+func (v *View) CalledPartyAddressBytes() []byte {
+	return v.b[v.cdpa[0]:v.cdpa[1]]
+}
+
+// CallingPartyAddressBytes returns the value of the Calling Party Address,
+// without the length octet.
+func (v *View) CallingPartyAddressBytes() []byte {
+	return v.b[v.cgpa[0]:v.cgpa[1]]
+}
+
+// CalledPartyAddress decodes the Called Party Address.
+func (v *View) CalledPartyAddress() (*params.PartyAddress, error) {
+	return v.opts.parsePartyAddress(params.PCodeCalledPartyAddress, v.b[v.cdpa[0]-1:v.cdpa[1]])
+}
+
+// CallingPartyAddress decodes the Calling Party Address.
+func (v *View) CallingPartyAddress() (*params.PartyAddress, error) {
+	return v.opts.parsePartyAddress(params.PCodeCallingPartyAddress, v.b[v.cgpa[0]-1:v.cgpa[1]])
+}
+
+// Data returns the value of the Data.
+func (v *View) Data() []byte {
+	return v.b[v.data[0]:v.data[1]]
+}
+
+// OptionalParameter returns the value of the optional parameter with code,
+// without the code and the length octets, and reports whether it is present.
+func (v *View) OptionalParameter(code params.ParameterNameCode) ([]byte, bool) {
+	p, ok := v.optionalParameter(code)
+	if !ok {
+		return nil, false
+	}
+	return p[2:], true
+}
+
+// Segmentation decodes the Segmentation, or returns nil if it is not present.
+func (v *View) Segmentation() (*params.Segmentation, error) {
+	p, ok := v.optionalParameter(params.PCodeSegmentation)
+	if !ok {
+		return nil, nil
+	}
+
+	s, _, err := params.ParseSegmentation(p)
+	return s, err
+}
+
+// Importance returns the value of the Importance, and reports whether it is
+// present.
+func (v *View) Importance() (uint8, bool) {
+	p, ok := v.OptionalParameter(params.PCodeImportance)
+	if !ok || len(p) < 1 {
+		return 0, false
+	}
+	return p[0] & 0b111, true
+}
+
+// optionalParameter returns the optional parameter with code, including the
+// code and the length octets.
+func (v *View) optionalParameter(code params.ParameterNameCode) ([]byte, bool) {
+	if v.optional == 0 {
+		return nil, false
+	}
+
+	for b := v.b[v.optional:]; len(b) >= 2; {
+		c := params.ParameterNameCode(b[0])
+		if c == params.PCodeEndOfOptionalParameters {
+			return nil, false
+		}
+
+		end := 2 + int(b[1])
+		if len(b) < end {
+			return nil, false
+		}
+		if c == code {
+			return b[:end], true
+		}
+		b = b[end:]
+	}
+	return nil, false
+}
+
+// Message decodes the View into a Message with the ParseOptions of the View.
+// The Message refers to the byte sequence in the same way as the one parsed
+// with ParseMessage.
+func (v *View) Message() (Message, error) {
+	var m Message
+	switch v.MessageType() {
+	case MsgTypeUDT:
+		m = &UDT{opts: v.opts}
+	case MsgTypeUDTS:
+		m = &UDTS{opts: v.opts}
+	case MsgTypeXUDT:
+		m = &XUDT{opts: v.opts}
+	case MsgTypeXUDTS:
+		m = &XUDTS{opts: v.opts}
+	default:
+		return nil, UnsupportedTypeError(v.MessageType())
+	}
+
+	if err := m.UnmarshalBinary(v.b); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", v.MessageType(), err)
+	}
+	return m, nil
+}
@@ -5,6 +5,7 @@
 package sccp
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 
@@ -21,9 +22,17 @@ type XUDT struct {
 	Data                    *params.Data
 	Segmentation            *params.Segmentation
 	Importance              *params.Importance
+	ISNI                    *params.ISNI
 	EndOfOptionalParameters *params.EndOfOptionalParameters
-
-	ptr1, ptr2, ptr3, ptr4 uint8
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	ptr1, ptr2, ptr3, ptr4 uint8  // as parsed, see RawPointers.
+	lazyData               []byte // the Data not decoded yet, see LoadData.
+	trailing               []byte
+	opts                   parseOptions
+	reuse                  *reusable
 }
 
 // NewXUDT creates a new XUDT.
@@ -37,27 +46,25 @@ func NewXUDT(pcls int, retOnErr bool, hc uint8, cdpa, cgpa *params.PartyAddress,
 		Data:                params.NewData(data),
 	}
 
-	x.ptr1 = 4
-	x.ptr2 = x.ptr1 + uint8(cdpa.MarshalLen()) - 1
-	x.ptr3 = x.ptr2 + uint8(cgpa.MarshalLen()) - 1
-	x.ptr4 = 0
-
 	for _, opt := range opts {
 		switch opt.Code() {
 		case params.PCodeSegmentation:
 			x.Segmentation = opt.(*params.Segmentation)
 		case params.PCodeImportance:
 			x.Importance = opt.(*params.Importance)
+		case params.PCodeISNI:
+			x.ISNI = opt.(*params.ISNI)
 		case params.PCodeEndOfOptionalParameters:
-			x.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+			// always appended when there is any optional parameter.
 		default:
+			if takeUnknownParameter(&x.UnknownParameters, opt) {
+				continue
+			}
 			logf("unexpected parameter: %s in NewXUDT", opt.Code())
 		}
 	}
 
-	if len(opts) > 0 {
-		x.ptr4 = x.ptr3 + uint8(x.Data.MarshalLen()) - 1
-		// so that users don't have to give EndOfOptionalParameters explicitly
+	if x.Segmentation != nil || x.Importance != nil || x.ISNI != nil || len(x.UnknownParameters) > 0 {
 		x.EndOfOptionalParameters = params.NewEndOfOptionalParameters()
 	}
 
@@ -75,93 +82,100 @@ func (x *XUDT) MarshalBinary() ([]byte, error) {
 }
 
 // MarshalTo puts the byte sequence in the byte array given as b.
-// SCCP is dependent on the Pointers when serializing, which means that it might fail when invalid Pointers are set.
+// The pointers are computed from the parameters set at the time of calling it,
+// and the pointer to the optional part is 0 if there is no optional parameter.
 func (x *XUDT) MarshalTo(b []byte) error {
-	l := len(b)
-	if l < 5 {
-		return io.ErrUnexpectedEOF
+	if err := validateProtocolClass(x.Type, x.ProtocolClass); err != nil {
+		return err
 	}
 
-	b[0] = uint8(x.Type)
+	fixed, variable, optional := x.sections()
+	return marshalSections(b, x.Type, fixed, variable, optional, true)
+}
 
-	n := 1
-	m, err := x.ProtocolClass.Write(b[1:])
-	if err != nil {
-		return err
-	}
-	n += m
+// AppendTo appends the byte sequence generated from the XUDT to dst, and
+// returns the extended slice.
+func (x *XUDT) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, x)
+}
 
-	m, err = x.HopCounter.Write(b[n:])
-	if err != nil {
-		return err
-	}
-	n += m
+// WriteTo writes the byte sequence generated from the XUDT to w. It implements
+// io.WriterTo.
+func (x *XUDT) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, x)
+}
 
-	b[n] = x.ptr1
-	if p := int(x.ptr1); l < p {
-		return io.ErrUnexpectedEOF
+// sections returns the parameters in each section of the XUDT. The End of
+// Optional Parameters is not included, as it is always appended to the
+// optional part if there is any optional parameter.
+func (x *XUDT) sections() (fixed, variable, optional []params.Parameter) {
+	fixed = []params.Parameter{x.ProtocolClass, x.HopCounter}
+	variable = []params.Parameter{x.CalledPartyAddress, x.CallingPartyAddress, x.dataParameter()}
+
+	if param := x.Segmentation; param != nil {
+		optional = append(optional, param)
 	}
-	b[n+1] = x.ptr2
-	if p := int(x.ptr2 + 4); l < p {
-		return io.ErrUnexpectedEOF
+	if param := x.Importance; param != nil {
+		optional = append(optional, param)
 	}
-	b[n+2] = x.ptr3
-	if p := int(x.ptr3 + 5); l < p {
-		return io.ErrUnexpectedEOF
+	if param := x.ISNI; param != nil {
+		optional = append(optional, param)
 	}
-	b[n+3] = x.ptr4
-	if p := int(x.ptr4 + 6); l < p {
-		return io.ErrUnexpectedEOF
+	for _, param := range x.UnknownParameters {
+		optional = append(optional, param)
 	}
-	n += 4
 
-	cdpaEnd := int(x.ptr2 + 4)
-	cgpaEnd := int(x.ptr3 + 5)
-	dataEnd := int(x.ptr4 + 6)
-	if _, err := x.CalledPartyAddress.Write(b[n:cdpaEnd]); err != nil {
-		return err
-	}
+	return fixed, variable, optional
+}
 
-	if _, err := x.CallingPartyAddress.Write(b[cdpaEnd:cgpaEnd]); err != nil {
-		return err
-	}
+// RawPointers returns the pointers to the Called Party Address, the Calling
+// Party Address, the Data and the optional part as they were in the byte
+// sequence the XUDT is parsed from, which may be useful to diagnose malformed
+// messages. They are zero if the XUDT is not parsed, and not used by MarshalTo.
+func (x *XUDT) RawPointers() [4]uint8 {
+	return [4]uint8{x.ptr1, x.ptr2, x.ptr3, x.ptr4}
+}
 
-	if _, err := x.Data.Write(b[cgpaEnd:]); err != nil {
-		return err
+// LoadData returns the Data, decoding it first if the XUDT is parsed with
+// WithLazyData and it is not decoded yet. The decoded Data is set to the Data
+// field, so LoadData must not be called concurrently with the other uses of
+// the XUDT. String, LogValue and MarshalJSON do not set it.
+func (x *XUDT) LoadData() *params.Data {
+	if x.Data == nil && x.lazyData != nil {
+		// the length is already checked on parsing.
+		if d, _, err := params.ParseData(x.lazyData); err == nil {
+			x.Data, x.lazyData = d, nil
+		}
 	}
 
-	if x.ptr4 == 0 {
-		return nil
-	}
+	return x.Data
+}
 
-	offset := dataEnd
-	if param := x.Segmentation; param != nil {
-		m, err := param.Write(b[offset:])
-		if err != nil {
-			return err
+// peekData returns the Data as LoadData does, but without setting the Data
+// field, so that formatting the XUDT does not modify it.
+func (x *XUDT) peekData() *params.Data {
+	if x.Data == nil && x.lazyData != nil {
+		if d, _, err := params.ParseData(x.lazyData); err == nil {
+			return d
 		}
-		offset += m
 	}
-	if param := x.Importance; param != nil {
-		m, err := param.Write(b[offset:])
-		if err != nil {
-			return err
-		}
-		offset += m
-	}
-	if param := x.EndOfOptionalParameters; param != nil {
-		_, err := param.Write(b[offset:])
-		if err != nil {
-			return err
-		}
+
+	return x.Data
+}
+
+// dataParameter returns the Data to be marshaled, which is the one not
+// decoded yet as it is if the Data field is not set.
+func (x *XUDT) dataParameter() params.Parameter {
+	if x.Data == nil && x.lazyData != nil {
+		return &rawParameter{code: params.PCodeData, b: x.lazyData}
 	}
 
-	return nil
+	return x.Data
 }
 
 // ParseXUDT decodes given byte sequence as a SCCP XUDT.
-func ParseXUDT(b []byte) (*XUDT, error) {
-	x := &XUDT{}
+func ParseXUDT(b []byte, opts ...ParseOption) (*XUDT, error) {
+	x := &XUDT{opts: *newParseOptions(opts)}
 	if err := x.UnmarshalBinary(b); err != nil {
 		return nil, err
 	}
@@ -177,16 +191,22 @@ func (x *XUDT) UnmarshalBinary(b []byte) error {
 	}
 
 	x.Type = MsgType(b[0])
+	x.Segmentation, x.Importance, x.ISNI, x.EndOfOptionalParameters = nil, nil, nil, nil
+	x.UnknownParameters = nil
+	spare := x.reuse.take()
 
 	offset := 1
-	x.ProtocolClass = &params.ProtocolClass{}
+	x.ProtocolClass = spare.protocolClass()
 	n, err := x.ProtocolClass.Read(b[offset:])
 	if err != nil {
 		return err
 	}
+	if err := validateProtocolClass(x.Type, x.ProtocolClass); err != nil {
+		return err
+	}
 	offset += n
 
-	x.HopCounter = &params.HopCounter{}
+	x.HopCounter = spare.hopCounter()
 	n, err = x.HopCounter.Read(b[offset:])
 	if err != nil {
 		return err
@@ -227,29 +247,43 @@ func (x *XUDT) UnmarshalBinary(b []byte) error {
 		return io.ErrUnexpectedEOF
 	}
 
-	x.CalledPartyAddress, _, err = params.ParseCalledPartyAddress(b[offsetPtr1:cdpaEnd])
+	x.CalledPartyAddress, err = x.opts.readPartyAddress(spare.cdpa, params.PCodeCalledPartyAddress, b[offsetPtr1:cdpaEnd])
 	if err != nil {
 		return err
 	}
 
-	x.CallingPartyAddress, _, err = params.ParseCallingPartyAddress(b[offsetPtr2:cgpaEnd])
+	x.CallingPartyAddress, err = x.opts.readPartyAddress(spare.cgpa, params.PCodeCallingPartyAddress, b[offsetPtr2:cgpaEnd])
 	if err != nil {
 		return err
 	}
 
-	x.Data, _, err = params.ParseData(b[offsetPtr3:dataEnd])
-	if err != nil {
-		return err
+	x.Data, x.lazyData = nil, nil
+	if x.opts.lazyData {
+		x.lazyData = b[offsetPtr3:dataEnd]
+	} else {
+		x.Data = spare.dataParam()
+		if _, err := x.Data.Read(b[offsetPtr3:dataEnd]); err != nil {
+			return err
+		}
 	}
+	x.reuse.set(reusable{pcls: x.ProtocolClass, hc: x.HopCounter, cdpa: x.CalledPartyAddress, cgpa: x.CallingPartyAddress, data: x.Data})
 
+	x.trailing = nil
+	end := max(cdpaEnd, cgpaEnd, dataEnd)
 	if x.ptr4 == 0 {
+		if l > end {
+			x.trailing = b[end:]
+		}
 		return nil
 	}
 
-	opts, _, err := params.ParseOptionalParameters(b[offsetPtr4:])
+	opts, n, err := params.ParseOptionalParameters(b[offsetPtr4:])
 	if err != nil {
 		return err
 	}
+	if end = max(end, offsetPtr4+n); l > end {
+		x.trailing = b[end:]
+	}
 
 	for _, opt := range opts {
 		switch opt.Code() {
@@ -257,53 +291,78 @@ func (x *XUDT) UnmarshalBinary(b []byte) error {
 			x.Segmentation = opt.(*params.Segmentation)
 		case params.PCodeImportance:
 			x.Importance = opt.(*params.Importance)
+		case params.PCodeISNI:
+			x.ISNI = opt.(*params.ISNI)
 		case params.PCodeEndOfOptionalParameters:
 			x.EndOfOptionalParameters = opt.(*params.EndOfOptionalParameters)
+		default:
+			takeUnknownParameter(&x.UnknownParameters, opt)
 		}
 	}
 
 	return nil
 }
 
-// MarshalLen returns the serial length.
-func (x *XUDT) MarshalLen() int {
-	l := 7 // MsgType + ProtocolClass + HopCounter + Pointers
-
-	// if optional parameters exist
-	if x.ptr4 != 0 {
-		l += int(x.ptr4) - 1 // length without optional parameters
-		if param := x.Segmentation; param != nil {
-			l += param.MarshalLen()
-		}
-		if param := x.Importance; param != nil {
-			l += param.MarshalLen()
-		}
-		if param := x.EndOfOptionalParameters; param != nil {
-			l += param.MarshalLen()
-		}
+// Clone returns a deep copy of the XUDT that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (x *XUDT) Clone() *XUDT {
+	c := *x
+	c.ProtocolClass = clonePtr(x.ProtocolClass)
+	c.HopCounter = clonePtr(x.HopCounter)
+	c.CalledPartyAddress = x.CalledPartyAddress.Clone()
+	c.CallingPartyAddress = x.CallingPartyAddress.Clone()
+	c.Data = x.Data.Clone()
+	c.Segmentation = clonePtr(x.Segmentation)
+	c.Importance = clonePtr(x.Importance)
+	c.ISNI = x.ISNI.Clone()
+	c.EndOfOptionalParameters = clonePtr(x.EndOfOptionalParameters)
+	c.UnknownParameters = cloneUnknownParameters(x.UnknownParameters)
+	c.trailing = bytes.Clone(x.trailing)
+	c.lazyData = bytes.Clone(x.lazyData)
+	c.reuse = nil
+
+	return &c
+}
 
-		return l
-	}
+// Reset clears the XUDT to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+//
+// If the XUDT is taken from Pool, the parameters allocated by UnmarshalBinary
+// are kept and overwritten by the next UnmarshalBinary, so they must not be
+// used after Reset.
+func (x *XUDT) Reset() {
+	x.reuse.keep(x.ProtocolClass, x.HopCounter, x.CalledPartyAddress, x.CallingPartyAddress, x.Data)
+	*x = XUDT{opts: x.opts, reuse: x.reuse}
+}
 
-	l += int(x.ptr3) - 2 // length without Data
-	if param := x.Data; param != nil {
-		l += param.MarshalLen()
-	}
+// TrailingBytes returns the bytes that remain after the end of the XUDT computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (x *XUDT) TrailingBytes() []byte {
+	return x.trailing
+}
 
-	return l
+// MarshalLen returns the serial length.
+func (x *XUDT) MarshalLen() int {
+	fixed, variable, optional := x.sections()
+	return 1 + params.SectionsLen(fixed, variable, optional, true)
 }
 
 // String returns the XUDT values in human readable format.
 func (x *XUDT) String() string {
-	return fmt.Sprintf("%s: {ProtocolClass: %s, HopCounter: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, Data: %s, Segmentation: %s, Importance: %s}",
+	return fmt.Sprintf("%s: {ProtocolClass: %s, HopCounter: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, Data: %s, Segmentation: %s, Importance: %s, ISNI: %s}",
 		x.Type,
 		x.ProtocolClass,
 		x.HopCounter,
 		x.CalledPartyAddress,
 		x.CallingPartyAddress,
-		x.Data,
+		x.peekData(),
 		x.Segmentation,
 		x.Importance,
+		x.ISNI,
 	)
 }
 
@@ -0,0 +1,233 @@
+// Copyright 2019-2024 go-sccp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sccp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/wmnsk/go-sccp/params"
+)
+
+// XUDTS represents a SCCP Message Extended Unitdata Service (XUDTS).
+type XUDTS struct {
+	Type                MsgType
+	ReturnCause         *params.ReturnCause
+	HopCounter          *params.HopCounter
+	CalledPartyAddress  *params.PartyAddress
+	CallingPartyAddress *params.PartyAddress
+	Data                *params.Data
+	Segmentation        *params.Segmentation
+	Importance          *params.Importance
+	ISNI                *params.ISNI
+	// UnknownParameters are the optional parameters unknown to this package,
+	// which are kept as they are and marshaled after the known ones.
+	UnknownParameters []*params.UnknownParameter
+
+	trailing []byte
+	opts     parseOptions
+}
+
+// NewXUDTS creates a new XUDTS.
+//
+// The optional parameters given as opts should be the optional ones, e.g.,
+// created with params.NewImportanceOptional.
+func NewXUDTS(cause params.ReturnCauseValue, hc uint8, cdpa, cgpa *params.PartyAddress, data []byte, opts ...params.Parameter) *XUDTS {
+	x := &XUDTS{
+		Type:                MsgTypeXUDTS,
+		ReturnCause:         params.NewCause(cause),
+		HopCounter:          params.NewHopCounter(hc),
+		CalledPartyAddress:  cdpa,
+		CallingPartyAddress: cgpa,
+		Data:                params.NewData(data),
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			x.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			x.Importance = opt.(*params.Importance)
+		case params.PCodeISNI:
+			x.ISNI = opt.(*params.ISNI)
+		case params.PCodeEndOfOptionalParameters:
+			// always appended when there is any optional parameter.
+		default:
+			if takeUnknownParameter(&x.UnknownParameters, opt) {
+				continue
+			}
+			logf("unexpected parameter: %s in NewXUDTS", opt.Code())
+		}
+	}
+
+	return x
+}
+
+// MarshalBinary returns the byte sequence generated from a XUDTS instance.
+func (x *XUDTS) MarshalBinary() ([]byte, error) {
+	b := make([]byte, x.MarshalLen())
+	if err := x.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (x *XUDTS) MarshalTo(b []byte) error {
+	fixed, variable, optional := x.sections()
+	return marshalSections(b, x.Type, fixed, variable, optional, true)
+}
+
+// AppendTo appends the byte sequence generated from the XUDTS to dst, and
+// returns the extended slice.
+func (x *XUDTS) AppendTo(dst []byte) ([]byte, error) {
+	return appendMessage(dst, x)
+}
+
+// WriteTo writes the byte sequence generated from the XUDTS to w. It implements
+// io.WriterTo.
+func (x *XUDTS) WriteTo(w io.Writer) (int64, error) {
+	return writeMessage(w, x)
+}
+
+// sections returns the parameters in each section of the XUDTS.
+func (x *XUDTS) sections() (fixed, variable, optional []params.Parameter) {
+	fixed = []params.Parameter{x.ReturnCause, x.HopCounter}
+	variable = []params.Parameter{x.CalledPartyAddress, x.CallingPartyAddress, x.Data}
+
+	if param := x.Segmentation; param != nil {
+		optional = append(optional, param)
+	}
+	if param := x.Importance; param != nil {
+		optional = append(optional, param)
+	}
+	if param := x.ISNI; param != nil {
+		optional = append(optional, param)
+	}
+	for _, param := range x.UnknownParameters {
+		optional = append(optional, param)
+	}
+
+	return fixed, variable, optional
+}
+
+// ParseXUDTS decodes given byte sequence as a SCCP XUDTS.
+func ParseXUDTS(b []byte, opts ...ParseOption) (*XUDTS, error) {
+	x := &XUDTS{opts: *newParseOptions(opts)}
+	if err := x.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a SCCP XUDTS.
+func (x *XUDTS) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	x.Type = MsgType(b[0])
+	x.ReturnCause = &params.ReturnCause{}
+	x.HopCounter = &params.HopCounter{}
+	x.CalledPartyAddress = params.NewCalledPartyAddress(0, 0, 0, nil)
+	x.CallingPartyAddress = params.NewCallingPartyAddress(0, 0, 0, nil)
+	x.Data = &params.Data{}
+	x.Segmentation, x.Importance, x.ISNI = nil, nil, nil
+	x.UnknownParameters = nil
+
+	opts, n, err := x.opts.unmarshalSections(
+		b[1:],
+		[]params.Parameter{x.ReturnCause, x.HopCounter},
+		[]params.Parameter{x.CalledPartyAddress, x.CallingPartyAddress, x.Data},
+		true,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		switch opt.Code() {
+		case params.PCodeSegmentation:
+			x.Segmentation = opt.(*params.Segmentation)
+		case params.PCodeImportance:
+			x.Importance = opt.(*params.Importance)
+		case params.PCodeISNI:
+			x.ISNI = opt.(*params.ISNI)
+		default:
+			takeUnknownParameter(&x.UnknownParameters, opt)
+		}
+	}
+
+	x.trailing = trailingBytes(b, 1+n)
+	return nil
+}
+
+// Clone returns a deep copy of the XUDTS that does not share any memory with
+// the original, including the byte sequence it was parsed from.
+func (x *XUDTS) Clone() *XUDTS {
+	c := *x
+	c.ReturnCause = clonePtr(x.ReturnCause)
+	c.HopCounter = clonePtr(x.HopCounter)
+	c.CalledPartyAddress = x.CalledPartyAddress.Clone()
+	c.CallingPartyAddress = x.CallingPartyAddress.Clone()
+	c.Data = x.Data.Clone()
+	c.Segmentation = clonePtr(x.Segmentation)
+	c.Importance = clonePtr(x.Importance)
+	c.ISNI = x.ISNI.Clone()
+	c.UnknownParameters = cloneUnknownParameters(x.UnknownParameters)
+	c.trailing = bytes.Clone(x.trailing)
+
+	return &c
+}
+
+// Reset clears the XUDTS to be reused with UnmarshalBinary, keeping the
+// ParseOptions it was parsed with.
+func (x *XUDTS) Reset() {
+	*x = XUDTS{opts: x.opts}
+}
+
+// TrailingBytes returns the bytes that remain after the end of the XUDTS computed
+// from the pointers and lengths of the parameters when it is parsed, or nil if
+// there is no such bytes.
+//
+// The returned slice refers to the byte sequence given to UnmarshalBinary.
+// It is not included in the result of MarshalBinary.
+func (x *XUDTS) TrailingBytes() []byte {
+	return x.trailing
+}
+
+// MarshalLen returns the serial length.
+func (x *XUDTS) MarshalLen() int {
+	fixed, variable, optional := x.sections()
+	return 1 + params.SectionsLen(fixed, variable, optional, true)
+}
+
+// String returns the XUDTS values in human readable format.
+func (x *XUDTS) String() string {
+	return fmt.Sprintf("%s: {ReturnCause: %s, HopCounter: %s, CalledPartyAddress: %v, CallingPartyAddress: %v, Data: %s, Segmentation: %v, Importance: %v, ISNI: %v}",
+		x.Type,
+		x.ReturnCause,
+		x.HopCounter,
+		x.CalledPartyAddress,
+		x.CallingPartyAddress,
+		x.Data,
+		x.Segmentation,
+		x.Importance,
+		x.ISNI,
+	)
+}
+
+// MessageType returns the Message Type in int.
+func (x *XUDTS) MessageType() MsgType {
+	return MsgTypeXUDTS
+}
+
+// MessageTypeName returns the Message Type in string.
+func (x *XUDTS) MessageTypeName() string {
+	return x.MessageType().String()
+}